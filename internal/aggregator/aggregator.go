@@ -55,6 +55,9 @@ func (a *Aggregator) Aggregate(toolReports []models.ToolReport) (*models.Aggrega
 		}
 	}
 
+	// Record when each tool's report was generated
+	report.Freshness = freshness(toolReports, report.Timestamp)
+
 	// Calculate summary statistics
 	a.calculateSummary(report)
 
@@ -64,6 +67,18 @@ func (a *Aggregator) Aggregate(toolReports []models.ToolReport) (*models.Aggrega
 	return report, nil
 }
 
+// Recalculate recomputes the summary and health score after the report's
+// issues were changed, e.g. when triage suppressed some of them
+func (a *Aggregator) Recalculate(report *models.AggregatedReport) {
+	report.Summary = models.CrossToolSummary{
+		IssuesByTool:     make(map[string]int),
+		IssuesByCategory: make(map[string]int),
+		IssuesBySeverity: make(map[string]int),
+	}
+	a.calculateSummary(report)
+	a.calculateHealthScore(report)
+}
+
 // calculateSummary computes summary statistics from normalized issues
 func (a *Aggregator) calculateSummary(report *models.AggregatedReport) {
 	// Count issues by tool
@@ -85,6 +100,8 @@ func (a *Aggregator) calculateSummary(report *models.AggregatedReport) {
 			report.Summary.UnsupportedTools++
 		}
 	}
+
+	setCoverageSummary(report)
 }
 
 // calculateHealthScore determines overall health based on issues
@@ -143,22 +160,29 @@ func (a *Aggregator) AddTrend(current *models.AggregatedReport, previous *models
 		return
 	}
 
+	// Issues of tools missing from this run are unknown, not resolved
+	missing := UnreportedTools(previous, current)
+	previousIssues := comparableIssueCount(previous, missing)
+
 	trend := &models.Trend{
-		PreviousIssues: previous.Summary.TotalIssues,
+		PreviousIssues: previousIssues,
 		CurrentIssues:  current.Summary.TotalIssues,
 		ComparedWith:   previous.Timestamp,
+		MissingTools:   missing,
 	}
 
 	// Calculate change
-	change := current.Summary.TotalIssues - previous.Summary.TotalIssues
+	change := current.Summary.TotalIssues - previousIssues
 
 	// Determine direction
 	if change < 0 {
 		trend.Direction = "improving"
-		trend.ChangePercent = float64(change) / float64(previous.Summary.TotalIssues) * 100.0
+		trend.ChangePercent = float64(change) / float64(previousIssues) * 100.0
 	} else if change > 0 {
 		trend.Direction = "degrading"
-		trend.ChangePercent = float64(change) / float64(previous.Summary.TotalIssues) * 100.0
+		if previousIssues > 0 {
+			trend.ChangePercent = float64(change) / float64(previousIssues) * 100.0
+		}
 	} else {
 		trend.Direction = "stable"
 		trend.ChangePercent = 0.0
@@ -252,6 +252,41 @@ func TestAggregatorAggregateEmptyReports(t *testing.T) {
 	}
 }
 
+func TestAggregatorRecalculate(t *testing.T) {
+	agg := New()
+	report, err := agg.Aggregate([]models.ToolReport{{
+		Tool:        string(models.ToolS3),
+		IsSupported: true,
+		RawData: &models.S3Report{
+			Summary: models.S3Summary{TotalBuckets: 4},
+			Buckets: map[string]*models.BucketAnalysis{
+				"a": {Status: "MISSING_BUCKET"},
+				"b": {Status: "MISSING_BUCKET"},
+			},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Summary.TotalIssues != 2 {
+		t.Fatalf("expected 2 issues, got %d", report.Summary.TotalIssues)
+	}
+	before := report.Summary.ScorePercent
+
+	report.Issues = report.Issues[:1]
+	agg.Recalculate(report)
+
+	if report.Summary.TotalIssues != 1 || report.Summary.IssuesByTool[string(models.ToolS3)] != 1 {
+		t.Errorf("summary not recomputed: %+v", report.Summary)
+	}
+	if report.Summary.TotalTools != 1 {
+		t.Errorf("expected tool counts to be recomputed once, got %d", report.Summary.TotalTools)
+	}
+	if report.Summary.ScorePercent <= before {
+		t.Errorf("expected score to improve from %.1f, got %.1f", before, report.Summary.ScorePercent)
+	}
+}
+
 func TestAggregatorAddTrend(t *testing.T) {
 	aggregator := New()
 
@@ -0,0 +1,327 @@
+package aggregator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/pkg/spectrev1"
+)
+
+// Finding IDs used when converting legacy reports. Legacy statuses that
+// already are spectre/v1 IDs (S3, Pg and Mongo) are kept as is.
+const (
+	convertInvalidSecretID = "INVALID_SECRET"
+	convertMissingPrefixID = "MISSING_PREFIX"
+	convertConfigAnomalyID = "CONFIG_ANOMALY"
+	convertUsageAnomalyID  = "USAGE_ANOMALY"
+	convertScanErrorID     = "SCAN_ERROR"
+)
+
+var envelopeVersionPattern = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]`)
+
+// ConvertToSpectreV1 turns a legacy tool report into a spectre/v1 envelope.
+// Normalizing the envelope gives the same issues as normalizing the legacy
+// report. Severities without a spectre/v1 value (critical), counts other
+// than 1 and detection times other than the report timestamp are carried
+// in spectre/v1.1 fields, so such reports convert to spectre/v1.1. A report
+// that cannot be converted without losing data is an error.
+func ConvertToSpectreV1(report *models.ToolReport) (*models.SpectreV1Report, error) {
+	if _, ok := report.RawData.(*models.SpectreV1Report); ok {
+		return nil, fmt.Errorf("%s report is already a spectre/v1 envelope", report.Tool)
+	}
+
+	var findings []spectrev1.Finding
+	var uriHash string
+	var err error
+
+	switch raw := report.RawData.(type) {
+	case *models.VaultReport:
+		findings = convertVault(raw)
+	case *models.S3Report:
+		findings = convertS3(raw)
+	case *models.KafkaReport:
+		findings = convertKafka(raw)
+	case *models.ClickHouseReport:
+		findings, err = convertClickHouse(raw, report.Timestamp)
+	case *models.PgReport:
+		findings = convertPg(raw)
+	case *models.MongoReport:
+		findings = convertMongo(raw)
+		uriHash = raw.Metadata.URIHash
+	default:
+		return nil, fmt.Errorf("no legacy format known for tool: %s", report.Tool)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s report cannot be converted: %w", report.Tool, err)
+	}
+
+	targetType, ok := spectrev1.TargetType(report.Tool)
+	if !ok {
+		return nil, fmt.Errorf("no spectre/v1 target type for tool: %s", report.Tool)
+	}
+
+	return spectrev1.NewBuilder(report.Tool, envelopeVersion(report.Version), spectrev1.Target{Type: targetType, URIHash: uriHash}).
+		Timestamp(report.Timestamp).
+		Add(findings...).
+		Build()
+}
+
+func convertVault(report *models.VaultReport) []spectrev1.Finding {
+	var findings []spectrev1.Finding
+	for _, path := range sortedKeys(report.Secrets) {
+		secret := report.Secrets[path]
+		if secret.Status == "ok" {
+			continue
+		}
+
+		category := mapVaultStatus(secret.Status)
+		finding := legacyFinding(
+			vaultStatusID(secret.Status),
+			"",
+			path,
+			buildVaultEvidence(secret),
+		)
+		finding = withSeverity(finding, models.DetermineSeverity(category, models.ToolVault))
+		findings = append(findings, withOccurrences(finding, len(secret.References)))
+	}
+	return findings
+}
+
+func convertS3(report *models.S3Report) []spectrev1.Finding {
+	var findings []spectrev1.Finding
+	for _, name := range sortedKeys(report.Buckets) {
+		bucket := report.Buckets[name]
+		if bucket.Status == "OK" {
+			continue
+		}
+
+		if len(bucket.Prefixes) == 0 {
+			category := mapS3Status(bucket.Status)
+			finding := legacyFinding(
+				legacyID(bucket.Status, category),
+				"",
+				fmt.Sprintf("s3://%s", name),
+				bucket.Message,
+			)
+			findings = append(findings, withSeverity(finding, models.DetermineSeverity(category, models.ToolS3)))
+			continue
+		}
+
+		for _, prefix := range bucket.Prefixes {
+			if prefix.Status == "OK" {
+				continue
+			}
+			category := mapS3Status(prefix.Status)
+			finding := legacyFinding(
+				legacyID(prefix.Status, category),
+				"",
+				fmt.Sprintf("s3://%s/%s", name, prefix.Prefix),
+				prefix.Message,
+			)
+			finding = withSeverity(finding, models.DetermineSeverity(category, models.ToolS3))
+			findings = append(findings, withOccurrences(finding, prefix.ObjectCount))
+		}
+	}
+	return findings
+}
+
+func convertKafka(report *models.KafkaReport) []spectrev1.Finding {
+	var findings []spectrev1.Finding
+	for _, topic := range report.UnusedTopics {
+		finding := legacyFinding(
+			"UNUSED_TOPIC",
+			mapKafkaRiskToSeverity(topic.Risk),
+			fmt.Sprintf("topic:%s", topic.Name),
+			fmt.Sprintf("%s (partitions: %d, risk: %s)", topic.Reason, topic.Partitions, topic.Risk),
+		)
+		findings = append(findings, withOccurrences(finding, topic.Partitions))
+	}
+	return findings
+}
+
+func convertClickHouse(report *models.ClickHouseReport, ts time.Time) ([]spectrev1.Finding, error) {
+	var findings []spectrev1.Finding
+	for _, table := range report.Tables {
+		if !table.ZeroUsage {
+			continue
+		}
+		severity := spectrev1.SeverityMedium
+		if !table.IsReplicated {
+			severity = spectrev1.SeverityLow
+		}
+		finding := legacyFinding(
+			"UNUSED_TABLE",
+			severity,
+			table.FullName,
+			fmt.Sprintf("zero usage (reads: %d, writes: %d)", table.Reads, table.Writes),
+		)
+		findings = append(findings, withDetected(finding, table.FirstSeen, table.LastAccess, ts))
+	}
+
+	for _, anomaly := range report.Anomalies {
+		id := convertUsageAnomalyID
+		if anomaly.Type == "configuration" {
+			id = convertConfigAnomalyID
+		}
+		switch anomaly.Severity {
+		case models.SeverityCritical, models.SeverityHigh, models.SeverityMedium, models.SeverityLow:
+		case "":
+			return nil, fmt.Errorf("anomaly on %s has no severity", anomaly.AffectedTable)
+		default:
+			return nil, fmt.Errorf("anomaly on %s has unknown severity %q", anomaly.AffectedTable, anomaly.Severity)
+		}
+		finding := legacyFinding(id, "", anomaly.AffectedTable, anomaly.Description)
+		finding = withSeverity(finding, anomaly.Severity)
+		findings = append(findings, withDetected(finding, anomaly.DetectedAt, anomaly.DetectedAt, ts))
+	}
+	return findings, nil
+}
+
+func convertPg(report *models.PgReport) []spectrev1.Finding {
+	var findings []spectrev1.Finding
+	for _, finding := range report.Findings {
+		category := mapPgFindingCategory(finding.Type)
+		if category == "" {
+			continue
+		}
+		findings = append(findings, legacyFinding(
+			legacyID(finding.Type, category),
+			legacySeverity(finding.Severity),
+			buildPgResource(finding),
+			finding.Message,
+		))
+	}
+	return findings
+}
+
+func convertMongo(report *models.MongoReport) []spectrev1.Finding {
+	var findings []spectrev1.Finding
+	for _, finding := range report.Findings {
+		category := mapMongoFindingCategory(finding.Type)
+		if category == "" {
+			continue
+		}
+		findings = append(findings, legacyFinding(
+			legacyID(finding.Type, category),
+			legacySeverity(finding.Severity),
+			buildMongoResource(finding),
+			finding.Message,
+		))
+	}
+	return findings
+}
+
+// legacyFinding builds a finding, filling the location and message that
+// spectre/v1 requires when the legacy report left them empty.
+func legacyFinding(id, severity, location, message string) spectrev1.Finding {
+	if location == "" {
+		location = "unknown"
+	}
+	if message == "" {
+		message = id
+	}
+	return spectrev1.Finding{ID: id, Severity: severity, Location: location, Message: message}
+}
+
+// withSeverity sets the envelope severity closest to a normalized one.
+// Critical, which the envelope has no value for, is kept in tool_severity.
+func withSeverity(f spectrev1.Finding, severity string) spectrev1.Finding {
+	f.Severity = envelopeSeverity(severity)
+	if severity == models.SeverityCritical {
+		f.ToolSeverity = spectrev1.ToolSeverityCritical
+	}
+	return f
+}
+
+// withOccurrences sets the occurrence count when it is not the default 1.
+func withOccurrences(f spectrev1.Finding, n int) spectrev1.Finding {
+	if n != 1 {
+		f.Occurrences = &n
+	}
+	return f
+}
+
+// withDetected sets the first and last detection times that differ from
+// the report timestamp ts, which readers assume by default.
+func withDetected(f spectrev1.Finding, first, last, ts time.Time) spectrev1.Finding {
+	if !first.Equal(ts) {
+		f.FirstDetected = &first
+	}
+	if !last.Equal(ts) {
+		f.LastDetected = &last
+	}
+	return f
+}
+
+// vaultStatusID maps a VaultSpectre status to the ID vaultspectre uses in
+// spectre/v1 output.
+func vaultStatusID(status string) string {
+	switch status {
+	case "missing":
+		return "MISSING_SECRET"
+	case "access_denied":
+		return "ACCESS_DENIED"
+	case "invalid":
+		return convertInvalidSecretID
+	case "stale":
+		return "STALE_SECRET"
+	default:
+		return convertScanErrorID
+	}
+}
+
+// legacyID keeps a legacy status or finding type as the finding ID when it
+// normalizes to the same category, and falls back to SCAN_ERROR otherwise.
+func legacyID(status, category string) string {
+	if status != "" && mapSpectreV1IDToCategory(status) == category {
+		return status
+	}
+	return convertScanErrorID
+}
+
+// envelopeSeverity maps a normalized severity onto the spectre/v1 scale,
+// which has no critical. withSeverity keeps what it cannot express.
+func envelopeSeverity(severity string) string {
+	switch severity {
+	case models.SeverityCritical, models.SeverityHigh:
+		return spectrev1.SeverityHigh
+	case models.SeverityMedium:
+		return spectrev1.SeverityMedium
+	case models.SeverityLow:
+		return spectrev1.SeverityLow
+	default:
+		return spectrev1.SeverityMedium
+	}
+}
+
+// legacySeverity keeps a Pg/Mongo severity when spectre/v1 has it, so info
+// findings stay info.
+func legacySeverity(severity string) string {
+	if spectrev1.IsSeverity(severity) {
+		return severity
+	}
+	return spectrev1.SeverityMedium
+}
+
+// envelopeVersion returns a version matching the spectre/v1 pattern,
+// 0.0.0 when the legacy report has none.
+func envelopeVersion(version string) string {
+	version = strings.TrimPrefix(version, "v")
+	if envelopeVersionPattern.MatchString(version) {
+		return version
+	}
+	return "0.0.0"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
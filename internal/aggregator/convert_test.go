@@ -0,0 +1,353 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+// Property: a converted report normalizes to exactly the legacy report's
+// issues. Only the finding ID is new; the legacy path has none.
+func TestConvertToSpectreV1NormalizesLikeLegacy(t *testing.T) {
+	rng := rand.New(rand.NewPCG(44, 1))
+	normalizer := NewNormalizer()
+	ts := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	generators := map[models.ToolType]func(*rand.Rand) interface{}{
+		models.ToolVault:      randomVaultReport,
+		models.ToolS3:         randomS3Report,
+		models.ToolKafka:      randomKafkaReport,
+		models.ToolClickHouse: randomClickHouseReport,
+		models.ToolPg:         randomPgReport,
+		models.ToolMongo:      randomMongoReport,
+	}
+
+	for tool, generate := range generators {
+		for i := 0; i < 200; i++ {
+			legacy := &models.ToolReport{
+				Tool:        string(tool),
+				Version:     "v0.3.1",
+				Timestamp:   ts,
+				RawData:     generate(rng),
+				IsSupported: true,
+			}
+
+			want, err := normalizer.Normalize(legacy)
+			if err != nil {
+				t.Fatalf("%s #%d: normalize legacy: %v", tool, i, err)
+			}
+
+			envelope, err := ConvertToSpectreV1(legacy)
+			if err != nil {
+				t.Fatalf("%s #%d: convert: %v", tool, i, err)
+			}
+			// Go through JSON, as spectrehub convert writes the envelope.
+			data, err := json.Marshal(envelope)
+			if err != nil {
+				t.Fatalf("%s #%d: marshal: %v", tool, i, err)
+			}
+			envelope = &models.SpectreV1Report{}
+			if err := json.Unmarshal(data, envelope); err != nil {
+				t.Fatalf("%s #%d: unmarshal: %v", tool, i, err)
+			}
+			converted := &models.ToolReport{
+				Tool:        envelope.Tool,
+				Version:     envelope.Version,
+				Timestamp:   envelope.Timestamp,
+				RawData:     envelope,
+				IsSupported: true,
+			}
+			got, err := normalizer.Normalize(converted)
+			if err != nil {
+				t.Fatalf("%s #%d: normalize converted: %v", tool, i, err)
+			}
+
+			for j := range got {
+				if got[j].ID == "" {
+					t.Fatalf("%s #%d: converted issue %s has no ID", tool, i, got[j].Resource)
+				}
+				got[j].ID = ""
+			}
+
+			sortIssues(want)
+			sortIssues(got)
+			if !reflect.DeepEqual(want, got) {
+				t.Fatalf("%s #%d: converted issues differ\nlegacy:    %+v\nconverted: %+v", tool, i, want, got)
+			}
+		}
+	}
+}
+
+func sortIssues(issues []models.NormalizedIssue) {
+	sort.Slice(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if DiffKey(a) != DiffKey(b) {
+			return DiffKey(a) < DiffKey(b)
+		}
+		if a.Severity != b.Severity {
+			return a.Severity < b.Severity
+		}
+		return a.Evidence < b.Evidence
+	})
+}
+
+func pick(rng *rand.Rand, values ...string) string {
+	return values[rng.IntN(len(values))]
+}
+
+func randomWord(rng *rand.Rand) string {
+	return pick(rng, "orders", "users", "events", "billing", "audit", "tmp", "legacy") + fmt.Sprint(rng.IntN(5))
+}
+
+func randomVaultReport(rng *rand.Rand) interface{} {
+	report := &models.VaultReport{Secrets: map[string]*models.SecretInfo{}}
+	for i := rng.IntN(6); i > 0; i-- {
+		path := "secret/data/" + randomWord(rng)
+		secret := &models.SecretInfo{
+			Path:   path,
+			Status: pick(rng, "ok", "missing", "access_denied", "invalid", "error", "stale", "dynamic"),
+		}
+		switch rng.IntN(3) {
+		case 0:
+			secret.ErrorMsg = "permission denied"
+		case 1:
+			secret.IsStale = true
+			secret.LastAccessed = "2025-01-01"
+		}
+		for r := rng.IntN(3); r > 0; r-- {
+			secret.References = append(secret.References, models.VaultReference{File: "main.go", Line: r})
+		}
+		report.Secrets[path] = secret
+	}
+	return report
+}
+
+func randomS3Report(rng *rand.Rand) interface{} {
+	statuses := []string{"OK", "MISSING_BUCKET", "UNUSED_BUCKET", "MISSING_PREFIX", "STALE_PREFIX",
+		"VERSION_SPRAWL", "LIFECYCLE_MISCONFIG", "ACCESS_DENIED", "WEIRD_STATE"}
+	report := &models.S3Report{Buckets: map[string]*models.BucketAnalysis{}}
+	for i := rng.IntN(5); i > 0; i-- {
+		name := randomWord(rng)
+		bucket := &models.BucketAnalysis{Name: name, Status: pick(rng, statuses...), Message: "bucket " + name}
+		for p := rng.IntN(3); p > 0; p-- {
+			bucket.Prefixes = append(bucket.Prefixes, models.PrefixAnalysis{
+				Prefix:      randomWord(rng) + "/",
+				Status:      pick(rng, statuses...),
+				Message:     "prefix of " + name,
+				ObjectCount: rng.IntN(1000),
+			})
+		}
+		report.Buckets[name] = bucket
+	}
+	return report
+}
+
+func randomKafkaReport(rng *rand.Rand) interface{} {
+	report := &models.KafkaReport{}
+	for i := rng.IntN(5); i > 0; i-- {
+		report.UnusedTopics = append(report.UnusedTopics, &models.UnusedTopic{
+			Name:       randomWord(rng),
+			Partitions: rng.IntN(24),
+			Reason:     "no consumer groups",
+			Risk:       pick(rng, "high", "medium", "low", "unknown"),
+		})
+	}
+	return report
+}
+
+func randomClickHouseReport(rng *rand.Rand) interface{} {
+	report := &models.ClickHouseReport{}
+	for i := rng.IntN(5); i > 0; i-- {
+		name := randomWord(rng)
+		report.Tables = append(report.Tables, models.ClickTable{
+			Name:         name,
+			FullName:     "analytics." + name,
+			Reads:        uint64(rng.IntN(10)),
+			ZeroUsage:    rng.IntN(2) == 0,
+			IsReplicated: rng.IntN(2) == 0,
+			FirstSeen:    time.Date(2025, 1, 1+rng.IntN(28), 0, 0, 0, 0, time.UTC),
+		})
+	}
+	for i := rng.IntN(3); i > 0; i-- {
+		report.Anomalies = append(report.Anomalies, models.ClickAnomaly{
+			Type:          pick(rng, "configuration", "usage", "spike"),
+			Description:   "anomaly detected",
+			Severity:      pick(rng, "critical", "high", "medium", "low"),
+			AffectedTable: "analytics." + randomWord(rng),
+			DetectedAt:    time.Date(2025, 2, 1+rng.IntN(28), 0, 0, 0, 0, time.UTC),
+		})
+	}
+	return report
+}
+
+func randomPgReport(rng *rand.Rand) interface{} {
+	report := &models.PgReport{}
+	for i := rng.IntN(6); i > 0; i-- {
+		finding := models.PgFinding{
+			Type: pick(rng, "UNUSED_TABLE", "UNUSED_INDEX", "UNREFERENCED_TABLE", "MISSING_TABLE",
+				"MISSING_COLUMN", "BLOATED_INDEX", "MISSING_VACUUM", "NO_PRIMARY_KEY", "DUPLICATE_INDEX",
+				"UNINDEXED_QUERY", "CODE_MATCH", "OK", "RISKY", "NEW_CHECK", ""),
+			Severity: pick(rng, "high", "medium", "low", "info", "critical", ""),
+			Schema:   pick(rng, "public", ""),
+			Table:    randomWord(rng),
+			Message:  "pg finding",
+		}
+		switch rng.IntN(3) {
+		case 0:
+			finding.Column = randomWord(rng)
+		case 1:
+			finding.Index = randomWord(rng) + "_idx"
+		}
+		report.Findings = append(report.Findings, finding)
+	}
+	return report
+}
+
+func randomMongoReport(rng *rand.Rand) interface{} {
+	report := &models.MongoReport{}
+	for i := rng.IntN(6); i > 0; i-- {
+		report.Findings = append(report.Findings, models.MongoFinding{
+			Type: pick(rng, "UNUSED_COLLECTION", "UNUSED_INDEX", "ORPHANED_INDEX", "MISSING_COLLECTION",
+				"MISSING_INDEX", "MISSING_TTL", "OVERSIZED_COLLECTION", "DYNAMIC_COLLECTION",
+				"ADMIN_IN_DATA_DB", "INACTIVE_USER", "OK", "UNUSED_TOPIC", "NEW_CHECK"),
+			Severity:   pick(rng, "high", "medium", "low", "info", "bogus"),
+			Database:   pick(rng, "app", ""),
+			Collection: randomWord(rng),
+			Index:      pick(rng, "", "email_1"),
+			Message:    "mongo finding",
+		})
+	}
+	return report
+}
+
+func TestConvertToSpectreV1Envelope(t *testing.T) {
+	report := &models.ToolReport{
+		Tool:      string(models.ToolMongo),
+		Version:   "v0.2.0",
+		Timestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		RawData: &models.MongoReport{
+			Metadata: models.MongoMetadata{URIHash: "sha256:abc"},
+			Findings: []models.MongoFinding{
+				{Type: "UNUSED_INDEX", Severity: "info", Database: "app", Collection: "users", Index: "email_1", Message: "never used"},
+				{Type: "NEW_CHECK", Severity: "high", Collection: "orders"},
+			},
+		},
+	}
+
+	env, err := ConvertToSpectreV1(report)
+	if err != nil {
+		t.Fatalf("ConvertToSpectreV1: %v", err)
+	}
+	if env.Schema != models.SpectreV1Schema || env.Version != "0.2.0" {
+		t.Errorf("schema/version = %s/%s, want spectre/v1/0.2.0", env.Schema, env.Version)
+	}
+	if env.Target.Type != "mongodb" || env.Target.URIHash != "sha256:abc" {
+		t.Errorf("target = %+v, want mongodb with uri hash", env.Target)
+	}
+	if env.Summary.Total != 2 || env.Summary.Info != 1 || env.Summary.High != 1 {
+		t.Errorf("summary = %+v, want 1 info and 1 high", env.Summary)
+	}
+
+	first, second := env.Findings[0], env.Findings[1]
+	if first.ID != "UNUSED_INDEX" || first.Severity != "info" || first.Location != "app.users.email_1" {
+		t.Errorf("first finding = %+v", first)
+	}
+	// NEW_CHECK is not a known ID but still normalizes to error; the
+	// empty message is filled with the ID.
+	if second.ID != "NEW_CHECK" || second.Message != "NEW_CHECK" || second.Location != "unknown.orders" {
+		t.Errorf("second finding = %+v", second)
+	}
+}
+
+func TestConvertToSpectreV1CarriesV11Fields(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	report := &models.ToolReport{
+		Tool:      string(models.ToolVault),
+		Timestamp: ts,
+		RawData: &models.VaultReport{Secrets: map[string]*models.SecretInfo{
+			"secret/db": {Status: "missing", References: []models.VaultReference{{File: "a.go"}, {File: "b.go"}}},
+		}},
+	}
+
+	env, err := ConvertToSpectreV1(report)
+	if err != nil {
+		t.Fatalf("ConvertToSpectreV1: %v", err)
+	}
+	if env.Schema != models.SpectreV11Schema {
+		t.Errorf("schema = %s, want spectre/v1.1 for a critical finding", env.Schema)
+	}
+	f := env.Findings[0]
+	if f.Severity != "high" || f.ToolSeverity != "critical" {
+		t.Errorf("severity = %s/%s, want high with tool_severity critical", f.Severity, f.ToolSeverity)
+	}
+	if f.Occurrences == nil || *f.Occurrences != 2 {
+		t.Errorf("occurrences = %v, want 2 references", f.Occurrences)
+	}
+	if f.FirstDetected != nil || f.LastDetected != nil {
+		t.Errorf("detection times equal to the report time should be omitted: %+v", f)
+	}
+}
+
+func TestConvertToSpectreV1Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		report  models.ToolReport
+		wantErr string
+	}{
+		{
+			name:    "already spectre/v1",
+			report:  models.ToolReport{Tool: "s3spectre", RawData: &models.SpectreV1Report{}},
+			wantErr: "already a spectre/v1 envelope",
+		},
+		{
+			name: "anomaly without severity",
+			report: models.ToolReport{Tool: "clickspectre", RawData: &models.ClickHouseReport{
+				Anomalies: []models.ClickAnomaly{{Type: "usage", AffectedTable: "analytics.events"}},
+			}},
+			wantErr: "anomaly on analytics.events has no severity",
+		},
+		{
+			name: "anomaly with unknown severity",
+			report: models.ToolReport{Tool: "clickspectre", RawData: &models.ClickHouseReport{
+				Anomalies: []models.ClickAnomaly{{Type: "usage", Severity: "warning", AffectedTable: "analytics.events"}},
+			}},
+			wantErr: `anomaly on analytics.events has unknown severity "warning"`,
+		},
+		{
+			name:    "unsupported tool",
+			report:  models.ToolReport{Tool: "futurespectre", RawData: map[string]interface{}{}},
+			wantErr: "no legacy format known",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ConvertToSpectreV1(&tt.report)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ConvertToSpectreV1() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnvelopeVersion(t *testing.T) {
+	tests := map[string]string{
+		"1.2.3":    "1.2.3",
+		"v0.3.1":   "0.3.1",
+		"1.0.0-rc": "1.0.0-rc",
+		"unknown":  "0.0.0",
+		"":         "0.0.0",
+	}
+	for in, want := range tests {
+		if got := envelopeVersion(in); got != want {
+			t.Errorf("envelopeVersion(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
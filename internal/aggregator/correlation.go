@@ -0,0 +1,268 @@
+package aggregator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+// CorrelationRule links findings from different tools into incidents.
+// Rules only see normalized issues, so they can be unit tested without
+// running any scanner.
+type CorrelationRule interface {
+	// Name identifies the rule in incident IDs and output.
+	Name() string
+	// Correlate returns the incidents found among issues.
+	Correlate(issues []models.NormalizedIssue) []models.Incident
+}
+
+// Correlator runs a set of correlation rules over an aggregated report.
+type Correlator struct {
+	rules []CorrelationRule
+}
+
+// NewCorrelator creates a correlator. With no rules the built-in
+// DefaultCorrelationRules are used.
+func NewCorrelator(rules ...CorrelationRule) *Correlator {
+	if len(rules) == 0 {
+		rules = DefaultCorrelationRules()
+	}
+	return &Correlator{rules: rules}
+}
+
+// DefaultCorrelationRules returns the built-in cross-tool correlation rules.
+func DefaultCorrelationRules() []CorrelationRule {
+	return []CorrelationRule{
+		BucketPolicyRule(),
+		SecretMountRule(),
+		IdleDatabaseRule(),
+	}
+}
+
+// Correlate runs every rule and returns the incidents sorted by severity
+// (most severe first), then by ID.
+func (c *Correlator) Correlate(report *models.AggregatedReport) []models.Incident {
+	var incidents []models.Incident
+	for _, rule := range c.rules {
+		incidents = append(incidents, rule.Correlate(report.Issues)...)
+	}
+
+	sort.SliceStable(incidents, func(i, j int) bool {
+		pi, pj := severityPriority(incidents[i].Severity), severityPriority(incidents[j].Severity)
+		if pi != pj {
+			return pi > pj
+		}
+		return incidents[i].ID < incidents[j].ID
+	})
+
+	return incidents
+}
+
+// LinkRule correlates "anchor" findings with "related" findings that refer
+// to the same entity. Anchors are grouped by the entity key they name; an
+// incident is produced for every key that has at least one related finding.
+type LinkRule struct {
+	RuleName string
+	// Title formats the incident title for an entity key.
+	Title func(key string) string
+	// Anchor selects the primary findings and AnchorKey extracts the entity
+	// they are about; an empty key skips the finding.
+	Anchor    func(issue models.NormalizedIssue) bool
+	AnchorKey func(issue models.NormalizedIssue) string
+	// Related selects candidate findings and Links reports whether one of
+	// them refers to the given entity key.
+	Related func(issue models.NormalizedIssue) bool
+	Links   func(key string, issue models.NormalizedIssue) bool
+}
+
+// Name implements CorrelationRule.
+func (r LinkRule) Name() string {
+	return r.RuleName
+}
+
+// Correlate implements CorrelationRule.
+func (r LinkRule) Correlate(issues []models.NormalizedIssue) []models.Incident {
+	anchors := make(map[string][]models.NormalizedIssue)
+	var keys []string
+	var related []models.NormalizedIssue
+
+	for _, issue := range issues {
+		if r.Anchor(issue) {
+			if key := r.AnchorKey(issue); key != "" {
+				if _, ok := anchors[key]; !ok {
+					keys = append(keys, key)
+				}
+				anchors[key] = append(anchors[key], issue)
+				continue
+			}
+		}
+		if r.Related(issue) {
+			related = append(related, issue)
+		}
+	}
+
+	sort.Strings(keys)
+
+	var incidents []models.Incident
+	for _, key := range keys {
+		var linked []models.NormalizedIssue
+		for _, issue := range related {
+			if r.Links(key, issue) {
+				linked = append(linked, issue)
+			}
+		}
+		if len(linked) == 0 {
+			continue
+		}
+		incidents = append(incidents, newIncident(r.RuleName, key, r.Title(key), append(anchors[key], linked...)))
+	}
+
+	return incidents
+}
+
+// newIncident builds an incident, deriving severity and tools from issues.
+func newIncident(rule, key, title string, issues []models.NormalizedIssue) models.Incident {
+	inc := models.Incident{
+		ID:     rule + ":" + key,
+		Rule:   rule,
+		Title:  title,
+		Entity: key,
+		Issues: issues,
+	}
+
+	seen := make(map[string]bool)
+	for _, issue := range issues {
+		if severityPriority(issue.Severity) > severityPriority(inc.Severity) {
+			inc.Severity = issue.Severity
+		}
+		if !seen[issue.Tool] {
+			seen[issue.Tool] = true
+			inc.Tools = append(inc.Tools, issue.Tool)
+		}
+	}
+	sort.Strings(inc.Tools)
+
+	return inc
+}
+
+// BucketPolicyRule links an unused S3 bucket to unattached IAM policies
+// that still grant access to it: the bucket and the policy can be removed
+// together.
+func BucketPolicyRule() LinkRule {
+	return LinkRule{
+		RuleName: "unused-bucket-policy",
+		Title: func(key string) string {
+			return fmt.Sprintf("Unused bucket %s is still referenced by an unattached IAM policy", key)
+		},
+		Anchor: func(i models.NormalizedIssue) bool {
+			return i.Tool == string(models.ToolS3) && hasID(i, "UNUSED_BUCKET", models.StatusUnused)
+		},
+		AnchorKey: func(i models.NormalizedIssue) string {
+			name := strings.TrimPrefix(i.Resource, "s3://")
+			if idx := strings.Index(name, "/"); idx >= 0 {
+				// Prefix-level findings are not about the bucket itself.
+				if idx != len(name)-1 {
+					return ""
+				}
+				name = name[:idx]
+			}
+			return name
+		},
+		Related: func(i models.NormalizedIssue) bool {
+			return i.Tool == string(models.ToolIAM) && i.ID == "UNATTACHED_POLICY"
+		},
+		Links: func(key string, i models.NormalizedIssue) bool {
+			return mentions(i.Resource, key) || mentions(i.Evidence, key)
+		},
+	}
+}
+
+// SecretMountRule links a secret missing from Vault to Kubernetes secret
+// mounts of the same name that nothing uses: the reference is dead on both
+// ends.
+func SecretMountRule() LinkRule {
+	return LinkRule{
+		RuleName: "missing-secret-mount",
+		Title: func(key string) string {
+			return fmt.Sprintf("Secret %s is missing in Vault and mounted but unused in Kubernetes", key)
+		},
+		Anchor: func(i models.NormalizedIssue) bool {
+			return i.Tool == string(models.ToolVault) && hasID(i, "MISSING_SECRET", models.StatusMissing)
+		},
+		AnchorKey: func(i models.NormalizedIssue) string {
+			return strings.ToLower(lastSegment(i.Resource))
+		},
+		Related: func(i models.NormalizedIssue) bool {
+			return i.Tool == string(models.ToolKube) && i.ID == "UNUSED_SECRET_MOUNT"
+		},
+		Links: func(key string, i models.NormalizedIssue) bool {
+			return strings.ToLower(lastSegment(i.Resource)) == key
+		},
+	}
+}
+
+// IdleDatabaseRule links an idle RDS instance to Postgres findings about
+// unused tables in a database of the same name, which together suggest the
+// whole database can be retired.
+func IdleDatabaseRule() LinkRule {
+	return LinkRule{
+		RuleName: "idle-database",
+		Title: func(key string) string {
+			return fmt.Sprintf("Idle RDS instance %s hosts a database with unused tables", key)
+		},
+		Anchor: func(i models.NormalizedIssue) bool {
+			return i.Tool == string(models.ToolRDS) && i.ID == "IDLE_INSTANCE"
+		},
+		AnchorKey: func(i models.NormalizedIssue) string {
+			return lastSegment(i.Resource)
+		},
+		Related: func(i models.NormalizedIssue) bool {
+			return i.Tool == string(models.ToolPg) && hasID(i, "UNUSED_TABLE", models.StatusUnused)
+		},
+		Links: func(key string, i models.NormalizedIssue) bool {
+			// pgspectre reports db.schema.table when scanning several
+			// databases; otherwise the instance may be named in the evidence.
+			if parts := strings.Split(i.Resource, "."); len(parts) >= 3 && strings.EqualFold(parts[0], key) {
+				return true
+			}
+			return mentions(i.Evidence, key)
+		},
+	}
+}
+
+// hasID matches a finding by its spectre/v1 ID, falling back to the
+// normalized category for legacy reports that carry no ID.
+func hasID(issue models.NormalizedIssue, id, legacyCategory string) bool {
+	if issue.ID != "" {
+		return issue.ID == id
+	}
+	return issue.Category == legacyCategory
+}
+
+// lastSegment returns the part of a resource path after the final '/' or ':'.
+func lastSegment(resource string) string {
+	resource = strings.TrimRight(resource, "/")
+	if idx := strings.LastIndexAny(resource, "/:"); idx >= 0 {
+		return resource[idx+1:]
+	}
+	return resource
+}
+
+// mentions reports whether text contains name as a whole token, where
+// tokens are separated by anything other than letters, digits, '.', '-'
+// and '_'. This matches "arn:aws:s3:::logs/*" for "logs" but not "logs-old".
+func mentions(text, name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, tok := range strings.FieldsFunc(text, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_')
+	}) {
+		if strings.EqualFold(tok, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package aggregator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+func TestBucketPolicyRule(t *testing.T) {
+	issues := []models.NormalizedIssue{
+		{Tool: "s3spectre", ID: "UNUSED_BUCKET", Category: models.StatusUnused, Severity: models.SeverityMedium, Resource: "temp-migration-bucket"},
+		{Tool: "s3spectre", ID: "UNUSED_BUCKET", Category: models.StatusUnused, Severity: models.SeverityMedium, Resource: "lonely-bucket"},
+		{Tool: "iamspectre", ID: "UNATTACHED_POLICY", Category: models.StatusUnused, Severity: models.SeverityHigh,
+			Resource: "arn:aws:iam::123:policy/migration-rw", Evidence: "grants s3:* on arn:aws:s3:::temp-migration-bucket/*"},
+		{Tool: "iamspectre", ID: "UNATTACHED_POLICY", Category: models.StatusUnused, Severity: models.SeverityLow,
+			Resource: "arn:aws:iam::123:policy/other", Evidence: "grants s3:GetObject on arn:aws:s3:::temp-migration-bucket-old"},
+	}
+
+	incidents := BucketPolicyRule().Correlate(issues)
+	if len(incidents) != 1 {
+		t.Fatalf("expected 1 incident, got %d: %+v", len(incidents), incidents)
+	}
+	inc := incidents[0]
+	if inc.ID != "unused-bucket-policy:temp-migration-bucket" {
+		t.Errorf("ID = %q", inc.ID)
+	}
+	if inc.Severity != models.SeverityHigh {
+		t.Errorf("Severity = %q, want high", inc.Severity)
+	}
+	if len(inc.Issues) != 2 || inc.Issues[1].Resource != "arn:aws:iam::123:policy/migration-rw" {
+		t.Errorf("unexpected issues: %+v", inc.Issues)
+	}
+	if !reflect.DeepEqual(inc.Tools, []string{"iamspectre", "s3spectre"}) {
+		t.Errorf("Tools = %v", inc.Tools)
+	}
+}
+
+func TestBucketPolicyRuleLegacyS3(t *testing.T) {
+	issues := []models.NormalizedIssue{
+		{Tool: "s3spectre", Category: models.StatusUnused, Severity: models.SeverityMedium, Resource: "s3://data"},
+		{Tool: "s3spectre", Category: models.StatusUnused, Severity: models.SeverityLow, Resource: "s3://data/tmp/"},
+		{Tool: "iamspectre", ID: "UNATTACHED_POLICY", Severity: models.SeverityLow, Resource: "arn:aws:iam::1:policy/data", Evidence: "bucket data"},
+	}
+	incidents := BucketPolicyRule().Correlate(issues)
+	if len(incidents) != 1 || len(incidents[0].Issues) != 2 {
+		t.Fatalf("expected one incident linking bucket and policy, got %+v", incidents)
+	}
+}
+
+func TestSecretMountRule(t *testing.T) {
+	issues := []models.NormalizedIssue{
+		{Tool: "vaultspectre", ID: "MISSING_SECRET", Category: models.StatusMissing, Severity: models.SeverityHigh, Resource: "secret/data/api-key"},
+		{Tool: "vaultspectre", ID: "STALE_SECRET", Category: models.StatusStale, Severity: models.SeverityLow, Resource: "secret/data/db-pass"},
+		{Tool: "kubespectre", ID: "UNUSED_SECRET_MOUNT", Category: models.StatusUnused, Severity: models.SeverityMedium, Resource: "default/pod:web/secret:API-KEY"},
+		{Tool: "kubespectre", ID: "UNUSED_SECRET_MOUNT", Category: models.StatusUnused, Severity: models.SeverityMedium, Resource: "default/pod:web/secret:db-pass"},
+	}
+
+	incidents := SecretMountRule().Correlate(issues)
+	if len(incidents) != 1 {
+		t.Fatalf("expected 1 incident, got %+v", incidents)
+	}
+	if incidents[0].Entity != "api-key" || len(incidents[0].Issues) != 2 {
+		t.Errorf("unexpected incident: %+v", incidents[0])
+	}
+}
+
+func TestIdleDatabaseRule(t *testing.T) {
+	issues := []models.NormalizedIssue{
+		{Tool: "rdsspectre", ID: "IDLE_INSTANCE", Severity: models.SeverityMedium, Resource: "us-east-1/orders"},
+		{Tool: "pgspectre", ID: "UNUSED_TABLE", Severity: models.SeverityHigh, Resource: "orders.public.old_data"},
+		{Tool: "pgspectre", ID: "UNUSED_TABLE", Severity: models.SeverityHigh, Resource: "billing.public.old_data"},
+		{Tool: "pgspectre", ID: "UNUSED_TABLE", Severity: models.SeverityLow, Resource: "public.audit", Evidence: "on instance orders"},
+		{Tool: "pgspectre", ID: "UNUSED_INDEX", Severity: models.SeverityLow, Resource: "orders.public.users.index:idx"},
+	}
+
+	incidents := IdleDatabaseRule().Correlate(issues)
+	if len(incidents) != 1 {
+		t.Fatalf("expected 1 incident, got %+v", incidents)
+	}
+	if got := len(incidents[0].Issues); got != 3 {
+		t.Errorf("expected instance plus 2 tables, got %d: %+v", got, incidents[0].Issues)
+	}
+}
+
+func TestLinkRuleNoRelatedFindings(t *testing.T) {
+	issues := []models.NormalizedIssue{
+		{Tool: "rdsspectre", ID: "IDLE_INSTANCE", Severity: models.SeverityMedium, Resource: "us-east-1/orders"},
+	}
+	if incidents := IdleDatabaseRule().Correlate(issues); len(incidents) != 0 {
+		t.Errorf("expected no incidents, got %+v", incidents)
+	}
+}
+
+type stubRule struct {
+	name      string
+	incidents []models.Incident
+}
+
+func (s stubRule) Name() string { return s.name }
+
+func (s stubRule) Correlate([]models.NormalizedIssue) []models.Incident { return s.incidents }
+
+func TestCorrelatorSortsBySeverity(t *testing.T) {
+	c := NewCorrelator(
+		stubRule{name: "a", incidents: []models.Incident{{ID: "a:2", Severity: models.SeverityLow}, {ID: "a:1", Severity: models.SeverityHigh}}},
+		stubRule{name: "b", incidents: []models.Incident{{ID: "b:1", Severity: models.SeverityCritical}, {ID: "a:0", Severity: models.SeverityHigh}}},
+	)
+
+	incidents := c.Correlate(&models.AggregatedReport{})
+	var ids []string
+	for _, inc := range incidents {
+		ids = append(ids, inc.ID)
+	}
+	want := []string{"b:1", "a:0", "a:1", "a:2"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("order = %v, want %v", ids, want)
+	}
+}
+
+func TestNewCorrelatorDefaults(t *testing.T) {
+	c := NewCorrelator()
+	if len(c.rules) != len(DefaultCorrelationRules()) {
+		t.Errorf("expected default rules, got %d", len(c.rules))
+	}
+}
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		text, name string
+		want       bool
+	}{
+		{"arn:aws:s3:::logs/*", "logs", true},
+		{"arn:aws:s3:::logs-old/*", "logs", false},
+		{"bucket LOGS", "logs", true},
+		{"anything", "", false},
+	}
+	for _, tt := range tests {
+		if got := mentions(tt.text, tt.name); got != tt.want {
+			t.Errorf("mentions(%q, %q) = %v, want %v", tt.text, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLastSegment(t *testing.T) {
+	tests := map[string]string{
+		"secret/data/api-key":       "api-key",
+		"us-east-1/mydb":            "mydb",
+		"default/pod:web/secret:db": "db",
+		"plain":                     "plain",
+		"s3://bucket/":              "bucket",
+	}
+	for in, want := range tests {
+		if got := lastSegment(in); got != want {
+			t.Errorf("lastSegment(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package aggregator
+
+import (
+	"sort"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+// SetCoverage records which of the expected tools delivered a report and
+// marks the summary partial when some did not. Expected tools come from
+// discovery or the expected_tools setting; without any the coverage is
+// unknown and left unset.
+func (a *Aggregator) SetCoverage(report *models.AggregatedReport, expected []string) {
+	if len(expected) == 0 {
+		return
+	}
+
+	cov := &models.Coverage{}
+	seen := make(map[string]bool, len(expected))
+	for _, tool := range expected {
+		if tool == "" || seen[tool] {
+			continue
+		}
+		seen[tool] = true
+		cov.Expected = append(cov.Expected, tool)
+		if _, ok := report.ToolReports[tool]; ok {
+			cov.Delivered = append(cov.Delivered, tool)
+		} else {
+			cov.Missing = append(cov.Missing, tool)
+		}
+	}
+	sort.Strings(cov.Expected)
+	sort.Strings(cov.Delivered)
+	sort.Strings(cov.Missing)
+
+	report.Coverage = cov
+	setCoverageSummary(report)
+}
+
+// setCoverageSummary copies the coverage into the summary.
+func setCoverageSummary(report *models.AggregatedReport) {
+	if report.Coverage == nil {
+		return
+	}
+	report.Summary.CoveragePercent = report.Coverage.Percent()
+	report.Summary.Partial = len(report.Coverage.Missing) > 0
+}
+
+// UnreportedTools returns the tools that reported in baseline but not in
+// current. Their baseline issues were not re-checked, so comparisons must
+// not treat them as resolved. A current run without tool reports (e.g. a
+// hand-built fixture) only counts the tools its coverage marks missing.
+func UnreportedTools(baseline, current *models.AggregatedReport) []string {
+	missing := make(map[string]bool)
+	if current.Coverage != nil {
+		for _, tool := range current.Coverage.Missing {
+			missing[tool] = true
+		}
+	}
+	if len(current.ToolReports) > 0 {
+		for tool := range baseline.ToolReports {
+			if _, ok := current.ToolReports[tool]; !ok {
+				missing[tool] = true
+			}
+		}
+		for _, issue := range baseline.Issues {
+			if _, ok := current.ToolReports[issue.Tool]; !ok {
+				missing[issue.Tool] = true
+			}
+		}
+	}
+
+	tools := make([]string, 0, len(missing))
+	for tool := range missing {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+// comparableIssueCount is the number of baseline issues from tools that
+// also reported in the current run.
+func comparableIssueCount(baseline *models.AggregatedReport, missing []string) int {
+	if len(missing) == 0 {
+		return baseline.Summary.TotalIssues
+	}
+	skip := make(map[string]bool, len(missing))
+	for _, tool := range missing {
+		skip[tool] = true
+	}
+	n := baseline.Summary.TotalIssues
+	for _, issue := range baseline.Issues {
+		if skip[issue.Tool] {
+			n--
+		}
+	}
+	return n
+}
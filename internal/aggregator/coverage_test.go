@@ -0,0 +1,177 @@
+package aggregator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+// coverageRun builds a run with one issue per resource, keyed by tool.
+func coverageRun(ts time.Time, issues map[string][]string) *models.AggregatedReport {
+	report := &models.AggregatedReport{Timestamp: ts, ToolReports: map[string]models.ToolReport{}}
+	for tool, resources := range issues {
+		report.ToolReports[tool] = models.ToolReport{Tool: tool, IsSupported: true}
+		for _, res := range resources {
+			report.Issues = append(report.Issues, models.NormalizedIssue{
+				Tool: tool, Category: "unused", Severity: "high", Resource: res,
+			})
+		}
+	}
+	report.Summary.TotalIssues = len(report.Issues)
+	return report
+}
+
+func TestSetCoverage(t *testing.T) {
+	a := New()
+	report := coverageRun(time.Now(), map[string][]string{"vaultspectre": {"a"}, "s3spectre": nil})
+
+	a.SetCoverage(report, nil)
+	if report.Coverage != nil || report.Summary.Partial {
+		t.Fatal("coverage without expected tools should stay unknown")
+	}
+
+	a.SetCoverage(report, []string{"vaultspectre", "kafkaspectre", "s3spectre", "kafkaspectre"})
+	want := &models.Coverage{
+		Expected:  []string{"kafkaspectre", "s3spectre", "vaultspectre"},
+		Delivered: []string{"s3spectre", "vaultspectre"},
+		Missing:   []string{"kafkaspectre"},
+	}
+	if !reflect.DeepEqual(report.Coverage, want) {
+		t.Fatalf("coverage = %+v, want %+v", report.Coverage, want)
+	}
+	if !report.Summary.Partial || report.Summary.CoveragePercent < 66 || report.Summary.CoveragePercent > 67 {
+		t.Errorf("unexpected summary: partial=%v coverage=%.1f", report.Summary.Partial, report.Summary.CoveragePercent)
+	}
+
+	// Triage recomputes the summary; the partial label must survive.
+	a.Recalculate(report)
+	if !report.Summary.Partial {
+		t.Error("Recalculate dropped the partial label")
+	}
+}
+
+func TestMissingToolIssuesAreNotResolved(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	previous := coverageRun(t0, map[string][]string{
+		"vaultspectre": {"secret/a", "secret/b"},
+		"kafkaspectre": {"topic-1", "topic-2", "topic-3"},
+	})
+	// kafkaspectre timed out: its issues vanish from the current run.
+	current := coverageRun(t0.Add(time.Hour), map[string][]string{"vaultspectre": {"secret/a"}})
+	New().SetCoverage(current, []string{"vaultspectre", "kafkaspectre"})
+
+	diff := ComputeDiff(previous, current)
+	if diff.Summary.ResolvedCount != 1 || diff.ResolvedIssues[0].Resource != "secret/b" {
+		t.Errorf("expected only secret/b resolved, got %+v", diff.ResolvedIssues)
+	}
+	if diff.Summary.UnverifiedCount != 3 || !reflect.DeepEqual(diff.MissingTools, []string{"kafkaspectre"}) {
+		t.Errorf("expected 3 unverified kafkaspectre issues, got %d %v", diff.Summary.UnverifiedCount, diff.MissingTools)
+	}
+	if diff.Summary.Delta != -1 {
+		t.Errorf("delta = %d, want -1", diff.Summary.Delta)
+	}
+
+	trend := NewTrendAnalyzer().CalculateTrend(current, previous)
+	if trend.PreviousIssues != 2 || trend.ResolvedIssues != 1 || !reflect.DeepEqual(trend.MissingTools, []string{"kafkaspectre"}) {
+		t.Errorf("unexpected trend: %+v", trend)
+	}
+
+	New().AddTrend(current, previous)
+	if current.Trend.PreviousIssues != 2 || current.Trend.ResolvedIssues != 1 {
+		t.Errorf("unexpected AddTrend: %+v", current.Trend)
+	}
+}
+
+func TestUnreportedToolsWithoutToolReports(t *testing.T) {
+	previous := coverageRun(time.Now(), map[string][]string{"vaultspectre": {"a"}})
+	current := &models.AggregatedReport{}
+	if got := UnreportedTools(previous, current); len(got) != 0 {
+		t.Errorf("expected no unreported tools for a run without tool reports, got %v", got)
+	}
+}
+
+// withToolCounts fills IssuesByTool from the run's issues.
+func withToolCounts(report *models.AggregatedReport) *models.AggregatedReport {
+	report.Summary.IssuesByTool = make(map[string]int)
+	for tool := range report.ToolReports {
+		report.Summary.IssuesByTool[tool] = 0
+	}
+	for _, issue := range report.Issues {
+		report.Summary.IssuesByTool[issue.Tool]++
+	}
+	return report
+}
+
+func TestTrendsSkipToolMissingFromLatestRun(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	first := withToolCounts(coverageRun(t0, map[string][]string{
+		"vaultspectre": {"secret/a", "secret/b"},
+		"kafkaspectre": {"topic-1", "topic-2", "topic-3"},
+	}))
+	middle := withToolCounts(coverageRun(t0.Add(time.Hour), map[string][]string{
+		"vaultspectre": {"secret/a", "secret/b"},
+		"kafkaspectre": {"topic-1", "topic-2", "topic-3"},
+	}))
+	// kafkaspectre timed out in the latest run.
+	latest := withToolCounts(coverageRun(t0.Add(2*time.Hour), map[string][]string{"vaultspectre": {"secret/a"}}))
+	New().SetCoverage(latest, []string{"vaultspectre", "kafkaspectre"})
+
+	summary := NewTrendAnalyzer().AnalyzeLastNRuns([]*models.AggregatedReport{first, middle, latest})
+
+	kafka := summary.ByTool["kafkaspectre"]
+	if kafka == nil || !kafka.Missing || kafka.Change != 0 || kafka.ChangePercent != 0 {
+		t.Errorf("missing tool should not be compared, got %+v", kafka)
+	}
+	vault := summary.ByTool["vaultspectre"]
+	if vault == nil || vault.Missing || vault.Change != -1 {
+		t.Errorf("reporting tool should be compared, got %+v", vault)
+	}
+	if !reflect.DeepEqual(summary.PartialRuns, []bool{false, false, true}) {
+		t.Errorf("PartialRuns = %v, want the latest run marked", summary.PartialRuns)
+	}
+
+	report := NewTrendAnalyzer().GenerateComparisonReport(latest, middle)
+	if strings.Contains(report, "kafkaspectre:") {
+		t.Errorf("comparison should not show a change for the missing tool:\n%s", report)
+	}
+	if !strings.Contains(report, "Not compared (tool missing): kafkaspectre") {
+		t.Errorf("comparison should list the missing tool:\n%s", report)
+	}
+}
+
+func TestTrendsSkipToolMissingFromFirstRun(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	// kafkaspectre failed in the first run and reports again later.
+	first := withToolCounts(coverageRun(t0, map[string][]string{"vaultspectre": {"secret/a"}}))
+	New().SetCoverage(first, []string{"vaultspectre", "kafkaspectre"})
+	latest := withToolCounts(coverageRun(t0.Add(time.Hour), map[string][]string{
+		"vaultspectre": {"secret/a"},
+		"kafkaspectre": {"topic-1", "topic-2"},
+	}))
+
+	summary := NewTrendAnalyzer().AnalyzeLastNRuns([]*models.AggregatedReport{first, latest})
+	if kafka := summary.ByTool["kafkaspectre"]; kafka == nil || !kafka.Missing || kafka.ChangePercent != 0 {
+		t.Errorf("tool missing from the first run should not count as new issues, got %+v", kafka)
+	}
+	if !reflect.DeepEqual(summary.PartialRuns, []bool{true, false}) {
+		t.Errorf("PartialRuns = %v, want the first run marked", summary.PartialRuns)
+	}
+}
+
+func TestTrendsCompleteRunsHaveNoPartialMarks(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	runs := []*models.AggregatedReport{
+		withToolCounts(coverageRun(t0, map[string][]string{"vaultspectre": {"secret/a"}})),
+		withToolCounts(coverageRun(t0.Add(time.Hour), map[string][]string{"vaultspectre": nil})),
+	}
+	summary := NewTrendAnalyzer().AnalyzeLastNRuns(runs)
+	if summary.PartialRuns != nil {
+		t.Errorf("PartialRuns = %v, want nil", summary.PartialRuns)
+	}
+	if vault := summary.ByTool["vaultspectre"]; vault.Missing || vault.Change != -1 {
+		t.Errorf("unexpected trend %+v", vault)
+	}
+}
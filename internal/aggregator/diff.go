@@ -0,0 +1,105 @@
+package aggregator
+
+import "github.com/ppiankov/spectrehub/internal/models"
+
+// DiffResult is the structured output of a diff operation.
+type DiffResult struct {
+	Baseline       string                   `json:"baseline"`
+	Current        string                   `json:"current"`
+	NewIssues      []models.NormalizedIssue `json:"new_issues"`
+	ResolvedIssues []models.NormalizedIssue `json:"resolved_issues"`
+	// UnverifiedIssues are baseline issues of tools that did not report in
+	// the current run; they are neither resolved nor still open.
+	UnverifiedIssues []models.NormalizedIssue `json:"unverified_issues,omitempty"`
+	MissingTools     []string                 `json:"missing_tools,omitempty"`
+	Summary          DiffSummary              `json:"summary"`
+}
+
+// DiffSummary holds aggregate counts for a diff.
+type DiffSummary struct {
+	BaselineTotal int `json:"baseline_total"`
+	CurrentTotal  int `json:"current_total"`
+	NewCount      int `json:"new_count"`
+	ResolvedCount int `json:"resolved_count"`
+	// UnverifiedCount baseline issues were not re-checked (tool missing).
+	UnverifiedCount int            `json:"unverified_count,omitempty"`
+	Delta           int            `json:"delta"` // positive = more issues; excludes unverified issues
+	NewBySeverity   map[string]int `json:"new_by_severity"`
+	NewByTool       map[string]int `json:"new_by_tool"`
+	NewByCategory   map[string]int `json:"new_by_category"`
+}
+
+// DiffKey returns a string that uniquely identifies an issue across runs
+// for diff purposes.
+func DiffKey(issue models.NormalizedIssue) string {
+	return issue.Tool + "|" + issue.Category + "|" + issue.Resource
+}
+
+// ComputeDiff calculates new and resolved issues between baseline and current.
+// Baseline issues of tools missing from current are unverified, not resolved.
+func ComputeDiff(baseline, current *models.AggregatedReport) *DiffResult {
+	missingTools := UnreportedTools(baseline, current)
+	missing := make(map[string]bool, len(missingTools))
+	for _, tool := range missingTools {
+		missing[tool] = true
+	}
+
+	var unverified []models.NormalizedIssue
+	baseSet := make(map[string]models.NormalizedIssue, len(baseline.Issues))
+	for _, issue := range baseline.Issues {
+		if missing[issue.Tool] {
+			unverified = append(unverified, issue)
+			continue
+		}
+		baseSet[DiffKey(issue)] = issue
+	}
+
+	currSet := make(map[string]models.NormalizedIssue, len(current.Issues))
+	for _, issue := range current.Issues {
+		currSet[DiffKey(issue)] = issue
+	}
+
+	var newIssues, resolvedIssues []models.NormalizedIssue
+
+	for key, issue := range currSet {
+		if _, found := baseSet[key]; !found {
+			newIssues = append(newIssues, issue)
+		}
+	}
+
+	for key, issue := range baseSet {
+		if _, found := currSet[key]; !found {
+			resolvedIssues = append(resolvedIssues, issue)
+		}
+	}
+
+	// Build summary maps.
+	newBySeverity := map[string]int{}
+	newByTool := map[string]int{}
+	newByCategory := map[string]int{}
+	for _, issue := range newIssues {
+		newBySeverity[issue.Severity]++
+		newByTool[issue.Tool]++
+		newByCategory[issue.Category]++
+	}
+
+	return &DiffResult{
+		Baseline:         baseline.Timestamp.Format("2006-01-02 15:04:05"),
+		Current:          current.Timestamp.Format("2006-01-02 15:04:05"),
+		NewIssues:        newIssues,
+		ResolvedIssues:   resolvedIssues,
+		UnverifiedIssues: unverified,
+		MissingTools:     missingTools,
+		Summary: DiffSummary{
+			BaselineTotal:   len(baseline.Issues),
+			CurrentTotal:    len(current.Issues),
+			NewCount:        len(newIssues),
+			ResolvedCount:   len(resolvedIssues),
+			UnverifiedCount: len(unverified),
+			Delta:           len(current.Issues) - (len(baseline.Issues) - len(unverified)),
+			NewBySeverity:   newBySeverity,
+			NewByTool:       newByTool,
+			NewByCategory:   newByCategory,
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package aggregator
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+// freshness lists each tool's report timestamp and its age at now.
+func freshness(toolReports []models.ToolReport, now time.Time) []models.ToolFreshness {
+	out := make([]models.ToolFreshness, 0, len(toolReports))
+	for _, tr := range toolReports {
+		f := models.ToolFreshness{Tool: tr.Tool, Timestamp: tr.Timestamp}
+		if !tr.Timestamp.IsZero() {
+			if age := now.Sub(tr.Timestamp); age > 0 {
+				f.AgeSeconds = age.Seconds()
+			}
+		}
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Tool < out[j].Tool })
+	return out
+}
+
+// MarkStale flags the tool reports older than maxAge and returns them.
+// Reports without a timestamp are not flagged: their age is unknown. A
+// zero maxAge disables the check.
+func MarkStale(report *models.AggregatedReport, maxAge time.Duration) []models.ToolFreshness {
+	if maxAge <= 0 {
+		return nil
+	}
+	var stale []models.ToolFreshness
+	for i := range report.Freshness {
+		f := &report.Freshness[i]
+		if !f.Timestamp.IsZero() && f.Age() > maxAge {
+			f.Stale = true
+			stale = append(stale, *f)
+		}
+	}
+	return stale
+}
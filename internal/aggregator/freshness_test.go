@@ -0,0 +1,32 @@
+package aggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+func TestFreshnessAndMarkStale(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	report := &models.AggregatedReport{Freshness: freshness([]models.ToolReport{
+		{Tool: "s3spectre", Timestamp: now.Add(-90 * 24 * time.Hour)},
+		{Tool: "kubespectre", Timestamp: now.Add(-time.Hour)},
+		{Tool: "vaultspectre"},
+	}, now)}
+
+	if got := report.Freshness[0]; got.Tool != "kubespectre" || got.Age() != time.Hour {
+		t.Fatalf("expected sorted entries with ages, got %+v", report.Freshness)
+	}
+
+	if stale := MarkStale(report, 0); stale != nil {
+		t.Errorf("zero max age should disable the check, got %v", stale)
+	}
+	stale := MarkStale(report, 24*time.Hour)
+	if len(stale) != 1 || stale[0].Tool != "s3spectre" || !report.Freshness[1].Stale {
+		t.Errorf("expected only s3spectre stale, got %+v", stale)
+	}
+	if report.Freshness[2].Stale {
+		t.Error("a report without timestamp has unknown age and is not flagged")
+	}
+}
@@ -47,6 +47,9 @@ func (n *Normalizer) Normalize(report *models.ToolReport) ([]models.NormalizedIs
 
 // NormalizeSpectreV1 converts a spectre/v1 envelope into normalized issues.
 // The findings already contain id, severity, location, and message — the mapping is direct.
+// spectre/v1.1 metadata is copied as is; first_detected, last_detected,
+// occurrences and a known tool_severity replace FirstSeen, LastSeen, Count
+// and Severity.
 func (n *Normalizer) NormalizeSpectreV1(report *models.ToolReport, v1 *models.SpectreV1Report) ([]models.NormalizedIssue, error) {
 	var issues []models.NormalizedIssue
 
@@ -55,6 +58,7 @@ func (n *Normalizer) NormalizeSpectreV1(report *models.ToolReport, v1 *models.Sp
 
 		issue := models.NormalizedIssue{
 			Tool:      report.Tool,
+			ID:        f.ID,
 			Category:  category,
 			Severity:  mapSpectreSeverity(f.Severity),
 			Resource:  f.Location,
@@ -63,6 +67,30 @@ func (n *Normalizer) NormalizeSpectreV1(report *models.ToolReport, v1 *models.Sp
 			FirstSeen: report.Timestamp,
 			LastSeen:  report.Timestamp,
 		}
+		if f.EstimatedMonthlyWaste != nil {
+			issue.EstimatedMonthlyWaste = *f.EstimatedMonthlyWaste
+		}
+		// spectre/v1.1 metadata
+		issue.Attributes = f.Attributes
+		issue.Remediation = f.Remediation
+		issue.Fingerprint = f.Fingerprint
+		issue.References = f.References
+		issue.Confidence = f.Confidence
+		if f.FirstDetected != nil {
+			issue.FirstSeen = *f.FirstDetected
+		}
+		if f.LastDetected != nil {
+			issue.LastSeen = *f.LastDetected
+		}
+		if f.Occurrences != nil {
+			issue.Count = *f.Occurrences
+		}
+		// Only a known severity may override, so filters, thresholds and
+		// metrics never see a tool's private scale.
+		switch f.ToolSeverity {
+		case models.SeverityCritical, models.SeverityHigh, models.SeverityMedium, models.SeverityLow:
+			issue.Severity = f.ToolSeverity
+		}
 		issues = append(issues, issue)
 	}
 
@@ -73,7 +101,7 @@ func (n *Normalizer) NormalizeSpectreV1(report *models.ToolReport, v1 *models.Sp
 func mapSpectreV1IDToCategory(id string) string {
 	switch id {
 	// --- missing ---
-	case "MISSING_BUCKET", "MISSING_TABLE", "MISSING_COLUMN", "MISSING_COLLECTION", "MISSING_SECRET",
+	case "MISSING_BUCKET", "MISSING_PREFIX", "MISSING_TABLE", "MISSING_COLUMN", "MISSING_COLLECTION", "MISSING_SECRET",
 		// kubespectre
 		"MISSING_NETWORK_POLICY", "MISSING_AUDIT_POLICY":
 		return models.StatusMissing
@@ -132,19 +160,31 @@ func mapSpectreV1IDToCategory(id string) string {
 		"OVERSIZED_INSTANCE", "UNENCRYPTED_STORAGE", "PUBLIC_ACCESS",
 		"NO_AUTOMATED_BACKUPS", "NO_MULTI_AZ", "NO_DELETION_PROTECTION",
 		// iamspectre
-		"NO_MFA", "WILDCARD_POLICY", "OVERPRIVILEGED_SA", "CROSS_ACCOUNT_TRUST":
+		"NO_MFA", "WILDCARD_POLICY", "OVERPRIVILEGED_SA", "CROSS_ACCOUNT_TRUST",
+		// converted clickspectre reports
+		"CONFIG_ANOMALY":
 		return models.StatusMisconfig
 
 	// --- access_denied ---
 	case "RISKY", "ACCESS_DENIED":
 		return models.StatusAccessDeny
 
+	// --- invalid ---
+	case "INVALID_SECRET":
+		return models.StatusInvalid
+
 	// --- drift ---
 	case "DYNAMIC_COLLECTION",
 		// rdsspectre
-		"PARAMETER_GROUP_DRIFT":
+		"PARAMETER_GROUP_DRIFT",
+		// converted clickspectre reports
+		"USAGE_ANOMALY":
 		return models.StatusDrift
 
+	// --- error ---
+	case "SCAN_ERROR":
+		return models.StatusError
+
 	default:
 		return models.StatusError
 	}
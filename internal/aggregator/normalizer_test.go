@@ -484,6 +484,7 @@ func TestNormalizeMongoEmptyDatabase(t *testing.T) {
 func TestNormalizeSpectreV1(t *testing.T) {
 	normalizer := NewNormalizer()
 	ts := time.Date(2026, 2, 15, 12, 0, 0, 0, time.UTC)
+	bucketWaste := 12.5
 
 	v1Report := &models.SpectreV1Report{
 		Schema:    "spectre/v1",
@@ -492,7 +493,7 @@ func TestNormalizeSpectreV1(t *testing.T) {
 		Timestamp: ts,
 		Target:    models.SpectreV1Target{Type: "s3"},
 		Findings: []models.SpectreV1Finding{
-			{ID: "UNUSED_BUCKET", Severity: "medium", Location: "s3://old-bucket", Message: "no recent access"},
+			{ID: "UNUSED_BUCKET", Severity: "medium", Location: "s3://old-bucket", Message: "no recent access", EstimatedMonthlyWaste: &bucketWaste},
 			{ID: "MISSING_BUCKET", Severity: "high", Location: "s3://missing-bucket", Message: "bucket not found"},
 			{ID: "STALE_PREFIX", Severity: "low", Location: "s3://data/old/", Message: "stale prefix"},
 			{ID: "VERSION_SPRAWL", Severity: "medium", Location: "s3://versioned-bucket", Message: "too many versions"},
@@ -528,6 +529,13 @@ func TestNormalizeSpectreV1(t *testing.T) {
 		issueMap[issue.Resource] = issue
 	}
 
+	if got := issueMap["s3://old-bucket"].EstimatedMonthlyWaste; got != 12.5 {
+		t.Errorf("EstimatedMonthlyWaste = %v, want 12.5", got)
+	}
+	if got := issueMap["s3://missing-bucket"].EstimatedMonthlyWaste; got != 0 {
+		t.Errorf("EstimatedMonthlyWaste without waste = %v, want 0", got)
+	}
+
 	expectedCategories := map[string]string{
 		"s3://old-bucket":       models.StatusUnused,
 		"s3://missing-bucket":   models.StatusMissing,
@@ -567,6 +575,56 @@ func TestNormalizeSpectreV1(t *testing.T) {
 	}
 }
 
+func TestNormalizeSpectreV11Metadata(t *testing.T) {
+	ts := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	firstDetected := time.Date(2026, 2, 10, 8, 0, 0, 0, time.UTC)
+	confidence := 0.9
+	occurrences := 4
+	attrs := &models.ResourceAttributes{Account: "123456789012", Region: "us-east-1", Tags: map[string]string{"env": "staging"}}
+
+	v11 := &models.SpectreV1Report{
+		Schema:    models.SpectreV11Schema,
+		Tool:      "awsspectre",
+		Timestamp: ts,
+		Findings: []models.SpectreV1Finding{
+			{ID: "IDLE_EC2", Severity: "medium", Location: "i-1", Message: "idle",
+				Attributes: attrs, Remediation: "stop it", Fingerprint: "fp-1",
+				References: []string{"https://example.com/idle"}, FirstDetected: &firstDetected, Confidence: &confidence},
+			{ID: "IDLE_EC2", Severity: "medium", Location: "i-2", Message: "idle"},
+			{ID: "IDLE_EC2", Severity: "high", Location: "i-3", Message: "idle",
+				LastDetected: &firstDetected, Occurrences: &occurrences, ToolSeverity: "critical"},
+			{ID: "IDLE_EC2", Severity: "high", Location: "i-4", Message: "idle", ToolSeverity: "blocker"},
+		},
+	}
+	issues, err := NewNormalizer().Normalize(&models.ToolReport{Tool: "awsspectre", Timestamp: ts, IsSupported: true, RawData: v11})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := issues[0]
+	if got.Attributes != attrs || got.Remediation != "stop it" || got.Fingerprint != "fp-1" ||
+		len(got.References) != 1 || got.Confidence == nil || *got.Confidence != 0.9 {
+		t.Errorf("metadata not carried over: %+v", got)
+	}
+	if !got.FirstSeen.Equal(firstDetected) || !got.LastSeen.Equal(ts) {
+		t.Errorf("FirstSeen/LastSeen = %s/%s, want first_detected and report time", got.FirstSeen, got.LastSeen)
+	}
+
+	plain := issues[1]
+	if plain.Attributes != nil || plain.Fingerprint != "" || plain.Confidence != nil || !plain.FirstSeen.Equal(ts) {
+		t.Errorf("finding without metadata should normalize like spectre/v1: %+v", plain)
+	}
+
+	carried := issues[2]
+	if carried.Severity != "critical" || carried.Count != 4 || !carried.LastSeen.Equal(firstDetected) || !carried.FirstSeen.Equal(ts) {
+		t.Errorf("tool_severity/occurrences/last_detected not applied: %+v", carried)
+	}
+
+	if unknown := issues[3]; unknown.Severity != models.SeverityHigh {
+		t.Errorf("unknown tool_severity %q should keep severity high, got %s", "blocker", unknown.Severity)
+	}
+}
+
 func TestMapSpectreV1IDToCategory_NewTools(t *testing.T) {
 	tests := []struct {
 		id       string
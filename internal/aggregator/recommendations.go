@@ -2,44 +2,71 @@ package aggregator
 
 import (
 	"fmt"
+	"math"
 	"sort"
 
 	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/remediation"
 )
 
-// issueGroup represents a group of issues by tool, category, and severity
+// issueGroup represents a group of issues by tool, category (or finding ID), and severity
 type issueGroup struct {
 	tool     string
+	id       string
 	category string
 	severity string
 	count    int
+	waste    float64
+	issues   []models.NormalizedIssue
 }
 
+// maxTopResources caps how many affected resources a recommendation lists.
+const maxTopResources = 5
+
 // RecommendationGenerator creates actionable recommendations from aggregated issues
-type RecommendationGenerator struct{}
+type RecommendationGenerator struct {
+	kb *remediation.KnowledgeBase
+}
 
-// NewRecommendationGenerator creates a new recommendation generator
+// NewRecommendationGenerator creates a new recommendation generator that
+// uses the built-in remediation knowledge base
 func NewRecommendationGenerator() *RecommendationGenerator {
-	return &RecommendationGenerator{}
+	return &RecommendationGenerator{kb: remediation.Default()}
 }
 
-// GenerateRecommendations analyzes the report and creates prioritized recommendations
+// SetKnowledgeBase replaces the remediation knowledge base, e.g. with one
+// that includes user overrides
+func (r *RecommendationGenerator) SetKnowledgeBase(kb *remediation.KnowledgeBase) {
+	r.kb = kb
+}
+
+// GenerateRecommendations analyzes the report and creates prioritized recommendations.
+// Issues carrying a spectre/v1 finding ID are grouped per ID so each
+// recommendation maps to one remediation; legacy issues are grouped per category.
+// Recommendations are ranked by Score, highest first.
 func (r *RecommendationGenerator) GenerateRecommendations(report *models.AggregatedReport) []models.Recommendation {
-	// Group issues by (Tool, Category, Severity)
+	// Group issues by (Tool, ID or Category, Severity)
 	groups := make(map[string]*issueGroup)
 
 	for _, issue := range report.Issues {
-		key := fmt.Sprintf("%s:%s:%s", issue.Tool, issue.Category, issue.Severity)
-		if g, exists := groups[key]; exists {
-			g.count += issue.Count
-		} else {
-			groups[key] = &issueGroup{
+		kind := issue.Category
+		if issue.ID != "" {
+			kind = "id=" + issue.ID
+		}
+		key := fmt.Sprintf("%s:%s:%s", issue.Tool, kind, issue.Severity)
+		g, exists := groups[key]
+		if !exists {
+			g = &issueGroup{
 				tool:     issue.Tool,
+				id:       issue.ID,
 				category: issue.Category,
 				severity: issue.Severity,
-				count:    issue.Count,
 			}
+			groups[key] = g
 		}
+		g.count += issue.Count
+		g.waste += issue.EstimatedMonthlyWaste
+		g.issues = append(g.issues, issue)
 	}
 
 	// Generate recommendations from groups
@@ -47,23 +74,101 @@ func (r *RecommendationGenerator) GenerateRecommendations(report *models.Aggrega
 
 	for _, group := range groups {
 		rec := models.Recommendation{
-			Severity: group.severity,
-			Tool:     group.tool,
-			Action:   r.generateAction(group),
-			Impact:   r.generateImpact(group),
-			Count:    group.count,
+			Severity:     group.severity,
+			Tool:         group.tool,
+			Action:       r.generateAction(group),
+			Impact:       r.generateImpact(group),
+			Count:        group.count,
+			ID:           group.id,
+			Score:        recommendationScore(group),
+			MonthlyWaste: group.waste,
+			TopResources: topResources(group.issues),
+		}
+		if fix, ok := r.kb.Lookup(group.tool, group.id); ok {
+			rec.Remediation = &fix
 		}
 		recommendations = append(recommendations, rec)
 	}
 
-	// Sort by severity priority (critical > high > medium > low)
+	// Sort by score, then severity, then tool and action for stable output
 	sort.Slice(recommendations, func(i, j int) bool {
-		return r.severityPriority(recommendations[i].Severity) > r.severityPriority(recommendations[j].Severity)
+		a, b := recommendations[i], recommendations[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if pa, pb := severityPriority(a.Severity), severityPriority(b.Severity); pa != pb {
+			return pa > pb
+		}
+		if a.Tool != b.Tool {
+			return a.Tool < b.Tool
+		}
+		return a.Action < b.Action
 	})
 
 	return recommendations
 }
 
+// severityWeight is the base score of one finding at each severity.
+func severityWeight(severity string) float64 {
+	switch severity {
+	case models.SeverityCritical:
+		return 10
+	case models.SeverityHigh:
+		return 6
+	case models.SeverityMedium:
+		return 3
+	case models.SeverityLow:
+		return 1
+	default:
+		return 0.5
+	}
+}
+
+// recommendationScore combines severity, item count and monthly waste into
+// one ranking value. Count and waste grow logarithmically so severity still
+// dominates: ten medium findings score the same as one high finding, and
+// $1000/month of waste adds about as much as one high finding.
+func recommendationScore(group *issueGroup) float64 {
+	count := group.count
+	if count < 1 {
+		count = 1
+	}
+	score := severityWeight(group.severity)*(1+math.Log10(float64(count))) +
+		2*math.Log10(1+group.waste)
+	return math.Round(score*100) / 100
+}
+
+// topResources returns the most costly affected resources, falling back to
+// name order when waste is unknown.
+func topResources(issues []models.NormalizedIssue) []string {
+	sorted := make([]models.NormalizedIssue, 0, len(issues))
+	for _, issue := range issues {
+		if issue.Resource != "" {
+			sorted = append(sorted, issue)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].EstimatedMonthlyWaste != sorted[j].EstimatedMonthlyWaste {
+			return sorted[i].EstimatedMonthlyWaste > sorted[j].EstimatedMonthlyWaste
+		}
+		return sorted[i].Resource < sorted[j].Resource
+	})
+
+	var resources []string
+	seen := make(map[string]bool)
+	for _, issue := range sorted {
+		if len(resources) == maxTopResources {
+			break
+		}
+		if seen[issue.Resource] {
+			continue
+		}
+		seen[issue.Resource] = true
+		resources = append(resources, issue.Resource)
+	}
+	return resources
+}
+
 // generateAction creates actionable text based on category and count
 func (r *RecommendationGenerator) generateAction(group *issueGroup) string {
 	resourceName := r.getResourceName(group.tool)
@@ -167,7 +272,7 @@ func (r *RecommendationGenerator) getResourceName(tool string) string {
 }
 
 // severityPriority returns numeric priority for sorting (higher = more urgent)
-func (r *RecommendationGenerator) severityPriority(severity string) int {
+func severityPriority(severity string) int {
 	switch severity {
 	case models.SeverityCritical:
 		return 4
@@ -1,6 +1,8 @@
 package aggregator
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/ppiankov/spectrehub/internal/models"
@@ -33,6 +35,7 @@ func TestRecommendationGeneratorGenerateRecommendations(t *testing.T) {
 				Action:   "Fix 5 missing Vault secrets",
 				Impact:   "Services may fail to start or operate incorrectly",
 				Count:    5,
+				Score:    16.99,
 			},
 			wantSecond: models.Recommendation{
 				Severity: models.SeverityHigh,
@@ -40,6 +43,7 @@ func TestRecommendationGeneratorGenerateRecommendations(t *testing.T) {
 				Action:   "Clean up 1 unused S3 buckets/prefixes",
 				Impact:   "Significant waste of resources and potential security risks",
 				Count:    1,
+				Score:    6,
 			},
 			wantThird: models.Recommendation{
 				Severity: models.SeverityMedium,
@@ -47,6 +51,7 @@ func TestRecommendationGeneratorGenerateRecommendations(t *testing.T) {
 				Action:   "Fix 4 misconfiguration(s) in clickspectre",
 				Impact:   "Suboptimal performance or behavior",
 				Count:    4,
+				Score:    4.81,
 			},
 			expectOrdering: true,
 		},
@@ -69,13 +74,13 @@ func TestRecommendationGeneratorGenerateRecommendations(t *testing.T) {
 			if !tt.expectOrdering {
 				return
 			}
-			if recs[0] != tt.wantFirst {
+			if !reflect.DeepEqual(recs[0], tt.wantFirst) {
 				t.Fatalf("unexpected first recommendation: %+v", recs[0])
 			}
-			if recs[1] != tt.wantSecond {
+			if !reflect.DeepEqual(recs[1], tt.wantSecond) {
 				t.Fatalf("unexpected second recommendation: %+v", recs[1])
 			}
-			if recs[2] != tt.wantThird {
+			if !reflect.DeepEqual(recs[2], tt.wantThird) {
 				t.Fatalf("unexpected third recommendation: %+v", recs[2])
 			}
 		})
@@ -175,8 +180,6 @@ func TestRecommendationGeneratorGetResourceName(t *testing.T) {
 }
 
 func TestRecommendationGeneratorSeverityPriority(t *testing.T) {
-	generator := NewRecommendationGenerator()
-
 	tests := []struct {
 		severity string
 		expected int
@@ -191,7 +194,7 @@ func TestRecommendationGeneratorSeverityPriority(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.severity, func(t *testing.T) {
-			if got := generator.severityPriority(tt.severity); got != tt.expected {
+			if got := severityPriority(tt.severity); got != tt.expected {
 				t.Fatalf("expected %d, got %d", tt.expected, got)
 			}
 		})
@@ -262,3 +265,88 @@ func TestRecommendationGeneratorGroupBySeverity(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateRecommendationsRanksByScore(t *testing.T) {
+	report := &models.AggregatedReport{Issues: []models.NormalizedIssue{
+		// One high finding without waste.
+		{Tool: "iamspectre", ID: "STALE_ACCESS_KEY", Category: models.StatusStale, Severity: models.SeverityHigh, Resource: "alice/AKIA1", Count: 1},
+		// Three medium findings with significant waste outrank it.
+		{Tool: "awsspectre", ID: "IDLE_NAT_GATEWAY", Category: models.StatusUnused, Severity: models.SeverityMedium, Resource: "nat-a", Count: 1, EstimatedMonthlyWaste: 32},
+		{Tool: "awsspectre", ID: "IDLE_NAT_GATEWAY", Category: models.StatusUnused, Severity: models.SeverityMedium, Resource: "nat-b", Count: 1, EstimatedMonthlyWaste: 900},
+		{Tool: "awsspectre", ID: "IDLE_NAT_GATEWAY", Category: models.StatusUnused, Severity: models.SeverityMedium, Resource: "nat-c", Count: 1, EstimatedMonthlyWaste: 64},
+		// A different ID with the same tool and category stays separate.
+		{Tool: "awsspectre", ID: "UNUSED_EIP", Category: models.StatusUnused, Severity: models.SeverityMedium, Resource: "eip-1", Count: 1},
+	}}
+
+	recs := NewRecommendationGenerator().GenerateRecommendations(report)
+	if len(recs) != 3 {
+		t.Fatalf("expected 3 recommendations, got %d: %+v", len(recs), recs)
+	}
+
+	first := recs[0]
+	if first.ID != "IDLE_NAT_GATEWAY" || first.Count != 3 || first.MonthlyWaste != 996 {
+		t.Fatalf("unexpected first recommendation: %+v", first)
+	}
+	if !reflect.DeepEqual(first.TopResources, []string{"nat-b", "nat-c", "nat-a"}) {
+		t.Errorf("TopResources = %v, want ordered by waste", first.TopResources)
+	}
+	if first.Remediation == nil || first.Remediation.Effort == "" {
+		t.Errorf("expected remediation from the knowledge base, got %+v", first.Remediation)
+	}
+	if recs[1].ID != "STALE_ACCESS_KEY" || recs[2].ID != "UNUSED_EIP" {
+		t.Errorf("unexpected order: %s, %s", recs[1].ID, recs[2].ID)
+	}
+	for i := 1; i < len(recs); i++ {
+		if recs[i-1].Score < recs[i].Score {
+			t.Errorf("recommendations not sorted by score: %v then %v", recs[i-1].Score, recs[i].Score)
+		}
+	}
+}
+
+func TestGenerateRecommendationsUsesToolRemediation(t *testing.T) {
+	report := &models.AggregatedReport{Issues: []models.NormalizedIssue{
+		{Tool: "pgspectre", ID: "UNUSED_TABLE", Category: models.StatusUnused, Severity: models.SeverityMedium, Resource: "public.orders_old", Count: 1},
+		{Tool: "clickspectre", ID: "UNUSED_TABLE", Category: models.StatusUnused, Severity: models.SeverityMedium, Resource: "analytics.events_v1", Count: 1},
+	}}
+
+	snippets := map[string]string{}
+	for _, rec := range NewRecommendationGenerator().GenerateRecommendations(report) {
+		if rec.Remediation == nil {
+			t.Fatalf("%s: missing remediation", rec.Tool)
+		}
+		snippets[rec.Tool] = rec.Remediation.Snippet
+	}
+	if !strings.Contains(snippets["clickspectre"], "ON CLUSTER") {
+		t.Errorf("clickspectre got %q, want the ClickHouse snippet", snippets["clickspectre"])
+	}
+	if snippets["pgspectre"] == "" || strings.Contains(snippets["pgspectre"], "ON CLUSTER") {
+		t.Errorf("pgspectre got %q, want the Postgres snippet", snippets["pgspectre"])
+	}
+}
+
+func TestTopResourcesLimitAndDedup(t *testing.T) {
+	var issues []models.NormalizedIssue
+	for _, r := range []string{"g", "f", "e", "d", "c", "b", "a", "a", ""} {
+		issues = append(issues, models.NormalizedIssue{Resource: r})
+	}
+	got := topResources(issues)
+	if !reflect.DeepEqual(got, []string{"a", "b", "c", "d", "e"}) {
+		t.Errorf("topResources = %v", got)
+	}
+}
+
+func TestRecommendationScore(t *testing.T) {
+	one := recommendationScore(&issueGroup{severity: models.SeverityHigh, count: 1})
+	ten := recommendationScore(&issueGroup{severity: models.SeverityMedium, count: 10})
+	if one != ten {
+		t.Errorf("ten medium findings should score like one high: %v vs %v", ten, one)
+	}
+	zero := recommendationScore(&issueGroup{severity: models.SeverityLow})
+	if zero != 1 {
+		t.Errorf("a zero count should score like one finding, got %v", zero)
+	}
+	costly := recommendationScore(&issueGroup{severity: models.SeverityLow, count: 1, waste: 999})
+	if costly != 7 {
+		t.Errorf("score with $999 waste = %v, want 7", costly)
+	}
+}
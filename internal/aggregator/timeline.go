@@ -0,0 +1,71 @@
+package aggregator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+// Timeline event kinds.
+const (
+	TimelineAppeared        = "appeared"
+	TimelineDisappeared     = "disappeared"
+	TimelineSeverityChanged = "severity changed"
+)
+
+// TimelineEntry is the state of one issue fingerprint in one run.
+type TimelineEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Present   bool      `json:"present"`
+	Severity  string    `json:"severity,omitempty"`
+	// Event is set for runs where the issue appeared, disappeared or
+	// changed severity compared with the previous run.
+	Event  string `json:"event,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// IssueTimeline traces an issue through runs (oldest first), matching it by
+// the same fingerprint as ComputeDiff. Issues suppressed by triage still
+// count as present so a suppression is not mistaken for a fix.
+func IssueTimeline(runs []*models.AggregatedReport, issue models.NormalizedIssue) []TimelineEntry {
+	key := DiffKey(issue)
+	entries := make([]TimelineEntry, 0, len(runs))
+
+	var prev *TimelineEntry
+	for _, run := range runs {
+		entry := TimelineEntry{Timestamp: run.Timestamp}
+		if found, ok := findByKey(run, key); ok {
+			entry.Present = true
+			entry.Severity = found.Severity
+		}
+
+		switch {
+		case entry.Present && (prev == nil || !prev.Present):
+			entry.Event = TimelineAppeared
+			entry.Detail = entry.Severity
+		case !entry.Present && prev != nil && prev.Present:
+			entry.Event = TimelineDisappeared
+		case entry.Present && prev != nil && prev.Severity != entry.Severity:
+			entry.Event = TimelineSeverityChanged
+			entry.Detail = fmt.Sprintf("%s → %s", prev.Severity, entry.Severity)
+		}
+
+		entries = append(entries, entry)
+		prev = &entries[len(entries)-1]
+	}
+	return entries
+}
+
+// findByKey returns the issue with the given diff key in a run, looking at
+// suppressed issues too.
+func findByKey(run *models.AggregatedReport, key string) (models.NormalizedIssue, bool) {
+	for _, list := range [][]models.NormalizedIssue{run.Issues, run.Suppressed} {
+		for _, issue := range list {
+			if DiffKey(issue) == key {
+				return issue, true
+			}
+		}
+	}
+	return models.NormalizedIssue{}, false
+}
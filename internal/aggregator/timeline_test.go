@@ -0,0 +1,57 @@
+package aggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+func TestIssueTimeline(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	issue := models.NormalizedIssue{Tool: "s3spectre", Category: "unused", Resource: "s3://old", Severity: "low"}
+	with := func(sev string) []models.NormalizedIssue {
+		i := issue
+		i.Severity = sev
+		return []models.NormalizedIssue{i}
+	}
+
+	runs := []*models.AggregatedReport{
+		{Timestamp: day(1)},
+		{Timestamp: day(2), Issues: with("low")},
+		{Timestamp: day(3), Issues: with("low")},
+		{Timestamp: day(4), Issues: with("high")},
+		{Timestamp: day(5)},
+		{Timestamp: day(6), Suppressed: with("high")},
+	}
+
+	entries := IssueTimeline(runs, issue)
+	if len(entries) != len(runs) {
+		t.Fatalf("expected %d entries, got %d", len(runs), len(entries))
+	}
+
+	want := []struct {
+		present bool
+		event   string
+		detail  string
+	}{
+		{false, "", ""},
+		{true, TimelineAppeared, "low"},
+		{true, "", ""},
+		{true, TimelineSeverityChanged, "low → high"},
+		{false, TimelineDisappeared, ""},
+		{true, TimelineAppeared, "high"},
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Present != w.present || e.Event != w.event || e.Detail != w.detail {
+			t.Errorf("entry %d = %+v, want present=%v event=%q detail=%q", i, e, w.present, w.event, w.detail)
+		}
+	}
+}
+
+func TestIssueTimelineNoRuns(t *testing.T) {
+	if entries := IssueTimeline(nil, models.NormalizedIssue{}); len(entries) != 0 {
+		t.Errorf("expected no entries, got %v", entries)
+	}
+}
@@ -2,6 +2,8 @@ package aggregator
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/ppiankov/spectrehub/internal/models"
@@ -21,18 +23,23 @@ func (t *TrendAnalyzer) CalculateTrend(current, previous *models.AggregatedRepor
 		return nil
 	}
 
+	// Issues of tools missing from the current run are unknown, not resolved
+	missing := UnreportedTools(previous, current)
+	previousIssues := comparableIssueCount(previous, missing)
+
 	trend := &models.Trend{
-		PreviousIssues: previous.Summary.TotalIssues,
+		PreviousIssues: previousIssues,
 		CurrentIssues:  current.Summary.TotalIssues,
 		ComparedWith:   previous.Timestamp,
+		MissingTools:   missing,
 	}
 
 	// Calculate change
-	change := current.Summary.TotalIssues - previous.Summary.TotalIssues
+	change := current.Summary.TotalIssues - previousIssues
 
 	// Determine direction and percentage
-	if previous.Summary.TotalIssues > 0 {
-		trend.ChangePercent = float64(change) / float64(previous.Summary.TotalIssues) * 100.0
+	if previousIssues > 0 {
+		trend.ChangePercent = float64(change) / float64(previousIssues) * 100.0
 	}
 
 	if change < 0 {
@@ -69,10 +76,20 @@ func (t *TrendAnalyzer) AnalyzeLastNRuns(runs []*models.AggregatedReport) *model
 		summary.TimeRange = "Single run"
 	}
 
-	// Build issue sparkline (issue counts over time)
+	// Build issue sparkline (issue counts over time). Runs with missing
+	// tools count fewer issues without anything being fixed, so mark them.
 	summary.IssueSparkline = make([]int, len(runs))
+	partial := make([]bool, len(runs))
+	anyPartial := false
 	for i, run := range runs {
 		summary.IssueSparkline[i] = run.Summary.TotalIssues
+		if run.Coverage != nil && len(run.Coverage.Missing) > 0 {
+			partial[i] = true
+			anyPartial = true
+		}
+	}
+	if anyPartial {
+		summary.PartialRuns = partial
 	}
 
 	// Calculate per-tool trends
@@ -83,11 +100,14 @@ func (t *TrendAnalyzer) AnalyzeLastNRuns(runs []*models.AggregatedReport) *model
 	return summary
 }
 
-// calculateToolTrends calculates trend for each tool
+// calculateToolTrends calculates trend for each tool. A tool that did not
+// report in the earliest or latest run is marked missing instead of being
+// compared against zero.
 func (t *TrendAnalyzer) calculateToolTrends(runs []*models.AggregatedReport, summary *models.TrendSummary) {
 	// Get earliest and latest runs
 	earliest := runs[0]
 	latest := runs[len(runs)-1]
+	missing := missingFromEither(earliest, latest)
 
 	// Find all tools across both runs
 	allTools := make(map[string]bool)
@@ -102,6 +122,15 @@ func (t *TrendAnalyzer) calculateToolTrends(runs []*models.AggregatedReport, sum
 	for tool := range allTools {
 		previousCount := earliest.Summary.IssuesByTool[tool]
 		currentCount := latest.Summary.IssuesByTool[tool]
+		if missing[tool] {
+			summary.ByTool[tool] = &models.ToolTrend{
+				Name:           tool,
+				CurrentIssues:  currentCount,
+				PreviousIssues: previousCount,
+				Missing:        true,
+			}
+			continue
+		}
 		change := currentCount - previousCount
 
 		changePercent := 0.0
@@ -141,13 +170,14 @@ func (t *TrendAnalyzer) GenerateComparisonReport(current, previous *models.Aggre
 		trend.ChangePercent,
 		trend.Direction)
 
-	// Per-tool changes
+	// Per-tool changes; tools missing from either run are listed below
+	missing := missingFromEither(previous, current)
 	for tool := range current.Summary.IssuesByTool {
 		prevCount := previous.Summary.IssuesByTool[tool]
 		currCount := current.Summary.IssuesByTool[tool]
 
-		if prevCount == currCount {
-			continue // Skip unchanged tools
+		if prevCount == currCount || missing[tool] {
+			continue // Skip unchanged and uncomparable tools
 		}
 
 		report += fmt.Sprintf("%s:\n", tool)
@@ -170,9 +200,35 @@ func (t *TrendAnalyzer) GenerateComparisonReport(current, previous *models.Aggre
 		report += fmt.Sprintf("\nResolved Issues: %d\n", trend.ResolvedIssues)
 	}
 
+	// Tools that did not report in one of the runs
+	if len(missing) > 0 {
+		tools := make([]string, 0, len(missing))
+		for tool := range missing {
+			tools = append(tools, tool)
+		}
+		sort.Strings(tools)
+		report += fmt.Sprintf("\nNot compared (tool missing): %s\n", strings.Join(tools, ", "))
+	}
+
 	return report
 }
 
+// missingFromEither returns the tools that did not report in one of two
+// runs: those UnreportedTools finds in current, and those the earlier run's
+// coverage lists as missing.
+func missingFromEither(earlier, current *models.AggregatedReport) map[string]bool {
+	missing := make(map[string]bool)
+	for _, tool := range UnreportedTools(earlier, current) {
+		missing[tool] = true
+	}
+	if earlier.Coverage != nil {
+		for _, tool := range earlier.Coverage.Missing {
+			missing[tool] = true
+		}
+	}
+	return missing
+}
+
 // formatDate formats a timestamp for display
 func formatDate(t time.Time) string {
 	return t.Format("2006-01-02")
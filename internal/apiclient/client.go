@@ -3,8 +3,10 @@ package apiclient
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/ppiankov/spectrehub/internal/api"
@@ -29,6 +31,53 @@ func New(baseURL, licenseKey string) *Client {
 	}
 }
 
+// APIError is a request the API answered with an unexpected status.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return "API error: " + e.Message
+}
+
+// newAPIError reads the error message from a failed response.
+func newAPIError(resp *http.Response) *APIError {
+	var errResp map[string]string
+	_ = json.NewDecoder(resp.Body).Decode(&errResp)
+	msg := errResp["error"]
+	if msg == "" {
+		msg = resp.Status
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: msg}
+}
+
+// Retryable reports whether a failed request may succeed if sent again
+// later: the API could not be reached, timed out, was unavailable or rate
+// limited. A payload the API rejected is not retryable, and neither is a
+// refused license key, which must fail the run rather than be queued.
+func Retryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests:
+			return true
+		}
+		return apiErr.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// Unauthorized reports whether the API refused the license key (401 or 403).
+func Unauthorized(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
 // ReportPayload is the body for POST /v1/reports.
 type ReportPayload struct {
 	Repo       string  `json:"repo"`
@@ -78,13 +127,7 @@ func (c *Client) SubmitReport(payload ReportPayload) error {
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusCreated {
-		var errResp map[string]string
-		_ = json.NewDecoder(resp.Body).Decode(&errResp)
-		msg := errResp["error"]
-		if msg == "" {
-			msg = resp.Status
-		}
-		return fmt.Errorf("API error: %s", msg)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -184,13 +227,7 @@ func (c *Client) SubmitUserActivity(payload UserActivityPayload) error {
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusCreated {
-		var errResp map[string]string
-		_ = json.NewDecoder(resp.Body).Decode(&errResp)
-		msg := errResp["error"]
-		if msg == "" {
-			msg = resp.Status
-		}
-		return fmt.Errorf("API error: %s", msg)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -246,13 +283,7 @@ func (c *Client) SubmitFindings(payload FindingsPayload) (*FindingsResponse, err
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusCreated {
-		var errResp map[string]string
-		_ = json.NewDecoder(resp.Body).Decode(&errResp)
-		msg := errResp["error"]
-		if msg == "" {
-			msg = resp.Status
-		}
-		return nil, fmt.Errorf("API error: %s", msg)
+		return nil, newAPIError(resp)
 	}
 
 	var result FindingsResponse
@@ -406,13 +437,7 @@ func (c *Client) SubmitWaste(payload WastePayload) (*WasteResponse, error) {
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusCreated {
-		var errResp map[string]string
-		_ = json.NewDecoder(resp.Body).Decode(&errResp)
-		msg := errResp["error"]
-		if msg == "" {
-			msg = resp.Status
-		}
-		return nil, fmt.Errorf("API error: %s", msg)
+		return nil, newAPIError(resp)
 	}
 
 	var result WasteResponse
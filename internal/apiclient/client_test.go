@@ -393,3 +393,50 @@ func TestConnectionErrors(t *testing.T) {
 		t.Fatal("expected connection error for user activity")
 	}
 }
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+		{http.StatusTooManyRequests, true},
+		{http.StatusRequestTimeout, true},
+		{http.StatusUnauthorized, false},
+		{http.StatusForbidden, false},
+		{http.StatusBadRequest, false},
+		{http.StatusUnprocessableEntity, false},
+	}
+
+	for _, tt := range tests {
+		client := newMockClient("https://api.spectrehub.dev", testLicenseKey, func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(t, tt.status, map[string]string{"error": "nope"}), nil
+		})
+		err := client.SubmitReport(validReportPayload())
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+			t.Fatalf("HTTP %d: error = %v, want APIError", tt.status, err)
+		}
+		if err.Error() != "API error: nope" {
+			t.Errorf("HTTP %d: message = %q", tt.status, err.Error())
+		}
+		if got := Retryable(err); got != tt.want {
+			t.Errorf("Retryable(HTTP %d) = %v, want %v", tt.status, got, tt.want)
+		}
+		wantUnauthorized := tt.status == http.StatusUnauthorized || tt.status == http.StatusForbidden
+		if got := Unauthorized(err); got != wantUnauthorized {
+			t.Errorf("Unauthorized(HTTP %d) = %v, want %v", tt.status, got, wantUnauthorized)
+		}
+	}
+
+	offline := newMockClient("https://api.spectrehub.dev", testLicenseKey, func(r *http.Request) (*http.Response, error) {
+		return nil, errors.New("dial error")
+	})
+	if err := offline.SubmitReport(validReportPayload()); !Retryable(err) {
+		t.Errorf("Retryable(connection error) = false, want true")
+	}
+	if Retryable(errors.New("marshal report: bad")) {
+		t.Error("Retryable(local error) = true, want false")
+	}
+}
@@ -3,9 +3,11 @@ package cli
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ppiankov/spectrehub/internal/api"
 	"github.com/ppiankov/spectrehub/internal/collector"
+	"github.com/ppiankov/spectrehub/internal/tracing"
 	"github.com/spf13/cobra"
 )
 
@@ -15,8 +17,12 @@ var (
 	collectOutput     string
 	collectStore      bool
 	collectStorageDir string
+	collectStorageURL string
 	collectThreshold  int
 	collectRepo       string
+	collectMaxAge     time.Duration
+	collectFailStale  bool
+	collectRedacted   bool
 )
 
 // collectCmd represents the collect command
@@ -38,7 +44,9 @@ Example:
   spectrehub collect ./reports
   spectrehub collect ./reports/*.json
   spectrehub collect ./reports --format json --output summary.json
-  spectrehub collect ./reports --fail-threshold 50 --store`,
+  spectrehub collect ./reports --fail-threshold 50 --store
+  spectrehub collect ./reports --max-age 24h --fail-stale
+  spectrehub collect ./reports --show-redacted`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runCollect,
 }
@@ -52,13 +60,24 @@ func init() {
 		"store aggregated report for trend analysis")
 	collectCmd.Flags().StringVar(&collectStorageDir, "storage-dir", "",
 		"storage directory (default from config)")
+	collectCmd.Flags().StringVar(&collectStorageURL, "storage-url", "",
+		"store runs in a bucket instead, e.g. s3://bucket/prefix (default from config)")
 	collectCmd.Flags().IntVar(&collectThreshold, "fail-threshold", -1,
 		"exit with code 1 if issues exceed this threshold (default from config)")
 	collectCmd.Flags().StringVar(&collectRepo, "repo", "",
 		"repository identifier for API upload (e.g. org/repo)")
+	collectCmd.Flags().DurationVar(&collectMaxAge, "max-age", 0,
+		"warn about input reports older than this, e.g. 24h (default from config)")
+	collectCmd.Flags().BoolVar(&collectFailStale, "fail-stale", false,
+		"fail instead of warn when an input report is older than --max-age")
+	collectCmd.Flags().BoolVar(&collectRedacted, "show-redacted", false,
+		"print the API upload with the redaction profile applied, without storing, uploading or notifying")
 }
 
-func runCollect(cmd *cobra.Command, args []string) error {
+func runCollect(cmd *cobra.Command, args []string) (err error) {
+	ctx, span := tracing.Start(commandContext(cmd), "spectrehub.collect")
+	defer func() { tracing.End(span, err) }()
+
 	reportPaths := args
 
 	// Apply config defaults if flags not set
@@ -71,6 +90,10 @@ func runCollect(cmd *cobra.Command, args []string) error {
 	if collectThreshold == -1 {
 		collectThreshold = cfg.FailThreshold
 	}
+	maxAge := collectMaxAge
+	if maxAge == 0 {
+		maxAge = cfg.MaxReportAge
+	}
 
 	logVerbose("Collecting reports from: %s", strings.Join(reportPaths, ", "))
 	logDebug("Config: format=%s, store=%v, threshold=%d", collectFormat, collectStore, collectThreshold)
@@ -81,7 +104,7 @@ func runCollect(cmd *cobra.Command, args []string) error {
 		Verbose:        cfg.Verbose,
 	})
 
-	toolReports, err := c.CollectFromPaths(reportPaths)
+	toolReports, err := c.CollectFromPathsContext(ctx, reportPaths)
 	if err != nil {
 		logError("Failed to collect reports: %v", err)
 		return err
@@ -105,7 +128,7 @@ func runCollect(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	return RunPipeline(toolReports, PipelineConfig{
+	return RunPipelineContext(ctx, toolReports, PipelineConfig{
 		Format:     collectFormat,
 		Output:     collectOutput,
 		Store:      collectStore,
@@ -114,5 +137,14 @@ func runCollect(cmd *cobra.Command, args []string) error {
 		LicenseKey: cfg.LicenseKey,
 		APIURL:     cfg.APIURL,
 		Repo:       repo,
+
+		RemediationFile: cfg.RemediationFile,
+		Notifications:   cfg.Notifications,
+		Redaction:       cfg.Redaction,
+		Storage:         storageConfig(cfg.Storage, collectStorageURL),
+		ExpectedTools:   cfg.ExpectedTools,
+		MaxReportAge:    maxAge,
+		FailStale:       collectFailStale || cfg.FailStaleReports,
+		ShowRedacted:    collectRedacted,
 	})
 }
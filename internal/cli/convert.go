@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ppiankov/spectrehub/internal/aggregator"
+	"github.com/ppiankov/spectrehub/internal/collector"
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var convertOutput string
+
+var convertCmd = &cobra.Command{
+	Use:   "convert <file>",
+	Short: "Convert a legacy tool report to a spectre/v1 envelope",
+	Long: `Convert turns a report in a legacy tool format (vaultspectre, s3spectre,
+kafkaspectre, clickspectre, pgspectre, mongospectre) into a spectre/v1
+envelope, so old reports can be archived in one format.
+
+Legacy statuses become finding IDs (e.g. vault "missing" -> MISSING_SECRET,
+clickspectre configuration anomalies -> CONFIG_ANOMALY) and the converted
+report normalizes to exactly the issues of the legacy one, so diffs and
+trends carry across. What spectre/v1 cannot express goes into spectre/v1.1
+fields, and such reports are written as spectre/v1.1:
+
+  - critical severity: severity high with tool_severity critical
+  - per-issue counts (vault references, S3 objects, Kafka partitions): occurrences
+  - clickspectre first/last seen times: first_detected and last_detected
+
+A report that cannot be converted without losing data is rejected.
+
+Example:
+  spectrehub convert vaultspectre.json -o vaultspectre-v1.json
+  spectrehub convert legacy/s3spectre.json | spectrehub validate /dev/stdin`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvert,
+}
+
+func init() {
+	convertCmd.Flags().StringVarP(&convertOutput, "output", "o", "",
+		"write the envelope to file (default: stdout)")
+}
+
+func runConvert(cmd *cobra.Command, args []string) error {
+	report, err := collector.ParseFile(args[0])
+	if err != nil {
+		return &ValidationError{Message: fmt.Sprintf("cannot read %s: %v", args[0], err)}
+	}
+	if _, ok := report.RawData.(*models.SpectreV1Report); ok {
+		return &ValidationError{Message: fmt.Sprintf("%s is already a spectre/v1 envelope", args[0])}
+	}
+
+	envelope, err := aggregator.ConvertToSpectreV1(report)
+	if err != nil {
+		return &ValidationError{Message: fmt.Sprintf("cannot convert %s: %v", args[0], err)}
+	}
+	logVerbose("Converted %s report: %d findings", report.Tool, len(envelope.Findings))
+
+	writer := os.Stdout
+	if convertOutput != "" {
+		writer, err = os.Create(convertOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer func() { _ = writer.Close() }()
+	}
+
+	enc := json.NewEncoder(writer)
+	enc.SetIndent("", "  ")
+	return enc.Encode(envelope)
+}
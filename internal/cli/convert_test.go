@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ppiankov/spectrehub/internal/aggregator"
+	"github.com/ppiankov/spectrehub/internal/collector"
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/validator"
+)
+
+func TestRunConvertLegacyFixtures(t *testing.T) {
+	fixtures, err := filepath.Glob("../../testdata/contracts/*-v0.1.0.json")
+	if err != nil || len(fixtures) == 0 {
+		t.Fatalf("no legacy fixtures: %v", err)
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			out := filepath.Join(t.TempDir(), "converted.json")
+			convertOutput = out
+			defer func() { convertOutput = "" }()
+
+			if err := runConvert(nil, []string{fixture}); err != nil {
+				t.Fatalf("runConvert: %v", err)
+			}
+
+			legacy, err := collector.ParseFile(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			converted, err := collector.ParseFile(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if converted.Tool != legacy.Tool {
+				t.Errorf("tool = %s, want %s", converted.Tool, legacy.Tool)
+			}
+
+			data, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := validator.New().ValidateSpectreV1Report(data); err != nil {
+				t.Errorf("converted report is invalid: %v", err)
+			}
+
+			if got, want := diffKeys(t, converted), diffKeys(t, legacy); strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("diff keys = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRunConvertStdout(t *testing.T) {
+	output := captureStdout(t, func() {
+		if err := runConvert(nil, []string{"../../testdata/contracts/vaultspectre-v0.1.0.json"}); err != nil {
+			t.Errorf("runConvert: %v", err)
+		}
+	})
+	// A missing secret is critical, which only spectre/v1.1 can carry.
+	if !strings.Contains(output, `"schema": "spectre/v1.1"`) || !strings.Contains(output, `"MISSING_SECRET"`) ||
+		!strings.Contains(output, `"tool_severity": "critical"`) {
+		t.Errorf("output is not a converted envelope:\n%s", output)
+	}
+}
+
+func TestRunConvertRejectsEnvelope(t *testing.T) {
+	err := runConvert(nil, []string{"../../testdata/contracts/vaultspectre-spectrev1.json"})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || !strings.Contains(vErr.Message, "already a spectre/v1 envelope") {
+		t.Errorf("runConvert(spectre/v1) = %v, want ValidationError", err)
+	}
+}
+
+func TestRunConvertMissingFile(t *testing.T) {
+	var vErr *ValidationError
+	if err := runConvert(nil, []string{"/nonexistent/report.json"}); !errors.As(err, &vErr) {
+		t.Errorf("runConvert(missing) = %v, want ValidationError", err)
+	}
+}
+
+// diffKeys normalizes a report and returns its sorted issue diff keys.
+func diffKeys(t *testing.T, report *models.ToolReport) []string {
+	t.Helper()
+	issues, err := aggregator.NewNormalizer().Normalize(report)
+	if err != nil {
+		t.Fatalf("normalize %s: %v", report.Tool, err)
+	}
+	keys := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		keys = append(keys, aggregator.DiffKey(issue))
+	}
+	sort.Strings(keys)
+	return keys
+}
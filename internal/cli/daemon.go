@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/api"
+	"github.com/ppiankov/spectrehub/internal/discovery"
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/runner"
+	"github.com/ppiankov/spectrehub/internal/scheduler"
+	"github.com/ppiankov/spectrehub/internal/tracing"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	daemonStorageDir string
+	daemonRepo       string
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run scheduled audits continuously",
+	Long: `Daemon runs the audits listed under daemon.schedules in the config file,
+each on its own cron schedule, for example IAM hourly and S3 daily.
+
+Every run is stored for trends and goes through policy checks and
+notifications like 'spectrehub run --store'. A schedule never overlaps
+itself: an activation while the previous run is still going is skipped.
+Last runs are kept in daemon.json in the storage directory, so a restarted
+daemon runs anything it missed. On SIGTERM or SIGINT no new runs start and
+running scans get daemon.shutdown_timeout to finish.
+
+Example config:
+  daemon:
+    jitter: 2m
+    schedules:
+      - name: iam
+        cron: "@hourly"
+        tools: [iamspectre]
+      - name: s3
+        cron: "0 3 * * *"
+        tools: [s3spectre]
+
+Example:
+  spectrehub daemon
+  spectrehub daemon --repo org/infra --storage-dir /var/lib/spectrehub`,
+	RunE: runDaemon,
+}
+
+func init() {
+	daemonCmd.Flags().StringVar(&daemonStorageDir, "storage-dir", "",
+		"storage directory (default from config)")
+	daemonCmd.Flags().StringVar(&daemonRepo, "repo", "",
+		"repository identifier for API upload (e.g. org/repo)")
+}
+
+func runDaemon(cmd *cobra.Command, args []string) error {
+	dcfg := cfg.Daemon
+	if len(dcfg.Schedules) == 0 {
+		return &ValidationError{Message: "no schedules configured: add daemon.schedules to the config file"}
+	}
+	if err := validateScheduleTools(dcfg.Schedules); err != nil {
+		return err
+	}
+
+	storageDir := daemonStorageDir
+	if storageDir == "" {
+		storageDir = cfg.StorageDir
+	}
+	repo := daemonRepo
+	if repo == "" {
+		repo = cfg.Repo
+	}
+	if repo != "" {
+		if err := api.ValidateRepo(repo); err != nil {
+			return &ValidationError{Message: fmt.Sprintf("invalid repo value: %v", err)}
+		}
+	}
+
+	storagePath, err := getStoragePath(storageDir)
+	if err != nil {
+		logError("Failed to get storage path: %v", err)
+		return err
+	}
+	state, err := scheduler.LoadState(filepath.Join(storagePath, scheduler.StateFileName))
+	if err != nil {
+		return err
+	}
+
+	pcfg := PipelineConfig{
+		Format:     "json",
+		Output:     os.DevNull, // runs are read back from storage
+		Store:      true,
+		StorageDir: storageDir,
+		LicenseKey: cfg.LicenseKey,
+		APIURL:     cfg.APIURL,
+		Repo:       repo,
+
+		RemediationFile: cfg.RemediationFile,
+		Notifications:   cfg.Notifications,
+		Redaction:       cfg.Redaction,
+		Storage:         cfg.Storage,
+		ExpectedTools:   cfg.ExpectedTools,
+	}
+
+	jobs := make([]scheduler.Job, 0, len(dcfg.Schedules))
+	var pipelineMu sync.Mutex
+	for _, sc := range dcfg.Schedules {
+		schedule, err := scheduler.Parse(sc.Cron)
+		if err != nil {
+			return &ValidationError{Message: fmt.Sprintf("daemon schedule %q: %v", sc.Name, err)}
+		}
+		opts := toolRunOptions{
+			Timeout:      sc.Timeout,
+			Retries:      cfg.Retries,
+			RetryBackoff: cfg.RetryBackoff,
+			Tools:        sc.Tools,
+		}
+		if opts.Timeout == 0 {
+			opts.Timeout = runner.DefaultTimeout
+		}
+		name := sc.Name
+		jobs = append(jobs, scheduler.Job{
+			Name:     name,
+			Schedule: schedule,
+			Run: func(ctx context.Context) error {
+				return runScheduledAudit(ctx, name, opts, pcfg, &pipelineMu)
+			},
+		})
+	}
+
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "SpectreHub daemon started with %d schedule(s), storing runs in %s\n", len(jobs), storagePath)
+	err = scheduler.New(jobs, state, scheduler.Options{
+		Jitter:          dcfg.Jitter,
+		ShutdownTimeout: dcfg.ShutdownTimeout,
+		Logf:            daemonLogf,
+	}).Run(ctx)
+	if err != nil {
+		logError("%v", err)
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "SpectreHub daemon stopped")
+	return nil
+}
+
+// runScheduledAudit is one run of a schedule. Tools run concurrently with
+// other schedules; the pipeline is serialized because stored runs and
+// notification state are shared.
+func runScheduledAudit(ctx context.Context, name string, opts toolRunOptions, pcfg PipelineConfig, pipelineMu *sync.Mutex) (err error) {
+	ctx, span := tracing.Start(ctx, "spectrehub.daemon.run", attribute.String("spectrehub.schedule", name))
+	defer func() { tracing.End(span, err) }()
+
+	plan := discoverTools(ctx)
+	toolReports, executions, err := executeTools(ctx, plan, opts, pcfg)
+	if err != nil {
+		return err
+	}
+
+	pipelineMu.Lock()
+	defer pipelineMu.Unlock()
+	pcfg.Executions = executions
+	return RunPipelineContext(ctx, toolReports, pcfg)
+}
+
+// validateScheduleTools rejects tool names that spectrehub cannot run.
+func validateScheduleTools(schedules []scheduler.ScheduleConfig) error {
+	for _, sc := range schedules {
+		for _, tool := range sc.Tools {
+			if _, ok := discovery.Registry[models.ToolType(tool)]; !ok {
+				known := make([]string, 0, len(discovery.Registry))
+				for t := range discovery.Registry {
+					known = append(known, string(t))
+				}
+				sort.Strings(known)
+				return &ValidationError{Message: fmt.Sprintf("daemon schedule %q: unknown tool %q (known: %s)",
+					sc.Name, tool, strings.Join(known, ", "))}
+			}
+		}
+	}
+	return nil
+}
+
+// daemonLogf writes timestamped scheduler messages to stderr.
+func daemonLogf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%s %s\n", time.Now().Format(time.RFC3339), fmt.Sprintf(format, args...))
+}
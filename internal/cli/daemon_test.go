@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/config"
+	"github.com/ppiankov/spectrehub/internal/scheduler"
+	"github.com/spf13/cobra"
+)
+
+func TestRunDaemonValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		daemon  scheduler.Config
+		repo    string
+		wantErr string
+	}{
+		{"no schedules", scheduler.Config{}, "", "no schedules configured"},
+		{"unknown tool", scheduler.Config{Schedules: []scheduler.ScheduleConfig{
+			{Name: "iam", Cron: "@hourly", Tools: []string{"nosuchspectre"}},
+		}}, "", `unknown tool "nosuchspectre"`},
+		{"bad repo", scheduler.Config{Schedules: []scheduler.ScheduleConfig{
+			{Name: "iam", Cron: "@hourly", Tools: []string{"iamspectre"}},
+		}}, "not a repo", "invalid repo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withTestConfig(t, &config.Config{StorageDir: t.TempDir(), Repo: tt.repo, Daemon: tt.daemon})
+			err := runDaemon(nil, nil)
+			if _, ok := err.(*ValidationError); !ok || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected ValidationError containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRunDaemonStopsOnCancel(t *testing.T) {
+	withTestConfig(t, &config.Config{StorageDir: t.TempDir(), Daemon: scheduler.Config{
+		Schedules: []scheduler.ScheduleConfig{{Name: "iam", Cron: "@hourly", Tools: []string{"iamspectre"}}},
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	if err := runDaemon(cmd, nil); err != nil {
+		t.Fatalf("runDaemon: %v", err)
+	}
+}
+
+func TestRunScheduledAuditNoRunnableTools(t *testing.T) {
+	opts := toolRunOptions{Timeout: time.Second, Tools: []string{"nosuchspectre"}}
+	var mu sync.Mutex
+	err := runScheduledAudit(context.Background(), "iam", opts, PipelineConfig{}, &mu)
+	if err == nil || !strings.Contains(err.Error(), "is runnable") {
+		t.Errorf("expected no-runnable error, got %v", err)
+	}
+}
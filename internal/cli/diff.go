@@ -6,8 +6,8 @@ import (
 	"os"
 	"strings"
 
+	"github.com/ppiankov/spectrehub/internal/aggregator"
 	"github.com/ppiankov/spectrehub/internal/models"
-	"github.com/ppiankov/spectrehub/internal/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +16,7 @@ var (
 	diffOutput   string
 	diffBaseline string
 	diffFailNew  bool
+	diffURL      string
 )
 
 var diffCmd = &cobra.Command{
@@ -49,28 +50,15 @@ func init() {
 		"path to baseline report JSON (default: previous stored run)")
 	diffCmd.Flags().BoolVar(&diffFailNew, "fail-new", false,
 		"exit 1 if new issues are found (for CI gating)")
+	diffCmd.Flags().StringVar(&diffURL, "storage-url", "",
+		"read runs from a bucket, e.g. s3://bucket/prefix (default from config)")
 }
 
 // DiffResult is the structured output of a diff operation.
-type DiffResult struct {
-	Baseline       string                   `json:"baseline"`
-	Current        string                   `json:"current"`
-	NewIssues      []models.NormalizedIssue `json:"new_issues"`
-	ResolvedIssues []models.NormalizedIssue `json:"resolved_issues"`
-	Summary        DiffSummary              `json:"summary"`
-}
+type DiffResult = aggregator.DiffResult
 
 // DiffSummary holds aggregate counts for a diff.
-type DiffSummary struct {
-	BaselineTotal int            `json:"baseline_total"`
-	CurrentTotal  int            `json:"current_total"`
-	NewCount      int            `json:"new_count"`
-	ResolvedCount int            `json:"resolved_count"`
-	Delta         int            `json:"delta"` // positive = more issues
-	NewBySeverity map[string]int `json:"new_by_severity"`
-	NewByTool     map[string]int `json:"new_by_tool"`
-	NewByCategory map[string]int `json:"new_by_category"`
-}
+type DiffSummary = aggregator.DiffSummary
 
 func runDiff(cmd *cobra.Command, args []string) error {
 	storagePath, err := getStoragePath(cfg.StorageDir)
@@ -79,10 +67,17 @@ func runDiff(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	store := storage.NewLocal(storagePath)
+	store, err := openStore(storagePath, storageConfig(cfg.Storage, diffURL), cfg.Repo)
+	if err != nil {
+		return err
+	}
 
 	// Load current (latest) run.
 	current, err := store.GetLatestRun()
+	if tampered(err) {
+		logError("Refusing tampered history: %v", err)
+		return err
+	}
 	if err != nil {
 		logError("No current run found: %v", err)
 		fmt.Println("No stored runs found. Run 'spectrehub run --store' first.")
@@ -99,6 +94,10 @@ func runDiff(cmd *cobra.Command, args []string) error {
 		}
 	} else {
 		reports, err := store.GetLastNRuns(2)
+		if tampered(err) {
+			logError("Refusing tampered history: %v", err)
+			return err
+		}
 		if err != nil || len(reports) < 2 {
 			fmt.Println("Need at least 2 stored runs for diff.")
 			fmt.Println("Run 'spectrehub run --store' to generate more reports.")
@@ -132,61 +131,12 @@ func runDiff(cmd *cobra.Command, args []string) error {
 
 // issueKey returns a string that uniquely identifies an issue for diff purposes.
 func issueKey(issue models.NormalizedIssue) string {
-	return issue.Tool + "|" + issue.Category + "|" + issue.Resource
+	return aggregator.DiffKey(issue)
 }
 
 // computeDiff calculates new and resolved issues between baseline and current.
 func computeDiff(baseline, current *models.AggregatedReport) *DiffResult {
-	baseSet := make(map[string]models.NormalizedIssue, len(baseline.Issues))
-	for _, issue := range baseline.Issues {
-		baseSet[issueKey(issue)] = issue
-	}
-
-	currSet := make(map[string]models.NormalizedIssue, len(current.Issues))
-	for _, issue := range current.Issues {
-		currSet[issueKey(issue)] = issue
-	}
-
-	var newIssues, resolvedIssues []models.NormalizedIssue
-
-	for key, issue := range currSet {
-		if _, found := baseSet[key]; !found {
-			newIssues = append(newIssues, issue)
-		}
-	}
-
-	for key, issue := range baseSet {
-		if _, found := currSet[key]; !found {
-			resolvedIssues = append(resolvedIssues, issue)
-		}
-	}
-
-	// Build summary maps.
-	newBySeverity := map[string]int{}
-	newByTool := map[string]int{}
-	newByCategory := map[string]int{}
-	for _, issue := range newIssues {
-		newBySeverity[issue.Severity]++
-		newByTool[issue.Tool]++
-		newByCategory[issue.Category]++
-	}
-
-	return &DiffResult{
-		Baseline:       baseline.Timestamp.Format("2006-01-02 15:04:05"),
-		Current:        current.Timestamp.Format("2006-01-02 15:04:05"),
-		NewIssues:      newIssues,
-		ResolvedIssues: resolvedIssues,
-		Summary: DiffSummary{
-			BaselineTotal: len(baseline.Issues),
-			CurrentTotal:  len(current.Issues),
-			NewCount:      len(newIssues),
-			ResolvedCount: len(resolvedIssues),
-			Delta:         len(current.Issues) - len(baseline.Issues),
-			NewBySeverity: newBySeverity,
-			NewByTool:     newByTool,
-			NewByCategory: newByCategory,
-		},
-	}
+	return aggregator.ComputeDiff(baseline, current)
 }
 
 // outputDiff renders the diff result to the chosen format.
@@ -233,7 +183,11 @@ func printDiffText(w *os.File, r *DiffResult) error {
 		deltaSign = ""
 	}
 	p("Issues: %d → %d (%s%d)\n", r.Summary.BaselineTotal, r.Summary.CurrentTotal, deltaSign, r.Summary.Delta)
-	p("New: %d   Resolved: %d\n\n", r.Summary.NewCount, r.Summary.ResolvedCount)
+	p("New: %d   Resolved: %d", r.Summary.NewCount, r.Summary.ResolvedCount)
+	if r.Summary.UnverifiedCount > 0 {
+		p("   Unverified: %d", r.Summary.UnverifiedCount)
+	}
+	p("\n\n")
 
 	// New issues.
 	if len(r.NewIssues) > 0 {
@@ -259,6 +213,16 @@ func printDiffText(w *os.File, r *DiffResult) error {
 		p("\n")
 	}
 
+	// Issues of tools that did not report in the current run.
+	if len(r.MissingTools) > 0 {
+		p("Unverified (tool missing from current run: %s):\n", strings.Join(r.MissingTools, ", "))
+		p("--------------------------------------------------\n")
+		for _, issue := range r.UnverifiedIssues {
+			p("  ? %s — %s: %s\n", issue.Tool, issue.Category, issue.Resource)
+		}
+		p("\n")
+	}
+
 	// Breakdown tables.
 	if len(r.Summary.NewBySeverity) > 0 {
 		p("New by Severity:\n")
@@ -12,6 +12,7 @@ import (
 	"github.com/ppiankov/spectrehub/internal/apiclient"
 	"github.com/ppiankov/spectrehub/internal/config"
 	"github.com/ppiankov/spectrehub/internal/discovery"
+	"github.com/ppiankov/spectrehub/internal/outbox"
 	"github.com/spf13/cobra"
 )
 
@@ -28,6 +29,7 @@ var doctorCmd = &cobra.Command{
   4. Repo identifier — configured for upload?
   5. Spectre tools — installed and runnable?
   6. Storage — directory writable?
+  7. Outbox — API uploads waiting to be retried?
 
 Fix the issues it reports, then run 'spectrehub run' with confidence.`,
 	RunE: runDoctor,
@@ -70,6 +72,9 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	// 6. Storage directory
 	checks = append(checks, checkStorage())
 
+	// 7. Queued API uploads
+	checks = append(checks, checkOutbox())
+
 	// Build summary
 	fails, warns := 0, 0
 	for _, c := range checks {
@@ -327,6 +332,36 @@ func checkStorage() doctorCheck {
 	}
 }
 
+func checkOutbox() doctorCheck {
+	storagePath := cfg.StorageDir
+	if storagePath == "" {
+		storagePath = ".spectre"
+	}
+
+	path, err := getStoragePath(storagePath)
+	if err != nil {
+		return doctorCheck{Name: "outbox", Status: "fail", Detail: err.Error()}
+	}
+	entries, err := outbox.OpenDir(path).Entries()
+	if err != nil {
+		return doctorCheck{Name: "outbox", Status: "fail", Detail: err.Error()}
+	}
+	if len(entries) == 0 {
+		return doctorCheck{Name: "outbox", Status: "ok", Detail: "no queued uploads"}
+	}
+
+	oldest := entries[0]
+	detail := fmt.Sprintf("%d queued upload(s), oldest from run %s", len(entries), oldest.RunTimestamp.Format(time.RFC3339))
+	if oldest.LastError != "" {
+		detail += fmt.Sprintf(" (%s)", oldest.LastError)
+	}
+	return doctorCheck{
+		Name:   "outbox",
+		Status: "warn",
+		Detail: detail + ". Run: spectrehub sync",
+	}
+}
+
 // joinMax joins up to n strings with ", ".
 func joinMax(s []string, n int) string {
 	if len(s) <= n {
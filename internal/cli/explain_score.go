@@ -8,7 +8,6 @@ import (
 	"strings"
 
 	"github.com/ppiankov/spectrehub/internal/models"
-	"github.com/ppiankov/spectrehub/internal/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -70,7 +69,10 @@ func runExplainScore(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to resolve storage path: %w", err)
 	}
 
-	store := storage.NewLocal(storagePath)
+	store, err := openStore(storagePath, cfg.Storage, cfg.Repo)
+	if err != nil {
+		return err
+	}
 	report, err := store.GetLatestRun()
 	if err != nil {
 		return fmt.Errorf("no stored runs found. Run 'spectrehub run --store' first: %w", err)
@@ -3,21 +3,28 @@ package cli
 import (
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ppiankov/spectrehub/internal/compliance"
 	"github.com/ppiankov/spectrehub/internal/models"
-	"github.com/ppiankov/spectrehub/internal/storage"
+	"github.com/ppiankov/spectrehub/internal/redact"
+	"github.com/ppiankov/spectrehub/internal/reporter"
 	"github.com/spf13/cobra"
 )
 
 var (
-	exportFormat string
-	exportOutput string
-	exportLastN  int
+	exportFormat    string
+	exportOutput    string
+	exportLastN     int
+	exportFramework string
+	exportForce     bool
 )
 
 var exportCmd = &cobra.Command{
@@ -31,10 +38,20 @@ Supported formats:
   json   Structured JSON for programmatic consumption
   sarif  SARIF 2.1.0 for GitHub Advanced Security and code scanning
 
+The redaction profile in the config applies to every format.
+
+With --framework (soc2, iso27001, cis or a framework from compliance_file),
+csv and json exports report evidence per control over the selected runs:
+each control's related findings with first/last seen and whether they are
+open, suppressed, resolved or unverified. A control fails when the latest
+run has an open finding against it.
+
 Example:
   spectrehub export --format csv -o audit-evidence.csv
+  spectrehub export --format csv -o audit-evidence.csv --force
   spectrehub export --format sarif -o results.sarif --last 1
-  spectrehub export --format json --last 30 -o evidence.json`,
+  spectrehub export --format json --last 30 -o evidence.json
+  spectrehub export --framework soc2 --last 90 -o soc2-evidence.csv`,
 	RunE: runExport,
 }
 
@@ -43,8 +60,12 @@ func init() {
 		"output format: csv, json, or sarif")
 	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "",
 		"write output to file (default: stdout)")
+	exportCmd.Flags().BoolVar(&exportForce, "force", false,
+		"overwrite the output file if it exists")
 	exportCmd.Flags().IntVarP(&exportLastN, "last", "n", 1,
 		"number of recent runs to include")
+	exportCmd.Flags().StringVar(&exportFramework, "framework", "",
+		"report evidence per control of this framework: soc2, iso27001 or cis")
 }
 
 // ComplianceRecord is a single row in the compliance export.
@@ -62,23 +83,62 @@ type ComplianceRecord struct {
 
 // ComplianceExport is the full export payload.
 type ComplianceExport struct {
-	ExportedAt string             `json:"exported_at"`
-	RunCount   int                `json:"run_count"`
-	IssueCount int                `json:"issue_count"`
-	Framework  string             `json:"framework"`
-	Records    []ComplianceRecord `json:"records"`
+	ExportedAt string `json:"exported_at"`
+	RunCount   int    `json:"run_count"`
+	IssueCount int    `json:"issue_count"`
+	Framework  string `json:"framework"`
+	// SignaturesVerified is set when every exported run carried a valid
+	// signature.
+	SignaturesVerified bool `json:"signatures_verified"`
+	// Chain is the hash chain verification of the whole stored history.
+	Chain   *HistoryChain      `json:"chain,omitempty"`
+	Records []ComplianceRecord `json:"records"`
+	// Controls is the per-control evidence when a framework is selected.
+	Controls []compliance.ControlResult `json:"controls,omitempty"`
+}
+
+// HistoryChain is the result of verifying the hash chain across stored runs.
+type HistoryChain struct {
+	Root     string   `json:"root,omitempty"` // hash of the first chained run
+	Head     string   `json:"head,omitempty"` // hash of the newest run
+	Length   int      `json:"length"`
+	Verified bool     `json:"verified"`
+	Problems []string `json:"problems,omitempty"`
 }
 
 func runExport(cmd *cobra.Command, args []string) error {
+	var framework *compliance.Framework
+	if exportFramework != "" {
+		if exportFormat == "sarif" {
+			return &ValidationError{Message: "--framework applies to csv and json exports"}
+		}
+		mappings, err := compliance.Load(cfg.ComplianceFile)
+		if err != nil {
+			return &ValidationError{Message: err.Error()}
+		}
+		fw, ok := mappings.Framework(exportFramework)
+		if !ok {
+			return &ValidationError{Message: fmt.Sprintf("unknown framework %q (available: %s)", exportFramework, strings.Join(mappings.Frameworks(), ", "))}
+		}
+		framework = &fw
+	}
+
 	storagePath, err := getStoragePath(cfg.StorageDir)
 	if err != nil {
 		logError("Failed to get storage path: %v", err)
 		return err
 	}
 
-	store := storage.NewLocal(storagePath)
+	store, err := openStore(storagePath, cfg.Storage, cfg.Repo)
+	if err != nil {
+		return err
+	}
 
 	reports, err := store.GetLastNRuns(exportLastN)
+	if tampered(err) {
+		logError("Refusing tampered history: %v", err)
+		return err
+	}
 	if err != nil || len(reports) == 0 {
 		fmt.Println("No stored runs found. Run 'spectrehub run --store' first.")
 		return nil
@@ -86,11 +146,46 @@ func runExport(cmd *cobra.Command, args []string) error {
 
 	logVerbose("Exporting %d runs", len(reports))
 
+	redactor, err := redact.New(cfg.Redaction)
+	if err != nil {
+		return &ValidationError{Message: err.Error()}
+	}
+	for i, report := range reports {
+		reports[i] = redactor.Report(report)
+	}
+
 	export := buildComplianceExport(reports)
+	export.SignaturesVerified = store.Verifies()
+	if framework != nil {
+		export.Framework = framework.Name
+		export.Controls = framework.Evaluate(reports)
+	}
+
+	chain, err := store.VerifyChain()
+	if err != nil {
+		return err
+	}
+	export.Chain = &HistoryChain{
+		Root:     chain.Root,
+		Head:     chain.Head,
+		Length:   chain.Length,
+		Verified: chain.Valid(),
+		Problems: chain.Problems,
+	}
+	if !chain.Valid() {
+		fmt.Fprintf(os.Stderr, "Warning: stored history failed verification (%d problem(s)); run 'spectrehub verify-history' for details\n", len(chain.Problems))
+	}
 
 	var writer *os.File
 	if exportOutput != "" {
-		writer, err = os.Create(exportOutput)
+		flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if !exportForce {
+			flags |= os.O_EXCL
+		}
+		writer, err = os.OpenFile(exportOutput, flags, 0644)
+		if errors.Is(err, fs.ErrExist) {
+			return &ValidationError{Message: fmt.Sprintf("%s already exists (use --force to overwrite)", exportOutput)}
+		}
 		if err != nil {
 			return fmt.Errorf("failed to create output file: %w", err)
 		}
@@ -101,6 +196,9 @@ func runExport(cmd *cobra.Command, args []string) error {
 
 	switch exportFormat {
 	case "csv":
+		if framework != nil {
+			return writeControlsCSV(writer, export)
+		}
 		return writeCSV(writer, export)
 	case "json":
 		return writeExportJSON(writer, export)
@@ -181,138 +279,52 @@ func writeCSV(w *os.File, export *ComplianceExport) error {
 	return nil
 }
 
-func writeExportJSON(w *os.File, export *ComplianceExport) error {
-	enc := json.NewEncoder(w)
-	enc.SetIndent("", "  ")
-	return enc.Encode(export)
-}
-
-// SARIF 2.1.0 output for GitHub Advanced Security integration.
-// Minimal structures — only what's needed for valid SARIF.
-
-type sarifLog struct {
-	Schema  string     `json:"$schema"`
-	Version string     `json:"version"`
-	Runs    []sarifRun `json:"runs"`
-}
-
-type sarifRun struct {
-	Tool    sarifTool     `json:"tool"`
-	Results []sarifResult `json:"results"`
-}
-
-type sarifTool struct {
-	Driver sarifDriver `json:"driver"`
-}
-
-type sarifDriver struct {
-	Name    string      `json:"name"`
-	Version string      `json:"version"`
-	Rules   []sarifRule `json:"rules"`
-}
-
-type sarifRule struct {
-	ID               string             `json:"id"`
-	ShortDescription sarifMessage       `json:"shortDescription"`
-	DefaultConfig    sarifDefaultConfig `json:"defaultConfiguration"`
-}
-
-type sarifDefaultConfig struct {
-	Level string `json:"level"`
-}
-
-type sarifMessage struct {
-	Text string `json:"text"`
-}
-
-type sarifResult struct {
-	RuleID    string          `json:"ruleId"`
-	Level     string          `json:"level"`
-	Message   sarifMessage    `json:"message"`
-	Locations []sarifLocation `json:"locations"`
-}
-
-type sarifLocation struct {
-	PhysicalLocation sarifPhysical `json:"physicalLocation"`
-}
-
-type sarifPhysical struct {
-	ArtifactLocation sarifArtifact `json:"artifactLocation"`
-}
-
-type sarifArtifact struct {
-	URI string `json:"uri"`
-}
+// writeControlsCSV writes one row per control and related finding; a
+// control without findings gets a single row.
+func writeControlsCSV(w *os.File, export *ComplianceExport) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
 
-func writeSARIF(w *os.File, reports []*models.AggregatedReport) error {
-	rulesMap := map[string]sarifRule{}
-	var results []sarifResult
+	header := []string{
+		"control", "title", "control_status", "finding_id", "tool", "category",
+		"severity", "resource", "first_seen", "last_seen", "runs", "finding_status",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
 
-	for _, report := range reports {
-		for _, issue := range report.Issues {
-			ruleID := issue.Tool + "/" + issue.Category
-			if _, exists := rulesMap[ruleID]; !exists {
-				rulesMap[ruleID] = sarifRule{
-					ID:               ruleID,
-					ShortDescription: sarifMessage{Text: issue.Tool + " " + issue.Category},
-					DefaultConfig:    sarifDefaultConfig{Level: sarifLevel(issue.Severity)},
-				}
+	for _, c := range export.Controls {
+		if len(c.Findings) == 0 {
+			if err := writer.Write([]string{c.Control, c.Title, c.Status, "", "", "", "", "", "", "", "0", ""}); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, f := range c.Findings {
+			row := []string{
+				c.Control, c.Title, c.Status, f.ID, f.Tool, f.Category,
+				f.Severity, f.Resource, f.FirstSeen.Format(time.RFC3339), f.LastSeen.Format(time.RFC3339),
+				strconv.Itoa(f.Runs), f.Status,
+			}
+			if err := writer.Write(row); err != nil {
+				return err
 			}
-
-			results = append(results, sarifResult{
-				RuleID:  ruleID,
-				Level:   sarifLevel(issue.Severity),
-				Message: sarifMessage{Text: formatEvidence(issue)},
-				Locations: []sarifLocation{{
-					PhysicalLocation: sarifPhysical{
-						ArtifactLocation: sarifArtifact{URI: issue.Resource},
-					},
-				}},
-			})
 		}
 	}
 
-	var rules []sarifRule
-	for _, r := range rulesMap {
-		rules = append(rules, r)
-	}
-	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
-
-	log := sarifLog{
-		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
-		Version: "2.1.0",
-		Runs: []sarifRun{{
-			Tool: sarifTool{
-				Driver: sarifDriver{
-					Name:    "spectrehub",
-					Version: "0.2.0",
-					Rules:   rules,
-				},
-			},
-			Results: results,
-		}},
-	}
+	return nil
+}
 
+func writeExportJSON(w *os.File, export *ComplianceExport) error {
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
-	return enc.Encode(log)
-}
-
-func sarifLevel(severity string) string {
-	switch severity {
-	case "critical", "high":
-		return "error"
-	case "medium":
-		return "warning"
-	default:
-		return "note"
-	}
+	return enc.Encode(export)
 }
 
-func formatEvidence(issue models.NormalizedIssue) string {
-	parts := []string{issue.Tool + ": " + issue.Category + " — " + issue.Resource}
-	if issue.Evidence != "" {
-		parts = append(parts, issue.Evidence)
+func writeSARIF(w *os.File, reports []*models.AggregatedReport) error {
+	var issues []models.NormalizedIssue
+	for _, report := range reports {
+		issues = append(issues, report.Issues...)
 	}
-	return strings.Join(parts, ". ")
+	return reporter.WriteSARIF(w, issues, nil)
 }
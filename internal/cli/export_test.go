@@ -3,12 +3,17 @@ package cli
 import (
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/ppiankov/spectrehub/internal/config"
 	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/redact"
+	"github.com/ppiankov/spectrehub/internal/reporter"
 )
 
 func sampleReports() []*models.AggregatedReport {
@@ -133,7 +138,7 @@ func TestWriteSARIF(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	var log sarifLog
+	var log reporter.SARIFLog
 	if err := json.Unmarshal(data, &log); err != nil {
 		t.Fatalf("unmarshal sarif: %v", err)
 	}
@@ -152,41 +157,6 @@ func TestWriteSARIF(t *testing.T) {
 	}
 }
 
-func TestSarifLevel(t *testing.T) {
-	tests := []struct {
-		severity string
-		want     string
-	}{
-		{"critical", "error"},
-		{"high", "error"},
-		{"medium", "warning"},
-		{"low", "note"},
-		{"", "note"},
-	}
-	for _, tt := range tests {
-		got := sarifLevel(tt.severity)
-		if got != tt.want {
-			t.Errorf("sarifLevel(%q) = %q, want %q", tt.severity, got, tt.want)
-		}
-	}
-}
-
-func TestFormatEvidence(t *testing.T) {
-	issue := models.NormalizedIssue{
-		Tool:     "vaultspectre",
-		Category: "missing",
-		Resource: "secret/db",
-		Evidence: "key not found",
-	}
-	got := formatEvidence(issue)
-	if !strings.Contains(got, "vaultspectre") {
-		t.Errorf("expected tool in evidence, got %s", got)
-	}
-	if !strings.Contains(got, "key not found") {
-		t.Errorf("expected evidence text, got %s", got)
-	}
-}
-
 func TestBuildComplianceExportMultipleRuns(t *testing.T) {
 	reports := []*models.AggregatedReport{
 		{
@@ -218,3 +188,147 @@ func TestBuildComplianceExportMultipleRuns(t *testing.T) {
 		t.Errorf("expected critical first, got %s", export.Records[0].Severity)
 	}
 }
+
+func TestRunExportRedacted(t *testing.T) {
+	report := sampleReports()[0]
+	dir := setupTestStorage(t, report)
+	withTestConfig(t, &config.Config{
+		StorageDir: dir,
+		Redaction:  redact.Config{Resources: redact.ResourcesHash, Key: "k", StripEvidence: true},
+	})
+
+	oldFormat, oldOutput, oldLast := exportFormat, exportOutput, exportLastN
+	t.Cleanup(func() { exportFormat, exportOutput, exportLastN = oldFormat, oldOutput, oldLast })
+
+	for _, format := range []string{"csv", "json", "sarif"} {
+		exportFormat = format
+		exportOutput = filepath.Join(t.TempDir(), "export."+format)
+		exportLastN = 1
+		if err := runExport(nil, nil); err != nil {
+			t.Fatalf("runExport %s: %v", format, err)
+		}
+		data, err := os.ReadFile(exportOutput)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, plain := range []string{"secret/db", "s3://old-bucket", "key not found"} {
+			if strings.Contains(string(data), plain) {
+				t.Errorf("%s export contains %q", format, plain)
+			}
+		}
+	}
+}
+
+func TestRunExportFramework(t *testing.T) {
+	day1 := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	iam := map[string]models.ToolReport{"iamspectre": {Tool: "iamspectre"}}
+	staleKey := models.NormalizedIssue{Tool: "iamspectre", ID: "STALE_ACCESS_KEY", Category: "stale", Severity: "high", Resource: "user/alice"}
+	noMFA := models.NormalizedIssue{Tool: "iamspectre", ID: "NO_MFA", Category: "misconfig", Severity: "high", Resource: "user/bob"}
+	dir := setupTestStorage(t,
+		&models.AggregatedReport{Timestamp: day1, Issues: []models.NormalizedIssue{staleKey, noMFA}, ToolReports: iam},
+		&models.AggregatedReport{Timestamp: day1.Add(24 * time.Hour), Issues: []models.NormalizedIssue{staleKey}, ToolReports: iam},
+	)
+	withTestConfig(t, &config.Config{StorageDir: dir})
+
+	oldFormat, oldOutput, oldLast, oldFramework := exportFormat, exportOutput, exportLastN, exportFramework
+	t.Cleanup(func() {
+		exportFormat, exportOutput, exportLastN, exportFramework = oldFormat, oldOutput, oldLast, oldFramework
+	})
+	exportFormat, exportLastN, exportFramework = "json", 2, "soc2"
+	exportOutput = filepath.Join(t.TempDir(), "soc2.json")
+
+	if err := runExport(nil, nil); err != nil {
+		t.Fatalf("runExport: %v", err)
+	}
+	data, _ := os.ReadFile(exportOutput)
+	var export ComplianceExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatal(err)
+	}
+	if export.Framework != "SOC 2 Trust Services Criteria (2017)" {
+		t.Errorf("framework = %q", export.Framework)
+	}
+	controls := make(map[string]string)
+	for _, c := range export.Controls {
+		controls[c.Control] = c.Status
+		if c.Control == "CC6.1" {
+			found := map[string]string{}
+			for _, f := range c.Findings {
+				found[f.ID] = f.Status
+			}
+			if found["STALE_ACCESS_KEY"] != "open" || found["NO_MFA"] != "resolved" {
+				t.Errorf("CC6.1 findings = %+v", c.Findings)
+			}
+		}
+	}
+	if controls["CC6.1"] != "fail" || controls["A1.2"] != "pass" {
+		t.Errorf("control statuses = %v", controls)
+	}
+
+	exportFormat = "csv"
+	exportOutput = filepath.Join(t.TempDir(), "soc2.csv")
+	if err := runExport(nil, nil); err != nil {
+		t.Fatalf("runExport csv: %v", err)
+	}
+	f, _ := os.Open(exportOutput)
+	defer func() { _ = f.Close() }()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows[0][0] != "control" || rows[0][len(rows[0])-1] != "finding_status" {
+		t.Errorf("header = %v", rows[0])
+	}
+	var sawOpen bool
+	for _, row := range rows[1:] {
+		if row[0] == "CC6.1" && row[3] == "STALE_ACCESS_KEY" && row[2] == "fail" && row[10] == "2" && row[11] == "open" {
+			sawOpen = true
+		}
+	}
+	if !sawOpen {
+		t.Errorf("no open CC6.1 row in %v", rows)
+	}
+}
+
+func TestRunExportFrameworkInvalid(t *testing.T) {
+	withTestConfig(t, &config.Config{StorageDir: t.TempDir()})
+	oldFormat, oldFramework := exportFormat, exportFramework
+	t.Cleanup(func() { exportFormat, exportFramework = oldFormat, oldFramework })
+
+	for _, tt := range []struct{ format, framework string }{{"json", "pci"}, {"sarif", "soc2"}} {
+		exportFormat, exportFramework = tt.format, tt.framework
+		var valErr *ValidationError
+		if err := runExport(nil, nil); !errors.As(err, &valErr) {
+			t.Errorf("%s/%s: runExport = %v, want ValidationError", tt.format, tt.framework, err)
+		}
+	}
+}
+
+func TestRunExportRefusesOverwrite(t *testing.T) {
+	dir := setupTestStorage(t, sampleReports()[0])
+	withTestConfig(t, &config.Config{StorageDir: dir})
+	oldFormat, oldOutput, oldLast, oldForce := exportFormat, exportOutput, exportLastN, exportForce
+	t.Cleanup(func() { exportFormat, exportOutput, exportLastN, exportForce = oldFormat, oldOutput, oldLast, oldForce })
+
+	exportFormat, exportLastN, exportForce = "csv", 1, false
+	exportOutput = filepath.Join(t.TempDir(), "evidence.csv")
+	if err := os.WriteFile(exportOutput, []byte("keep me\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var valErr *ValidationError
+	if err := runExport(nil, nil); !errors.As(err, &valErr) || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("runExport over an existing file = %v, want ValidationError naming --force", err)
+	}
+	if data, _ := os.ReadFile(exportOutput); string(data) != "keep me\n" {
+		t.Errorf("existing file was modified: %q", data)
+	}
+
+	exportForce = true
+	if err := runExport(nil, nil); err != nil {
+		t.Fatalf("runExport --force: %v", err)
+	}
+	if data, _ := os.ReadFile(exportOutput); strings.Contains(string(data), "keep me") {
+		t.Errorf("--force did not overwrite the file: %q", data)
+	}
+}
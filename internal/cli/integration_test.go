@@ -15,6 +15,7 @@ import (
 	"github.com/ppiankov/spectrehub/internal/config"
 	"github.com/ppiankov/spectrehub/internal/discovery"
 	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/reporter"
 	"github.com/ppiankov/spectrehub/internal/storage"
 )
 
@@ -390,7 +391,7 @@ func TestRunExportSARIF(t *testing.T) {
 	}
 
 	data, _ := os.ReadFile(outFile)
-	var log sarifLog
+	var log reporter.SARIFLog
 	if err := json.Unmarshal(data, &log); err != nil {
 		t.Fatalf("unmarshal: %v", err)
 	}
@@ -467,6 +468,46 @@ func TestRunCollectWithFixtures(t *testing.T) {
 	}
 }
 
+func TestRunCollectStaleReports(t *testing.T) {
+	withTestConfig(t, &config.Config{Format: "json", StorageDir: t.TempDir(), MaxReportAge: 24 * time.Hour})
+
+	oldFormat, oldOutput, oldStore := collectFormat, collectOutput, collectStore
+	oldThreshold, oldFailStale := collectThreshold, collectFailStale
+	t.Cleanup(func() {
+		collectFormat, collectOutput, collectStore = oldFormat, oldOutput, oldStore
+		collectThreshold, collectFailStale = oldThreshold, oldFailStale
+	})
+
+	outFile := filepath.Join(t.TempDir(), "collected.json")
+	collectFormat, collectOutput, collectStore, collectThreshold = "json", outFile, false, 0
+	fixture := "../../testdata/contracts/vaultspectre-spectrev1.json" // generated 2026-02-23
+
+	// Default: warn and record the stale report.
+	collectFailStale = false
+	if err := runCollect(nil, []string{fixture}); err != nil {
+		t.Fatalf("runCollect: %v", err)
+	}
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report models.AggregatedReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Freshness) != 1 || !report.Freshness[0].Stale ||
+		!report.Freshness[0].Timestamp.Equal(time.Date(2026, 2, 23, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected freshness: %+v", report.Freshness)
+	}
+
+	// --fail-stale turns the warning into an input error.
+	collectFailStale = true
+	err = runCollect(nil, []string{fixture})
+	if _, ok := err.(*ValidationError); !ok || !strings.Contains(err.Error(), "vaultspectre") {
+		t.Errorf("expected stale ValidationError, got %v", err)
+	}
+}
+
 func TestRunCollectNoValidFiles(t *testing.T) {
 	withTestConfig(t, &config.Config{Format: "text"})
 
@@ -2008,7 +2049,7 @@ func TestRunExportSARIFStdout(t *testing.T) {
 		}
 	})
 
-	var log sarifLog
+	var log reporter.SARIFLog
 	if err := json.Unmarshal([]byte(output), &log); err != nil {
 		t.Fatalf("invalid SARIF JSON: %v", err)
 	}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/atomicfile"
+	"github.com/ppiankov/spectrehub/internal/metrics"
+	"github.com/ppiankov/spectrehub/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	metricsOutput string
+	metricsListen string
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Expose stored runs as Prometheus metrics",
+	Long: `Render the latest stored run in the Prometheus text format.
+
+Exposes the overall and per-tool score, issue counts by tool, category and
+severity, estimated monthly waste, per-tool run duration and success, and
+the time of the last run in which every tool succeeded. Labels never
+include resource names, so series stay bounded.
+
+With --output the metrics are written atomically to a .prom file for the
+node_exporter textfile collector; run it after each 'spectrehub run'.
+With --listen a /metrics endpoint serves the latest run on every scrape.
+
+Example:
+  spectrehub metrics
+  spectrehub metrics --output /var/lib/node_exporter/textfile/spectrehub.prom
+  spectrehub metrics --listen :9469`,
+	RunE: runMetrics,
+}
+
+func init() {
+	metricsCmd.Flags().StringVarP(&metricsOutput, "output", "o", "",
+		"write a node_exporter textfile (.prom) instead of stdout")
+	metricsCmd.Flags().StringVar(&metricsListen, "listen", "",
+		"serve /metrics on this address (e.g. :9469)")
+}
+
+func runMetrics(cmd *cobra.Command, args []string) error {
+	if metricsOutput != "" && metricsListen != "" {
+		return &ValidationError{Message: "--output and --listen cannot be used together"}
+	}
+	if metricsOutput != "" && !strings.HasSuffix(metricsOutput, ".prom") {
+		return &ValidationError{Message: "--output must end in .prom for the textfile collector"}
+	}
+
+	storagePath, err := getStoragePath(cfg.StorageDir)
+	if err != nil {
+		logError("Failed to get storage path: %v", err)
+		return err
+	}
+	store, err := openStore(storagePath, cfg.Storage, cfg.Repo)
+	if err != nil {
+		return err
+	}
+
+	if metricsListen != "" {
+		return serveMetrics(store, metricsListen)
+	}
+
+	snap, err := metrics.Load(store)
+	if err != nil {
+		logError("Failed to load runs: %v", err)
+		return err
+	}
+
+	if metricsOutput == "" {
+		return metrics.Write(os.Stdout, snap)
+	}
+	if err := writeTextfile(metricsOutput, snap); err != nil {
+		return err
+	}
+	logVerbose("Metrics written to %s", metricsOutput)
+	return nil
+}
+
+// writeTextfile writes through a temp file and rename so the collector
+// never reads a partial file.
+func writeTextfile(path string, snap *metrics.Snapshot) error {
+	var buf bytes.Buffer
+	if err := metrics.Write(&buf, snap); err != nil {
+		return err
+	}
+	if err := atomicfile.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("write metrics file: %w", err)
+	}
+	return nil
+}
+
+// metricsHandler serves the latest stored run on every request.
+func metricsHandler(store storage.Storage) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		snap, err := metrics.Load(store)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		var buf bytes.Buffer
+		if err := metrics.Write(&buf, snap); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", metrics.ContentType)
+		_, _ = w.Write(buf.Bytes())
+	})
+	return mux
+}
+
+// serveMetrics runs the /metrics endpoint until SIGINT or SIGTERM.
+func serveMetrics(store storage.Storage, addr string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           metricsHandler(store),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServe() }()
+	fmt.Fprintf(os.Stderr, "Serving metrics on %s/metrics\n", addr)
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("metrics server: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("metrics server shutdown: %w", err)
+	}
+	return nil
+}
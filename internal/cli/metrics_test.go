@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/config"
+	"github.com/ppiankov/spectrehub/internal/storage"
+)
+
+func TestRunMetricsTextfile(t *testing.T) {
+	dir := setupTestStorage(t, baseReport(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), 2))
+	withTestConfig(t, &config.Config{StorageDir: dir})
+
+	out := filepath.Join(t.TempDir(), "spectrehub.prom")
+	metricsOutput, metricsListen = out, ""
+	t.Cleanup(func() { metricsOutput = "" })
+
+	if err := runMetrics(metricsCmd, nil); err != nil {
+		t.Fatalf("runMetrics: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `spectrehub_issues{tool="vaultspectre",category="missing",severity="critical"} 2`) {
+		t.Errorf("unexpected metrics:\n%s", data)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(out))
+	if len(entries) != 1 {
+		t.Errorf("expected only the .prom file, found %d entries", len(entries))
+	}
+}
+
+func TestRunMetricsValidation(t *testing.T) {
+	withTestConfig(t, &config.Config{StorageDir: t.TempDir()})
+	t.Cleanup(func() { metricsOutput, metricsListen = "", "" })
+
+	metricsOutput, metricsListen = "out.txt", ""
+	if err := runMetrics(metricsCmd, nil); err == nil || !strings.Contains(err.Error(), ".prom") {
+		t.Errorf("expected .prom error, got %v", err)
+	}
+
+	metricsOutput, metricsListen = "out.prom", ":0"
+	if err := runMetrics(metricsCmd, nil); err == nil {
+		t.Error("expected error for --output with --listen")
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	dir := t.TempDir()
+	srv := httptest.NewServer(metricsHandler(storage.NewLocal(dir)))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without runs, got %d", resp.StatusCode)
+	}
+
+	// Runs stored after startup are picked up on the next scrape.
+	if err := storage.NewLocal(dir).SaveAggregatedReport(baseReport(time.Now(), 1)); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected response %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(string(body), "spectrehub_score_percent ") {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+}
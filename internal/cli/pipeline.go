@@ -1,20 +1,30 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/ppiankov/spectrehub/internal/aggregator"
 	"github.com/ppiankov/spectrehub/internal/api"
 	"github.com/ppiankov/spectrehub/internal/apiclient"
 	"github.com/ppiankov/spectrehub/internal/ingest"
 	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/notify"
+	"github.com/ppiankov/spectrehub/internal/outbox"
 	"github.com/ppiankov/spectrehub/internal/policy"
+	"github.com/ppiankov/spectrehub/internal/redact"
+	"github.com/ppiankov/spectrehub/internal/remediation"
 	"github.com/ppiankov/spectrehub/internal/reporter"
 	"github.com/ppiankov/spectrehub/internal/storage"
+	"github.com/ppiankov/spectrehub/internal/tracing"
+	"github.com/ppiankov/spectrehub/internal/triage"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // PipelineConfig holds options for the shared aggregation pipeline.
@@ -27,35 +37,104 @@ type PipelineConfig struct {
 	LicenseKey string
 	APIURL     string
 	Repo       string
+
+	// RemediationFile overrides the built-in remediation knowledge base.
+	RemediationFile string
+
+	// Notifications configures alerts sent after the run.
+	Notifications notify.Config
+	// Executions are the tool runs behind the reports (run command only).
+	Executions []models.ToolExecution
+	// ExpectedTools should each deliver a report; together with the tools
+	// in Executions they define the run's coverage.
+	ExpectedTools []string
+	// MaxReportAge flags input reports older than this (0 disables);
+	// FailStale turns the warning into an error.
+	MaxReportAge time.Duration
+	FailStale    bool
+
+	// Redaction applies to API uploads and notifications; stored runs
+	// keep the full data.
+	Redaction redact.Config
+	// ShowRedacted prints what would be uploaded instead of storing,
+	// uploading or notifying.
+	ShowRedacted bool
+
+	// Storage holds the keys that encrypt and sign stored runs.
+	Storage storage.Config
 }
 
 // RunPipeline executes the aggregation pipeline on a set of tool reports.
 // This is the shared logic between collect and run commands:
-// aggregate → trend → recommendations → store → output → threshold check.
+// aggregate → triage → trend → recommendations → correlation → store → output → policy → notify → threshold check.
 func RunPipeline(toolReports []models.ToolReport, pcfg PipelineConfig) error {
+	return RunPipelineContext(context.Background(), toolReports, pcfg)
+}
+
+// RunPipelineContext is RunPipeline with a parent context for trace spans
+// around aggregation, storage and API upload.
+func RunPipelineContext(ctx context.Context, toolReports []models.ToolReport, pcfg PipelineConfig) error {
 	// Step 1: Aggregate reports
 	agg := aggregator.New()
+	_, span := tracing.Start(ctx, "aggregator.aggregate", attribute.Int("spectrehub.tool_reports", len(toolReports)))
 	aggregatedReport, err := agg.Aggregate(toolReports)
+	if err == nil {
+		span.SetAttributes(attribute.Int("spectrehub.issues", aggregatedReport.Summary.TotalIssues))
+	}
+	tracing.End(span, err)
 	if err != nil {
 		logError("Failed to aggregate reports: %v", err)
 		return err
 	}
 
+	aggregatedReport.Executions = pcfg.Executions
+	if err := checkFreshness(aggregatedReport, pcfg); err != nil {
+		return err
+	}
+	agg.SetCoverage(aggregatedReport, expectedTools(pcfg))
+	if cov := aggregatedReport.Coverage; cov != nil && len(cov.Missing) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: partial coverage, no report from %s\n", strings.Join(cov.Missing, ", "))
+	}
+
 	logVerbose("Aggregated %d issues across %d tools", aggregatedReport.Summary.TotalIssues, aggregatedReport.Summary.TotalTools)
 
-	// Step 2: Add trend analysis if storage is enabled and previous runs exist
-	if pcfg.Store {
+	// Step 1.5: Honor triage decisions (acknowledged, suppressed, owners)
+	if pcfg.StorageDir != "" {
+		if err := applyTriage(agg, aggregatedReport, pcfg.StorageDir); err != nil {
+			logError("Failed to apply triage: %v", err)
+			return err
+		}
+	}
+
+	// Step 2: Add trend analysis if storage is enabled and previous runs exist.
+	// Notifications compare against the previous run too.
+	var previousReport *models.AggregatedReport
+	if pcfg.Store || pcfg.Notifications.Enabled() {
 		storagePath, err := getStoragePath(pcfg.StorageDir)
 		if err != nil {
 			logError("Failed to get storage path: %v", err)
 			return err
 		}
 
-		store := storage.NewLocal(storagePath)
+		store, err := openStore(storagePath, pcfg.Storage, pcfg.Repo)
+		if err != nil {
+			return err
+		}
 
-		if previousReport, err := store.GetLatestRun(); err == nil {
-			logVerbose("Found previous run from %s", previousReport.Timestamp)
-			agg.AddTrend(aggregatedReport, previousReport)
+		var previous *models.AggregatedReport
+		err = traced(ctx, "storage.load_previous", func() (err error) {
+			previous, err = store.GetLatestRun()
+			return err
+		})
+		if err == nil {
+			logVerbose("Found previous run from %s", previous.Timestamp)
+			previousReport = previous
+			if pcfg.Store {
+				agg.AddTrend(aggregatedReport, previousReport)
+			}
+		} else if tampered(err) {
+			logError("Refusing tampered history: %v", err)
+			return err
 		} else {
 			logDebug("No previous run found: %v", err)
 		}
@@ -63,10 +142,27 @@ func RunPipeline(toolReports []models.ToolReport, pcfg PipelineConfig) error {
 
 	// Step 3: Generate recommendations
 	recGen := aggregator.NewRecommendationGenerator()
+	if pcfg.RemediationFile != "" {
+		kb, err := remediation.Load(pcfg.RemediationFile)
+		if err != nil {
+			logError("Failed to load remediation file: %v", err)
+			return err
+		}
+		recGen.SetKnowledgeBase(kb)
+	}
 	aggregatedReport.Recommendations = recGen.GenerateRecommendations(aggregatedReport)
 
 	logVerbose("Generated %d recommendations", len(aggregatedReport.Recommendations))
 
+	// Step 3.5: Correlate related findings across tools
+	aggregatedReport.Incidents = aggregator.NewCorrelator().Correlate(aggregatedReport)
+
+	logVerbose("Correlated %d cross-tool incidents", len(aggregatedReport.Incidents))
+
+	if pcfg.ShowRedacted {
+		return showRedacted(aggregatedReport, toolReports, pcfg)
+	}
+
 	// Step 4: Store if enabled
 	if pcfg.Store {
 		storagePath, err := getStoragePath(pcfg.StorageDir)
@@ -75,40 +171,55 @@ func RunPipeline(toolReports []models.ToolReport, pcfg PipelineConfig) error {
 			return err
 		}
 
-		store := storage.NewLocal(storagePath)
-
-		if err := store.EnsureDirectoryExists(); err != nil {
-			logError("Failed to create storage directory: %v", err)
+		store, err := openStore(storagePath, pcfg.Storage, pcfg.Repo)
+		if err != nil {
 			return err
 		}
 
-		if err := store.SaveAggregatedReport(aggregatedReport); err != nil {
+		if err := traced(ctx, "storage.save", func() error {
+			return store.SaveAggregatedReport(aggregatedReport)
+		}); err != nil {
 			logError("Failed to store report: %v", err)
 			return err
 		}
 
-		logVerbose("Stored report in: %s", storagePath)
+		logVerbose("Stored report in: %s", store.Location())
 	}
 
 	// Step 5: Submit to API if license key is configured
 	if pcfg.LicenseKey != "" {
-		if err := submitToAPI(aggregatedReport, pcfg); err != nil {
+		// Step 5.0: Retry uploads queued by earlier runs (non-fatal)
+		if err := traced(ctx, "api.retry_outbox", func() error {
+			return retryOutbox(pcfg)
+		}); err != nil {
+			logVerbose("Queued upload retry skipped: %v", err)
+		}
+
+		if err := traced(ctx, "api.submit_report", func() error {
+			return submitToAPI(aggregatedReport, pcfg)
+		}); err != nil {
 			logError("API upload failed: %v", err)
 			return err
 		}
 
 		// Step 5.5: Submit user activity from mongospectre findings (non-fatal)
-		if err := submitUserActivity(toolReports, pcfg); err != nil {
+		if err := traced(ctx, "api.submit_user_activity", func() error {
+			return submitUserActivity(toolReports, pcfg)
+		}); err != nil {
 			logVerbose("User activity sync skipped: %v", err)
 		}
 
 		// Step 5.6: Submit finding lifecycle data (non-fatal)
-		if err := submitFindings(toolReports, pcfg); err != nil {
+		if err := traced(ctx, "api.submit_findings", func() error {
+			return submitFindings(toolReports, aggregatedReport.Timestamp, pcfg)
+		}); err != nil {
 			logVerbose("Finding lifecycle sync skipped: %v", err)
 		}
 
 		// Step 5.7: Submit waste tracking data (non-fatal)
-		if err := submitWaste(toolReports, pcfg); err != nil {
+		if err := traced(ctx, "api.submit_waste", func() error {
+			return submitWaste(toolReports, aggregatedReport.Timestamp, pcfg)
+		}); err != nil {
 			logVerbose("Waste tracking sync skipped: %v", err)
 		}
 	}
@@ -120,53 +231,27 @@ func RunPipeline(toolReports []models.ToolReport, pcfg PipelineConfig) error {
 	}
 
 	// Step 7: Policy enforcement (if .spectrehub-policy.yaml exists)
-	if policyPath := policy.FindPolicyFile(); policyPath != "" {
-		logVerbose("Found policy file: %s", policyPath)
-
-		pol, err := policy.LoadFromFile(policyPath)
-		if err != nil {
-			logError("Failed to load policy: %v", err)
-			return err
-		}
+	violations, err := enforcePolicy(aggregatedReport, toolReports, pcfg)
+	if err != nil {
+		logError("Failed to load policy: %v", err)
+		return err
+	}
 
-		if pol != nil {
-			result := pol.Evaluate(aggregatedReport)
-			if !result.Pass {
-				for _, v := range result.Violations {
-					logError("Policy violation [%s]: %s", v.Rule, v.Message)
-				}
-				return &ThresholdExceededError{
-					IssueCount: len(result.Violations),
-					Threshold:  0,
-				}
-			}
-			logVerbose("Policy check passed")
-
-			// Step 7.1: SLA policy enforcement (requires API)
-			if pcfg.LicenseKey != "" {
-				if slaViolations := evaluateSLAPolicy(pol, pcfg); slaViolations != nil && !slaViolations.Pass {
-					for _, v := range slaViolations.Violations {
-						logError("SLA policy violation [%s]: %s", v.Rule, v.Message)
-					}
-					return &ThresholdExceededError{
-						IssueCount: len(slaViolations.Violations),
-						Threshold:  0,
-					}
-				}
-			}
+	// Step 7.5: Notify about regressions (non-fatal)
+	if pcfg.Notifications.Enabled() {
+		sendNotifications(notify.Run{
+			Repo:        pcfg.Repo,
+			Report:      aggregatedReport,
+			Previous:    previousReport,
+			Violations:  violations,
+			FailedTools: failedTools(pcfg.Executions),
+		}, pcfg)
+	}
 
-			// Step 7.2: User activity policy enforcement (requires API + mongospectre)
-			if pcfg.LicenseKey != "" {
-				if uaResult := evaluateUserActivityPolicy(pol, toolReports, pcfg); uaResult != nil && !uaResult.Pass {
-					for _, v := range uaResult.Violations {
-						logError("User activity policy violation [%s]: %s", v.Rule, v.Message)
-					}
-					return &ThresholdExceededError{
-						IssueCount: len(uaResult.Violations),
-						Threshold:  0,
-					}
-				}
-			}
+	if len(violations) > 0 {
+		return &ThresholdExceededError{
+			IssueCount: len(violations),
+			Threshold:  0,
 		}
 	}
 
@@ -182,6 +267,174 @@ func RunPipeline(toolReports []models.ToolReport, pcfg PipelineConfig) error {
 	return nil
 }
 
+// redactionPreview is what a run would send to the API, printed by
+// --show-redacted.
+type redactionPreview struct {
+	Report       *models.AggregatedReport        `json:"report"`
+	Findings     []apiclient.FindingsPayload     `json:"findings,omitempty"`
+	Waste        []apiclient.WastePayload        `json:"waste,omitempty"`
+	UserActivity []apiclient.UserActivityPayload `json:"user_activity,omitempty"`
+}
+
+// showRedacted prints the report and uploads of a run with the redaction
+// profile applied. Nothing is stored, uploaded or notified.
+func showRedacted(report *models.AggregatedReport, toolReports []models.ToolReport, pcfg PipelineConfig) error {
+	redactor, err := redact.New(pcfg.Redaction)
+	if err != nil {
+		return &ValidationError{Message: err.Error()}
+	}
+	if redactor == nil {
+		fmt.Fprintf(os.Stderr, "Warning: no redaction profile configured, showing the data unredacted\n")
+	}
+
+	preview := redactionPreview{Report: redactor.Report(report)}
+	for _, p := range findingsPayloads(toolReports) {
+		preview.Findings = append(preview.Findings, redactor.Findings(p))
+	}
+	for _, p := range wastePayloads(toolReports) {
+		preview.Waste = append(preview.Waste, redactor.Waste(p))
+	}
+	for _, p := range userActivityPayloads(toolReports) {
+		preview.UserActivity = append(preview.UserActivity, redactor.UserActivity(p))
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(preview)
+}
+
+// traced runs fn inside a span named name.
+func traced(ctx context.Context, name string, fn func() error) error {
+	_, span := tracing.Start(ctx, name)
+	err := fn()
+	tracing.End(span, err)
+	return err
+}
+
+// enforcePolicy evaluates .spectrehub-policy.yaml, if present, and returns
+// the violations that fail the run. Base rules are checked first; SLA and
+// user activity rules (which need the API) only when those pass. The error
+// is for a policy file that cannot be loaded.
+func enforcePolicy(report *models.AggregatedReport, toolReports []models.ToolReport, pcfg PipelineConfig) ([]policy.Violation, error) {
+	policyPath := policy.FindPolicyFile()
+	if policyPath == "" {
+		return nil, nil
+	}
+	logVerbose("Found policy file: %s", policyPath)
+
+	pol, err := policy.LoadFromFile(policyPath)
+	if err != nil {
+		return nil, err
+	}
+	if pol == nil {
+		return nil, nil
+	}
+
+	result := pol.Evaluate(report)
+	if !result.Pass {
+		for _, v := range result.Violations {
+			logError("Policy violation [%s]: %s", v.Rule, v.Message)
+		}
+		return result.Violations, nil
+	}
+	logVerbose("Policy check passed")
+
+	// Step 7.1: SLA policy enforcement (requires API)
+	if pcfg.LicenseKey != "" {
+		if slaViolations := evaluateSLAPolicy(pol, pcfg); slaViolations != nil && !slaViolations.Pass {
+			for _, v := range slaViolations.Violations {
+				logError("SLA policy violation [%s]: %s", v.Rule, v.Message)
+			}
+			return slaViolations.Violations, nil
+		}
+	}
+
+	// Step 7.2: User activity policy enforcement (requires API + mongospectre)
+	if pcfg.LicenseKey != "" {
+		if uaResult := evaluateUserActivityPolicy(pol, toolReports, pcfg); uaResult != nil && !uaResult.Pass {
+			for _, v := range uaResult.Violations {
+				logError("User activity policy violation [%s]: %s", v.Rule, v.Message)
+			}
+			return uaResult.Violations, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// sendNotifications delivers alerts for the run. Failures are logged and
+// never fail the run; undelivered events are retried on the next run.
+func sendNotifications(run notify.Run, pcfg PipelineConfig) {
+	redactor, err := redact.New(pcfg.Redaction)
+	if err != nil {
+		logError("Notifications skipped: %v", err)
+		return
+	}
+	run.Violations = redactor.Violations(run.Violations, run.Report)
+	run.Report = redactor.Report(run.Report)
+	run.Previous = redactor.Report(run.Previous)
+
+	var statePath string
+	if storagePath, err := getStoragePath(pcfg.StorageDir); err == nil {
+		statePath = filepath.Join(storagePath, notify.StateFileName)
+	}
+
+	deliveries, err := notify.New(pcfg.Notifications, statePath).Notify(context.Background(), run)
+	for _, d := range deliveries {
+		switch {
+		case d.Err != nil:
+			logError("Notification to %s failed: %v", d.Channel, d.Err)
+		case d.Sent > 0:
+			fmt.Fprintf(os.Stderr, "Notified %s (%d event(s))\n", d.Channel, d.Sent)
+		default:
+			logVerbose("Notification to %s skipped: %d event(s) already sent", d.Channel, d.Skipped)
+		}
+	}
+	if err != nil {
+		logError("Failed to save notification state: %v", err)
+	}
+}
+
+// failedTools lists the executions that did not produce a report.
+func failedTools(execs []models.ToolExecution) []notify.ToolFailure {
+	var failures []notify.ToolFailure
+	for _, e := range execs {
+		if !e.Success {
+			failures = append(failures, notify.ToolFailure{Tool: e.Tool, Error: e.Error})
+		}
+	}
+	return failures
+}
+
+// checkFreshness flags input reports older than pcfg.MaxReportAge. It
+// warns, or with FailStale returns a ValidationError naming them.
+func checkFreshness(report *models.AggregatedReport, pcfg PipelineConfig) error {
+	stale := aggregator.MarkStale(report, pcfg.MaxReportAge)
+	if len(stale) == 0 {
+		return nil
+	}
+	descs := make([]string, 0, len(stale))
+	for _, f := range stale {
+		descs = append(descs, fmt.Sprintf("%s (%s old)", f.Tool, f.Age().Round(time.Minute)))
+	}
+	msg := fmt.Sprintf("stale reports older than %s: %s", pcfg.MaxReportAge, strings.Join(descs, ", "))
+	if pcfg.FailStale {
+		logError("%s", msg)
+		return &ValidationError{Message: msg}
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+	return nil
+}
+
+// expectedTools lists the configured tools and every tool that was run.
+func expectedTools(pcfg PipelineConfig) []string {
+	tools := append([]string(nil), pcfg.ExpectedTools...)
+	for _, e := range pcfg.Executions {
+		tools = append(tools, e.Tool)
+	}
+	return tools
+}
+
 // generateOutput generates the output in the specified format(s).
 func generateOutput(report *models.AggregatedReport, format, outputPath string) error {
 	var writer *os.File
@@ -239,6 +492,25 @@ func generateOutput(report *models.AggregatedReport, format, outputPath string)
 	}
 }
 
+// applyTriage loads the triage store from the storage directory and applies
+// it to the report. Suppressed issues are set aside and the summary is
+// recomputed without them.
+func applyTriage(agg *aggregator.Aggregator, report *models.AggregatedReport, storageDir string) error {
+	storagePath, err := getStoragePath(storageDir)
+	if err != nil {
+		return err
+	}
+	store, err := triage.OpenDir(storagePath)
+	if err != nil {
+		return err
+	}
+	if n := store.Apply(report, time.Now()); n > 0 {
+		agg.Recalculate(report)
+		logVerbose("Suppressed %d issues per %s", n, store.Path())
+	}
+	return nil
+}
+
 // getStoragePath resolves the storage path, expanding ~ and converting to absolute.
 func getStoragePath(storageDir string) (string, error) {
 	if len(storageDir) >= 2 && storageDir[:2] == "~/" {
@@ -257,6 +529,31 @@ func getStoragePath(storageDir string) (string, error) {
 	return absPath, nil
 }
 
+// storageConfig returns scfg with its URL replaced by a --storage-url flag.
+func storageConfig(scfg storage.Config, flagURL string) storage.Config {
+	if flagURL != "" {
+		scfg.URL = flagURL
+	}
+	return scfg
+}
+
+// openStore returns the run storage: storagePath, or the bucket scfg.URL
+// points to with the runs of repo under their own prefix. Runs are
+// encrypted, signed and verified with the keys scfg points to.
+func openStore(storagePath string, scfg storage.Config, repo string) (storage.Storage, error) {
+	store, err := scfg.Open(storagePath, repo)
+	if err != nil {
+		return nil, &ValidationError{Message: err.Error()}
+	}
+	return store, nil
+}
+
+// tampered reports whether err is a stored run that failed verification.
+func tampered(err error) bool {
+	var integrityErr *storage.IntegrityError
+	return errors.As(err, &integrityErr)
+}
+
 // submitUserActivity extracts user activity data from mongospectre findings
 // and sends it to the SpectreHub API. Returns nil if no user data is found
 // or if the license tier doesn't support user activity.
@@ -275,6 +572,32 @@ func submitUserActivity(toolReports []models.ToolReport, pcfg PipelineConfig) er
 		return nil
 	}
 
+	redactor, err := redact.New(pcfg.Redaction)
+	if err != nil {
+		return err
+	}
+
+	for _, payload := range userActivityPayloads(toolReports) {
+		payload = redactor.UserActivity(payload)
+		if err := client.SubmitUserActivity(payload); err != nil {
+			return fmt.Errorf("submit user activity: %w", err)
+		}
+
+		fmt.Fprintf(os.Stderr, "User activity synced (%d users for %s)\n",
+			len(payload.Users), payload.TargetHash)
+
+		// Fetch and display enriched summary from API (non-fatal).
+		if summary, err := client.GetUserActivitySummary(payload.TargetHash); err == nil && summary != nil {
+			printUserActivitySummary(summary)
+		}
+	}
+	return nil
+}
+
+// userActivityPayloads builds the user activity uploads of the mongospectre
+// spectre/v1 reports.
+func userActivityPayloads(toolReports []models.ToolReport) []apiclient.UserActivityPayload {
+	var payloads []apiclient.UserActivityPayload
 	for _, tr := range toolReports {
 		v1, ok := tr.RawData.(*models.SpectreV1Report)
 		if !ok || v1 == nil || v1.Tool != "mongospectre" {
@@ -286,24 +609,12 @@ func submitUserActivity(toolReports []models.ToolReport, pcfg PipelineConfig) er
 			continue
 		}
 
-		payload := apiclient.UserActivityPayload{
+		payloads = append(payloads, apiclient.UserActivityPayload{
 			TargetHash: v1.Target.URIHash,
 			Users:      entries,
-		}
-
-		if err := client.SubmitUserActivity(payload); err != nil {
-			return fmt.Errorf("submit user activity: %w", err)
-		}
-
-		fmt.Fprintf(os.Stderr, "User activity synced (%d users for %s)\n",
-			len(entries), v1.Target.URIHash)
-
-		// Fetch and display enriched summary from API (non-fatal).
-		if summary, err := client.GetUserActivitySummary(v1.Target.URIHash); err == nil && summary != nil {
-			printUserActivitySummary(summary)
-		}
+		})
 	}
-	return nil
+	return payloads
 }
 
 // printUserActivitySummary prints an enriched user activity summary table to stderr.
@@ -402,9 +713,10 @@ func evaluateUserActivityPolicy(pol *policy.Policy, toolReports []models.ToolRep
 }
 
 // submitFindings extracts finding lifecycle data from all spectre/v1 tool
-// reports and sends each batch to the SpectreHub API. Returns nil if no
-// findings are found or the license key is empty.
-func submitFindings(toolReports []models.ToolReport, pcfg PipelineConfig) error {
+// reports and sends each batch to the SpectreHub API. A batch that cannot be
+// delivered now is queued in the outbox. Returns nil if no findings are
+// found or the license key is empty.
+func submitFindings(toolReports []models.ToolReport, runTimestamp time.Time, pcfg PipelineConfig) error {
 	if strings.TrimSpace(pcfg.LicenseKey) == "" {
 		return nil
 	}
@@ -419,6 +731,32 @@ func submitFindings(toolReports []models.ToolReport, pcfg PipelineConfig) error
 		return nil
 	}
 
+	redactor, err := redact.New(pcfg.Redaction)
+	if err != nil {
+		return err
+	}
+
+	for _, payload := range findingsPayloads(toolReports) {
+		payload = redactor.Findings(payload)
+		resp, err := client.SubmitFindings(payload)
+		if err != nil {
+			if queueUpload(pcfg, outbox.KindFindings, payload.Tool, runTimestamp, payload, err) {
+				continue
+			}
+			return fmt.Errorf("submit findings for %s: %w", payload.Tool, err)
+		}
+		if resp != nil {
+			fmt.Fprintf(os.Stderr, "Findings synced for %s (stored=%d, resolved=%d)\n",
+				resp.Tool, resp.Stored, resp.Resolved)
+		}
+	}
+	return nil
+}
+
+// findingsPayloads builds one finding lifecycle upload per spectre/v1 report
+// with findings.
+func findingsPayloads(toolReports []models.ToolReport) []apiclient.FindingsPayload {
+	var payloads []apiclient.FindingsPayload
 	for _, tr := range toolReports {
 		v1, ok := tr.RawData.(*models.SpectreV1Report)
 		if !ok || v1 == nil {
@@ -430,27 +768,19 @@ func submitFindings(toolReports []models.ToolReport, pcfg PipelineConfig) error
 			continue
 		}
 
-		payload := apiclient.FindingsPayload{
+		payloads = append(payloads, apiclient.FindingsPayload{
 			Tool:     v1.Tool,
 			Findings: entries,
-		}
-
-		resp, err := client.SubmitFindings(payload)
-		if err != nil {
-			return fmt.Errorf("submit findings for %s: %w", v1.Tool, err)
-		}
-		if resp != nil {
-			fmt.Fprintf(os.Stderr, "Findings synced for %s (stored=%d, resolved=%d)\n",
-				resp.Tool, resp.Stored, resp.Resolved)
-		}
+		})
 	}
-	return nil
+	return payloads
 }
 
 // submitWaste extracts waste tracking data from awsspectre, gcpspectre, and
-// azurespectre tool reports and sends each batch to the SpectreHub API.
-// Returns nil if no waste data is found or the license key is empty.
-func submitWaste(toolReports []models.ToolReport, pcfg PipelineConfig) error {
+// azurespectre tool reports and sends each batch to the SpectreHub API. A
+// batch that cannot be delivered now is queued in the outbox. Returns nil
+// if no waste data is found or the license key is empty.
+func submitWaste(toolReports []models.ToolReport, runTimestamp time.Time, pcfg PipelineConfig) error {
 	if strings.TrimSpace(pcfg.LicenseKey) == "" {
 		return nil
 	}
@@ -465,6 +795,32 @@ func submitWaste(toolReports []models.ToolReport, pcfg PipelineConfig) error {
 		return nil
 	}
 
+	redactor, err := redact.New(pcfg.Redaction)
+	if err != nil {
+		return err
+	}
+
+	for _, payload := range wastePayloads(toolReports) {
+		payload = redactor.Waste(payload)
+		resp, err := client.SubmitWaste(payload)
+		if err != nil {
+			if queueUpload(pcfg, outbox.KindWaste, payload.Tool, runTimestamp, payload, err) {
+				continue
+			}
+			return fmt.Errorf("submit waste for %s: %w", payload.Tool, err)
+		}
+		if resp != nil {
+			fmt.Fprintf(os.Stderr, "Waste synced for %s (stored=%d, cleaned=%d)\n",
+				resp.Tool, resp.Stored, resp.Cleaned)
+		}
+	}
+	return nil
+}
+
+// wastePayloads builds one waste tracking upload per spectre/v1 report with
+// cost findings.
+func wastePayloads(toolReports []models.ToolReport) []apiclient.WastePayload {
+	var payloads []apiclient.WastePayload
 	for _, tr := range toolReports {
 		v1, ok := tr.RawData.(*models.SpectreV1Report)
 		if !ok || v1 == nil {
@@ -476,26 +832,19 @@ func submitWaste(toolReports []models.ToolReport, pcfg PipelineConfig) error {
 			continue
 		}
 
-		payload := apiclient.WastePayload{
+		payloads = append(payloads, apiclient.WastePayload{
 			Tool:    v1.Tool,
 			Entries: entries,
-		}
-
-		resp, err := client.SubmitWaste(payload)
-		if err != nil {
-			return fmt.Errorf("submit waste for %s: %w", v1.Tool, err)
-		}
-		if resp != nil {
-			fmt.Fprintf(os.Stderr, "Waste synced for %s (stored=%d, cleaned=%d)\n",
-				resp.Tool, resp.Stored, resp.Cleaned)
-		}
+		})
 	}
-	return nil
+	return payloads
 }
 
 // submitToAPI sends the aggregated report to the SpectreHub API.
-// Returns an error if the upload fails — callers should treat this as fatal
-// so CI pipelines don't silently pass with untracked results.
+// A transient failure (API unreachable, timeout, 5xx, rate limited) queues the report
+// in the outbox when a storage directory is configured. Any other failure
+// returns an error — callers should treat this as fatal so CI pipelines
+// don't silently pass with untracked results.
 func submitToAPI(report *models.AggregatedReport, pcfg PipelineConfig) error {
 	if strings.TrimSpace(pcfg.LicenseKey) == "" {
 		return nil
@@ -514,7 +863,11 @@ func submitToAPI(report *models.AggregatedReport, pcfg PipelineConfig) error {
 		return nil
 	}
 
-	rawJSON, _ := json.Marshal(report)
+	redactor, err := redact.New(pcfg.Redaction)
+	if err != nil {
+		return err
+	}
+	rawJSON, _ := json.Marshal(redactor.Report(report))
 
 	payload := apiclient.ReportPayload{
 		Repo:       pcfg.Repo,
@@ -536,6 +889,9 @@ func submitToAPI(report *models.AggregatedReport, pcfg PipelineConfig) error {
 	}
 
 	if err := client.SubmitReport(payload); err != nil {
+		if queueUpload(pcfg, outbox.KindReport, "", report.Timestamp, payload, err) {
+			return nil
+		}
 		return fmt.Errorf("failed to sync report to API: %w", err)
 	}
 
@@ -1,16 +1,31 @@
 package cli
 
 import (
+	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/ppiankov/spectrehub/internal/apiclient"
+	"github.com/ppiankov/spectrehub/internal/config"
 	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/notify"
+	"github.com/ppiankov/spectrehub/internal/policy"
+	"github.com/ppiankov/spectrehub/internal/redact"
+	"github.com/ppiankov/spectrehub/internal/storage"
+	"github.com/ppiankov/spectrehub/internal/tracing"
+	"github.com/ppiankov/spectrehub/internal/tracing/tracingtest"
+	"github.com/ppiankov/spectrehub/internal/triage"
 )
 
 // --- getStoragePath tests ---
@@ -264,3 +279,570 @@ func TestSubmitUserActivityNonV1Report(t *testing.T) {
 		t.Fatalf("expected nil for non-v1 report, got %v", err)
 	}
 }
+
+func TestRunPipelineHonorsTriage(t *testing.T) {
+	storageDir := t.TempDir()
+	withTestConfig(t, &config.Config{})
+
+	store, err := triage.OpenDir(storageDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	suppressed := models.NormalizedIssue{Tool: "vaultspectre", Category: models.StatusMissing, Resource: "secret/a"}
+	store.Suppress(suppressed, "decommissioned service", time.Time{}, time.Now())
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	toolReports := []models.ToolReport{{
+		Tool:        "vaultspectre",
+		Version:     "0.1.0",
+		Timestamp:   time.Now(),
+		IsSupported: true,
+		RawData: &models.VaultReport{
+			Tool:    "vaultspectre",
+			Summary: models.VaultSummary{TotalReferences: 5, StatusMissing: 2},
+			Secrets: map[string]*models.SecretInfo{
+				"secret/a": {Status: "missing"},
+				"secret/b": {Status: "missing"},
+			},
+		},
+	}}
+
+	err = RunPipeline(toolReports, PipelineConfig{
+		Format:     "json",
+		Output:     filepath.Join(t.TempDir(), "pipeline.json"),
+		Store:      true,
+		StorageDir: storageDir,
+		Threshold:  1, // two issues would exceed it; one suppressed does not
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline: %v", err)
+	}
+
+	latest, err := storage.NewLocal(storageDir).GetLatestRun()
+	if err != nil {
+		t.Fatalf("GetLatestRun: %v", err)
+	}
+	if latest.Summary.TotalIssues != 1 || len(latest.Suppressed) != 1 {
+		t.Fatalf("expected 1 issue and 1 suppressed, got %d/%d", latest.Summary.TotalIssues, len(latest.Suppressed))
+	}
+	if latest.Suppressed[0].Resource != "secret/a" || latest.Suppressed[0].Triage != models.TriageSuppressed {
+		t.Errorf("unexpected suppressed issue: %+v", latest.Suppressed[0])
+	}
+}
+
+func TestRunPipelineSendsNotifications(t *testing.T) {
+	storageDir := t.TempDir()
+	withTestConfig(t, &config.Config{})
+
+	var posts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		posts = append(posts, body["text"])
+	}))
+	defer srv.Close()
+
+	toolReports := []models.ToolReport{{
+		Tool:        "vaultspectre",
+		Version:     "0.1.0",
+		Timestamp:   time.Now(),
+		IsSupported: true,
+		RawData: &models.VaultReport{
+			Tool:    "vaultspectre",
+			Summary: models.VaultSummary{TotalReferences: 1, StatusMissing: 1},
+			Secrets: map[string]*models.SecretInfo{"secret/a": {Status: "missing"}},
+		},
+	}}
+	pcfg := PipelineConfig{
+		Format:     "json",
+		Output:     filepath.Join(t.TempDir(), "pipeline.json"),
+		Store:      true,
+		StorageDir: storageDir,
+		Repo:       "org/app",
+		Notifications: notify.Config{
+			Channels: []notify.Channel{{Name: "ops", Type: notify.TypeSlack, URL: srv.URL, Triggers: []string{notify.TriggerNewCritical}}},
+		},
+	}
+
+	if err := RunPipeline(toolReports, pcfg); err != nil {
+		t.Fatalf("RunPipeline: %v", err)
+	}
+	if len(posts) != 1 || !strings.Contains(posts[0], "1 new critical finding") || !strings.Contains(posts[0], "org/app") {
+		t.Fatalf("expected one critical notification, got %q", posts)
+	}
+
+	// The same finding on the next run is neither new nor re-sent.
+	if err := RunPipeline(toolReports, pcfg); err != nil {
+		t.Fatalf("RunPipeline: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Errorf("expected no notification for an unchanged run, got %d", len(posts))
+	}
+	if _, err := os.Stat(filepath.Join(storageDir, notify.StateFileName)); err != nil {
+		t.Errorf("expected notification state file: %v", err)
+	}
+}
+
+func TestRunPipelineRecordsCoverage(t *testing.T) {
+	withTestConfig(t, &config.Config{})
+	toolReports := []models.ToolReport{{
+		Tool:        "vaultspectre",
+		Timestamp:   time.Now(),
+		IsSupported: true,
+		RawData:     &models.VaultReport{Tool: "vaultspectre", Summary: models.VaultSummary{TotalReferences: 1}},
+	}}
+	out := filepath.Join(t.TempDir(), "pipeline.json")
+	pcfg := PipelineConfig{
+		Format:        "json",
+		Output:        out,
+		ExpectedTools: []string{"s3spectre"},
+		Executions: []models.ToolExecution{
+			{Tool: "vaultspectre", Success: true},
+			{Tool: "kafkaspectre", Error: "timed out after 5m0s", FailureClass: "timeout"},
+		},
+	}
+	if err := RunPipeline(toolReports, pcfg); err != nil {
+		t.Fatalf("RunPipeline: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report models.AggregatedReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Coverage == nil || strings.Join(report.Coverage.Missing, ",") != "kafkaspectre,s3spectre" {
+		t.Fatalf("unexpected coverage: %+v", report.Coverage)
+	}
+	if !report.Summary.Partial {
+		t.Error("expected partial score")
+	}
+}
+
+func TestRunPipelineContextSpans(t *testing.T) {
+	rec := tracingtest.Record(t)
+	withTestConfig(t, &config.Config{})
+
+	toolReports := []models.ToolReport{{
+		Tool:        "vaultspectre",
+		Timestamp:   time.Now(),
+		IsSupported: true,
+		RawData:     &models.VaultReport{Tool: "vaultspectre"},
+	}}
+
+	ctx, parent := tracing.Start(context.Background(), "spectrehub.collect")
+	err := RunPipelineContext(ctx, toolReports, PipelineConfig{
+		Format:     "json",
+		Output:     filepath.Join(t.TempDir(), "out.json"),
+		Store:      true,
+		StorageDir: t.TempDir(),
+	})
+	parent.End()
+	if err != nil {
+		t.Fatalf("RunPipelineContext: %v", err)
+	}
+
+	for _, name := range []string{"aggregator.aggregate", "storage.load_previous", "storage.save"} {
+		s := tracingtest.Find(rec, name)
+		if s == nil {
+			t.Errorf("missing span %s", name)
+			continue
+		}
+		if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s is not a child of the command span", name)
+		}
+	}
+	// No previous run yet: the lookup span records the miss.
+	if s := tracingtest.Find(rec, "storage.load_previous"); s != nil && s.Status().Description == "" {
+		t.Error("expected storage.load_previous to record the missing run")
+	}
+	if tracingtest.Find(rec, "api.submit_report") != nil {
+		t.Error("unexpected API span without a license key")
+	}
+}
+
+func TestRunPipelineInvalidTriageStore(t *testing.T) {
+	storageDir := t.TempDir()
+	withTestConfig(t, &config.Config{})
+	if err := os.WriteFile(filepath.Join(storageDir, triage.FileName), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := RunPipeline(nil, PipelineConfig{Format: "json", Output: filepath.Join(t.TempDir(), "out.json"), StorageDir: storageDir})
+	if err == nil || !strings.Contains(err.Error(), "triage") {
+		t.Errorf("expected triage store error, got %v", err)
+	}
+}
+
+// --- redaction tests ---
+
+func TestSubmitToAPIRedacted(t *testing.T) {
+	var rawJSON string
+	ts := newIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload apiclient.ReportPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		rawJSON = payload.RawJSON
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	report := minimalReport()
+	report.Issues[0].Evidence = "secret/db is referenced by deploy.yaml"
+	report.ToolReports["vaultspectre"] = models.ToolReport{Tool: "vaultspectre", RawData: map[string]string{"path": "secret/db"}}
+
+	err := submitToAPI(report, PipelineConfig{
+		LicenseKey: validTestLicenseKey,
+		APIURL:     ts.URL,
+		Repo:       "org/test",
+		Redaction:  redact.Config{Resources: redact.ResourcesHash, Key: "k", StripEvidence: true},
+	})
+	if err != nil {
+		t.Fatalf("submitToAPI: %v", err)
+	}
+	if rawJSON == "" || strings.Contains(rawJSON, "secret/db") || strings.Contains(rawJSON, "deploy.yaml") {
+		t.Errorf("uploaded report is not redacted: %s", rawJSON)
+	}
+	if report.Issues[0].Resource != "secret/db" {
+		t.Error("submitToAPI redacted the report in place")
+	}
+}
+
+func TestRunPipelineShowRedacted(t *testing.T) {
+	withTestConfig(t, &config.Config{})
+	storageDir := t.TempDir()
+
+	pcfg := PipelineConfig{
+		Format:       "json",
+		Store:        true,
+		StorageDir:   storageDir,
+		LicenseKey:   validTestLicenseKey,
+		APIURL:       "http://127.0.0.1:1",
+		Redaction:    redact.Config{Resources: redact.ResourcesMask, Key: "k"},
+		ShowRedacted: true,
+	}
+	output := captureStdout(t, func() {
+		if err := RunPipeline(s3FindingReports(), pcfg); err != nil {
+			t.Errorf("RunPipeline: %v", err)
+		}
+	})
+
+	var preview redactionPreview
+	if err := json.Unmarshal([]byte(output), &preview); err != nil {
+		t.Fatalf("preview is not JSON: %v\n%s", err, output)
+	}
+	if strings.Contains(output, "s3://old") {
+		t.Errorf("preview contains the plain bucket:\n%s", output)
+	}
+	if len(preview.Findings) != 1 || !strings.HasPrefix(preview.Findings[0].Findings[0].ResourceID, "s3://o***#") {
+		t.Errorf("findings = %+v", preview.Findings)
+	}
+
+	// A preview neither stores nor uploads.
+	if runs, _ := filepath.Glob(filepath.Join(storageDir, "runs", "*")); len(runs) != 0 {
+		t.Errorf("preview stored runs: %v", runs)
+	}
+	if n, _ := queuedUploads(storageDir); n != 0 {
+		t.Errorf("preview queued %d upload(s)", n)
+	}
+}
+
+func TestSendNotificationsRedacted(t *testing.T) {
+	var posts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		posts = append(posts, body["text"])
+	}))
+	defer srv.Close()
+
+	sendNotifications(notify.Run{Report: minimalReport()}, PipelineConfig{
+		StorageDir: t.TempDir(),
+		Notifications: notify.Config{
+			Channels: []notify.Channel{{Name: "ops", Type: notify.TypeSlack, URL: srv.URL}},
+		},
+		Redaction: redact.Config{Resources: redact.ResourcesHash, Key: "k"},
+	})
+	if len(posts) != 1 || strings.Contains(posts[0], "secret/db") || !strings.Contains(posts[0], "hmac-") {
+		t.Errorf("notification is not redacted: %q", posts)
+	}
+}
+
+func TestSendNotificationsRedactsCustomRuleMessages(t *testing.T) {
+	var posts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		posts = append(posts, body["text"])
+	}))
+	defer srv.Close()
+
+	report := minimalReport()
+	pol := &policy.Policy{Custom: []policy.CustomRule{{
+		Name:    "no-missing-db-secrets",
+		Expr:    `issue.resource.startsWith("secret/")`,
+		Message: "{{.Issue.resource}} is missing",
+	}}}
+	result := pol.Evaluate(report)
+	if result.Pass || !strings.Contains(result.Violations[0].Message, "secret/db") {
+		t.Fatalf("expected a violation naming the resource, got %+v", result)
+	}
+
+	rcfg := redact.Config{Resources: redact.ResourcesHash, Key: "k"}
+	sendNotifications(notify.Run{Report: report, Violations: result.Violations}, PipelineConfig{
+		StorageDir: t.TempDir(),
+		Notifications: notify.Config{
+			Channels: []notify.Channel{{Name: "ops", Type: notify.TypeSlack, URL: srv.URL, Triggers: []string{notify.TriggerPolicyFailed}}},
+		},
+		Redaction: rcfg,
+	})
+	redactor, _ := redact.New(rcfg)
+	want := redactor.Resource("secret/db") + " is missing"
+	if len(posts) != 1 || strings.Contains(posts[0], "secret/db") || !strings.Contains(posts[0], want) {
+		t.Errorf("violation message is not redacted: %q", posts)
+	}
+	if result.Violations[0].Message != "secret/db is missing" {
+		t.Errorf("redaction modified the violations: %+v", result.Violations)
+	}
+}
+
+// --- protected storage tests ---
+
+const testSigningSeedHex = "2222222222222222222222222222222222222222222222222222222222222222"
+
+// setupSignedStorage saves reports signed with testSigningSeedHex and
+// configures the CLI to verify them.
+func setupSignedStorage(t *testing.T, reports ...*models.AggregatedReport) (dir string, scfg storage.Config) {
+	t.Helper()
+	t.Setenv(storage.EncryptionKeyEnv, "")
+	t.Setenv(storage.SigningKeyEnv, testSigningSeedHex)
+	keys, err := scfg.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir = t.TempDir()
+	store := storage.NewLocal(dir)
+	store.SetKeys(keys)
+	for _, r := range reports {
+		if err := store.SaveAggregatedReport(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir, scfg
+}
+
+func TestSignedHistoryRefusesTampering(t *testing.T) {
+	r1 := baseReport(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC), 3)
+	r2 := baseReport(time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC), 2)
+	dir, scfg := setupSignedStorage(t, r1, r2)
+	withTestConfig(t, &config.Config{StorageDir: dir, LastRuns: 7, Storage: scfg})
+
+	oldDiff, oldDiffOut, oldBaseline := diffFormat, diffOutput, diffBaseline
+	oldExport, oldExportOut, oldLast := exportFormat, exportOutput, exportLastN
+	oldSummarize, oldCompare, oldTUI := summarizeFormat, summarizeCompare, summarizeTUI
+	t.Cleanup(func() {
+		diffFormat, diffOutput, diffBaseline = oldDiff, oldDiffOut, oldBaseline
+		exportFormat, exportOutput, exportLastN = oldExport, oldExportOut, oldLast
+		summarizeFormat, summarizeCompare, summarizeTUI = oldSummarize, oldCompare, oldTUI
+	})
+	diffFormat, diffOutput, diffBaseline = "json", filepath.Join(t.TempDir(), "diff.json"), ""
+	exportFormat, exportOutput, exportLastN = "json", filepath.Join(t.TempDir(), "export.json"), 2
+	summarizeFormat, summarizeCompare, summarizeTUI = "text", true, false
+
+	commands := map[string]func() error{
+		"diff":      func() error { return runDiff(nil, nil) },
+		"export":    func() error { return runExport(nil, nil) },
+		"summarize": func() error { return runSummarize(nil, nil) },
+	}
+	for name, run := range commands {
+		captureStdout(t, func() {
+			if err := run(); err != nil {
+				t.Errorf("%s on signed history: %v", name, err)
+			}
+		})
+	}
+	var export ComplianceExport
+	data, _ := os.ReadFile(exportOutput)
+	if err := json.Unmarshal(data, &export); err != nil || !export.SignaturesVerified || export.RunCount != 2 {
+		t.Errorf("export = %+v, %v, want 2 verified runs", export, err)
+	}
+
+	// Lower the issue count of the older run behind the signature's back.
+	path := filepath.Join(dir, "runs", "2026-01-01T10-00-00-aggregated.json")
+	data, _ = os.ReadFile(path)
+	data = []byte(strings.Replace(string(data), `"total_issues": 3`, `"total_issues": 0`, 1))
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, run := range commands {
+		var err error
+		captureStdout(t, func() { err = run() })
+		var integrityErr *storage.IntegrityError
+		if !errors.As(err, &integrityErr) {
+			t.Errorf("%s on tampered history = %v, want IntegrityError", name, err)
+		}
+	}
+}
+
+func TestRunPipelineEncryptedStorage(t *testing.T) {
+	withTestConfig(t, &config.Config{})
+	t.Setenv(storage.SigningKeyEnv, "")
+	t.Setenv(storage.EncryptionKeyEnv, strings.Repeat("11", 32))
+	storageDir := t.TempDir()
+
+	pcfg := PipelineConfig{
+		Format:     "json",
+		Output:     filepath.Join(t.TempDir(), "pipeline.json"),
+		Store:      true,
+		StorageDir: storageDir,
+	}
+	for i := 0; i < 2; i++ {
+		if err := RunPipeline(s3FindingReports(), pcfg); err != nil {
+			t.Fatalf("RunPipeline: %v", err)
+		}
+	}
+
+	// The second run loads the first as its previous run.
+	files, _ := filepath.Glob(filepath.Join(storageDir, "runs", "*"))
+	if len(files) == 0 {
+		t.Fatal("no stored runs")
+	}
+	for _, file := range files {
+		if !strings.HasSuffix(file, "-aggregated.json.enc") {
+			t.Errorf("stored %s, want only encrypted runs", file)
+		}
+		data, _ := os.ReadFile(file)
+		if strings.Contains(string(data), "s3://old") {
+			t.Errorf("%s is readable without the key", file)
+		}
+	}
+
+	store, err := openStore(storageDir, storage.Config{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest, err := store.GetLatestRun(); err != nil || latest.Summary.TotalIssues == 0 {
+		t.Errorf("GetLatestRun() = %v, %v", latest, err)
+	}
+}
+
+// memoryBucket is a minimal path-style S3 stand-in: objects, conditional
+// puts and ListObjectsV2 in one page.
+type memoryBucket struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (b *memoryBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	key := strings.TrimPrefix(r.URL.Path, "/history/")
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	existing, exists := b.objects[key]
+	etag := fmt.Sprintf(`"%x"`, sha256.Sum256(existing))
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		prefix := r.URL.Query().Get("prefix")
+		var out strings.Builder
+		out.WriteString("<ListBucketResult>")
+		for k := range b.objects {
+			if rest, ok := strings.CutPrefix(k, prefix); ok && !strings.Contains(rest, "/") {
+				out.WriteString("<Contents><Key>" + k + "</Key></Contents>")
+			}
+		}
+		out.WriteString("</ListBucketResult>")
+		_, _ = io.WriteString(w, out.String())
+	case r.Method == http.MethodGet:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write(existing)
+	case r.Method == http.MethodPut:
+		if (r.Header.Get("If-None-Match") == "*" && exists) ||
+			(r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != etag) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		b.objects[key] = body
+	case r.Method == http.MethodDelete:
+		delete(b.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestStorageURLSharesHistoryAcrossRunners(t *testing.T) {
+	bucket := &memoryBucket{objects: make(map[string][]byte)}
+	srv := httptest.NewServer(bucket)
+	t.Cleanup(srv.Close)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	scfg := storage.Config{
+		Environment: "prod",
+		S3:          storage.S3Config{Endpoint: srv.URL, Region: "us-east-1", PathStyle: true},
+	}
+	withTestConfig(t, &config.Config{Repo: "org/app", Storage: scfg})
+
+	// A previous CI job stored its run in the bucket.
+	previous, err := storage.NewS3("history", "ci/org/app/prod", scfg.S3, storage.CredentialsFromEnv())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := previous.SaveAggregatedReport(baseReport(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC), 3)); err != nil {
+		t.Fatalf("SaveAggregatedReport: %v", err)
+	}
+
+	// This job starts with an empty storage directory.
+	err = RunPipeline(s3FindingReports(), PipelineConfig{
+		Format:     "json",
+		Output:     filepath.Join(t.TempDir(), "pipeline.json"),
+		Store:      true,
+		StorageDir: t.TempDir(),
+		Repo:       "org/app",
+		Storage:    storageConfig(scfg, "s3://history/ci"),
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline: %v", err)
+	}
+	runs, _ := previous.ListRuns()
+	if len(runs) != 2 {
+		t.Fatalf("bucket holds %d runs, want 2: %v", len(runs), runs)
+	}
+	if _, ok := bucket.objects["ci/org/app/prod/save.lock"]; ok {
+		t.Error("save lock was not released")
+	}
+
+	oldFormat, oldOutput, oldBaseline, oldURL := diffFormat, diffOutput, diffBaseline, diffURL
+	t.Cleanup(func() {
+		diffFormat, diffOutput, diffBaseline, diffURL = oldFormat, oldOutput, oldBaseline, oldURL
+	})
+	outFile := filepath.Join(t.TempDir(), "diff.json")
+	diffFormat, diffOutput, diffBaseline, diffURL = "json", outFile, "", "s3://history/ci"
+	if err := runDiff(nil, nil); err != nil {
+		t.Fatalf("runDiff: %v", err)
+	}
+	data, _ := os.ReadFile(outFile)
+	var result DiffResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if result.Baseline != "2026-01-01 10:00:00" || result.Summary.BaselineTotal != 3 {
+		t.Errorf("diff baseline = %s with %d issues, want the previous job's run", result.Baseline, result.Summary.BaselineTotal)
+	}
+}
+
+func TestOpenStoreInvalidURL(t *testing.T) {
+	_, err := openStore(t.TempDir(), storage.Config{URL: "gs://bucket"}, "")
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+}
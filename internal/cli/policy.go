@@ -0,0 +1,301 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+var (
+	policyFile   string
+	policyFormat string
+)
+
+// maxExplainMatches caps how many findings are listed per rule in text output.
+const maxExplainMatches = 10
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Check, lint and explain the policy file",
+	Long: `Work with the .spectrehub-policy.yaml file outside of a full run.
+
+Subcommands:
+  check    Evaluate the policy against a stored run or report file
+  lint     Strictly validate the policy file (unknown keys, types, ranges)
+  explain  Show which rules apply to which findings
+
+The policy file is located the same way as during run/collect (current
+directory and its parents) unless --policy is given.
+
+Example:
+  spectrehub policy lint
+  spectrehub policy check
+  spectrehub policy check ./report.json --format json
+  spectrehub policy explain --policy ./ci-policy.yaml`,
+}
+
+var policyCheckCmd = &cobra.Command{
+	Use:   "check [report.json]",
+	Short: "Evaluate the policy against a stored run or report file",
+	Long: `Evaluate every policy rule and print pass/fail per rule.
+
+Without an argument the latest stored run is used. Rules that need
+SpectreHub API data (SLA and user activity) are listed as skipped.
+
+Exit codes:
+  0  All rules pass
+  1  One or more rules fail
+  2  Policy file is invalid`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPolicyCheck,
+}
+
+var policyLintCmd = &cobra.Command{
+	Use:   "lint [policy.yaml]",
+	Short: "Strictly validate the policy file",
+	Long: `Validate the policy file against the policy schema and report every
+problem with its line number: unknown keys (with typo suggestions), wrong
+value types, out-of-range values, unknown categories and tools.
+
+Exit codes:
+  0  Policy is valid
+  2  Policy has problems`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPolicyLint,
+}
+
+var policyExplainCmd = &cobra.Command{
+	Use:   "explain [report.json]",
+	Short: "Show which rules apply to which findings",
+	Long: `For every policy rule, list the findings it applies to and whether
+the rule passes. Without an argument the latest stored run is used.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPolicyExplain,
+}
+
+func init() {
+	policyCmd.PersistentFlags().StringVar(&policyFile, "policy", "",
+		"path to policy file (default: .spectrehub-policy.yaml in current or parent directory)")
+	policyCheckCmd.Flags().StringVar(&policyFormat, "format", "text",
+		"output format: text or json")
+	policyExplainCmd.Flags().StringVar(&policyFormat, "format", "text",
+		"output format: text or json")
+
+	policyCmd.AddCommand(policyCheckCmd)
+	policyCmd.AddCommand(policyLintCmd)
+	policyCmd.AddCommand(policyExplainCmd)
+}
+
+// policyCheckResult is the JSON output of `policy check` and `policy explain`.
+type policyCheckResult struct {
+	Policy string              `json:"policy"`
+	Report string              `json:"report"`
+	Pass   bool                `json:"pass"`
+	Rules  []policy.RuleResult `json:"rules"`
+}
+
+func runPolicyCheck(cmd *cobra.Command, args []string) error {
+	result, err := evaluatePolicy(args)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	if policyFormat == "json" {
+		if err := writePolicyJSON(w, result); err != nil {
+			return err
+		}
+	} else {
+		writePolicyCheckText(w, result)
+	}
+
+	if !result.Pass {
+		return &ThresholdExceededError{
+			IssueCount: countFailedRules(result.Rules),
+			Threshold:  0,
+		}
+	}
+	return nil
+}
+
+func runPolicyExplain(cmd *cobra.Command, args []string) error {
+	result, err := evaluatePolicy(args)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	if policyFormat == "json" {
+		return writePolicyJSON(w, result)
+	}
+	writePolicyExplainText(w, result)
+	return nil
+}
+
+func runPolicyLint(cmd *cobra.Command, args []string) error {
+	path := policyFile
+	if len(args) == 1 {
+		path = args[0]
+	}
+	if path == "" {
+		path = policy.FindPolicyFile()
+	}
+	if path == "" {
+		return &ValidationError{Message: "no policy file found (looked for .spectrehub-policy.yaml in current and parent directories)"}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read policy: %w", err)
+	}
+
+	issues := policy.Lint(data)
+	w := cmd.OutOrStdout()
+	if len(issues) == 0 {
+		fmt.Fprintf(w, "OK: %s is a valid policy\n", path)
+		return nil
+	}
+
+	for _, issue := range issues {
+		fmt.Fprintf(w, "%s:%s\n", path, issue)
+	}
+	return &ValidationError{Message: fmt.Sprintf("%s: %d policy problem(s)", path, len(issues))}
+}
+
+// evaluatePolicy loads the policy and the target report and runs every rule.
+func evaluatePolicy(args []string) (*policyCheckResult, error) {
+	path := policyFile
+	if path == "" {
+		path = policy.FindPolicyFile()
+	}
+	if path == "" {
+		return nil, &ValidationError{Message: "no policy file found (looked for .spectrehub-policy.yaml in current and parent directories)"}
+	}
+
+	pol, err := policy.LoadFromFile(path)
+	if err != nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("%v (run 'spectrehub policy lint' for details)", err)}
+	}
+	if pol == nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("policy file not found: %s", path)}
+	}
+
+	report, source, err := loadPolicyTarget(args)
+	if err != nil {
+		return nil, err
+	}
+	logVerbose("Evaluating policy %s against %s", path, source)
+
+	rules := pol.Explain(report)
+	return &policyCheckResult{
+		Policy: path,
+		Report: source,
+		Pass:   countFailedRules(rules) == 0,
+		Rules:  rules,
+	}, nil
+}
+
+// loadPolicyTarget returns the report named on the command line, or the
+// latest stored run, together with a label describing where it came from.
+func loadPolicyTarget(args []string) (*models.AggregatedReport, string, error) {
+	if len(args) == 1 {
+		report, err := loadReportFromFile(args[0])
+		if err != nil {
+			return nil, "", err
+		}
+		return report, args[0], nil
+	}
+
+	storagePath, err := getStoragePath(cfg.StorageDir)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve storage path: %w", err)
+	}
+	store, err := openStore(storagePath, cfg.Storage, cfg.Repo)
+	if err != nil {
+		return nil, "", err
+	}
+	report, err := store.GetLatestRun()
+	if err != nil {
+		return nil, "", fmt.Errorf("no stored runs found. Run 'spectrehub run --store' first or pass a report file: %w", err)
+	}
+	return report, "stored run " + report.Timestamp.Format("2006-01-02 15:04:05"), nil
+}
+
+func countFailedRules(rules []policy.RuleResult) int {
+	n := 0
+	for _, r := range rules {
+		if !r.Pass {
+			n++
+		}
+	}
+	return n
+}
+
+func writePolicyJSON(w io.Writer, result *policyCheckResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+func ruleMarker(r policy.RuleResult) string {
+	switch {
+	case r.Skipped:
+		return "-"
+	case r.Pass:
+		return "✓"
+	default:
+		return "✗"
+	}
+}
+
+func writePolicyCheckText(w io.Writer, result *policyCheckResult) {
+	fmt.Fprintf(w, "Policy: %s\n", result.Policy)
+	fmt.Fprintf(w, "Report: %s\n\n", result.Report)
+
+	if len(result.Rules) == 0 {
+		fmt.Fprintln(w, "No rules configured.")
+		return
+	}
+
+	for _, r := range result.Rules {
+		fmt.Fprintf(w, "  %s %-24s %s\n", ruleMarker(r), r.Rule, r.Message)
+	}
+
+	fmt.Fprintln(w)
+	if result.Pass {
+		fmt.Fprintln(w, "Policy: PASS")
+	} else {
+		fmt.Fprintf(w, "Policy: FAIL (%d of %d rules failed)\n", countFailedRules(result.Rules), len(result.Rules))
+	}
+}
+
+func writePolicyExplainText(w io.Writer, result *policyCheckResult) {
+	fmt.Fprintf(w, "Policy: %s\n", result.Policy)
+	fmt.Fprintf(w, "Report: %s\n", result.Report)
+
+	if len(result.Rules) == 0 {
+		fmt.Fprintln(w, "\nNo rules configured.")
+		return
+	}
+
+	for _, r := range result.Rules {
+		fmt.Fprintf(w, "\n%s %s = %s\n", ruleMarker(r), r.Rule, r.Setting)
+		fmt.Fprintf(w, "    %s\n", r.Message)
+		if len(r.Matches) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "    Applies to %d finding(s):\n", len(r.Matches))
+		for i, issue := range r.Matches {
+			if i == maxExplainMatches {
+				fmt.Fprintf(w, "      ... and %d more\n", len(r.Matches)-maxExplainMatches)
+				break
+			}
+			fmt.Fprintf(w, "      [%s] %s %s: %s\n", issue.Severity, issue.Tool, issue.Category, issue.Resource)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/config"
+)
+
+// withPolicyFlags resets policy command flags after the test.
+func withPolicyFlags(t *testing.T, file, format string) {
+	t.Helper()
+	oldFile, oldFormat := policyFile, policyFormat
+	policyFile, policyFormat = file, format
+	t.Cleanup(func() { policyFile, policyFormat = oldFile, oldFormat })
+}
+
+func writePolicy(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".spectrehub-policy.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPolicyCheckPass(t *testing.T) {
+	dir := setupTestStorage(t, baseReport(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC), 2))
+	withTestConfig(t, &config.Config{StorageDir: dir})
+	withPolicyFlags(t, writePolicy(t, "version: \"1\"\nrules:\n  max_issues: 5\n"), "text")
+
+	var out bytes.Buffer
+	policyCheckCmd.SetOut(&out)
+	t.Cleanup(func() { policyCheckCmd.SetOut(nil) })
+
+	if err := runPolicyCheck(policyCheckCmd, nil); err != nil {
+		t.Fatalf("runPolicyCheck: %v", err)
+	}
+	if !strings.Contains(out.String(), "Policy: PASS") {
+		t.Errorf("expected PASS, got:\n%s", out.String())
+	}
+}
+
+func TestPolicyCheckFail(t *testing.T) {
+	dir := setupTestStorage(t, baseReport(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC), 3))
+	withTestConfig(t, &config.Config{StorageDir: dir})
+	withPolicyFlags(t, writePolicy(t, "version: \"1\"\nrules:\n  max_critical: 0\n  max_issues: 10\n"), "text")
+
+	var out bytes.Buffer
+	policyCheckCmd.SetOut(&out)
+	t.Cleanup(func() { policyCheckCmd.SetOut(nil) })
+
+	err := runPolicyCheck(policyCheckCmd, nil)
+	if HandleError(err) != ExitPolicyFail {
+		t.Fatalf("expected policy failure, got %v", err)
+	}
+	if !strings.Contains(out.String(), "✗ max_critical") || !strings.Contains(out.String(), "✓ max_issues") {
+		t.Errorf("expected per-rule markers, got:\n%s", out.String())
+	}
+}
+
+func TestPolicyCheckReportFileJSON(t *testing.T) {
+	withTestConfig(t, &config.Config{StorageDir: t.TempDir()})
+	withPolicyFlags(t, writePolicy(t, "version: \"1\"\nrules:\n  require_tools: [vaultspectre]\n"), "json")
+
+	report := baseReport(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC), 1)
+	data, _ := json.Marshal(report)
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(reportPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	policyCheckCmd.SetOut(&out)
+	t.Cleanup(func() { policyCheckCmd.SetOut(nil) })
+
+	if err := runPolicyCheck(policyCheckCmd, []string{reportPath}); err != nil {
+		t.Fatalf("runPolicyCheck: %v", err)
+	}
+
+	var result policyCheckResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !result.Pass || result.Report != reportPath || len(result.Rules) != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestPolicyCheckInvalidPolicy(t *testing.T) {
+	withTestConfig(t, &config.Config{StorageDir: t.TempDir()})
+	withPolicyFlags(t, writePolicy(t, "version: \"1\"\nrules:\n  max_critcal: 0\n"), "text")
+
+	err := runPolicyCheck(policyCheckCmd, nil)
+	if HandleError(err) != ExitInvalidInput {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+func TestPolicyLint(t *testing.T) {
+	path := writePolicy(t, "version: \"1\"\nrules:\n  max_critcal: 0\n")
+	withPolicyFlags(t, "", "text")
+
+	var out bytes.Buffer
+	policyLintCmd.SetOut(&out)
+	t.Cleanup(func() { policyLintCmd.SetOut(nil) })
+
+	err := runPolicyLint(policyLintCmd, []string{path})
+	if HandleError(err) != ExitInvalidInput {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if !strings.Contains(out.String(), path+":3:3: unknown key \"max_critcal\"") {
+		t.Errorf("expected line-numbered issue, got:\n%s", out.String())
+	}
+}
+
+func TestPolicyLintValid(t *testing.T) {
+	withPolicyFlags(t, writePolicy(t, "version: \"1\"\nrules:\n  max_issues: 1\n"), "text")
+
+	var out bytes.Buffer
+	policyLintCmd.SetOut(&out)
+	t.Cleanup(func() { policyLintCmd.SetOut(nil) })
+
+	if err := runPolicyLint(policyLintCmd, nil); err != nil {
+		t.Fatalf("runPolicyLint: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "OK:") {
+		t.Errorf("expected OK, got %q", out.String())
+	}
+}
+
+func TestPolicyExplainText(t *testing.T) {
+	dir := setupTestStorage(t, baseReport(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC), 12))
+	withTestConfig(t, &config.Config{StorageDir: dir})
+	withPolicyFlags(t, writePolicy(t, "version: \"1\"\nrules:\n  max_critical: 0\n  max_open_critical_days: 7\n"), "text")
+
+	var out bytes.Buffer
+	policyExplainCmd.SetOut(&out)
+	t.Cleanup(func() { policyExplainCmd.SetOut(nil) })
+
+	if err := runPolicyExplain(policyExplainCmd, nil); err != nil {
+		t.Fatalf("runPolicyExplain: %v", err)
+	}
+	s := out.String()
+	if !strings.Contains(s, "Applies to 12 finding(s)") {
+		t.Errorf("expected match count, got:\n%s", s)
+	}
+	if !strings.Contains(s, "... and 2 more") {
+		t.Errorf("expected truncation, got:\n%s", s)
+	}
+	if !strings.Contains(s, "- max_open_critical_days") {
+		t.Errorf("expected skipped API rule, got:\n%s", s)
+	}
+}
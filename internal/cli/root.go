@@ -1,10 +1,13 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/ppiankov/spectrehub/internal/config"
+	"github.com/ppiankov/spectrehub/internal/tracing"
 	"github.com/spf13/cobra"
 )
 
@@ -49,6 +52,7 @@ Other commands:
   spectrehub discover
   spectrehub diff --last 2
   spectrehub export --format sarif
+  spectrehub policy check
   spectrehub collect ./reports --repo org/name`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Load configuration
@@ -72,12 +76,37 @@ Other commands:
 
 // Execute runs the root command
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx := context.Background()
+
+	// Optional OTLP tracing, configured by OTEL_EXPORTER_OTLP_* env vars
+	shutdown, err := tracing.Setup(ctx, os.Getenv, buildVersion)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: tracing disabled: %v\n", err)
+	}
+
+	err = rootCmd.ExecuteContext(ctx)
+
+	flushCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	if serr := shutdown(flushCtx); serr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to export traces: %v\n", serr)
+	}
+	cancel()
+
+	if err != nil {
 		// Cobra already prints the error
 		os.Exit(ExitRuntimeError)
 	}
 }
 
+// commandContext returns the command's context. Command functions called
+// directly (tests) may have no command or no context.
+func commandContext(cmd *cobra.Command) context.Context {
+	if cmd != nil && cmd.Context() != nil {
+		return cmd.Context()
+	}
+	return context.Background()
+}
+
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&configFile, "config", "",
@@ -92,13 +121,19 @@ func init() {
 	rootCmd.AddCommand(summarizeCmd)
 	rootCmd.AddCommand(discoverCmd)
 	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(daemonCmd)
 	rootCmd.AddCommand(activateCmd)
 	rootCmd.AddCommand(diffCmd)
 	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(verifyHistoryCmd)
+	rootCmd.AddCommand(syncCmd)
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(doctorCmd)
 	rootCmd.AddCommand(explainScoreCmd)
 	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(convertCmd)
+	rootCmd.AddCommand(policyCmd)
+	rootCmd.AddCommand(metricsCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
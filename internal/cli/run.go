@@ -11,8 +11,12 @@ import (
 	"github.com/ppiankov/spectrehub/internal/api"
 	"github.com/ppiankov/spectrehub/internal/collector"
 	"github.com/ppiankov/spectrehub/internal/discovery"
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/notify"
 	"github.com/ppiankov/spectrehub/internal/runner"
+	"github.com/ppiankov/spectrehub/internal/tracing"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -20,10 +24,13 @@ var (
 	runOutput     string
 	runStore      bool
 	runStorageDir string
+	runStorageURL string
 	runThreshold  int
 	runTimeout    time.Duration
 	runDryRun     bool
 	runRepo       string
+	runRetries    int
+	runRedacted   bool
 )
 
 var runCmd = &cobra.Command{
@@ -37,7 +44,9 @@ var runCmd = &cobra.Command{
   4. Report   — print results (text, json, or both)
 
 Use --dry-run to see the discovery plan without executing anything.
-Use --timeout to set per-tool execution timeout (default: 5m).`,
+Use --timeout to set per-tool execution timeout (default: 5m).
+Use --retries to retry tools that time out or crash, with backoff.
+Use --show-redacted to preview the API upload after redaction.`,
 	RunE: runRun,
 }
 
@@ -50,6 +59,8 @@ func init() {
 		"persist results for trend analysis")
 	runCmd.Flags().StringVar(&runStorageDir, "storage-dir", "",
 		"storage directory (default: .spectre)")
+	runCmd.Flags().StringVar(&runStorageURL, "storage-url", "",
+		"store runs in a bucket instead, e.g. s3://bucket/prefix (default from config)")
 	runCmd.Flags().IntVar(&runThreshold, "fail-threshold", 0,
 		"exit 1 if issues exceed threshold (0 = disabled)")
 	runCmd.Flags().DurationVar(&runTimeout, "timeout", runner.DefaultTimeout,
@@ -58,13 +69,33 @@ func init() {
 		"show discovery plan without executing tools")
 	runCmd.Flags().StringVar(&runRepo, "repo", "",
 		"repository identifier for API upload (e.g. org/repo)")
+	runCmd.Flags().IntVar(&runRetries, "retries", 0,
+		"retries of a tool that timed out or crashed (default from config)")
+	runCmd.Flags().BoolVar(&runRedacted, "show-redacted", false,
+		"print the API upload with the redaction profile applied, without storing, uploading or notifying")
 }
 
-func runRun(cmd *cobra.Command, args []string) error {
+func runRun(cmd *cobra.Command, args []string) (err error) {
+	ctx, span := tracing.Start(commandContext(cmd), "spectrehub.run")
+	defer func() { tracing.End(span, err) }()
+
+	if runStorageDir == "" {
+		runStorageDir = cfg.StorageDir
+	}
+
+	repo := runRepo
+	if repo == "" {
+		repo = cfg.Repo
+	}
+	if repo != "" {
+		if err := api.ValidateRepo(repo); err != nil {
+			return &ValidationError{Message: fmt.Sprintf("invalid repo value: %v", err)}
+		}
+	}
+
 	// Step 1: Discover
 	logVerbose("discovering spectre tools...")
-	d := discovery.New(exec.LookPath, os.Getenv)
-	plan := d.Discover()
+	plan := discoverTools(ctx)
 
 	logVerbose("found %d tools, %d runnable", plan.TotalFound, plan.TotalRunnable)
 
@@ -98,9 +129,80 @@ func runRun(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Steps 2-4: Execute, aggregate and report
+	retries := runRetries
+	if retries == 0 {
+		retries = cfg.Retries
+	}
+	opts := toolRunOptions{Timeout: runTimeout, Retries: retries, RetryBackoff: cfg.RetryBackoff}
+	return runAudit(ctx, plan, opts, PipelineConfig{
+		Format:     runFormat,
+		Output:     runOutput,
+		Store:      runStore,
+		StorageDir: runStorageDir,
+		Threshold:  runThreshold,
+		LicenseKey: cfg.LicenseKey,
+		APIURL:     cfg.APIURL,
+		Repo:       repo,
+
+		RemediationFile: cfg.RemediationFile,
+		Notifications:   cfg.Notifications,
+		Redaction:       cfg.Redaction,
+		Storage:         storageConfig(cfg.Storage, runStorageURL),
+		ExpectedTools:   cfg.ExpectedTools,
+		ShowRedacted:    runRedacted,
+	})
+}
+
+// discoverTools finds installed tools and configured targets.
+func discoverTools(ctx context.Context) *discovery.DiscoveryPlan {
+	_, span := tracing.Start(ctx, "discovery.discover")
+	defer span.End()
+
+	plan := discovery.New(exec.LookPath, os.Getenv).Discover()
+	span.SetAttributes(
+		attribute.Int("spectrehub.tools_found", plan.TotalFound),
+		attribute.Int("spectrehub.tools_runnable", plan.TotalRunnable),
+	)
+	return plan
+}
+
+// toolRunOptions control how discovered tools are executed.
+type toolRunOptions struct {
+	Timeout      time.Duration
+	Retries      int
+	RetryBackoff time.Duration
+	Tools        []string // empty runs every runnable tool
+}
+
+// runAudit executes the runnable tools in plan and feeds their outputs
+// through the shared pipeline.
+func runAudit(ctx context.Context, plan *discovery.DiscoveryPlan, opts toolRunOptions, pcfg PipelineConfig) error {
+	toolReports, executions, err := executeTools(ctx, plan, opts, pcfg)
+	if err != nil {
+		return err
+	}
+
+	// Step 4: Report through shared pipeline
+	pcfg.Executions = executions
+	return RunPipelineContext(ctx, toolReports, pcfg)
+}
+
+// executeTools runs the tools and collects their reports. When every tool
+// fails it sends the tool_failed notification configured in pcfg, since
+// the pipeline never runs.
+func executeTools(ctx context.Context, plan *discovery.DiscoveryPlan, opts toolRunOptions, pcfg PipelineConfig) ([]models.ToolReport, []models.ToolExecution, error) {
 	// Step 2: Execute
-	configs := runner.ConfigsFromDiscovery(plan, runTimeout)
+	configs := filterConfigs(runner.ConfigsFromDiscovery(plan, opts.Timeout), opts.Tools)
+	if len(configs) == 0 {
+		return nil, nil, fmt.Errorf("none of the tools %s is runnable", strings.Join(opts.Tools, ", "))
+	}
+	for i := range configs {
+		configs[i].Retries = opts.Retries
+		configs[i].RetryBackoff = opts.RetryBackoff
+	}
 
+	// Output keeps stderr in *exec.ExitError, where the runner reads it.
 	execFn := func(ctx context.Context, name string, args ...string) ([]byte, error) {
 		c := exec.CommandContext(ctx, name, args...)
 		return c.Output()
@@ -109,8 +211,8 @@ func runRun(cmd *cobra.Command, args []string) error {
 	r := runner.New(execFn)
 	defer func() { _ = r.Cleanup() }()
 
-	logVerbose("executing %d tool(s) with timeout %s...", len(configs), runTimeout)
-	results := r.Run(context.Background(), configs)
+	logVerbose("executing %d tool(s) with timeout %s...", len(configs), opts.Timeout)
+	results := r.Run(ctx, configs)
 
 	// Report execution results
 	successCount := 0
@@ -119,12 +221,24 @@ func runRun(cmd *cobra.Command, args []string) error {
 			logVerbose("  ✓ %s (%s)", res.Binary, res.Duration)
 			successCount++
 		} else {
-			logError("  ✗ %s: %s", res.Binary, res.Error)
+			logError("  ✗ %s [%s]: %s", res.Binary, res.FailureClass, res.Error)
+			for _, line := range res.Stderr {
+				logVerbose("      %s", line)
+			}
 		}
 	}
 
+	executions := runner.Executions(results)
+
 	if successCount == 0 {
-		return fmt.Errorf("all tools failed — nothing to aggregate")
+		if pcfg.Notifications.Enabled() {
+			sendNotifications(notify.Run{
+				Repo:        pcfg.Repo,
+				Report:      &models.AggregatedReport{Timestamp: time.Now().UTC()},
+				FailedTools: failedTools(executions),
+			}, pcfg)
+		}
+		return nil, executions, fmt.Errorf("all tools failed — nothing to aggregate")
 	}
 
 	logVerbose("%d/%d tools succeeded", successCount, len(results))
@@ -136,34 +250,32 @@ func runRun(cmd *cobra.Command, args []string) error {
 		MaxConcurrency: len(outputFiles),
 		Verbose:        verbose,
 	})
-	toolReports, err := coll.CollectFromPaths(outputFiles)
+	toolReports, err := coll.CollectFromPathsContext(ctx, outputFiles)
 	if err != nil {
-		return fmt.Errorf("failed to collect tool outputs: %w", err)
+		return nil, executions, fmt.Errorf("failed to collect tool outputs: %w", err)
 	}
 
 	if len(toolReports) == 0 {
-		return fmt.Errorf("no valid tool reports produced")
+		return nil, executions, fmt.Errorf("no valid tool reports produced")
 	}
 
-	// Step 4: Report through shared pipeline
-	repo := runRepo
-	if repo == "" {
-		repo = cfg.Repo
+	return toolReports, executions, nil
+}
+
+// filterConfigs keeps the configs for tools; empty tools keeps all.
+func filterConfigs(configs []runner.RunConfig, tools []string) []runner.RunConfig {
+	if len(tools) == 0 {
+		return configs
 	}
-	if repo != "" {
-		if err := api.ValidateRepo(repo); err != nil {
-			return &ValidationError{Message: fmt.Sprintf("invalid repo value: %v", err)}
+	want := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		want[t] = true
+	}
+	var kept []runner.RunConfig
+	for _, c := range configs {
+		if want[string(c.Tool)] {
+			kept = append(kept, c)
 		}
 	}
-
-	return RunPipeline(toolReports, PipelineConfig{
-		Format:     runFormat,
-		Output:     runOutput,
-		Store:      runStore,
-		StorageDir: runStorageDir,
-		Threshold:  runThreshold,
-		LicenseKey: cfg.LicenseKey,
-		APIURL:     cfg.APIURL,
-		Repo:       repo,
-	})
+	return kept
 }
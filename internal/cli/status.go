@@ -1,9 +1,11 @@
 package cli
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/ppiankov/spectrehub/internal/apiclient"
 	"github.com/spf13/cobra"
@@ -17,7 +19,8 @@ var statusCmd = &cobra.Command{
 	Long: `Status displays current SpectreHub configuration and license details.
 
 If a license key is configured, it validates against the API and shows
-plan tier, repo usage, and expiration.
+plan tier, repo usage, and expiration. It also shows how many failed API
+uploads are queued in the outbox (see spectrehub sync).
 
 Example:
   spectrehub status
@@ -31,9 +34,10 @@ func init() {
 }
 
 type statusResult struct {
-	License    *statusLicense `json:"license,omitempty"`
-	Config     statusConfig   `json:"config"`
-	ConfigFile string         `json:"config_file"`
+	License       *statusLicense `json:"license,omitempty"`
+	Config        statusConfig   `json:"config"`
+	ConfigFile    string         `json:"config_file"`
+	QueuedUploads int            `json:"queued_uploads"` // failed API uploads waiting in the outbox
 }
 
 type statusLicense struct {
@@ -47,21 +51,42 @@ type statusLicense struct {
 
 type statusConfig struct {
 	StorageDir string `json:"storage_dir"`
+	StorageURL string `json:"storage_url,omitempty"`
 	Format     string `json:"format"`
 	Repo       string `json:"repo,omitempty"`
 	HasKey     bool   `json:"has_license_key"`
+
+	// Protection of stored runs; PublicKey verifies their signatures.
+	Encrypted bool   `json:"storage_encrypted"`
+	Signed    bool   `json:"storage_signed"`
+	PublicKey string `json:"storage_public_key,omitempty"`
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
 	result := statusResult{
 		Config: statusConfig{
 			StorageDir: cfg.StorageDir,
+			StorageURL: cfg.Storage.URL,
 			Format:     cfg.Format,
 			Repo:       cfg.Repo,
 			HasKey:     cfg.LicenseKey != "",
 		},
 		ConfigFile: configFile,
 	}
+	if keys, err := cfg.Storage.Load(); err != nil {
+		logVerbose("Storage keys unreadable: %v", err)
+	} else if keys != nil {
+		result.Config.Encrypted = keys.Encryption != nil
+		result.Config.Signed = keys.Verify != nil
+		if keys.Verify != nil {
+			result.Config.PublicKey = hex.EncodeToString(keys.Verify)
+		}
+	}
+	if queued, err := queuedUploads(cfg.StorageDir); err == nil {
+		result.QueuedUploads = queued
+	} else {
+		logVerbose("Outbox unreadable: %v", err)
+	}
 
 	// If license key is configured, validate and fetch repos.
 	if cfg.LicenseKey != "" {
@@ -129,7 +154,30 @@ func writeStatusText(result statusResult) error {
 		fmt.Println("License:  not configured (free tier)")
 	}
 
-	fmt.Printf("Storage:  %s\n", result.Config.StorageDir)
+	var protection []string
+	if result.Config.Encrypted {
+		protection = append(protection, "encrypted")
+	}
+	if result.Config.Signed {
+		protection = append(protection, "signed")
+	}
+	location := result.Config.StorageDir
+	if result.Config.StorageURL != "" {
+		location = result.Config.StorageURL
+	}
+	if len(protection) > 0 {
+		fmt.Printf("Storage:  %s (%s)\n", location, strings.Join(protection, ", "))
+	} else {
+		fmt.Printf("Storage:  %s\n", location)
+	}
+	if result.Config.PublicKey != "" {
+		fmt.Printf("Signing:  public key %s\n", result.Config.PublicKey)
+	}
+	if result.QueuedUploads > 0 {
+		fmt.Printf("Outbox:   %d queued upload(s), run 'spectrehub sync' to send\n", result.QueuedUploads)
+	} else {
+		fmt.Println("Outbox:   empty")
+	}
 	if result.Config.Repo != "" {
 		fmt.Printf("Repo:     %s\n", result.Config.Repo)
 	}
@@ -3,11 +3,14 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/ppiankov/spectrehub/internal/aggregator"
+	"github.com/ppiankov/spectrehub/internal/config"
 	"github.com/ppiankov/spectrehub/internal/models"
 	"github.com/ppiankov/spectrehub/internal/reporter"
 	"github.com/ppiankov/spectrehub/internal/storage"
+	"github.com/ppiankov/spectrehub/internal/triage"
 	"github.com/ppiankov/spectrehub/internal/tui"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
@@ -19,6 +22,7 @@ var (
 	summarizeCompare bool
 	summarizeFormat  string
 	summarizeTUI     bool
+	summarizeURL     string
 )
 
 // summarizeCmd represents the summarize command
@@ -51,6 +55,8 @@ func init() {
 		"output format: text or json")
 	summarizeCmd.Flags().BoolVar(&summarizeTUI, "tui", false,
 		"launch interactive TUI (auto-enabled when TTY)")
+	summarizeCmd.Flags().StringVar(&summarizeURL, "storage-url", "",
+		"read runs from a bucket, e.g. s3://bucket/prefix (default from config)")
 }
 
 func runSummarize(cmd *cobra.Command, args []string) error {
@@ -65,9 +71,12 @@ func runSummarize(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	store := storage.NewLocal(storagePath)
+	store, err := openStore(storagePath, storageConfig(cfg.Storage, summarizeURL), cfg.Repo)
+	if err != nil {
+		return err
+	}
 
-	logVerbose("Loading runs from: %s", storagePath)
+	logVerbose("Loading runs from: %s", store.Location())
 
 	// Check if storage exists
 	runs, err := store.ListRuns()
@@ -89,12 +98,12 @@ func runSummarize(cmd *cobra.Command, args []string) error {
 		return runComparisonReport(store)
 	} else {
 		// Trend mode: show trends across last N runs
-		return runTrendReport(store, summarizeLastN)
+		return runTrendReport(store, storagePath, summarizeLastN)
 	}
 }
 
 // runComparisonReport generates a comparison report between latest and previous runs
-func runComparisonReport(store *storage.LocalStorage) error {
+func runComparisonReport(store storage.Storage) error {
 	// Load last 2 runs
 	reports, err := store.GetLastNRuns(2)
 	if err != nil {
@@ -122,8 +131,9 @@ func runComparisonReport(store *storage.LocalStorage) error {
 	return nil
 }
 
-// runTrendReport generates a trend report across last N runs
-func runTrendReport(store *storage.LocalStorage, lastN int) error {
+// runTrendReport generates a trend report across last N runs. Triage
+// decisions are kept in storagePath even when runs are stored elsewhere.
+func runTrendReport(store storage.Storage, storagePath string, lastN int) error {
 	// Load last N runs
 	reports, err := store.GetLastNRuns(lastN)
 	if err != nil {
@@ -153,7 +163,19 @@ func runTrendReport(store *storage.LocalStorage, lastN int) error {
 		useTUI = true
 	}
 	if useTUI {
-		return tui.Run(reports[len(reports)-1], trendSummary)
+		triageStore, err := triage.OpenDir(storagePath)
+		if err != nil {
+			logError("Failed to load triage store: %v", err)
+			return err
+		}
+		return tui.Run(reports, trendSummary, tui.Options{
+			Triage:  triageStore,
+			Presets: cfg.TUIPresets,
+			SavePresets: func(presets []config.FilterPreset) error {
+				cfg.TUIPresets = presets
+				return config.WritePresets(presets, cfg.File)
+			},
+		})
 	}
 
 	// Output based on format
@@ -192,27 +214,19 @@ func printTrendSummaryText(summary *models.TrendSummary, reports []*models.Aggre
 		previous := reports[len(reports)-2]
 		current := latestReport
 
-		change := current.Summary.TotalIssues - previous.Summary.TotalIssues
+		// CalculateTrend leaves out issues of tools missing from this run
+		trend := aggregator.NewTrendAnalyzer().CalculateTrend(current, previous)
 		var direction string
-		var indicator string
-
-		if change < 0 {
+		switch trend.Direction {
+		case "improving":
 			direction = "improved"
-			indicator = "↓"
-		} else if change > 0 {
+		case "degrading":
 			direction = "degraded"
-			indicator = "↑"
-		} else {
+		default:
 			direction = "stable"
-			indicator = "→"
 		}
 
-		changePercent := 0.0
-		if previous.Summary.TotalIssues > 0 {
-			changePercent = float64(change) / float64(previous.Summary.TotalIssues) * 100.0
-		}
-
-		fmt.Printf(" (%s %s %.1f%%)\n", indicator, direction, changePercent)
+		fmt.Printf(" (%s %s %.1f%%)\n", aggregator.GetTrendIndicator(trend.Direction), direction, trend.ChangePercent)
 	} else {
 		fmt.Println()
 	}
@@ -224,6 +238,16 @@ func printTrendSummaryText(summary *models.TrendSummary, reports []*models.Aggre
 		fmt.Println("Issue Trend (over time):")
 		fmt.Print("  ")
 		printSparkline(summary.IssueSparkline)
+		if len(summary.PartialRuns) == len(summary.IssueSparkline) {
+			marks := make([]rune, len(summary.PartialRuns))
+			for i, partial := range summary.PartialRuns {
+				marks[i] = ' '
+				if partial {
+					marks[i] = '^'
+				}
+			}
+			fmt.Printf("  %s partial run (tools missing)\n", strings.TrimRight(string(marks), " "))
+		}
 		fmt.Println()
 	}
 
@@ -234,6 +258,10 @@ func printTrendSummaryText(summary *models.TrendSummary, reports []*models.Aggre
 		fmt.Println("--------------------------------------------------")
 
 		for toolName, toolTrend := range summary.ByTool {
+			if toolTrend.Missing {
+				fmt.Printf("  %s: not compared (tool missing from a run)\n", toolName)
+				continue
+			}
 			indicator := "→"
 			if toolTrend.Change < 0 {
 				indicator = "↓"
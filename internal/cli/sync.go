@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/api"
+	"github.com/ppiankov/spectrehub/internal/apiclient"
+	"github.com/ppiankov/spectrehub/internal/outbox"
+	"github.com/ppiankov/spectrehub/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var syncStorageDir string
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Send API uploads queued by earlier runs",
+	Long: `Sync sends the report, finding lifecycle and waste uploads that earlier
+runs could not deliver because the API was unreachable, unavailable or rate
+limited. Failed uploads are kept in the outbox directory inside the storage
+directory, one per tool and run, and every run retries the ones whose
+backoff has passed (1 minute, doubling up to 6 hours). Sync retries all of
+them now.
+
+Uploads are sent oldest run first. Sync stops at the first upload that fails
+again and keeps the rest queued; an upload the API rejects is dropped.
+
+Example:
+  spectrehub sync
+  spectrehub sync --storage-dir /var/lib/spectrehub`,
+	RunE: runSync,
+}
+
+func init() {
+	syncCmd.Flags().StringVar(&syncStorageDir, "storage-dir", "",
+		"storage directory holding the outbox (default from config)")
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	storageDir := syncStorageDir
+	if storageDir == "" {
+		storageDir = cfg.StorageDir
+	}
+	storagePath, err := getStoragePath(storageDir)
+	if err != nil {
+		logError("Failed to get storage path: %v", err)
+		return err
+	}
+
+	box, err := openOutbox(storagePath, cfg.Storage)
+	if err != nil {
+		return err
+	}
+	pending, err := box.Len()
+	if err != nil {
+		return err
+	}
+	if pending == 0 {
+		fmt.Println("Nothing to sync: the outbox is empty.")
+		return nil
+	}
+
+	if cfg.LicenseKey == "" {
+		return &ValidationError{Message: fmt.Sprintf("%d queued upload(s) but no license key configured. Set SPECTREHUB_LICENSE_KEY or run: spectrehub activate <key>", pending)}
+	}
+	if err := api.ValidateLicenseKey(cfg.LicenseKey); err != nil {
+		return &ValidationError{Message: fmt.Sprintf("invalid license key: %v", err)}
+	}
+
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = "https://api.spectrehub.dev"
+	}
+
+	client := apiclient.New(apiURL, cfg.LicenseKey)
+	result, err := box.Drain(true, func(entry *outbox.Entry) error {
+		logVerbose("Sending %s (attempt %d)", entry.Name(), entry.Attempts+1)
+		return sendQueued(client, entry)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, dropped := range result.Dropped {
+		fmt.Fprintf(os.Stderr, "Warning: dropped rejected upload %v\n", dropped)
+	}
+	fmt.Printf("Synced %d queued upload(s), %d pending\n", result.Sent, result.Pending)
+	if result.LastError != nil {
+		return fmt.Errorf("%d upload(s) still pending: %w", result.Pending, result.LastError)
+	}
+	return nil
+}
+
+// retryOutbox sends the queued uploads whose backoff has passed. It runs
+// before a run's own uploads, so older runs reach the API first.
+func retryOutbox(pcfg PipelineConfig) error {
+	if pcfg.StorageDir == "" {
+		return nil
+	}
+	storagePath, err := getStoragePath(pcfg.StorageDir)
+	if err != nil {
+		return err
+	}
+
+	box, err := openOutbox(storagePath, pcfg.Storage)
+	if err != nil {
+		return err
+	}
+	if pending, err := box.Len(); err != nil || pending == 0 {
+		return err
+	}
+	// A malformed key fails every upload locally; keep them queued.
+	if err := api.ValidateLicenseKey(pcfg.LicenseKey); err != nil {
+		return fmt.Errorf("invalid license key: %w", err)
+	}
+
+	apiURL := pcfg.APIURL
+	if apiURL == "" {
+		apiURL = "https://api.spectrehub.dev"
+	}
+
+	client := apiclient.New(apiURL, pcfg.LicenseKey)
+	result, err := box.Drain(false, func(entry *outbox.Entry) error {
+		return sendQueued(client, entry)
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.Sent > 0 {
+		fmt.Fprintf(os.Stderr, "Sent %d queued upload(s) from earlier runs\n", result.Sent)
+	}
+	for _, dropped := range result.Dropped {
+		fmt.Fprintf(os.Stderr, "Warning: dropped rejected upload %v\n", dropped)
+	}
+	return result.LastError
+}
+
+// openOutbox returns the outbox of storagePath, encrypting queued payloads
+// with the storage encryption key scfg points to.
+func openOutbox(storagePath string, scfg storage.Config) (*outbox.Outbox, error) {
+	keys, err := scfg.Load()
+	if err != nil {
+		return nil, &ValidationError{Message: err.Error()}
+	}
+	box := outbox.OpenDir(storagePath)
+	box.SetKeys(keys)
+	return box, nil
+}
+
+// queueUpload keeps an upload that failed transiently in the outbox, so the
+// next run or spectrehub sync delivers it. It reports whether the upload was
+// queued; uploads are only queued when a storage directory is configured.
+func queueUpload(pcfg PipelineConfig, kind, tool string, runTimestamp time.Time, payload interface{}, cause error) bool {
+	if pcfg.StorageDir == "" || !apiclient.Retryable(cause) {
+		return false
+	}
+	storagePath, err := getStoragePath(pcfg.StorageDir)
+	if err != nil {
+		logError("Failed to get storage path: %v", err)
+		return false
+	}
+
+	box, err := openOutbox(storagePath, pcfg.Storage)
+	if err != nil {
+		logError("Failed to queue %s upload: %v", kind, err)
+		return false
+	}
+	entry, err := box.Add(kind, tool, runTimestamp, payload, cause)
+	if err != nil {
+		logError("Failed to queue %s upload: %v", kind, err)
+		return false
+	}
+
+	pending, _ := box.Len()
+	fmt.Fprintf(os.Stderr, "Warning: %s upload failed, queued for retry (%d pending, run 'spectrehub sync' to send now): %v\n",
+		entry.Name(), pending, cause)
+	return true
+}
+
+// sendQueued delivers a queued upload. Failures that retrying cannot fix are
+// marked permanent so the upload is dropped.
+func sendQueued(client *apiclient.Client, entry *outbox.Entry) error {
+	var err error
+	switch entry.Kind {
+	case outbox.KindReport:
+		var payload apiclient.ReportPayload
+		if err := json.Unmarshal(entry.Payload, &payload); err != nil {
+			return outbox.Permanent(fmt.Errorf("decode payload: %w", err))
+		}
+		err = client.SubmitReport(payload)
+	case outbox.KindFindings:
+		var payload apiclient.FindingsPayload
+		if err := json.Unmarshal(entry.Payload, &payload); err != nil {
+			return outbox.Permanent(fmt.Errorf("decode payload: %w", err))
+		}
+		_, err = client.SubmitFindings(payload)
+	case outbox.KindWaste:
+		var payload apiclient.WastePayload
+		if err := json.Unmarshal(entry.Payload, &payload); err != nil {
+			return outbox.Permanent(fmt.Errorf("decode payload: %w", err))
+		}
+		_, err = client.SubmitWaste(payload)
+	default:
+		return outbox.Permanent(fmt.Errorf("unknown upload kind %q", entry.Kind))
+	}
+
+	// A refused license key is fixed by the user, not by waiting, but the
+	// queued upload stays so sync can deliver it once the key is corrected.
+	if err != nil && !apiclient.Retryable(err) && !apiclient.Unauthorized(err) {
+		return outbox.Permanent(err)
+	}
+	return err
+}
+
+// queuedUploads returns the outbox depth of a storage directory.
+func queuedUploads(storageDir string) (int, error) {
+	if storageDir == "" {
+		return 0, nil
+	}
+	storagePath, err := getStoragePath(storageDir)
+	if err != nil {
+		return 0, err
+	}
+	return outbox.OpenDir(storagePath).Len()
+}
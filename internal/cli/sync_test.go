@@ -0,0 +1,354 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/apiclient"
+	"github.com/ppiankov/spectrehub/internal/config"
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/outbox"
+)
+
+// stubAPI answers uploads with status and records the paths it accepted.
+type stubAPI struct {
+	mu       sync.Mutex
+	status   int
+	accepted []string
+}
+
+func (s *stubAPI) setStatus(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+func (s *stubAPI) paths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.accepted...)
+}
+
+func (s *stubAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status != http.StatusCreated {
+		w.WriteHeader(s.status)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": http.StatusText(s.status)})
+		return
+	}
+	s.accepted = append(s.accepted, r.URL.Path)
+	w.WriteHeader(http.StatusCreated)
+	_, _ = w.Write([]byte(`{}`))
+}
+
+func newStubAPI(t *testing.T, status int) (*stubAPI, string) {
+	t.Helper()
+	stub := &stubAPI{status: status}
+	srv := newIPv4Server(t, stub)
+	t.Cleanup(srv.Close)
+	return stub, srv.URL
+}
+
+func s3FindingReports() []models.ToolReport {
+	ts := time.Date(2026, 2, 15, 10, 0, 0, 0, time.UTC)
+	return []models.ToolReport{{
+		Tool:        "s3spectre",
+		Version:     "0.2.1",
+		Timestamp:   ts,
+		IsSupported: true,
+		RawData: &models.SpectreV1Report{
+			Schema:    "spectre/v1",
+			Tool:      "s3spectre",
+			Version:   "0.2.1",
+			Timestamp: ts,
+			Target:    models.SpectreV1Target{Type: "s3"},
+			Findings: []models.SpectreV1Finding{
+				{ID: "UNUSED_BUCKET", Severity: "medium", Location: "s3://old", Message: "unused"},
+			},
+			Summary: models.SpectreV1Summary{Total: 1, Medium: 1},
+		},
+	}, {
+		// Gives the run resource totals, so it has a health score.
+		Tool:        "vaultspectre",
+		Version:     "0.1.0",
+		Timestamp:   ts,
+		IsSupported: true,
+		RawData: &models.VaultReport{
+			Summary: models.VaultSummary{TotalReferences: 5},
+			Secrets: map[string]*models.SecretInfo{},
+		},
+	}}
+}
+
+func queuedNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := outbox.OpenDir(dir).Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestRunPipelineQueuesUploadsWhenAPIDown(t *testing.T) {
+	stub, apiURL := newStubAPI(t, http.StatusServiceUnavailable)
+	dir := t.TempDir()
+	chdirNoPolicy(t)
+	withTestConfig(t, &config.Config{})
+
+	err := RunPipeline(s3FindingReports(), PipelineConfig{
+		Format:     "json",
+		Output:     filepath.Join(t.TempDir(), "out.json"),
+		StorageDir: dir,
+		LicenseKey: validTestLicenseKey,
+		APIURL:     apiURL,
+		Repo:       "org/test",
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline with API down = %v, want nil (uploads queued)", err)
+	}
+
+	names := queuedNames(t, dir)
+	if len(names) != 2 || !strings.HasPrefix(names[0], "report ") || !strings.HasPrefix(names[1], "findings s3spectre ") {
+		t.Fatalf("queued = %v, want the report and s3spectre findings", names)
+	}
+
+	// The API is back: sync sends everything, the report first.
+	stub.setStatus(http.StatusCreated)
+	withTestConfig(t, &config.Config{StorageDir: dir, LicenseKey: validTestLicenseKey, APIURL: apiURL})
+	output := captureStdout(t, func() {
+		if err := runSync(nil, nil); err != nil {
+			t.Errorf("runSync: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Synced 2 queued upload(s), 0 pending") {
+		t.Errorf("sync output = %q", output)
+	}
+	if got := strings.Join(stub.paths(), ","); got != "/v1/reports,/v1/findings" {
+		t.Errorf("API received %s, want the report then the findings", got)
+	}
+	if n, _ := queuedUploads(dir); n != 0 {
+		t.Errorf("queued uploads after sync = %d, want 0", n)
+	}
+}
+
+func TestSubmitToAPIQueuesOncePerRun(t *testing.T) {
+	_, apiURL := newStubAPI(t, http.StatusBadGateway)
+	dir := t.TempDir()
+	pcfg := PipelineConfig{StorageDir: dir, LicenseKey: validTestLicenseKey, APIURL: apiURL, Repo: "org/test"}
+
+	for i := 0; i < 2; i++ {
+		if err := submitToAPI(minimalReport(), pcfg); err != nil {
+			t.Fatalf("submitToAPI #%d: %v", i, err)
+		}
+	}
+
+	entries, err := outbox.OpenDir(dir).Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Attempts != 2 || !strings.Contains(entries[0].LastError, "Bad Gateway") {
+		t.Errorf("entries = %+v, want one report queued after 2 attempts", entries)
+	}
+}
+
+func TestSubmitToAPIRejectedIsNotQueued(t *testing.T) {
+	_, apiURL := newStubAPI(t, http.StatusBadRequest)
+	dir := t.TempDir()
+
+	err := submitToAPI(minimalReport(), PipelineConfig{StorageDir: dir, LicenseKey: validTestLicenseKey, APIURL: apiURL, Repo: "org/test"})
+	if err == nil {
+		t.Fatal("submitToAPI(400) = nil, want error")
+	}
+	if n, _ := queuedUploads(dir); n != 0 {
+		t.Errorf("queued uploads = %d, want 0 for a rejected payload", n)
+	}
+}
+
+func TestSubmitToAPIRefusedKeyFailsRun(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		_, apiURL := newStubAPI(t, status)
+		dir := t.TempDir()
+
+		err := submitToAPI(minimalReport(), PipelineConfig{StorageDir: dir, LicenseKey: validTestLicenseKey, APIURL: apiURL, Repo: "org/test"})
+		if err == nil {
+			t.Errorf("submitToAPI(%d) = nil, want error", status)
+		}
+		if n, _ := queuedUploads(dir); n != 0 {
+			t.Errorf("queued uploads = %d, want 0 after HTTP %d", n, status)
+		}
+	}
+}
+
+func TestSyncKeepsUploadsOnRefusedKey(t *testing.T) {
+	_, apiURL := newStubAPI(t, http.StatusUnauthorized)
+	dir := t.TempDir()
+	payload := apiclient.ReportPayload{Repo: "org/test", TotalTools: 1, Health: "good", RawJSON: "{}"}
+	if _, err := outbox.OpenDir(dir).Add(outbox.KindReport, "", time.Now(), payload, nil); err != nil {
+		t.Fatal(err)
+	}
+	withTestConfig(t, &config.Config{StorageDir: dir, LicenseKey: validTestLicenseKey, APIURL: apiURL})
+
+	var err error
+	captureStdout(t, func() { err = runSync(nil, nil) })
+	if err == nil {
+		t.Error("runSync with a refused key = nil, want error")
+	}
+	if n, _ := queuedUploads(dir); n != 1 {
+		t.Errorf("queued uploads = %d, want the upload kept for a corrected key", n)
+	}
+}
+
+func TestSubmitToAPIWithoutStorageDirFails(t *testing.T) {
+	_, apiURL := newStubAPI(t, http.StatusServiceUnavailable)
+	if err := submitToAPI(minimalReport(), PipelineConfig{LicenseKey: validTestLicenseKey, APIURL: apiURL, Repo: "org/test"}); err == nil {
+		t.Fatal("submitToAPI without storage dir = nil, want error")
+	}
+}
+
+func TestRetryOutboxSendsDueUploads(t *testing.T) {
+	stub, apiURL := newStubAPI(t, http.StatusCreated)
+	dir := t.TempDir()
+	box := outbox.OpenDir(dir)
+
+	// One upload whose backoff has passed, one still waiting.
+	due := outbox.Entry{
+		Kind:         outbox.KindReport,
+		RunTimestamp: time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC),
+		Payload:      json.RawMessage(`{"repo":"org/test","total_tools":1,"issues":0,"score":100,"health":"excellent","raw_json":"{}"}`),
+		Attempts:     1,
+		NextAttempt:  time.Now().Add(-time.Minute),
+	}
+	writeOutboxEntry(t, box, due, "report-20260201T100000.000000000Z.json")
+	if _, err := box.Add(outbox.KindWaste, "awsspectre", time.Date(2026, 2, 2, 10, 0, 0, 0, time.UTC), map[string]string{"tool": "awsspectre"}, errors.New("timeout")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := retryOutbox(PipelineConfig{StorageDir: dir, LicenseKey: validTestLicenseKey, APIURL: apiURL}); err != nil {
+		t.Fatalf("retryOutbox: %v", err)
+	}
+	if got := strings.Join(stub.paths(), ","); got != "/v1/reports" {
+		t.Errorf("API received %q, want only the due report", got)
+	}
+	if names := queuedNames(t, dir); len(names) != 1 || !strings.HasPrefix(names[0], "waste awsspectre") {
+		t.Errorf("queued = %v, want the waste upload still waiting", names)
+	}
+}
+
+func TestRetryOutboxKeepsUploadsWithMalformedKey(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := outbox.OpenDir(dir).Add(outbox.KindReport, "", time.Now(), struct{}{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := retryOutbox(PipelineConfig{StorageDir: dir, LicenseKey: "bad"}); err == nil {
+		t.Error("retryOutbox(bad key) = nil, want error")
+	}
+	if n, _ := queuedUploads(dir); n != 1 {
+		t.Errorf("queued uploads = %d, want 1", n)
+	}
+}
+
+func TestRunSyncStillDown(t *testing.T) {
+	_, apiURL := newStubAPI(t, http.StatusServiceUnavailable)
+	dir := t.TempDir()
+	payload := apiclient.ReportPayload{Repo: "org/test", TotalTools: 1, Health: "good", RawJSON: "{}"}
+	if _, err := outbox.OpenDir(dir).Add(outbox.KindReport, "", time.Now(), payload, nil); err != nil {
+		t.Fatal(err)
+	}
+	withTestConfig(t, &config.Config{StorageDir: dir, LicenseKey: validTestLicenseKey, APIURL: apiURL})
+
+	var err error
+	captureStdout(t, func() { err = runSync(nil, nil) })
+	if err == nil || !strings.Contains(err.Error(), "1 upload(s) still pending") {
+		t.Errorf("runSync(API down) = %v, want pending error", err)
+	}
+}
+
+func TestRunSyncEmptyAndUnlicensed(t *testing.T) {
+	dir := t.TempDir()
+	withTestConfig(t, &config.Config{StorageDir: dir})
+
+	output := captureStdout(t, func() {
+		if err := runSync(nil, nil); err != nil {
+			t.Errorf("runSync(empty) = %v", err)
+		}
+	})
+	if !strings.Contains(output, "Nothing to sync") {
+		t.Errorf("output = %q", output)
+	}
+
+	if _, err := outbox.OpenDir(dir).Add(outbox.KindReport, "", time.Now(), struct{}{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	var vErr *ValidationError
+	if err := runSync(nil, nil); !errors.As(err, &vErr) {
+		t.Errorf("runSync(no license) = %v, want ValidationError", err)
+	}
+}
+
+func TestStatusAndDoctorShowQueueDepth(t *testing.T) {
+	dir := t.TempDir()
+	box := outbox.OpenDir(dir)
+	for _, tool := range []string{"awsspectre", "iamspectre"} {
+		if _, err := box.Add(outbox.KindFindings, tool, time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC), struct{}{}, errors.New("connection refused")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	withTestConfig(t, &config.Config{StorageDir: dir})
+
+	oldFormat := statusFormat
+	t.Cleanup(func() { statusFormat = oldFormat })
+	statusFormat = "json"
+	output := captureStdout(t, func() {
+		if err := runStatus(nil, nil); err != nil {
+			t.Errorf("runStatus: %v", err)
+		}
+	})
+	var result statusResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("status JSON: %v", err)
+	}
+	if result.QueuedUploads != 2 {
+		t.Errorf("queued_uploads = %d, want 2", result.QueuedUploads)
+	}
+
+	check := checkOutbox()
+	if check.Status != "warn" || !strings.Contains(check.Detail, "2 queued upload(s)") || !strings.Contains(check.Detail, "connection refused") {
+		t.Errorf("checkOutbox() = %+v", check)
+	}
+}
+
+func writeOutboxEntry(t *testing.T, box *outbox.Outbox, entry outbox.Entry, name string) {
+	t.Helper()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(box.Dir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(box.Dir(), name), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// chdirNoPolicy runs the test in an empty directory so no policy file applies.
+func chdirNoPolicy(t *testing.T) {
+	t.Helper()
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(origDir) })
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 
@@ -13,6 +14,12 @@ var validateCmd = &cobra.Command{
 	Short: "Validate a report against the spectre/v1 schema",
 	Long: `Validate checks that a JSON report file conforms to the spectre/v1 schema.
 
+The report is checked against the JSON Schema built into spectrehub for its
+schema version (schemas/spectre-v1.schema.json for spectre/v1,
+schemas/spectre-v1.1.schema.json for spectre/v1.1), then for target.type matching
+the tool and summary.total matching the findings. Each problem is reported
+with the JSON pointer of the offending value, e.g. /findings/0/severity.
+
 Returns exit 0 if valid, exit 2 if invalid with details on stderr.
 
 Example:
@@ -37,6 +44,10 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	fmt.Println("VALID: conforms to spectre/v1")
+	var envelope struct {
+		Schema string `json:"schema"`
+	}
+	_ = json.Unmarshal(data, &envelope) // already validated
+	fmt.Printf("VALID: conforms to %s\n", envelope.Schema)
 	return nil
 }
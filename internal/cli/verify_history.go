@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ppiankov/spectrehub/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyHistoryStorageDir string
+	verifyHistoryFormat     string
+)
+
+var verifyHistoryCmd = &cobra.Command{
+	Use:   "verify-history",
+	Short: "Verify the hash chain across stored runs",
+	Long: `Verify-history checks that stored runs were not edited, deleted or
+reordered. Every saved run records the SHA-256 of the run stored before it
+and a sequence number, and the storage directory keeps the hash of the
+newest run in chain.json. Verify-history walks the runs oldest first and
+reports:
+
+  - runs missing from the middle, start or end of the history
+  - runs whose content changed after they were saved
+  - runs moved to another timestamp or saved out of order
+  - runs added by hand after the chain started
+
+Runs saved before chaining existed are accepted at the start of the
+history. Signed and encrypted runs are checked with the configured keys.
+
+Exits with code 2 when the history fails verification.
+
+Example:
+  spectrehub verify-history
+  spectrehub verify-history --format json --storage-dir /var/lib/spectrehub`,
+	RunE: runVerifyHistory,
+}
+
+func init() {
+	verifyHistoryCmd.Flags().StringVar(&verifyHistoryStorageDir, "storage-dir", "",
+		"storage directory holding the runs (default from config)")
+	verifyHistoryCmd.Flags().StringVar(&verifyHistoryFormat, "format", "text",
+		"output format: text or json")
+}
+
+func runVerifyHistory(cmd *cobra.Command, args []string) error {
+	if verifyHistoryFormat != "text" && verifyHistoryFormat != "json" {
+		return &ValidationError{Message: fmt.Sprintf("invalid format %q (must be text or json)", verifyHistoryFormat)}
+	}
+
+	storageDir := verifyHistoryStorageDir
+	if storageDir == "" {
+		storageDir = cfg.StorageDir
+	}
+	storagePath, err := getStoragePath(storageDir)
+	if err != nil {
+		logError("Failed to get storage path: %v", err)
+		return err
+	}
+
+	store, err := openStore(storagePath, cfg.Storage, cfg.Repo)
+	if err != nil {
+		return err
+	}
+	result, err := store.VerifyChain()
+	if err != nil {
+		return err
+	}
+
+	if verifyHistoryFormat == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		writeChainText(result)
+	}
+
+	if !result.Valid() {
+		return &ValidationError{Message: fmt.Sprintf("stored history failed verification: %d problem(s)", len(result.Problems))}
+	}
+	return nil
+}
+
+func writeChainText(result *storage.ChainResult) {
+	if result.Runs == 0 {
+		fmt.Println("No stored runs found.")
+	} else {
+		fmt.Printf("Runs:     %d (%d chained, %d before chaining)\n", result.Runs, result.Length, result.Unchained)
+	}
+	if result.Root != "" {
+		fmt.Printf("Root:     %s\n", result.Root)
+		fmt.Printf("Head:     %s\n", result.Head)
+	}
+
+	if result.Valid() {
+		fmt.Println("History verified: no gaps, edits or reordering.")
+		return
+	}
+	fmt.Printf("History FAILED verification (%d problem(s)):\n", len(result.Problems))
+	for _, p := range result.Problems {
+		fmt.Printf("  - %s\n", p)
+	}
+}
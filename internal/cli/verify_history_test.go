@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/config"
+)
+
+func chainedHistory(t *testing.T) string {
+	t.Helper()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return setupTestStorage(t,
+		baseReport(now.Add(-48*time.Hour), 1),
+		baseReport(now.Add(-24*time.Hour), 2),
+		baseReport(now, 3),
+	)
+}
+
+func TestRunVerifyHistory(t *testing.T) {
+	dir := chainedHistory(t)
+	withTestConfig(t, &config.Config{StorageDir: dir})
+
+	oldDir, oldFormat := verifyHistoryStorageDir, verifyHistoryFormat
+	t.Cleanup(func() { verifyHistoryStorageDir, verifyHistoryFormat = oldDir, oldFormat })
+	verifyHistoryStorageDir, verifyHistoryFormat = "", "text"
+
+	var runErr error
+	out := captureStdout(t, func() { runErr = runVerifyHistory(nil, nil) })
+	if runErr != nil {
+		t.Fatalf("runVerifyHistory: %v", runErr)
+	}
+	if !strings.Contains(out, "3 chained") || !strings.Contains(out, "History verified") {
+		t.Errorf("output = %q", out)
+	}
+
+	// Remove the middle run.
+	if err := os.Remove(filepath.Join(dir, "runs", "2026-02-28T12-00-00-aggregated.json")); err != nil {
+		t.Fatal(err)
+	}
+	verifyHistoryFormat = "json"
+	out = captureStdout(t, func() { runErr = runVerifyHistory(nil, nil) })
+	var valErr *ValidationError
+	if !errors.As(runErr, &valErr) {
+		t.Fatalf("runVerifyHistory = %v, want ValidationError", runErr)
+	}
+	var result struct {
+		Length   int      `json:"length"`
+		Problems []string `json:"problems"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if result.Length != 2 || len(result.Problems) != 1 || !strings.Contains(result.Problems[0], "missing before") {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestRunVerifyHistoryInvalidFormat(t *testing.T) {
+	withTestConfig(t, &config.Config{StorageDir: t.TempDir()})
+	oldFormat := verifyHistoryFormat
+	t.Cleanup(func() { verifyHistoryFormat = oldFormat })
+	verifyHistoryFormat = "xml"
+
+	var valErr *ValidationError
+	if err := runVerifyHistory(nil, nil); !errors.As(err, &valErr) {
+		t.Errorf("runVerifyHistory = %v, want ValidationError", err)
+	}
+}
+
+func TestRunExportIncludesChain(t *testing.T) {
+	dir := chainedHistory(t)
+	withTestConfig(t, &config.Config{StorageDir: dir})
+
+	oldFormat, oldOutput, oldLast, oldForce := exportFormat, exportOutput, exportLastN, exportForce
+	t.Cleanup(func() { exportFormat, exportOutput, exportLastN, exportForce = oldFormat, oldOutput, oldLast, oldForce })
+	// Each export rewrites the same file.
+	exportFormat, exportLastN, exportForce = "json", 1, true
+	exportOutput = filepath.Join(t.TempDir(), "export.json")
+
+	readExport := func() ComplianceExport {
+		t.Helper()
+		if err := runExport(nil, nil); err != nil {
+			t.Fatalf("runExport: %v", err)
+		}
+		data, err := os.ReadFile(exportOutput)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var export ComplianceExport
+		if err := json.Unmarshal(data, &export); err != nil {
+			t.Fatal(err)
+		}
+		return export
+	}
+
+	export := readExport()
+	if export.Chain == nil || !export.Chain.Verified || export.Chain.Length != 3 || export.Chain.Root == "" {
+		t.Fatalf("chain = %+v", export.Chain)
+	}
+
+	// An edited run still exports, but the chain records the failure.
+	path := filepath.Join(dir, "runs", "2026-02-27T12-00-00-aggregated.json")
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, []byte(strings.Replace(string(data), `"secret/a"`, `"secret/z"`, 1)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	export = readExport()
+	if export.Chain.Verified || len(export.Chain.Problems) == 0 {
+		t.Errorf("chain = %+v, want verification failure", export.Chain)
+	}
+}
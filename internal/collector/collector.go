@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Config holds configuration for the collector
@@ -64,6 +66,12 @@ func (c *Collector) CollectFromDirectory(dir string) ([]models.ToolReport, error
 
 // CollectFromPaths reads JSON files from multiple files or directories.
 func (c *Collector) CollectFromPaths(paths []string) ([]models.ToolReport, error) {
+	return c.CollectFromPathsContext(context.Background(), paths)
+}
+
+// CollectFromPathsContext is CollectFromPaths with a parent context, used
+// for cancellation and as the parent of per-file trace spans.
+func (c *Collector) CollectFromPathsContext(ctx context.Context, paths []string) ([]models.ToolReport, error) {
 	if len(paths) == 0 {
 		return nil, fmt.Errorf("no paths provided")
 	}
@@ -108,7 +116,7 @@ func (c *Collector) CollectFromPaths(paths []string) ([]models.ToolReport, error
 		fmt.Printf("Found %d JSON file(s) to process\n", len(files))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
 	defer cancel()
 
 	return c.collectFiles(ctx, files)
@@ -214,7 +222,7 @@ func (c *Collector) worker(ctx context.Context, wg *sync.WaitGroup, fileCh <-cha
 				return
 			}
 
-			report, err := c.processFile(file)
+			report, err := c.processFile(ctx, file)
 			resultCh <- &collectResult{
 				file:   file,
 				report: report,
@@ -227,8 +235,27 @@ func (c *Collector) worker(ctx context.Context, wg *sync.WaitGroup, fileCh <-cha
 	}
 }
 
-// processFile reads and processes a single JSON file
-func (c *Collector) processFile(filePath string) (*models.ToolReport, error) {
+// processFile reads and processes a single JSON file inside a trace span.
+func (c *Collector) processFile(ctx context.Context, filePath string) (*models.ToolReport, error) {
+	_, span := tracing.Start(ctx, "collector.process_file",
+		attribute.String("spectrehub.file", filepath.Base(filePath)),
+	)
+	report, err := c.parseFile(filePath)
+	if report != nil {
+		span.SetAttributes(attribute.String("spectrehub.tool", report.Tool))
+	}
+	tracing.End(span, err)
+	return report, err
+}
+
+// parseFile reads, detects and parses a single report file.
+func (c *Collector) parseFile(filePath string) (*models.ToolReport, error) {
+	return ParseFile(filePath)
+}
+
+// ParseFile reads, detects and parses a single report file of any
+// extension, e.g. /dev/stdin.
+func ParseFile(filePath string) (*models.ToolReport, error) {
 	// Read file
 	data, err := os.ReadFile(filePath)
 	if err != nil {
@@ -1,9 +1,14 @@
 package collector
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/ppiankov/spectrehub/internal/tracing"
+	"github.com/ppiankov/spectrehub/internal/tracing/tracingtest"
+	"go.opentelemetry.io/otel/codes"
 )
 
 func TestNewDefaults(t *testing.T) {
@@ -84,6 +89,40 @@ func TestCollectFromPathsSingleFile(t *testing.T) {
 	}
 }
 
+func TestCollectFromPathsContextSpans(t *testing.T) {
+	rec := tracingtest.Record(t)
+
+	bad := filepath.Join(t.TempDir(), "broken.json")
+	if err := os.WriteFile(bad, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, parent := tracing.Start(context.Background(), "spectrehub.collect")
+	c := New(Config{MaxConcurrency: 2})
+	if _, err := c.CollectFromPathsContext(ctx, []string{"../../testdata/contracts/vaultspectre-v0.1.0.json", bad}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parent.End()
+
+	var ok, failed int
+	for _, s := range rec.Ended() {
+		if s.Name() != "collector.process_file" {
+			continue
+		}
+		if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span for %v is not a child of the collect span", s.Attributes())
+		}
+		if s.Status().Code == codes.Error {
+			failed++
+		} else {
+			ok++
+		}
+	}
+	if ok != 1 || failed != 1 {
+		t.Errorf("expected 1 ok and 1 failed file span, got %d/%d", ok, failed)
+	}
+}
+
 func TestCollectFromPathsDirectory(t *testing.T) {
 	c := New(Config{MaxConcurrency: 2})
 	reports, err := c.CollectFromPaths([]string{"../../testdata/contracts"})
@@ -8,6 +8,7 @@ import (
 
 	"github.com/ppiankov/spectrehub/internal/models"
 	"github.com/ppiankov/spectrehub/internal/validator"
+	"github.com/ppiankov/spectrehub/pkg/spectrev1/spectrev1test"
 )
 
 // TestToolDetection verifies that tool type detection works correctly for each contract file
@@ -220,8 +221,8 @@ func TestCollectFromDirectory(t *testing.T) {
 		t.Fatalf("CollectFromDirectory failed: %v", err)
 	}
 
-	if len(reports) != 18 {
-		t.Errorf("Expected 18 reports (6 legacy + 12 spectre/v1), got %d", len(reports))
+	if len(reports) != 19 {
+		t.Errorf("Expected 19 reports (6 legacy + 12 spectre/v1 + 1 spectre/v1.1), got %d", len(reports))
 	}
 
 	// Verify we got one report from each tool
@@ -231,7 +232,7 @@ func TestCollectFromDirectory(t *testing.T) {
 	}
 
 	expectedTools := []string{"vaultspectre", "s3spectre", "kafkaspectre", "clickspectre", "pgspectre", "mongospectre",
-		"kubespectre", "redisspectre", "ecrspectre", "rdsspectre", "azurespectre"}
+		"kubespectre", "redisspectre", "ecrspectre", "rdsspectre", "azurespectre", "awsspectre"}
 	for _, tool := range expectedTools {
 		if !toolsSeen[tool] {
 			t.Errorf("Expected to see report from %s", tool)
@@ -415,6 +416,7 @@ func TestSpectreV1Validation(t *testing.T) {
 		"../../testdata/contracts/ecrspectre-spectrev1.json",
 		"../../testdata/contracts/rdsspectre-spectrev1.json",
 		"../../testdata/contracts/azurespectre-spectrev1.json",
+		"../../testdata/contracts/awsspectre-spectrev1.1.json",
 	}
 
 	for _, file := range files {
@@ -436,6 +438,26 @@ func TestSpectreV1Validation(t *testing.T) {
 	}
 }
 
+// TestSpectreV1Conformance runs the checks scanner repositories run with
+// spectrev1test over spectrehub's own contract files.
+func TestSpectreV1Conformance(t *testing.T) {
+	spectrev1test.Run(t,
+		spectrev1test.Contract{File: "../../testdata/contracts/s3spectre-spectrev1.json", Tool: "s3spectre", Findings: 3},
+		spectrev1test.Contract{File: "../../testdata/contracts/pgspectre-spectrev1.json", Tool: "pgspectre", Findings: 3},
+		spectrev1test.Contract{File: "../../testdata/contracts/kafkaspectre-spectrev1.json", Tool: "kafkaspectre", Findings: 2},
+		spectrev1test.Contract{File: "../../testdata/contracts/clickspectre-spectrev1.json", Tool: "clickspectre", Findings: 2},
+		spectrev1test.Contract{File: "../../testdata/contracts/vaultspectre-spectrev1.json", Tool: "vaultspectre", Findings: 3},
+		spectrev1test.Contract{File: "../../testdata/contracts/mongospectre-spectrev1.json", Tool: "mongospectre", Findings: 2},
+		spectrev1test.Contract{File: "../../testdata/contracts/mongospectre-spectrev1-users.json", Tool: "mongospectre", Findings: 4},
+		spectrev1test.Contract{File: "../../testdata/contracts/kubespectre-spectrev1.json", Tool: "kubespectre", Findings: 3},
+		spectrev1test.Contract{File: "../../testdata/contracts/redisspectre-spectrev1.json", Tool: "redisspectre", Findings: 3},
+		spectrev1test.Contract{File: "../../testdata/contracts/ecrspectre-spectrev1.json", Tool: "ecrspectre", Findings: 2},
+		spectrev1test.Contract{File: "../../testdata/contracts/rdsspectre-spectrev1.json", Tool: "rdsspectre", Findings: 2},
+		spectrev1test.Contract{File: "../../testdata/contracts/azurespectre-spectrev1.json", Tool: "azurespectre", Findings: 3},
+		spectrev1test.Contract{File: "../../testdata/contracts/awsspectre-spectrev1.1.json", Tool: "awsspectre", Findings: 2},
+	)
+}
+
 // TestSpectreV1ValidationRejectsInvalid verifies that invalid spectre/v1 files are rejected
 func TestSpectreV1ValidationRejectsInvalid(t *testing.T) {
 	v := validator.New()
@@ -448,12 +470,12 @@ func TestSpectreV1ValidationRejectsInvalid(t *testing.T) {
 		{
 			name:           "bad severity",
 			file:           "../../testdata/invalid/spectrev1-bad-severity.json",
-			wantErrContain: "invalid severity",
+			wantErrContain: "/findings/0/severity: \"critical\" is not one of",
 		},
 		{
 			name:           "missing fields",
 			file:           "../../testdata/invalid/spectrev1-missing-fields.json",
-			wantErrContain: "Missing required field",
+			wantErrContain: "/timestamp: required property is missing",
 		},
 	}
 
@@ -546,3 +568,38 @@ func TestIsSpectreV1(t *testing.T) {
 		})
 	}
 }
+
+// TestSpectreV11Parsing verifies that spectre/v1.1 metadata is parsed and
+// detection treats the envelope like spectre/v1.
+func TestSpectreV11Parsing(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/contracts/awsspectre-spectrev1.1.json")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	if !IsSpectreV1(data) {
+		t.Fatal("Expected IsSpectreV1 to return true for spectre/v1.1")
+	}
+	toolType, err := DetectToolType(data)
+	if err != nil || toolType != models.ToolAWS {
+		t.Fatalf("DetectToolType = %s, %v", toolType, err)
+	}
+
+	rawData, err := ParseReport(data, toolType)
+	if err != nil {
+		t.Fatalf("ParseReport failed: %v", err)
+	}
+	report := rawData.(*models.SpectreV1Report)
+	if report.Schema != models.SpectreV11Schema {
+		t.Errorf("Schema = %q", report.Schema)
+	}
+	f := report.Findings[0]
+	if f.Attributes == nil || f.Attributes.Region != "us-east-1" || f.Attributes.Tags["team"] != "payments" {
+		t.Errorf("Attributes = %+v", f.Attributes)
+	}
+	if f.Fingerprint != "sha256:5f2c0e1b9a7d" || f.Remediation == "" || len(f.References) != 1 {
+		t.Errorf("metadata not parsed: %+v", f)
+	}
+	if f.FirstDetected == nil || f.FirstDetected.Format("2006-01-02") != "2026-02-10" || f.Confidence == nil || *f.Confidence != 0.9 {
+		t.Errorf("first_detected/confidence not parsed: %+v", f)
+	}
+}
@@ -7,12 +7,13 @@ import (
 	"github.com/ppiankov/spectrehub/internal/models"
 )
 
-// IsSpectreV1 returns true if the JSON data contains a spectre/v1 schema field.
+// IsSpectreV1 returns true if the JSON data is a spectre/v1 envelope of any
+// version (spectre/v1 or spectre/v1.1).
 func IsSpectreV1(data []byte) bool {
 	var schemaField struct {
 		Schema string `json:"schema"`
 	}
-	if err := json.Unmarshal(data, &schemaField); err == nil && schemaField.Schema == "spectre/v1" {
+	if err := json.Unmarshal(data, &schemaField); err == nil && models.IsSpectreV1Schema(schemaField.Schema) {
 		return true
 	}
 	return false
@@ -20,7 +21,7 @@ func IsSpectreV1(data []byte) bool {
 
 // DetectToolType identifies which Spectre tool produced the JSON data
 // It uses a three-phase approach:
-// 0. Check for spectre/v1 envelope (schema: "spectre/v1")
+// 0. Check for spectre/v1 envelope (schema: "spectre/v1" or "spectre/v1.1")
 // 1. Check for explicit "tool" field
 // 2. Fallback to structural analysis
 func DetectToolType(data []byte) (models.ToolType, error) {
@@ -33,15 +33,15 @@ func ParseReport(data []byte, toolType models.ToolType) (interface{}, error) {
 	}
 }
 
-// ParseSpectreV1Report parses a spectre/v1 envelope JSON.
+// ParseSpectreV1Report parses a spectre/v1 or spectre/v1.1 envelope JSON.
 func ParseSpectreV1Report(data []byte) (*models.SpectreV1Report, error) {
 	var report models.SpectreV1Report
 	if err := json.Unmarshal(data, &report); err != nil {
 		return nil, fmt.Errorf("failed to parse spectre/v1 report: %w", err)
 	}
 
-	if report.Schema != "spectre/v1" {
-		return nil, fmt.Errorf("expected schema spectre/v1, got %q", report.Schema)
+	if !models.IsSpectreV1Schema(report.Schema) {
+		return nil, fmt.Errorf("expected schema %s or %s, got %q", models.SpectreV1Schema, models.SpectreV11Schema, report.Schema)
 	}
 	if report.Tool == "" {
 		return nil, fmt.Errorf("spectre/v1 envelope missing required field: tool")
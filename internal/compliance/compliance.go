@@ -0,0 +1,371 @@
+// Package compliance maps findings to compliance framework controls: SOC 2
+// Trust Services Criteria, ISO 27001 Annex A and CIS benchmarks.
+//
+// Built-in mappings are embedded in the binary; teams can override or
+// extend them, or add their own frameworks, with a YAML file using the same
+// layout. Evaluate turns a window of stored runs into per-control evidence.
+package compliance
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/triage"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed controls.yaml
+var builtin []byte
+
+// Control statuses.
+const (
+	StatusPass       = "pass"       // no open finding in the latest run
+	StatusFail       = "fail"       // at least one open finding in the latest run
+	StatusUnverified = "unverified" // no open finding, but a tool that reported one earlier is missing
+)
+
+// Finding statuses over the run window.
+const (
+	FindingOpen       = "open"
+	FindingSuppressed = "suppressed" // present in the latest run but suppressed by triage
+	FindingResolved   = "resolved"
+	FindingUnverified = "unverified" // its tool did not report in the latest run
+)
+
+// Control is one control of a framework.
+type Control struct {
+	Title      string   `yaml:"title"`
+	Findings   []string `yaml:"findings,omitempty"`
+	Categories []string `yaml:"categories,omitempty"`
+}
+
+// Framework is a named set of controls keyed by control ID.
+type Framework struct {
+	Name     string             `yaml:"name"`
+	Controls map[string]Control `yaml:"controls"`
+}
+
+// Mappings holds the frameworks keyed by lower-case framework ID.
+type Mappings struct {
+	frameworks map[string]Framework
+}
+
+var (
+	defaultOnce     sync.Once
+	defaultMappings *Mappings
+)
+
+// Default returns the built-in mappings. The embedded file is validated by
+// tests, so a parse failure here is a build defect.
+func Default() *Mappings {
+	defaultOnce.Do(func() {
+		frameworks, err := parse(builtin)
+		if err != nil {
+			panic(fmt.Sprintf("compliance: invalid built-in mappings: %v", err))
+		}
+		defaultMappings = &Mappings{frameworks: frameworks}
+	})
+	return defaultMappings
+}
+
+// Load returns the built-in mappings with the frameworks from path layered
+// on top. An empty path returns Default().
+func Load(path string) (*Mappings, error) {
+	if path == "" {
+		return Default(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read compliance file: %w", err)
+	}
+	overrides, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse compliance file %s: %w", path, err)
+	}
+
+	return Default().Merge(overrides), nil
+}
+
+// Merge returns a copy of m with overrides applied. New frameworks and
+// controls are added. For an existing control, a title, findings or
+// categories set in the override replace the built-in value; fields left
+// empty keep it.
+func (m *Mappings) Merge(overrides map[string]Framework) *Mappings {
+	frameworks := make(map[string]Framework, len(m.frameworks)+len(overrides))
+	for id, fw := range m.frameworks {
+		controls := make(map[string]Control, len(fw.Controls))
+		for cid, c := range fw.Controls {
+			controls[cid] = c
+		}
+		frameworks[id] = Framework{Name: fw.Name, Controls: controls}
+	}
+
+	for id, o := range overrides {
+		fw, ok := frameworks[id]
+		if !ok {
+			fw = Framework{Name: id, Controls: make(map[string]Control)}
+		}
+		if o.Name != "" {
+			fw.Name = o.Name
+		}
+		for cid, oc := range o.Controls {
+			c := fw.Controls[cid]
+			if oc.Title != "" {
+				c.Title = oc.Title
+			}
+			if oc.Findings != nil {
+				c.Findings = oc.Findings
+			}
+			if oc.Categories != nil {
+				c.Categories = oc.Categories
+			}
+			fw.Controls[cid] = c
+		}
+		frameworks[id] = fw
+	}
+	return &Mappings{frameworks: frameworks}
+}
+
+// Framework returns the framework with the given ID, case-insensitively.
+func (m *Mappings) Framework(id string) (Framework, bool) {
+	if m == nil {
+		return Framework{}, false
+	}
+	fw, ok := m.frameworks[strings.ToLower(id)]
+	return fw, ok
+}
+
+// Frameworks returns the framework IDs, sorted.
+func (m *Mappings) Frameworks() []string {
+	ids := make([]string, 0, len(m.frameworks))
+	for id := range m.frameworks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ControlIDs returns the control IDs of fw in natural order, so A.9.2.5
+// sorts before A.12.1.2.
+func (fw Framework) ControlIDs() []string {
+	ids := make([]string, 0, len(fw.Controls))
+	for id := range fw.Controls {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return naturalLess(ids[i], ids[j]) })
+	return ids
+}
+
+// Matches reports whether issue is evidence against the control.
+func (c Control) Matches(issue models.NormalizedIssue) bool {
+	for _, id := range c.Findings {
+		if issue.ID != "" && issue.ID == id {
+			return true
+		}
+	}
+	for _, cat := range c.Categories {
+		if issue.Category == cat {
+			return true
+		}
+	}
+	return false
+}
+
+// ControlResult is the evidence for one control over a window of runs.
+type ControlResult struct {
+	Control  string     `json:"control"`
+	Title    string     `json:"title"`
+	Status   string     `json:"status"` // pass, fail or unverified
+	Findings []Evidence `json:"findings,omitempty"`
+}
+
+// Evidence is a finding related to a control, tracked across the window.
+type Evidence struct {
+	ID        string    `json:"id,omitempty"`
+	Tool      string    `json:"tool"`
+	Category  string    `json:"category"`
+	Severity  string    `json:"severity"`
+	Resource  string    `json:"resource"`
+	FirstSeen time.Time `json:"first_seen"` // first run in the window reporting it
+	LastSeen  time.Time `json:"last_seen"`  // last run in the window reporting it
+	Runs      int       `json:"runs"`       // runs in the window reporting it
+	Status    string    `json:"status"`     // open, suppressed, resolved or unverified
+}
+
+// Evaluate builds the evidence for every control of fw from reports, the
+// run window. A control fails when the latest run has an open finding
+// against it; findings seen earlier in the window but gone from the latest
+// run are resolved, unless their tool did not report in the latest run.
+func (fw Framework) Evaluate(reports []*models.AggregatedReport) []ControlResult {
+	runs := make([]*models.AggregatedReport, 0, len(reports))
+	for _, r := range reports {
+		if r != nil {
+			runs = append(runs, r)
+		}
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Timestamp.Before(runs[j].Timestamp) })
+
+	results := make([]ControlResult, 0, len(fw.Controls))
+	for _, id := range fw.ControlIDs() {
+		control := fw.Controls[id]
+		findings := evidence(control, runs)
+		results = append(results, ControlResult{
+			Control:  id,
+			Title:    control.Title,
+			Status:   controlStatus(findings),
+			Findings: findings,
+		})
+	}
+	return results
+}
+
+// evidence collects the findings matching control across runs, oldest
+// run first.
+func evidence(control Control, runs []*models.AggregatedReport) []Evidence {
+	if len(runs) == 0 {
+		return nil
+	}
+	latest := runs[len(runs)-1]
+
+	found := make(map[string]*Evidence)
+	var keys []string
+	observe := func(run *models.AggregatedReport, issue models.NormalizedIssue, status string) {
+		if !control.Matches(issue) {
+			return
+		}
+		key := triage.Key(issue)
+		e, ok := found[key]
+		if !ok {
+			e = &Evidence{ID: issue.ID, Tool: issue.Tool, Category: issue.Category, Resource: issue.Resource, FirstSeen: run.Timestamp}
+			found[key] = e
+			keys = append(keys, key)
+		}
+		if e.Runs == 0 || !e.LastSeen.Equal(run.Timestamp) {
+			e.Runs++
+		}
+		e.LastSeen = run.Timestamp
+		e.Severity = issue.Severity
+		if run == latest {
+			e.Status = status
+		}
+	}
+	for _, run := range runs {
+		for _, issue := range run.Issues {
+			observe(run, issue, FindingOpen)
+		}
+		for _, issue := range run.Suppressed {
+			observe(run, issue, FindingSuppressed)
+		}
+	}
+
+	out := make([]Evidence, 0, len(keys))
+	for _, key := range keys {
+		e := found[key]
+		if e.Status == "" {
+			e.Status = FindingResolved
+			if _, ok := latest.ToolReports[e.Tool]; !ok {
+				e.Status = FindingUnverified
+			}
+		}
+		out = append(out, *e)
+	}
+
+	order := map[string]int{FindingOpen: 0, FindingUnverified: 1, FindingSuppressed: 2, FindingResolved: 3}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Status != out[j].Status {
+			return order[out[i].Status] < order[out[j].Status]
+		}
+		if out[i].Tool != out[j].Tool {
+			return out[i].Tool < out[j].Tool
+		}
+		return out[i].Resource < out[j].Resource
+	})
+	return out
+}
+
+func controlStatus(findings []Evidence) string {
+	status := StatusPass
+	for _, f := range findings {
+		switch f.Status {
+		case FindingOpen:
+			return StatusFail
+		case FindingUnverified:
+			status = StatusUnverified
+		}
+	}
+	return status
+}
+
+// naturalLess compares strings with embedded numbers numerically.
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		an, arest := leadingNumber(a)
+		bn, brest := leadingNumber(b)
+		if an >= 0 && bn >= 0 {
+			if an != bn {
+				return an < bn
+			}
+			a, b = arest, brest
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+// leadingNumber parses the digits at the start of s, or returns -1.
+func leadingNumber(s string) (int, string) {
+	i, n := 0, 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		n = n*10 + int(s[i]-'0')
+		i++
+	}
+	if i == 0 {
+		return -1, s
+	}
+	return n, s[i:]
+}
+
+// categories are the issue categories a control can list.
+var categories = map[string]bool{
+	models.StatusMissing: true, models.StatusUnused: true, models.StatusStale: true,
+	models.StatusDrift: true, models.StatusError: true, models.StatusMisconfig: true,
+	models.StatusAccessDeny: true, models.StatusInvalid: true,
+}
+
+// parse decodes a mappings file, rejecting unknown fields and unknown
+// categories. Framework IDs are lower-cased.
+func parse(data []byte) (map[string]Framework, error) {
+	raw := make(map[string]Framework)
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&raw); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	frameworks := make(map[string]Framework, len(raw))
+	for id, fw := range raw {
+		for cid, c := range fw.Controls {
+			for _, cat := range c.Categories {
+				if !categories[cat] {
+					return nil, fmt.Errorf("%s %s: unknown category %q", id, cid, cat)
+				}
+			}
+		}
+		frameworks[strings.ToLower(id)] = fw
+	}
+	return frameworks, nil
+}
@@ -0,0 +1,170 @@
+package compliance
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+func TestDefaultFrameworks(t *testing.T) {
+	m := Default()
+	if got := m.Frameworks(); !reflect.DeepEqual(got, []string{"cis", "iso27001", "soc2"}) {
+		t.Fatalf("Frameworks() = %v", got)
+	}
+	for _, id := range m.Frameworks() {
+		fw, _ := m.Framework(id)
+		if fw.Name == "" {
+			t.Errorf("%s: missing name", id)
+		}
+		for cid, c := range fw.Controls {
+			if c.Title == "" {
+				t.Errorf("%s %s: missing title", id, cid)
+			}
+			if len(c.Findings) == 0 && len(c.Categories) == 0 {
+				t.Errorf("%s %s: maps no findings or categories", id, cid)
+			}
+		}
+	}
+}
+
+func TestDefaultMapsStaleAccessKey(t *testing.T) {
+	issue := models.NormalizedIssue{Tool: "iamspectre", ID: "STALE_ACCESS_KEY", Category: models.StatusStale}
+	for fwID, control := range map[string]string{"soc2": "CC6.1", "iso27001": "A.9.2.5", "cis": "AWS 1.14"} {
+		fw, ok := Default().Framework(fwID)
+		if !ok {
+			t.Fatalf("framework %s missing", fwID)
+		}
+		if !fw.Controls[control].Matches(issue) {
+			t.Errorf("%s %s does not map STALE_ACCESS_KEY", fwID, control)
+		}
+	}
+	if _, ok := Default().Framework("SOC2"); !ok {
+		t.Error("framework lookup is case-sensitive")
+	}
+}
+
+func TestControlIDsNaturalOrder(t *testing.T) {
+	fw, _ := Default().Framework("iso27001")
+	ids := fw.ControlIDs()
+	index := make(map[string]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+	if index["A.9.2.5"] > index["A.10.1.1"] || index["A.9.2.1"] > index["A.9.2.3"] {
+		t.Errorf("ControlIDs() = %v", ids)
+	}
+}
+
+func TestLoadCustomMappings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "controls.yaml")
+	content := `soc2:
+  controls:
+    CC6.1:
+      findings: [STALE_ACCESS_KEY]
+    CC9.9:
+      title: Internal review
+      categories: [drift]
+internal:
+  name: Internal access policy
+  controls:
+    IAP-1:
+      title: No unused buckets
+      findings: [UNUSED_BUCKET]
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	soc2, _ := m.Framework("soc2")
+	if c := soc2.Controls["CC6.1"]; !reflect.DeepEqual(c.Findings, []string{"STALE_ACCESS_KEY"}) || c.Title == "" {
+		t.Errorf("CC6.1 = %+v, want replaced findings and the built-in title", c)
+	}
+	if c := soc2.Controls["CC9.9"]; c.Title != "Internal review" {
+		t.Errorf("CC9.9 = %+v", c)
+	}
+	if soc2.Name != "SOC 2 Trust Services Criteria (2017)" {
+		t.Errorf("name = %q", soc2.Name)
+	}
+	if fw, ok := m.Framework("internal"); !ok || fw.Name != "Internal access policy" {
+		t.Errorf("custom framework = %+v", fw)
+	}
+
+	// The built-in mappings are not modified.
+	if c, _ := Default().Framework("soc2"); len(c.Controls["CC6.1"].Findings) == 1 {
+		t.Error("Default() was mutated by Load")
+	}
+}
+
+func TestLoadRejectsInvalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"unknown field":    "soc2:\n  controls:\n    CC6.1:\n      control_title: x\n",
+		"unknown category": "soc2:\n  controls:\n    CC6.1:\n      categories: [forgotten]\n",
+	} {
+		path := filepath.Join(dir, "controls.yaml")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("%s: Load() succeeded", name)
+		}
+	}
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("missing file accepted")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	fw := Framework{Controls: map[string]Control{
+		"AC-1": {Title: "Access keys", Findings: []string{"STALE_ACCESS_KEY"}},
+		"AC-2": {Title: "MFA", Findings: []string{"NO_MFA"}},
+		"AC-3": {Title: "Buckets", Findings: []string{"UNUSED_BUCKET"}},
+		"AC-4": {Title: "Nothing found"},
+	}}
+
+	day1 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	day3 := day2.Add(24 * time.Hour)
+	key := models.NormalizedIssue{Tool: "iamspectre", ID: "STALE_ACCESS_KEY", Category: "stale", Severity: "high", Resource: "user/alice"}
+	mfa := models.NormalizedIssue{Tool: "iamspectre", ID: "NO_MFA", Category: "misconfig", Severity: "high", Resource: "user/bob"}
+	bucket := models.NormalizedIssue{Tool: "s3spectre", ID: "UNUSED_BUCKET", Category: "unused", Severity: "low", Resource: "s3://old"}
+	iam := map[string]models.ToolReport{"iamspectre": {Tool: "iamspectre"}}
+
+	runs := []*models.AggregatedReport{
+		// Out of order on purpose: Evaluate sorts by timestamp.
+		{Timestamp: day3, Issues: []models.NormalizedIssue{key}, ToolReports: iam},
+		{Timestamp: day1, Issues: []models.NormalizedIssue{key, mfa, bucket}},
+		{Timestamp: day2, Issues: []models.NormalizedIssue{key}, Suppressed: []models.NormalizedIssue{mfa}},
+	}
+
+	results := fw.Evaluate(runs)
+	byID := make(map[string]ControlResult, len(results))
+	for _, r := range results {
+		byID[r.Control] = r
+	}
+
+	ac1 := byID["AC-1"]
+	if ac1.Status != StatusFail || len(ac1.Findings) != 1 {
+		t.Fatalf("AC-1 = %+v", ac1)
+	}
+	if e := ac1.Findings[0]; e.Status != FindingOpen || e.Runs != 3 || !e.FirstSeen.Equal(day1) || !e.LastSeen.Equal(day3) {
+		t.Errorf("AC-1 evidence = %+v", e)
+	}
+	if r := byID["AC-2"]; r.Status != StatusPass || r.Findings[0].Status != FindingResolved || !r.Findings[0].LastSeen.Equal(day2) {
+		t.Errorf("AC-2 = %+v", r)
+	}
+	if r := byID["AC-3"]; r.Status != StatusUnverified || r.Findings[0].Status != FindingUnverified {
+		t.Errorf("AC-3 = %+v, want unverified: s3spectre did not report in the latest run", r)
+	}
+	if r := byID["AC-4"]; r.Status != StatusPass || len(r.Findings) != 0 {
+		t.Errorf("AC-4 = %+v", r)
+	}
+}
@@ -1,11 +1,21 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/ppiankov/spectrehub/internal/atomicfile"
+	"github.com/ppiankov/spectrehub/internal/notify"
+	"github.com/ppiankov/spectrehub/internal/redact"
+	"github.com/ppiankov/spectrehub/internal/scheduler"
+	"github.com/ppiankov/spectrehub/internal/storage"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
 // Config holds all configuration for SpectreHub
@@ -13,6 +23,9 @@ type Config struct {
 	// Storage configuration
 	StorageDir string `mapstructure:"storage_dir"`
 
+	// Encryption and signing of stored runs
+	Storage storage.Config `mapstructure:"storage"`
+
 	// Threshold for CI/CD failure
 	FailThreshold int `mapstructure:"fail_threshold"`
 
@@ -36,6 +49,54 @@ type Config struct {
 
 	// API URL (defaults to https://api.spectrehub.dev)
 	APIURL string `mapstructure:"api_url"`
+
+	// Remediation knowledge base overrides (YAML keyed by ID or tool/ID)
+	RemediationFile string `mapstructure:"remediation_file"`
+
+	// Compliance control mapping overrides (YAML keyed by framework ID)
+	ComplianceFile string `mapstructure:"compliance_file"`
+
+	// Tools every run should report on; a run without one of them is partial
+	ExpectedTools []string `mapstructure:"expected_tools"`
+
+	// Input reports older than this are stale (0 disables the check)
+	MaxReportAge time.Duration `mapstructure:"max_report_age"`
+
+	// Fail instead of warn when an input report is stale
+	FailStaleReports bool `mapstructure:"fail_stale_reports"`
+
+	// Retries of a tool that timed out or crashed during run/daemon
+	Retries int `mapstructure:"retries"`
+
+	// Wait before the first retry, doubling on each further attempt
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+
+	// Webhook and chat notifications after a run
+	Notifications notify.Config `mapstructure:"notifications"`
+
+	// What to remove before data leaves the machine (upload, export, notify)
+	Redaction redact.Config `mapstructure:"redaction"`
+
+	// Schedules for spectrehub daemon
+	Daemon scheduler.Config `mapstructure:"daemon"`
+
+	// Saved TUI filter presets
+	TUIPresets []FilterPreset `mapstructure:"tui_presets"`
+
+	// File is the config file that was loaded, empty when none was found
+	File string `mapstructure:"-"`
+}
+
+// FilterPreset is a named set of TUI filters. Empty fields match everything.
+type FilterPreset struct {
+	Name     string `mapstructure:"name" yaml:"name"`
+	Tool     string `mapstructure:"tool" yaml:"tool,omitempty"`
+	Severity string `mapstructure:"severity" yaml:"severity,omitempty"`
+	Category string `mapstructure:"category" yaml:"category,omitempty"`
+	Owner    string `mapstructure:"owner" yaml:"owner,omitempty"`
+	Search   string `mapstructure:"search" yaml:"search,omitempty"`
+	// Group shows the findings as a tool/category/rule tree
+	Group bool `mapstructure:"group" yaml:"group,omitempty"`
 }
 
 // DefaultConfig returns configuration with default values
@@ -75,6 +136,8 @@ func LoadFromFile(configPath string) (*Config, error) {
 	v.SetDefault("repo", "")
 	v.SetDefault("license_key", "")
 	v.SetDefault("api_url", "https://api.spectrehub.dev")
+	v.SetDefault("remediation_file", "")
+	v.SetDefault("compliance_file", "")
 
 	// Set config file settings
 	v.SetConfigName("spectrehub")
@@ -117,6 +180,7 @@ func LoadFromFile(configPath string) (*Config, error) {
 	if err := v.Unmarshal(cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	cfg.File = v.ConfigFileUsed()
 
 	// Validate config
 	if err := cfg.Validate(); err != nil {
@@ -153,6 +217,48 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("storage_dir cannot be empty")
 	}
 
+	// Validate max_report_age
+	if c.MaxReportAge < 0 {
+		return fmt.Errorf("max_report_age cannot be negative")
+	}
+
+	// Validate retries
+	if c.Retries < 0 || c.RetryBackoff < 0 {
+		return fmt.Errorf("retries and retry_backoff cannot be negative")
+	}
+
+	// Validate notifications
+	if err := c.Notifications.Validate(); err != nil {
+		return err
+	}
+
+	// Validate redaction profile
+	if err := c.Redaction.Validate(); err != nil {
+		return err
+	}
+
+	// Validate daemon schedules
+	if err := c.Daemon.Validate(); err != nil {
+		return err
+	}
+
+	// Validate TUI presets
+	names := make(map[string]bool)
+	for i, p := range c.TUIPresets {
+		if p.Name == "" {
+			return fmt.Errorf("tui_presets[%d]: name is required", i)
+		}
+		if names[p.Name] {
+			return fmt.Errorf("tui_presets: duplicate name %q", p.Name)
+		}
+		names[p.Name] = true
+		switch p.Severity {
+		case "", "critical", "high", "medium", "low":
+		default:
+			return fmt.Errorf("tui_presets %q: invalid severity %q (must be critical, high, medium, or low)", p.Name, p.Severity)
+		}
+	}
+
 	return nil
 }
 
@@ -246,6 +352,84 @@ func WriteActivation(licenseKey, apiURL, path string) error {
 	return nil
 }
 
+// WritePresets atomically replaces the TUI filter presets in the config
+// file. Only the tui_presets key is rewritten; the other keys, their order
+// and comments are kept as they are.
+func WritePresets(presets []FilterPreset, path string) error {
+	if path == "" {
+		path = ConfigPath()
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("create config directory: %w", err)
+	}
+
+	var doc yaml.Node
+	mode := os.FileMode(0600)
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("read config: %w", err)
+		}
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("read config: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode {
+		doc = yaml.Node{Kind: yaml.DocumentNode}
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("read config: %s is not a YAML mapping", path)
+	}
+
+	if presets == nil {
+		presets = []FilterPreset{}
+	}
+	var value yaml.Node
+	if err := value.Encode(presets); err != nil {
+		return fmt.Errorf("encode presets: %w", err)
+	}
+	setMappingValue(root, "tui_presets", &value)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+
+	if err := atomicfile.WriteFile(path, buf.Bytes(), mode); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+// setMappingValue replaces the value of key in a YAML mapping node, keeping
+// the key's comments, or appends the key when it is missing.
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
 // GenerateSampleConfig generates a sample configuration file content
 func GenerateSampleConfig() string {
 	return `# SpectreHub Configuration
@@ -254,6 +438,29 @@ func GenerateSampleConfig() string {
 # Directory to store aggregated reports
 storage_dir: .spectre
 
+# Protect stored runs. With an encryption key runs are saved with AES-256-GCM
+# (*.json.enc); with a signing key each run gets a detached ed25519
+# signature (*.sig) and summarize, diff and export refuse runs whose
+# signature is missing or wrong. Keys are 32 random bytes, hex or base64:
+#   openssl rand -hex 32 > ~/.spectrehub/storage.key
+# Or set SPECTREHUB_STORAGE_KEY / SPECTREHUB_SIGNING_KEY. Machines that only
+# read runs can verify with the public key shown by spectrehub status.
+# storage:
+#   encryption_key_file: ~/.spectrehub/storage.key
+#   signing_key_file: ~/.spectrehub/signing.key
+#   public_key: <hex>
+#
+# Keep runs in an S3-compatible bucket so ephemeral CI runners share their
+# history (--storage-url on run, collect, summarize and diff). Runs of each
+# repo and environment live under <prefix>/<repo>/<environment>. Credentials
+# come from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN.
+#   url: s3://my-bucket/spectrehub
+#   environment: prod
+#   s3:
+#     endpoint: http://localhost:9000   # MinIO; default is AWS
+#     region: us-east-1
+#     path_style: true
+
 # Fail threshold for CI/CD (exit code 1 if issues exceed this number)
 # Set to 0 to disable threshold checking
 fail_threshold: 50
@@ -277,5 +484,105 @@ debug: false
 
 # API URL (change only for self-hosted or testing)
 # api_url: https://api.spectrehub.dev
+
+# Override or extend the built-in remediation guidance, keyed by finding ID
+# or by <tool>/<ID> for one tool only (e.g. clickspectre/UNUSED_TABLE):
+#   UNUSED_BUCKET:
+#     fix: Ask #data-platform before deleting
+#     snippet: aws s3 rb s3://<bucket> --force
+#     effort: low          # low, medium or high
+#     doc: https://wiki.example.com/s3-cleanup
+# remediation_file: .spectrehub-remediations.yaml
+
+# Override or extend the compliance control mappings used by
+# export --framework, or add your own framework:
+#   soc2:
+#     controls:
+#       CC6.1:
+#         findings: [STALE_ACCESS_KEY, NO_MFA]
+#   internal:
+#     name: Internal access policy
+#     controls:
+#       IAP-1:
+#         title: No unused buckets
+#         findings: [UNUSED_BUCKET]
+# compliance_file: .spectrehub-controls.yaml
+
+# Tools every run/collect should include. A run missing one of them gets a
+# partial score, and the missing tool's previous issues are not counted as
+# resolved in trends and diffs. run also expects every tool it executes.
+# expected_tools: [vaultspectre, s3spectre, kafkaspectre]
+
+# Warn when collect aggregates a tool report older than this, or fail with
+# fail_stale_reports. Each tool's report time is shown in the output.
+# max_report_age: 72h
+# fail_stale_reports: false
+
+# Retry tools that time out or crash (not missing binaries, credential
+# errors or non-JSON output). The backoff doubles on each attempt.
+# retries: 2
+# retry_backoff: 2s
+
+# Notifications after run/collect. Each channel receives the triggers it
+# lists (default: all): new_critical, new_high, score_below,
+# policy_failed, tool_failed. An event is sent once and again only after
+# it clears, or after renotify_after.
+# notifications:
+#   score_below: 70
+#   retries: 3              # extra attempts, with exponential backoff
+#   backoff: 1s
+#   renotify_after: 24h
+#   channels:
+#     - name: ops
+#       type: slack          # webhook, slack or teams
+#       url: https://hooks.slack.com/services/T000/B000/XXXX
+#       triggers: [new_critical, score_below, tool_failed]
+#     - name: audit
+#       type: webhook
+#       url: https://example.com/hooks/spectrehub
+#       headers:
+#         Authorization: Bearer <token>
+#       template: |
+#         {{.Repo}} score {{printf "%.0f" .Score}}%
+#         {{range .Events}}- {{.Summary}}
+#         {{end}}
+
+# Redaction applied to API uploads, export and notifications; stored runs
+# keep the full data. Resource identifiers (bucket names, Vault paths,
+# usernames) are replaced with a keyed HMAC-SHA256 so they stay stable
+# across runs: hash replaces them entirely, mask keeps the scheme and first
+# letters. Redacting identifiers also drops the raw tool data. Preview with
+# spectrehub collect --show-redacted.
+# redaction:
+#   drop_raw_data: true
+#   resources: hash          # hash or mask
+#   key: <secret>            # or SPECTREHUB_REDACTION_KEY
+#   strip_evidence: true     # evidence text and tool stderr
+#   fields: [id, count, first_seen, last_seen]  # finding fields to keep
+
+# Schedules for spectrehub daemon. Each schedule runs its tools (default:
+# every runnable tool) on a cron expression or @hourly/@daily/@every 30m,
+# stores the run and sends notifications.
+# daemon:
+#   jitter: 2m                # random delay added to each activation
+#   shutdown_timeout: 5m      # on SIGTERM, wait this long for running scans
+#   schedules:
+#     - name: iam
+#       cron: "@hourly"
+#       tools: [iamspectre]
+#     - name: storage
+#       cron: "30 3 * * *"    # daily at 03:30
+#       tools: [s3spectre, gcsspectre]
+#       timeout: 15m          # per-tool timeout
+
+# Filter presets for the summarize TUI (press p to apply, P to save)
+# tui_presets:
+#   - name: k8s-critical
+#     tool: kubespectre
+#     severity: critical
+#     category: misconfig
+#   - name: unowned
+#     owner: (unassigned)
+#     group: true
 `
 }
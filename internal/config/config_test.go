@@ -3,7 +3,11 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/redact"
 )
 
 func TestWriteActivationNewFile(t *testing.T) {
@@ -182,6 +186,12 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "storage_dir cannot be empty",
 		},
+		{
+			name:    "invalid redaction mode",
+			cfg:     Config{StorageDir: ".spectre", Format: "text", LastRuns: 7, Redaction: redact.Config{Resources: "encrypt"}},
+			wantErr: true,
+			errMsg:  "invalid resources mode",
+		},
 	}
 
 	for _, tt := range tests {
@@ -279,6 +289,8 @@ format: json
 last_runs: 10
 verbose: true
 debug: true
+remediation_file: fixes.yaml
+compliance_file: controls.yaml
 `
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		t.Fatal(err)
@@ -307,6 +319,71 @@ debug: true
 	if !cfg.Debug {
 		t.Error("expected debug=true")
 	}
+	if cfg.RemediationFile != "fixes.yaml" {
+		t.Errorf("expected remediation_file=fixes.yaml, got %s", cfg.RemediationFile)
+	}
+	if cfg.ComplianceFile != "controls.yaml" {
+		t.Errorf("expected compliance_file=controls.yaml, got %s", cfg.ComplianceFile)
+	}
+}
+
+func TestLoadFromFileDaemon(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spectrehub.yaml")
+	content := `daemon:
+  jitter: 2m
+  shutdown_timeout: 30s
+  schedules:
+    - name: iam
+      cron: "@hourly"
+      tools: [iamspectre]
+    - name: storage
+      cron: "30 3 * * *"
+      timeout: 15m
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	d := cfg.Daemon
+	if d.Jitter != 2*time.Minute || d.ShutdownTimeout != 30*time.Second || len(d.Schedules) != 2 {
+		t.Fatalf("unexpected daemon config: %+v", d)
+	}
+	if d.Schedules[0].Tools[0] != "iamspectre" || d.Schedules[1].Timeout != 15*time.Minute {
+		t.Errorf("unexpected schedules: %+v", d.Schedules)
+	}
+
+	bad := strings.Replace(content, `"@hourly"`, `"hourly"`, 1)
+	if err := os.WriteFile(path, []byte(bad), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFromFile(path); err == nil || !strings.Contains(err.Error(), "iam") {
+		t.Errorf("expected invalid cron error, got %v", err)
+	}
+}
+
+func TestLoadFromFileRetries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spectrehub.yaml")
+	if err := os.WriteFile(path, []byte("retries: 2\nretry_backoff: 5s\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if cfg.Retries != 2 || cfg.RetryBackoff != 5*time.Second {
+		t.Errorf("unexpected retries: %d %s", cfg.Retries, cfg.RetryBackoff)
+	}
+
+	if err := os.WriteFile(path, []byte("retries: -1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFromFile(path); err == nil || !strings.Contains(err.Error(), "negative") {
+		t.Errorf("expected negative retries error, got %v", err)
+	}
 }
 
 func TestLoadFromFileInvalidConfig(t *testing.T) {
@@ -393,3 +470,128 @@ func TestLoadFromFileWithEnvVars(t *testing.T) {
 		t.Error("expected verbose=true from env")
 	}
 }
+
+func TestWritePresetsRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spectrehub.yaml")
+	if err := os.WriteFile(path, []byte("storage_dir: /custom/path\nlast_runs: 3\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	presets := []FilterPreset{
+		{Name: "k8s-critical", Tool: "kubespectre", Severity: "critical", Category: "misconfig"},
+		{Name: "unowned", Owner: "(unassigned)", Group: true},
+	}
+	if err := WritePresets(presets, path); err != nil {
+		t.Fatalf("WritePresets: %v", err)
+	}
+
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if cfg.StorageDir != "/custom/path" || cfg.LastRuns != 3 {
+		t.Errorf("existing values not preserved: %+v", cfg)
+	}
+	if len(cfg.TUIPresets) != 2 || cfg.TUIPresets[0] != presets[0] || cfg.TUIPresets[1] != presets[1] {
+		t.Errorf("presets = %+v, want %+v", cfg.TUIPresets, presets)
+	}
+	if cfg.File != path {
+		t.Errorf("File = %q, want %q", cfg.File, path)
+	}
+}
+
+func TestWritePresetsNewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "spectrehub.yaml")
+	if err := WritePresets([]FilterPreset{{Name: "all-high", Severity: "high"}}, path); err != nil {
+		t.Fatalf("WritePresets: %v", err)
+	}
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if len(cfg.TUIPresets) != 1 || cfg.TUIPresets[0].Severity != "high" {
+		t.Errorf("presets = %+v", cfg.TUIPresets)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("new config file should be owner-only: %v, %v", info, err)
+	}
+}
+
+func TestWritePresetsKeepsCommentsAndOrder(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spectrehub.yaml")
+	original := `# Team config, reviewed quarterly.
+last_runs: 3 # one per sprint
+tui_presets:
+  - name: old
+    tool: vaultspectre
+# Keep reports on the shared volume.
+storage_dir: /custom/path
+`
+	if err := os.WriteFile(path, []byte(original), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WritePresets([]FilterPreset{{Name: "all-high", Severity: "high"}}, path); err != nil {
+		t.Fatalf("WritePresets: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Team config, reviewed quarterly.
+last_runs: 3 # one per sprint
+tui_presets:
+  - name: all-high
+    severity: high
+# Keep reports on the shared volume.
+storage_dir: /custom/path
+`
+	if string(data) != want {
+		t.Errorf("config after WritePresets:\n%s\nwant:\n%s", data, want)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0640 {
+		t.Errorf("mode = %o, want the original 640", perm)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("temporary files left: %v", entries)
+	}
+}
+
+func TestValidatePresets(t *testing.T) {
+	base := func(presets ...FilterPreset) Config {
+		return Config{StorageDir: ".spectre", Format: "text", LastRuns: 7, TUIPresets: presets}
+	}
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"valid", base(FilterPreset{Name: "a", Severity: "low"}), ""},
+		{"missing name", base(FilterPreset{Tool: "s3spectre"}), "name is required"},
+		{"duplicate", base(FilterPreset{Name: "a"}, FilterPreset{Name: "a"}), "duplicate"},
+		{"bad severity", base(FilterPreset{Name: "a", Severity: "urgent"}), "invalid severity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// Package metrics renders stored runs in the Prometheus text exposition
+// format, for the node_exporter textfile collector or a /metrics endpoint.
+//
+// Labels are limited to tool, target type, category and severity so the
+// number of series stays bounded no matter how many resources are scanned.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/storage"
+)
+
+// ContentType is the media type of the exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Snapshot is the data exposed as metrics.
+type Snapshot struct {
+	// Latest is the most recent stored run.
+	Latest *models.AggregatedReport
+	// LastSuccess is when the most recent run without tool failures
+	// happened; zero when there is none.
+	LastSuccess time.Time
+}
+
+// Load builds a snapshot from storage. It returns storage errors, including
+// when no runs exist.
+func Load(store storage.Storage) (*Snapshot, error) {
+	runs, err := store.ListRuns()
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("no stored runs found")
+	}
+
+	snap := &Snapshot{}
+	// ListRuns is oldest first; walk back until a run succeeded.
+	for i := len(runs) - 1; i >= 0; i-- {
+		report, err := store.LoadAggregatedReport(runs[i])
+		if err != nil {
+			return nil, err
+		}
+		if snap.Latest == nil {
+			snap.Latest = report
+		}
+		if Succeeded(report) {
+			snap.LastSuccess = report.Timestamp
+			break
+		}
+	}
+	return snap, nil
+}
+
+// Succeeded reports whether every tool in the run produced a report. Runs
+// without execution records (collect) succeeded by definition.
+func Succeeded(report *models.AggregatedReport) bool {
+	for _, e := range report.Executions {
+		if !e.Success {
+			return false
+		}
+	}
+	return true
+}
+
+// family is one metric with its samples.
+type family struct {
+	name    string
+	help    string
+	samples []sample
+}
+
+type sample struct {
+	labels []label
+	value  float64
+}
+
+type label struct {
+	name, value string
+}
+
+// Write renders the snapshot in the Prometheus text format. Output is
+// deterministic: families in a fixed order, samples sorted by labels.
+func Write(w io.Writer, snap *Snapshot) error {
+	var b strings.Builder
+	for _, f := range families(snap) {
+		sort.SliceStable(f.samples, func(i, j int) bool {
+			return labelKey(f.samples[i].labels) < labelKey(f.samples[j].labels)
+		})
+		fmt.Fprintf(&b, "# HELP %s %s\n", f.name, f.help)
+		fmt.Fprintf(&b, "# TYPE %s gauge\n", f.name)
+		for _, s := range f.samples {
+			b.WriteString(f.name)
+			if len(s.labels) > 0 {
+				b.WriteString("{")
+				for i, l := range s.labels {
+					if i > 0 {
+						b.WriteString(",")
+					}
+					fmt.Fprintf(&b, "%s=\"%s\"", l.name, escape(l.value))
+				}
+				b.WriteString("}")
+			}
+			b.WriteString(" ")
+			b.WriteString(strconv.FormatFloat(s.value, 'f', -1, 64))
+			b.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func families(snap *Snapshot) []family {
+	report := snap.Latest
+
+	score := family{name: "spectrehub_score_percent", help: "Overall health score of the latest run (0-100)."}
+	score.samples = append(score.samples, sample{value: report.Summary.ScorePercent})
+
+	toolScore := family{name: "spectrehub_tool_score_percent", help: "Health score per tool and target type in the latest run (0-100)."}
+	for tool, tr := range report.ToolReports {
+		toolScore.samples = append(toolScore.samples, sample{
+			labels: []label{{"tool", tool}, {"target", targetType(tool, tr)}},
+			value:  tr.Score,
+		})
+	}
+
+	issues := family{name: "spectrehub_issues", help: "Open issues in the latest run by tool, category and severity."}
+	type issueKey struct{ tool, category, severity string }
+	counts := make(map[issueKey]int)
+	var waste float64
+	for _, issue := range report.Issues {
+		counts[issueKey{issue.Tool, issue.Category, issue.Severity}]++
+		waste += issue.EstimatedMonthlyWaste
+	}
+	for k, n := range counts {
+		issues.samples = append(issues.samples, sample{
+			labels: []label{{"tool", k.tool}, {"category", k.category}, {"severity", k.severity}},
+			value:  float64(n),
+		})
+	}
+
+	wasteFamily := family{name: "spectrehub_estimated_monthly_waste_dollars", help: "Estimated monthly waste in USD across open issues in the latest run."}
+	wasteFamily.samples = append(wasteFamily.samples, sample{value: waste})
+
+	duration := family{name: "spectrehub_tool_run_duration_seconds", help: "Execution time per tool in the latest run."}
+	success := family{name: "spectrehub_tool_run_success", help: "Whether each tool produced a report in the latest run (1 or 0)."}
+	for _, e := range report.Executions {
+		labels := []label{{"tool", e.Tool}}
+		duration.samples = append(duration.samples, sample{labels: labels, value: e.DurationSeconds})
+		success.samples = append(success.samples, sample{labels: labels, value: boolValue(e.Success)})
+	}
+
+	lastRun := family{name: "spectrehub_last_run_timestamp_seconds", help: "Unix time of the latest run."}
+	lastRun.samples = append(lastRun.samples, sample{value: unixSeconds(report.Timestamp)})
+
+	lastSuccess := family{name: "spectrehub_last_successful_run_timestamp_seconds", help: "Unix time of the latest run in which every tool succeeded."}
+	if !snap.LastSuccess.IsZero() {
+		lastSuccess.samples = append(lastSuccess.samples, sample{value: unixSeconds(snap.LastSuccess)})
+	}
+
+	return []family{score, toolScore, issues, wasteFamily, duration, success, lastRun, lastSuccess}
+}
+
+// targetType returns the scanned target's type: the spectre/v1 envelope
+// value when present, else the type the tool is known to scan.
+func targetType(tool string, tr models.ToolReport) string {
+	switch raw := tr.RawData.(type) {
+	case *models.SpectreV1Report:
+		if raw.Target.Type != "" {
+			return raw.Target.Type
+		}
+	case map[string]interface{}: // reloaded from storage
+		if target, ok := raw["target"].(map[string]interface{}); ok {
+			if t, ok := target["type"].(string); ok && t != "" {
+				return t
+			}
+		}
+	}
+	return models.SpectreV1TargetTypes[tool]
+}
+
+func labelKey(labels []label) string {
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		parts[i] = l.value
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// escape escapes a label value per the exposition format.
+func escape(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func unixSeconds(t time.Time) float64 {
+	return float64(t.Unix())
+}
@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/storage"
+)
+
+var ts = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func sampleReport(at time.Time, execs ...models.ToolExecution) *models.AggregatedReport {
+	return &models.AggregatedReport{
+		Timestamp: at,
+		Issues: []models.NormalizedIssue{
+			{Tool: "vaultspectre", Category: "missing", Severity: "critical", Resource: "secret/a"},
+			{Tool: "vaultspectre", Category: "missing", Severity: "critical", Resource: "secret/b"},
+			{Tool: "awsspectre", Category: "unused", Severity: "high", Resource: "i-123", EstimatedMonthlyWaste: 42.5},
+			{Tool: "awsspectre", Category: "unused", Severity: "low", Resource: `vol-"9"`, EstimatedMonthlyWaste: 7.5},
+		},
+		ToolReports: map[string]models.ToolReport{
+			"vaultspectre": {Tool: "vaultspectre", Score: 80},
+			"awsspectre": {Tool: "awsspectre", Score: 65.5, RawData: &models.SpectreV1Report{
+				Target: models.SpectreV1Target{Type: "aws-account"},
+			}},
+		},
+		Summary:    models.CrossToolSummary{ScorePercent: 72.75},
+		Executions: execs,
+	}
+}
+
+func TestWrite(t *testing.T) {
+	snap := &Snapshot{
+		Latest: sampleReport(ts,
+			models.ToolExecution{Tool: "vaultspectre", DurationSeconds: 1.25, Success: true},
+			models.ToolExecution{Tool: "awsspectre", DurationSeconds: 30, Success: true},
+		),
+		LastSuccess: ts,
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, snap); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# HELP spectrehub_score_percent Overall health score of the latest run (0-100).
+# TYPE spectrehub_score_percent gauge
+spectrehub_score_percent 72.75
+# HELP spectrehub_tool_score_percent Health score per tool and target type in the latest run (0-100).
+# TYPE spectrehub_tool_score_percent gauge
+spectrehub_tool_score_percent{tool="awsspectre",target="aws-account"} 65.5
+spectrehub_tool_score_percent{tool="vaultspectre",target="vault"} 80
+# HELP spectrehub_issues Open issues in the latest run by tool, category and severity.
+# TYPE spectrehub_issues gauge
+spectrehub_issues{tool="awsspectre",category="unused",severity="high"} 1
+spectrehub_issues{tool="awsspectre",category="unused",severity="low"} 1
+spectrehub_issues{tool="vaultspectre",category="missing",severity="critical"} 2
+# HELP spectrehub_estimated_monthly_waste_dollars Estimated monthly waste in USD across open issues in the latest run.
+# TYPE spectrehub_estimated_monthly_waste_dollars gauge
+spectrehub_estimated_monthly_waste_dollars 50
+# HELP spectrehub_tool_run_duration_seconds Execution time per tool in the latest run.
+# TYPE spectrehub_tool_run_duration_seconds gauge
+spectrehub_tool_run_duration_seconds{tool="awsspectre"} 30
+spectrehub_tool_run_duration_seconds{tool="vaultspectre"} 1.25
+# HELP spectrehub_tool_run_success Whether each tool produced a report in the latest run (1 or 0).
+# TYPE spectrehub_tool_run_success gauge
+spectrehub_tool_run_success{tool="awsspectre"} 1
+spectrehub_tool_run_success{tool="vaultspectre"} 1
+# HELP spectrehub_last_run_timestamp_seconds Unix time of the latest run.
+# TYPE spectrehub_last_run_timestamp_seconds gauge
+spectrehub_last_run_timestamp_seconds 1772355600
+# HELP spectrehub_last_successful_run_timestamp_seconds Unix time of the latest run in which every tool succeeded.
+# TYPE spectrehub_last_successful_run_timestamp_seconds gauge
+spectrehub_last_successful_run_timestamp_seconds 1772355600
+`
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteHasNoResourceLabels(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, &Snapshot{Latest: sampleReport(ts)}); err != nil {
+		t.Fatal(err)
+	}
+	for _, resource := range []string{"secret/a", "i-123", "vol-"} {
+		if strings.Contains(buf.String(), resource) {
+			t.Errorf("output leaks resource %q", resource)
+		}
+	}
+	if strings.Contains(buf.String(), "spectrehub_last_successful_run_timestamp_seconds 1") {
+		t.Error("expected no last-success sample without a successful run")
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got := escape("a\\b\"c\nd"); got != `a\\b\"c\nd` {
+		t.Errorf("escape = %q", got)
+	}
+}
+
+func TestTargetTypeFromStoredRawData(t *testing.T) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"target":{"type":"gcp-project"}}`), &raw); err != nil {
+		t.Fatal(err)
+	}
+	if got := targetType("iamspectre", models.ToolReport{RawData: raw}); got != "gcp-project" {
+		t.Errorf("targetType = %q, want gcp-project", got)
+	}
+	if got := targetType("unknownspectre", models.ToolReport{}); got != "" {
+		t.Errorf("targetType = %q, want empty", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	store := storage.NewLocal(t.TempDir())
+	ok := sampleReport(ts, models.ToolExecution{Tool: "vaultspectre", Success: true})
+	failed := sampleReport(ts.Add(time.Hour), models.ToolExecution{Tool: "vaultspectre", Success: false, Error: "timeout"})
+	for _, r := range []*models.AggregatedReport{ok, failed} {
+		if err := store.SaveAggregatedReport(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snap, err := Load(store)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !snap.Latest.Timestamp.Equal(failed.Timestamp) {
+		t.Errorf("Latest = %s, want %s", snap.Latest.Timestamp, failed.Timestamp)
+	}
+	if !snap.LastSuccess.Equal(ts) {
+		t.Errorf("LastSuccess = %s, want %s", snap.LastSuccess, ts)
+	}
+}
+
+func TestLoadEmpty(t *testing.T) {
+	if _, err := Load(storage.NewLocal(t.TempDir())); err == nil {
+		t.Error("expected error without stored runs")
+	}
+}
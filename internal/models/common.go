@@ -201,10 +201,18 @@ const (
 	SeverityLow      = "low"
 )
 
+// Triage statuses for issues
+const (
+	TriageOpen         = "open"
+	TriageAcknowledged = "acknowledged"
+	TriageSuppressed   = "suppressed"
+)
+
 // NormalizedIssue is the atomic unit that every tool maps into
 // This enables diff, query, and correlation without refactoring
 type NormalizedIssue struct {
 	Tool      string    `json:"tool"`               // vaultspectre, s3spectre, etc.
+	ID        string    `json:"id,omitempty"`       // tool finding ID (spectre/v1), e.g. MISSING_SECRET
 	Category  string    `json:"category"`           // missing, unused, stale, error, misconfig
 	Severity  string    `json:"severity"`           // critical, high, medium, low
 	Resource  string    `json:"resource"`           // vault path / s3://bucket / topic / db.table
@@ -212,16 +220,96 @@ type NormalizedIssue struct {
 	Count     int       `json:"count,omitempty"`    // how many instances
 	FirstSeen time.Time `json:"first_seen,omitempty"`
 	LastSeen  time.Time `json:"last_seen,omitempty"`
+
+	EstimatedMonthlyWaste float64 `json:"estimated_monthly_waste,omitempty"` // USD, spectre/v1 only
+
+	// Finding metadata, spectre/v1.1 only.
+	Attributes  *ResourceAttributes `json:"attributes,omitempty"`
+	Remediation string              `json:"remediation,omitempty"`
+	Fingerprint string              `json:"fingerprint,omitempty"`
+	References  []string            `json:"references,omitempty"`
+	Confidence  *float64            `json:"confidence,omitempty"` // 0 to 1
+
+	Triage string `json:"triage,omitempty"` // acknowledged, suppressed or open, when triaged
+	Owner  string `json:"owner,omitempty"`  // assigned during triage
 }
 
 // AggregatedReport contains the complete aggregated output from all tools
 type AggregatedReport struct {
 	Timestamp       time.Time             `json:"timestamp"`
-	Issues          []NormalizedIssue     `json:"issues"`          // Atomic issue list
-	ToolReports     map[string]ToolReport `json:"tool_reports"`    // Per-tool raw data
-	Summary         CrossToolSummary      `json:"summary"`         // Overall statistics
-	Trend           *Trend                `json:"trend,omitempty"` // Comparison with previous run
-	Recommendations []Recommendation      `json:"recommendations"` // Prioritized actions
+	Issues          []NormalizedIssue     `json:"issues"`               // Atomic issue list
+	ToolReports     map[string]ToolReport `json:"tool_reports"`         // Per-tool raw data
+	Summary         CrossToolSummary      `json:"summary"`              // Overall statistics
+	Trend           *Trend                `json:"trend,omitempty"`      // Comparison with previous run
+	Recommendations []Recommendation      `json:"recommendations"`      // Prioritized actions
+	Incidents       []Incident            `json:"incidents,omitempty"`  // Correlated cross-tool findings
+	Suppressed      []NormalizedIssue     `json:"suppressed,omitempty"` // Issues hidden by triage, not counted
+	Executions      []ToolExecution       `json:"executions,omitempty"` // Tool runs, when produced by spectrehub run
+	Coverage        *Coverage             `json:"coverage,omitempty"`   // Expected vs delivered tools, when known
+	Freshness       []ToolFreshness       `json:"freshness,omitempty"`  // When each tool's input report was generated
+	Chain           *ChainLink            `json:"chain,omitempty"`      // Link to the previous stored run, set by storage
+}
+
+// ChainLink ties a stored run to the run stored before it. Each run records
+// the SHA-256 of its predecessor, so editing, deleting or reordering stored
+// runs breaks the chain.
+type ChainLink struct {
+	Sequence     int    `json:"sequence"`                // 1 for the first run of the chain
+	PreviousHash string `json:"previous_hash,omitempty"` // empty when no run was stored before
+}
+
+// ToolFreshness is the age of a tool's input report when it was aggregated.
+type ToolFreshness struct {
+	Tool       string    `json:"tool"`
+	Timestamp  time.Time `json:"timestamp,omitempty"` // zero when the report carries no timestamp
+	AgeSeconds float64   `json:"age_seconds,omitempty"`
+	Stale      bool      `json:"stale,omitempty"` // older than max_report_age
+}
+
+// Age is the report age, zero when the timestamp is unknown.
+func (f ToolFreshness) Age() time.Duration {
+	return time.Duration(f.AgeSeconds * float64(time.Second))
+}
+
+// Coverage compares the tools a run expected with those that delivered a
+// report. The score of a run with missing tools is partial: their issues
+// are absent, not resolved.
+type Coverage struct {
+	Expected  []string `json:"expected"`
+	Delivered []string `json:"delivered"`
+	Missing   []string `json:"missing,omitempty"`
+}
+
+// Percent is the share of expected tools that delivered a report.
+func (c *Coverage) Percent() float64 {
+	if len(c.Expected) == 0 {
+		return 100
+	}
+	return float64(len(c.Delivered)) / float64(len(c.Expected)) * 100
+}
+
+// ToolExecution records how a tool invocation went during spectrehub run
+type ToolExecution struct {
+	Tool            string  `json:"tool"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Success         bool    `json:"success"`
+	Error           string  `json:"error,omitempty"`
+	// FailureClass is not_found, timeout, auth, non_json or crash.
+	FailureClass string   `json:"failure_class,omitempty"`
+	Attempts     int      `json:"attempts,omitempty"`
+	Stderr       []string `json:"stderr,omitempty"` // trailing stderr lines of a failed run
+}
+
+// ToolErrors returns the tool runs that failed, so a scanner that errored is
+// distinguishable from one that found nothing.
+func (r *AggregatedReport) ToolErrors() []ToolExecution {
+	var failed []ToolExecution
+	for _, e := range r.Executions {
+		if !e.Success {
+			failed = append(failed, e)
+		}
+	}
+	return failed
 }
 
 // ToolReport contains data for a single tool
@@ -248,6 +336,8 @@ type CrossToolSummary struct {
 	TotalTools       int            `json:"total_tools"`
 	SupportedTools   int            `json:"supported_tools"`
 	UnsupportedTools int            `json:"unsupported_tools"`
+	CoveragePercent  float64        `json:"coverage_percent,omitempty"` // share of expected tools that reported
+	Partial          bool           `json:"partial,omitempty"`          // score excludes missing tools
 }
 
 // Trend represents change between current and previous run
@@ -259,6 +349,10 @@ type Trend struct {
 	ComparedWith   time.Time `json:"compared_with"`   // When previous run was
 	NewIssues      int       `json:"new_issues"`      // Issues that appeared
 	ResolvedIssues int       `json:"resolved_issues"` // Issues that disappeared
+	// MissingTools reported in the previous run but not this one; their
+	// previous issues are left out of the comparison instead of counting as
+	// resolved.
+	MissingTools []string `json:"missing_tools,omitempty"`
 }
 
 // Recommendation represents an actionable item to fix
@@ -268,13 +362,40 @@ type Recommendation struct {
 	Action   string `json:"action"` // What to do
 	Impact   string `json:"impact"` // Why it matters
 	Count    int    `json:"count"`  // How many items
+
+	ID           string       `json:"id,omitempty"`                      // finding ID the items share (spectre/v1)
+	Score        float64      `json:"score"`                             // ranking score, higher first
+	MonthlyWaste float64      `json:"estimated_monthly_waste,omitempty"` // summed USD waste of the items
+	TopResources []string     `json:"top_resources,omitempty"`           // most costly affected resources
+	Remediation  *Remediation `json:"remediation,omitempty"`             // how to fix, when known
+}
+
+// Remediation describes how to fix a kind of finding.
+type Remediation struct {
+	Fix     string `json:"fix" yaml:"fix"`                             // what to do
+	Snippet string `json:"snippet,omitempty" yaml:"snippet,omitempty"` // example CLI command or IaC attribute
+	Effort  string `json:"effort,omitempty" yaml:"effort,omitempty"`   // low, medium, high
+	DocURL  string `json:"doc_url,omitempty" yaml:"doc,omitempty"`     // upstream documentation
+}
+
+// Incident links related findings reported by different tools about the
+// same underlying entity (a bucket, a secret, a database instance).
+type Incident struct {
+	ID       string            `json:"id"`       // rule:entity, stable across runs
+	Rule     string            `json:"rule"`     // correlation rule that produced it
+	Title    string            `json:"title"`    // one-line description
+	Entity   string            `json:"entity"`   // shared entity name
+	Severity string            `json:"severity"` // highest severity among linked issues
+	Tools    []string          `json:"tools"`    // tools contributing findings
+	Issues   []NormalizedIssue `json:"issues"`   // linked findings
 }
 
 // TrendSummary provides historical trend analysis
 type TrendSummary struct {
 	TimeRange      string                `json:"time_range"` // e.g., "Last 7 days"
 	RunsAnalyzed   int                   `json:"runs_analyzed"`
-	IssueSparkline []int                 `json:"issue_sparkline"` // Issue counts over time
+	IssueSparkline []int                 `json:"issue_sparkline"`        // Issue counts over time
+	PartialRuns    []bool                `json:"partial_runs,omitempty"` // Parallel to IssueSparkline; true where tools did not report
 	ByTool         map[string]*ToolTrend `json:"by_tool"`
 }
 
@@ -283,8 +404,9 @@ type ToolTrend struct {
 	Name           string  `json:"name"`
 	CurrentIssues  int     `json:"current_issues"`
 	PreviousIssues int     `json:"previous_issues"`
-	Change         int     `json:"change"`         // Positive = more issues
-	ChangePercent  float64 `json:"change_percent"` // Positive = more issues
+	Change         int     `json:"change"`            // Positive = more issues
+	ChangePercent  float64 `json:"change_percent"`    // Positive = more issues
+	Missing        bool    `json:"missing,omitempty"` // Did not report in the first or last run; not compared
 }
 
 // CalculateHealthScore determines overall health from affected vs total resources.
@@ -1,67 +1,47 @@
 package models
 
-import "time"
+import "github.com/ppiankov/spectrehub/pkg/spectrev1"
+
+// The spectre/v1 envelope types are defined in the public pkg/spectrev1
+// package so scanners and spectrehub share one definition.
+
+// Versions of the spectre/v1 envelope. spectre/v1.1 only adds optional
+// finding fields, so both decode into the same types.
+const (
+	SpectreV1Schema  = spectrev1.SchemaV1
+	SpectreV11Schema = spectrev1.SchemaV11
+)
+
+// IsSpectreV1Schema reports whether schema names a version of the
+// spectre/v1 envelope.
+func IsSpectreV1Schema(schema string) bool {
+	return spectrev1.IsSchema(schema)
+}
 
 // SpectreV1Report represents a spectre/v1 envelope — the standardized output
 // format that all spectre tools emit with --format spectrehub.
-type SpectreV1Report struct {
-	Schema    string             `json:"schema"`
-	Tool      string             `json:"tool"`
-	Version   string             `json:"version"`
-	Timestamp time.Time          `json:"timestamp"`
-	Target    SpectreV1Target    `json:"target"`
-	Findings  []SpectreV1Finding `json:"findings"`
-	Summary   SpectreV1Summary   `json:"summary"`
-}
+type SpectreV1Report = spectrev1.Report
 
 // SpectreV1Target describes what was scanned.
-type SpectreV1Target struct {
-	Type    string `json:"type"` // s3, postgres, kafka, clickhouse, vault, mongodb, aws-account, gcp-project, gcs, gcp-projects
-	URIHash string `json:"uri_hash,omitempty"`
-}
+type SpectreV1Target = spectrev1.Target
 
 // SpectreV1Finding is a single issue in the spectre/v1 envelope.
-type SpectreV1Finding struct {
-	ID                    string   `json:"id"`
-	Severity              string   `json:"severity"` // high, medium, low, info
-	Location              string   `json:"location"`
-	Message               string   `json:"message"`
-	EstimatedMonthlyWaste *float64 `json:"estimated_monthly_waste,omitempty"`
-}
+type SpectreV1Finding = spectrev1.Finding
 
 // SpectreV1Summary counts findings by severity.
-type SpectreV1Summary struct {
-	Total  int `json:"total"`
-	High   int `json:"high"`
-	Medium int `json:"medium"`
-	Low    int `json:"low"`
-	Info   int `json:"info"`
-}
+type SpectreV1Summary = spectrev1.Summary
+
+// ResourceAttributes are structured attributes of the resource behind a
+// finding.
+type ResourceAttributes = spectrev1.ResourceAttributes
 
 // ValidSpectreV1Severities defines the allowed severity values in spectre/v1 findings.
-var ValidSpectreV1Severities = map[string]bool{
-	"high":   true,
-	"medium": true,
-	"low":    true,
-	"info":   true,
-}
+var ValidSpectreV1Severities = spectrev1.Severities()
 
 // SpectreV1TargetTypes maps tool names to their expected target.type values.
-// Tools not listed here (e.g., iamspectre which emits aws-account or gcp-project
-// depending on the cloud) are accepted with any target type.
-var SpectreV1TargetTypes = map[string]string{
-	"s3spectre":    "s3",
-	"pgspectre":    "postgres",
-	"kafkaspectre": "kafka",
-	"clickspectre": "clickhouse",
-	"vaultspectre": "vault",
-	"mongospectre": "mongodb",
-	"awsspectre":   "aws-account",
-	"gcsspectre":   "gcs",
-	"gcpspectre":   "gcp-projects",
-	"kubespectre":  "kubernetes",
-	"redisspectre": "redis",
-	"ecrspectre":   "ecr",
-	"rdsspectre":   "rds",
-	"azurespectre": "azure-subscription",
-}
+// It is a copy of spectrev1.TargetTypes().
+var SpectreV1TargetTypes = spectrev1.TargetTypes()
+
+// SpectreV1SharedTargetTypes are target.type values not tied to a single
+// tool. It is a copy of spectrev1.SharedTargetTypes().
+var SpectreV1SharedTargetTypes = spectrev1.SharedTargetTypes()
@@ -0,0 +1,217 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Notifier delivers events to the configured channels.
+type Notifier struct {
+	cfg       Config
+	statePath string
+	client    *http.Client
+	now       func() time.Time
+	sleep     func(time.Duration)
+}
+
+// New creates a notifier. statePath is the dedup state file; empty
+// disables deduplication across runs.
+func New(cfg Config, statePath string) *Notifier {
+	return &Notifier{
+		cfg:       cfg,
+		statePath: statePath,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		now:       time.Now,
+		sleep:     time.Sleep,
+	}
+}
+
+// Delivery is the outcome for one channel.
+type Delivery struct {
+	Channel string
+	Sent    int   // events delivered
+	Skipped int   // events already notified
+	Err     error // delivery failure after retries
+}
+
+// Notify evaluates the run and sends new events to every subscribed
+// channel. Delivery failures are reported per channel and the events are
+// retried on the next run; the returned error is only for the state file.
+func (n *Notifier) Notify(ctx context.Context, run Run) ([]Delivery, error) {
+	events := Evaluate(run, n.cfg)
+
+	st, err := loadState(n.statePath)
+	if err != nil {
+		return nil, err
+	}
+
+	now := n.now()
+	next := make(map[string]map[string]time.Time)
+	var deliveries []Delivery
+
+	for i, ch := range n.cfg.Channels {
+		label := ch.label(i)
+		prev := st.Channels[label]
+		active := make(map[string]time.Time)
+		d := Delivery{Channel: label}
+
+		var pending []Event
+		for _, e := range events {
+			if !ch.wants(e.Trigger) {
+				continue
+			}
+			if last, ok := prev[e.key]; ok && (n.cfg.RenotifyAfter == 0 || now.Sub(last) < n.cfg.RenotifyAfter) {
+				active[e.key] = last
+				d.Skipped++
+				continue
+			}
+			pending = append(pending, e)
+		}
+
+		if len(pending) > 0 {
+			if d.Err = n.send(ctx, ch, run, pending); d.Err == nil {
+				d.Sent = len(pending)
+				for _, e := range pending {
+					active[e.key] = now
+				}
+			}
+		}
+
+		next[label] = active
+		if d.Sent > 0 || d.Skipped > 0 || d.Err != nil {
+			deliveries = append(deliveries, d)
+		}
+	}
+
+	st.Channels = next
+	return deliveries, st.save()
+}
+
+// send renders and posts a message, retrying transient failures with
+// exponential backoff.
+func (n *Notifier) send(ctx context.Context, ch Channel, run Run, events []Event) error {
+	msg := Message{
+		Repo:        run.Repo,
+		Timestamp:   run.Report.Timestamp,
+		Score:       run.Report.Summary.ScorePercent,
+		Health:      run.Report.Summary.HealthScore,
+		TotalIssues: run.Report.Summary.TotalIssues,
+		Events:      events,
+	}
+	text, err := render(ch.Template, msg)
+	if err != nil {
+		return err
+	}
+	body, err := payload(ch.Type, text, msg)
+	if err != nil {
+		return err
+	}
+
+	retries := n.cfg.Retries
+	if retries == 0 {
+		retries = DefaultRetries
+	} else if retries < 0 {
+		retries = 0
+	}
+	backoff := n.cfg.Backoff
+	if backoff == 0 {
+		backoff = DefaultBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = n.post(ctx, ch, body)
+		if err == nil || attempt == retries || !retryable(err) {
+			return err
+		}
+		n.sleep(backoff << attempt)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// post sends one request.
+func (n *Notifier) post(ctx context.Context, ch Channel, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ch.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range ch.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post notification: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &statusError{code: resp.StatusCode, status: resp.Status}
+	}
+	return nil
+}
+
+// statusError is a non-2xx response.
+type statusError struct {
+	code   int
+	status string
+}
+
+func (e *statusError) Error() string {
+	return "notification rejected: " + e.status
+}
+
+// retryable reports whether a failed delivery may succeed when retried:
+// network errors, rate limiting and server errors.
+func retryable(err error) bool {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code == http.StatusTooManyRequests || se.code >= 500
+	}
+	return true
+}
+
+// payload builds the request body for a channel type.
+func payload(kind, text string, msg Message) ([]byte, error) {
+	switch kind {
+	case TypeSlack:
+		return json.Marshal(map[string]string{"text": text})
+	case TypeTeams:
+		title, body, _ := strings.Cut(text, "\n")
+		color := "FFA500"
+		for _, e := range msg.Events {
+			if e.Trigger == TriggerNewCritical || e.Trigger == TriggerToolFailed {
+				color = "D7000C"
+			}
+		}
+		return json.Marshal(map[string]string{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    title,
+			"themeColor": color,
+			"title":      title,
+			// Teams collapses single newlines; paragraphs keep one line each.
+			"text": strings.ReplaceAll(body, "\n", "\n\n"),
+		})
+	default:
+		return json.Marshal(struct {
+			Text        string    `json:"text"`
+			Repo        string    `json:"repo,omitempty"`
+			Timestamp   time.Time `json:"timestamp"`
+			Score       float64   `json:"score"`
+			Health      string    `json:"health"`
+			TotalIssues int       `json:"total_issues"`
+			Events      []Event   `json:"events"`
+		}{text, msg.Repo, msg.Timestamp, msg.Score, msg.Health, msg.TotalIssues, msg.Events})
+	}
+}
@@ -0,0 +1,311 @@
+// Package notify sends run results to webhooks and chat channels when a
+// run degrades: new critical or high findings, a score below a threshold,
+// policy failures or tools that failed to run.
+//
+// Alerts are deduplicated with a small state file next to the stored runs:
+// an event is sent when it first appears and again only after it has
+// cleared, or when renotify_after has elapsed.
+package notify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/aggregator"
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/policy"
+)
+
+// Triggers a channel can subscribe to.
+const (
+	TriggerNewCritical  = "new_critical"
+	TriggerNewHigh      = "new_high"
+	TriggerScoreBelow   = "score_below"
+	TriggerPolicyFailed = "policy_failed"
+	TriggerToolFailed   = "tool_failed"
+)
+
+// AllTriggers lists every trigger; channels without triggers get all of them.
+var AllTriggers = []string{
+	TriggerNewCritical, TriggerNewHigh, TriggerScoreBelow, TriggerPolicyFailed, TriggerToolFailed,
+}
+
+// Channel payload formats.
+const (
+	TypeWebhook = "webhook"
+	TypeSlack   = "slack"
+	TypeTeams   = "teams"
+)
+
+// Defaults for delivery.
+const (
+	DefaultRetries = 3
+	DefaultBackoff = time.Second
+)
+
+// maxDetails caps the lines listed under an event.
+const maxDetails = 10
+
+// Config is the notifications section of .spectrehub.yaml.
+type Config struct {
+	Channels []Channel `mapstructure:"channels"`
+	// ScoreBelow enables the score_below trigger when positive.
+	ScoreBelow float64 `mapstructure:"score_below"`
+	// Retries is the number of extra delivery attempts; 0 uses DefaultRetries
+	// and a negative value disables retries.
+	Retries int `mapstructure:"retries"`
+	// Backoff is the delay before the first retry, doubling on each retry.
+	Backoff time.Duration `mapstructure:"backoff"`
+	// RenotifyAfter re-sends an event that is still active after this long;
+	// zero alerts once until the event clears.
+	RenotifyAfter time.Duration `mapstructure:"renotify_after"`
+}
+
+// Channel is one notification destination.
+type Channel struct {
+	Name     string            `mapstructure:"name"`
+	Type     string            `mapstructure:"type"` // webhook, slack or teams
+	URL      string            `mapstructure:"url"`
+	Triggers []string          `mapstructure:"triggers"`
+	Template string            `mapstructure:"template"` // Go text/template over Message
+	Headers  map[string]string `mapstructure:"headers"`
+}
+
+// Enabled reports whether any channel is configured.
+func (c Config) Enabled() bool {
+	return len(c.Channels) > 0
+}
+
+// Validate checks channel types, URLs, triggers and templates.
+func (c Config) Validate() error {
+	if c.ScoreBelow < 0 || c.ScoreBelow > 100 {
+		return fmt.Errorf("notifications.score_below must be between 0 and 100")
+	}
+	if c.Backoff < 0 || c.RenotifyAfter < 0 {
+		return fmt.Errorf("notifications: backoff and renotify_after cannot be negative")
+	}
+	names := make(map[string]bool)
+	for i, ch := range c.Channels {
+		label := ch.label(i)
+		if names[label] {
+			return fmt.Errorf("notifications: duplicate channel name %q", label)
+		}
+		names[label] = true
+
+		switch ch.Type {
+		case TypeWebhook, TypeSlack, TypeTeams:
+		default:
+			return fmt.Errorf("notifications channel %q: invalid type %q (must be webhook, slack, or teams)", label, ch.Type)
+		}
+		if !strings.HasPrefix(ch.URL, "http://") && !strings.HasPrefix(ch.URL, "https://") {
+			return fmt.Errorf("notifications channel %q: url must be http(s)", label)
+		}
+		for _, t := range ch.Triggers {
+			if !validTrigger(t) {
+				return fmt.Errorf("notifications channel %q: unknown trigger %q (valid: %s)", label, t, strings.Join(AllTriggers, ", "))
+			}
+		}
+		if _, err := parseTemplate(ch.Template); err != nil {
+			return fmt.Errorf("notifications channel %q: %w", label, err)
+		}
+	}
+	return nil
+}
+
+// label names a channel in messages and the dedup state.
+func (ch Channel) label(i int) string {
+	if ch.Name != "" {
+		return ch.Name
+	}
+	return fmt.Sprintf("%s-%d", ch.Type, i+1)
+}
+
+// wants reports whether the channel subscribes to trigger.
+func (ch Channel) wants(trigger string) bool {
+	if len(ch.Triggers) == 0 {
+		return true
+	}
+	for _, t := range ch.Triggers {
+		if t == trigger {
+			return true
+		}
+	}
+	return false
+}
+
+func validTrigger(t string) bool {
+	for _, v := range AllTriggers {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ToolFailure is a tool that failed to produce a report.
+type ToolFailure struct {
+	Tool  string
+	Error string
+}
+
+// Run is the outcome of a pipeline run to notify about.
+type Run struct {
+	Repo     string
+	Report   *models.AggregatedReport
+	Previous *models.AggregatedReport // nil when there is no stored run
+	// Violations are the policy violations that failed the run.
+	Violations  []policy.Violation
+	FailedTools []ToolFailure
+}
+
+// Event is one reason to notify.
+type Event struct {
+	Trigger string   `json:"trigger"`
+	Summary string   `json:"summary"`
+	Details []string `json:"details,omitempty"`
+	// key identifies the event for deduplication.
+	key string
+}
+
+// Evaluate returns the events raised by a run. Without a previous run,
+// every critical and high finding counts as new.
+func Evaluate(run Run, cfg Config) []Event {
+	var events []Event
+	report := run.Report
+
+	baseline := run.Previous
+	if baseline == nil {
+		baseline = &models.AggregatedReport{}
+	}
+	diff := aggregator.ComputeDiff(baseline, report)
+	for _, sev := range []struct{ severity, trigger string }{
+		{"critical", TriggerNewCritical},
+		{"high", TriggerNewHigh},
+	} {
+		var keys, details []string
+		for _, issue := range diff.NewIssues {
+			if issue.Severity != sev.severity {
+				continue
+			}
+			keys = append(keys, aggregator.DiffKey(issue))
+			details = append(details, fmt.Sprintf("%s %s: %s", issue.Tool, issue.Category, issue.Resource))
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		events = append(events, Event{
+			Trigger: sev.trigger,
+			Summary: fmt.Sprintf("%d new %s finding%s", len(keys), sev.severity, plural(len(keys))),
+			Details: capDetails(details),
+			key:     sev.trigger + "|" + hashKeys(keys),
+		})
+	}
+
+	// A run without any tool report has no meaningful score.
+	if cfg.ScoreBelow > 0 && report.Summary.TotalTools > 0 && report.Summary.ScorePercent < cfg.ScoreBelow {
+		events = append(events, Event{
+			Trigger: TriggerScoreBelow,
+			Summary: fmt.Sprintf("Score %.1f%% is below %.1f%%", report.Summary.ScorePercent, cfg.ScoreBelow),
+			key:     TriggerScoreBelow,
+		})
+	}
+
+	if len(run.Violations) > 0 {
+		var rules, details []string
+		for _, v := range run.Violations {
+			rules = append(rules, v.Rule)
+			details = append(details, fmt.Sprintf("[%s] %s", v.Rule, v.Message))
+		}
+		events = append(events, Event{
+			Trigger: TriggerPolicyFailed,
+			Summary: fmt.Sprintf("Policy failed with %d violation%s", len(run.Violations), plural(len(run.Violations))),
+			Details: capDetails(details),
+			key:     TriggerPolicyFailed + "|" + hashKeys(rules),
+		})
+	}
+
+	if len(run.FailedTools) > 0 {
+		var tools, details []string
+		for _, f := range run.FailedTools {
+			tools = append(tools, f.Tool)
+			details = append(details, fmt.Sprintf("%s: %s", f.Tool, f.Error))
+		}
+		events = append(events, Event{
+			Trigger: TriggerToolFailed,
+			Summary: fmt.Sprintf("%d tool%s failed to run", len(tools), plural(len(tools))),
+			Details: capDetails(details),
+			key:     TriggerToolFailed + "|" + hashKeys(tools),
+		})
+	}
+
+	return events
+}
+
+// Message is the data passed to message templates.
+type Message struct {
+	Repo        string
+	Timestamp   time.Time
+	Score       float64
+	Health      string
+	TotalIssues int
+	Events      []Event
+}
+
+// defaultTemplate renders a plain-text message that reads well in chat.
+const defaultTemplate = `SpectreHub{{if .Repo}} ({{.Repo}}){{end}}: score {{printf "%.1f" .Score}}% ({{.Health}}), {{.TotalIssues}} issues
+{{range .Events}}• {{.Summary}}
+{{range .Details}}    - {{.}}
+{{end}}{{end}}`
+
+func parseTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = defaultTemplate
+	}
+	tmpl, err := template.New("message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// render executes a channel template.
+func render(text string, msg Message) (string, error) {
+	tmpl, err := parseTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, msg); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func capDetails(details []string) []string {
+	sort.Strings(details)
+	if len(details) <= maxDetails {
+		return details
+	}
+	more := len(details) - maxDetails
+	return append(details[:maxDetails:maxDetails], fmt.Sprintf("... and %d more", more))
+}
+
+// hashKeys returns a short order-independent fingerprint of keys.
+func hashKeys(keys []string) string {
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
@@ -0,0 +1,374 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/policy"
+)
+
+// receiver is a local webhook endpoint that records requests and replies
+// with the queued status codes, then 200.
+type receiver struct {
+	mu       sync.Mutex
+	bodies   []string
+	headers  []http.Header
+	statuses []int
+	server   *httptest.Server
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	t.Helper()
+	r := &receiver{statuses: statuses}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.bodies = append(r.bodies, string(body))
+		r.headers = append(r.headers, req.Header.Clone())
+		status := http.StatusOK
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *receiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.bodies)
+}
+
+func (r *receiver) last() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.bodies[len(r.bodies)-1]
+}
+
+var now = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func report(score float64, issues ...models.NormalizedIssue) *models.AggregatedReport {
+	return &models.AggregatedReport{
+		Timestamp: now,
+		Issues:    issues,
+		Summary:   models.CrossToolSummary{TotalTools: 2, TotalIssues: len(issues), ScorePercent: score, HealthScore: "warning"},
+	}
+}
+
+var (
+	secret = models.NormalizedIssue{Tool: "vaultspectre", Category: "missing", Severity: "critical", Resource: "secret/db"}
+	bucket = models.NormalizedIssue{Tool: "s3spectre", Category: "misconfig", Severity: "high", Resource: "s3://public"}
+	topic  = models.NormalizedIssue{Tool: "kafkaspectre", Category: "unused", Severity: "low", Resource: "topic-a"}
+)
+
+func testNotifier(cfg Config, statePath string) (*Notifier, *[]time.Duration) {
+	n := New(cfg, statePath)
+	n.now = func() time.Time { return now }
+	var slept []time.Duration
+	n.sleep = func(d time.Duration) { slept = append(slept, d) }
+	return n, &slept
+}
+
+func TestEvaluate(t *testing.T) {
+	run := Run{
+		Report:      report(55, secret, bucket, topic),
+		Previous:    report(80, bucket),
+		Violations:  []policy.Violation{{Rule: "max_critical", Message: "1 critical issues (max 0)"}},
+		FailedTools: []ToolFailure{{Tool: "pgspectre", Error: "exit status 1"}},
+	}
+	events := Evaluate(run, Config{ScoreBelow: 60})
+
+	var got []string
+	for _, e := range events {
+		got = append(got, e.Trigger+": "+e.Summary)
+	}
+	want := []string{
+		"new_critical: 1 new critical finding",
+		"score_below: Score 55.0% is below 60.0%",
+		"policy_failed: Policy failed with 1 violation",
+		"tool_failed: 1 tool failed to run",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("events:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if d := events[0].Details; len(d) != 1 || d[0] != "vaultspectre missing: secret/db" {
+		t.Errorf("unexpected details: %v", d)
+	}
+}
+
+func TestEvaluateWithoutPrevious(t *testing.T) {
+	events := Evaluate(Run{Report: report(90, secret, bucket, topic)}, Config{})
+	if len(events) != 2 || events[0].Trigger != TriggerNewCritical || events[1].Trigger != TriggerNewHigh {
+		t.Errorf("expected new critical and high events, got %+v", events)
+	}
+}
+
+func TestEvaluateCapsDetails(t *testing.T) {
+	var issues []models.NormalizedIssue
+	for i := 0; i < 15; i++ {
+		issue := secret
+		issue.Resource = fmt.Sprintf("secret/%02d", i)
+		issues = append(issues, issue)
+	}
+	events := Evaluate(Run{Report: report(90, issues...)}, Config{})
+	d := events[0].Details
+	if len(d) != maxDetails+1 || d[maxDetails] != "... and 5 more" {
+		t.Errorf("expected capped details, got %d: %v", len(d), d[len(d)-1])
+	}
+}
+
+func TestEvaluateSkipsScoreWithoutTools(t *testing.T) {
+	r := &models.AggregatedReport{Timestamp: now}
+	events := Evaluate(Run{Report: r, FailedTools: []ToolFailure{{Tool: "s3spectre", Error: "timeout"}}}, Config{ScoreBelow: 50})
+	if len(events) != 1 || events[0].Trigger != TriggerToolFailed {
+		t.Errorf("expected only tool_failed, got %+v", events)
+	}
+}
+
+func TestNotifyPayloadFormats(t *testing.T) {
+	hook, slack, teams := newReceiver(t), newReceiver(t), newReceiver(t)
+	cfg := Config{Channels: []Channel{
+		{Name: "hook", Type: TypeWebhook, URL: hook.server.URL, Headers: map[string]string{"authorization": "Bearer t0ken"}},
+		{Name: "slack", Type: TypeSlack, URL: slack.server.URL},
+		{Name: "teams", Type: TypeTeams, URL: teams.server.URL},
+	}}
+	n, _ := testNotifier(cfg, "")
+
+	deliveries, err := n.Notify(context.Background(), Run{Repo: "org/app", Report: report(72.5, secret)})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if len(deliveries) != 3 {
+		t.Fatalf("expected 3 deliveries, got %+v", deliveries)
+	}
+
+	var webhook struct {
+		Text   string  `json:"text"`
+		Repo   string  `json:"repo"`
+		Score  float64 `json:"score"`
+		Events []Event `json:"events"`
+	}
+	if err := json.Unmarshal([]byte(hook.last()), &webhook); err != nil {
+		t.Fatalf("webhook body: %v", err)
+	}
+	if webhook.Repo != "org/app" || webhook.Score != 72.5 || len(webhook.Events) != 1 || webhook.Events[0].Trigger != TriggerNewCritical {
+		t.Errorf("unexpected webhook payload: %+v", webhook)
+	}
+	if got := hook.headers[0].Get("Authorization"); got != "Bearer t0ken" {
+		t.Errorf("Authorization = %q", got)
+	}
+
+	var s map[string]string
+	if err := json.Unmarshal([]byte(slack.last()), &s); err != nil {
+		t.Fatalf("slack body: %v", err)
+	}
+	wantText := "SpectreHub (org/app): score 72.5% (warning), 1 issues\n• 1 new critical finding\n    - vaultspectre missing: secret/db"
+	if s["text"] != wantText {
+		t.Errorf("slack text = %q, want %q", s["text"], wantText)
+	}
+
+	var card map[string]string
+	if err := json.Unmarshal([]byte(teams.last()), &card); err != nil {
+		t.Fatalf("teams body: %v", err)
+	}
+	if card["@type"] != "MessageCard" || card["title"] != "SpectreHub (org/app): score 72.5% (warning), 1 issues" || card["themeColor"] != "D7000C" {
+		t.Errorf("unexpected teams card: %v", card)
+	}
+}
+
+func TestNotifyCustomTemplateAndTriggers(t *testing.T) {
+	r := newReceiver(t)
+	cfg := Config{
+		ScoreBelow: 80,
+		Channels: []Channel{{
+			Type:     TypeSlack,
+			URL:      r.server.URL,
+			Triggers: []string{TriggerScoreBelow},
+			Template: `{{.Repo}}:{{range .Events}} {{.Trigger}}{{end}}`,
+		}},
+	}
+	n, _ := testNotifier(cfg, "")
+	if _, err := n.Notify(context.Background(), Run{Repo: "org/app", Report: report(70, secret)}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(r.last(), `"text":"org/app: score_below"`) {
+		t.Errorf("unexpected body: %s", r.last())
+	}
+}
+
+func TestNotifyRetriesWithBackoff(t *testing.T) {
+	r := newReceiver(t, http.StatusBadGateway, http.StatusTooManyRequests)
+	cfg := Config{Backoff: 10 * time.Millisecond, Channels: []Channel{{Type: TypeSlack, URL: r.server.URL}}}
+	n, slept := testNotifier(cfg, "")
+
+	deliveries, err := n.Notify(context.Background(), Run{Report: report(90, secret)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deliveries[0].Err != nil || deliveries[0].Sent != 1 || r.count() != 3 {
+		t.Fatalf("expected delivery on third attempt, got %+v after %d requests", deliveries[0], r.count())
+	}
+	if len(*slept) != 2 || (*slept)[0] != 10*time.Millisecond || (*slept)[1] != 20*time.Millisecond {
+		t.Errorf("backoff = %v, want [10ms 20ms]", *slept)
+	}
+}
+
+func TestNotifyDoesNotRetryClientErrors(t *testing.T) {
+	r := newReceiver(t, http.StatusBadRequest)
+	n, slept := testNotifier(Config{Channels: []Channel{{Type: TypeSlack, URL: r.server.URL}}}, "")
+
+	deliveries, _ := n.Notify(context.Background(), Run{Report: report(90, secret)})
+	if deliveries[0].Err == nil || !strings.Contains(deliveries[0].Err.Error(), "400") {
+		t.Errorf("expected 400 error, got %v", deliveries[0].Err)
+	}
+	if r.count() != 1 || len(*slept) != 0 {
+		t.Errorf("expected a single attempt, got %d requests", r.count())
+	}
+}
+
+func TestNotifyDeduplicates(t *testing.T) {
+	r := newReceiver(t)
+	statePath := filepath.Join(t.TempDir(), "state", StateFileName)
+	cfg := Config{ScoreBelow: 60, Channels: []Channel{{Name: "ops", Type: TypeSlack, URL: r.server.URL, Triggers: []string{TriggerScoreBelow}}}}
+
+	notifyOnce := func(score float64) Delivery {
+		t.Helper()
+		n, _ := testNotifier(cfg, statePath)
+		deliveries, err := n.Notify(context.Background(), Run{Report: report(score)})
+		if err != nil {
+			t.Fatalf("Notify: %v", err)
+		}
+		if len(deliveries) == 0 {
+			return Delivery{}
+		}
+		return deliveries[0]
+	}
+
+	if d := notifyOnce(50); d.Sent != 1 {
+		t.Fatalf("first drop should alert, got %+v", d)
+	}
+	if d := notifyOnce(45); d.Sent != 0 || d.Skipped != 1 {
+		t.Errorf("still below should be deduplicated, got %+v", d)
+	}
+	if d := notifyOnce(75); d.Sent != 0 || d.Skipped != 0 {
+		t.Errorf("recovered score should not alert, got %+v", d)
+	}
+	if d := notifyOnce(50); d.Sent != 1 {
+		t.Errorf("a new drop should alert again, got %+v", d)
+	}
+	if r.count() != 2 {
+		t.Errorf("expected 2 notifications, got %d", r.count())
+	}
+}
+
+func TestStateIsOwnerOnly(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	statePath := filepath.Join(dir, StateFileName)
+	n, _ := testNotifier(Config{}, statePath)
+	if _, err := n.Notify(context.Background(), Run{Report: report(90)}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	for path, want := range map[string]os.FileMode{dir: 0700, statePath: 0600} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != want {
+			t.Errorf("%s mode = %o, want %o", filepath.Base(path), perm, want)
+		}
+	}
+}
+
+func TestNotifyRenotifyAfter(t *testing.T) {
+	r := newReceiver(t)
+	statePath := filepath.Join(t.TempDir(), StateFileName)
+	cfg := Config{ScoreBelow: 60, RenotifyAfter: 24 * time.Hour, Channels: []Channel{{Type: TypeSlack, URL: r.server.URL}}}
+
+	for _, offset := range []time.Duration{0, 12 * time.Hour, 25 * time.Hour} {
+		n, _ := testNotifier(cfg, statePath)
+		at := now.Add(offset)
+		n.now = func() time.Time { return at }
+		if _, err := n.Notify(context.Background(), Run{Report: report(50)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r.count() != 2 {
+		t.Errorf("expected alert at 0h and 25h, got %d", r.count())
+	}
+}
+
+func TestNotifyFailedDeliveryRetriedNextRun(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), StateFileName)
+	down := newReceiver(t, http.StatusServiceUnavailable)
+	cfg := Config{Retries: -1, Channels: []Channel{{Name: "ops", Type: TypeSlack, URL: down.server.URL}}}
+	run := Run{Report: report(90, secret)}
+
+	n, _ := testNotifier(cfg, statePath)
+	deliveries, _ := n.Notify(context.Background(), run)
+	if deliveries[0].Err == nil || down.count() != 1 {
+		t.Fatalf("expected one failed attempt, got %+v", deliveries)
+	}
+
+	n, _ = testNotifier(cfg, statePath)
+	deliveries, _ = n.Notify(context.Background(), run)
+	if deliveries[0].Err != nil || deliveries[0].Sent != 1 {
+		t.Errorf("expected the event to be sent on the next run, got %+v", deliveries[0])
+	}
+}
+
+func TestLoadStateInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), StateFileName)
+	n, _ := testNotifier(Config{}, path)
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := n.Notify(context.Background(), Run{Report: report(90)}); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	ok := Channel{Type: TypeSlack, URL: "https://hooks.example.com/x"}
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"valid", Config{ScoreBelow: 70, Channels: []Channel{ok}}, ""},
+		{"bad type", Config{Channels: []Channel{{Type: "email", URL: ok.URL}}}, "invalid type"},
+		{"bad url", Config{Channels: []Channel{{Type: TypeTeams, URL: "hooks.example.com"}}}, "url must be http(s)"},
+		{"bad trigger", Config{Channels: []Channel{{Type: TypeSlack, URL: ok.URL, Triggers: []string{"new_low"}}}}, "unknown trigger"},
+		{"bad template", Config{Channels: []Channel{{Type: TypeSlack, URL: ok.URL, Template: "{{.Nope"}}}, "invalid template"},
+		{"duplicate", Config{Channels: []Channel{{Name: "ops", Type: TypeSlack, URL: ok.URL}, {Name: "ops", Type: TypeTeams, URL: ok.URL}}}, "duplicate channel name"},
+		{"score range", Config{ScoreBelow: 120}, "score_below"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
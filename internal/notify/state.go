@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/atomicfile"
+)
+
+// StateFileName is the dedup state file inside the storage directory.
+const StateFileName = "notifications.json"
+
+// state records, per channel, the events that were active and notified at
+// the last run and when each was last sent.
+type state struct {
+	path     string
+	Channels map[string]map[string]time.Time `json:"channels"`
+}
+
+// loadState reads the state file. A missing file or empty path yields an
+// empty state; an empty path is never written.
+func loadState(path string) (*state, error) {
+	s := &state{path: path, Channels: make(map[string]map[string]time.Time)}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("read notification state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parse notification state %s: %w", path, err)
+	}
+	if s.Channels == nil {
+		s.Channels = make(map[string]map[string]time.Time)
+	}
+	return s, nil
+}
+
+// save writes the state atomically.
+func (s *state) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal notification state: %w", err)
+	}
+	if err := atomicfile.WritePrivate(s.path, data); err != nil {
+		return fmt.Errorf("write notification state: %w", err)
+	}
+	return nil
+}
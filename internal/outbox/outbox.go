@@ -0,0 +1,326 @@
+// Package outbox keeps API uploads that failed so they can be sent later,
+// by the next run or by spectrehub sync.
+//
+// Each upload is a JSON file in a directory next to the stored runs. The
+// file is named after the kind of upload, the tool and the run timestamp,
+// so queueing the same upload twice keeps a single copy. Payloads hold
+// what the stored runs hold, so the outbox is owner-only and payloads are
+// encrypted with the storage encryption key when one is configured.
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/atomicfile"
+	"github.com/ppiankov/spectrehub/internal/storage"
+)
+
+// DirName is the outbox directory inside the storage directory.
+const DirName = "outbox"
+
+// Kinds of upload, in the order they are sent for a run.
+const (
+	KindReport   = "report"
+	KindFindings = "findings"
+	KindWaste    = "waste"
+)
+
+var kindOrder = map[string]int{KindReport: 0, KindFindings: 1, KindWaste: 2}
+
+// Retry backoff: the first retry waits MinBackoff, each failure doubles it
+// up to MaxBackoff.
+const (
+	MinBackoff = time.Minute
+	MaxBackoff = 6 * time.Hour
+)
+
+// Entry is one queued upload.
+type Entry struct {
+	Kind         string          `json:"kind"`
+	Tool         string          `json:"tool,omitempty"` // findings and waste are sent per tool
+	RunTimestamp time.Time       `json:"run_timestamp"`
+	Payload      json.RawMessage `json:"payload,omitempty"`
+	QueuedAt     time.Time       `json:"queued_at"`
+	Attempts     int             `json:"attempts"`
+	LastAttempt  time.Time       `json:"last_attempt,omitempty"`
+	LastError    string          `json:"last_error,omitempty"`
+	NextAttempt  time.Time       `json:"next_attempt"`
+
+	// SealedPayload is Payload encrypted with the storage encryption key.
+	// It is set instead of Payload when the entry was read without the key.
+	SealedPayload []byte `json:"sealed_payload,omitempty"`
+}
+
+// Name identifies the upload, e.g. "findings awsspectre 2026-02-15T10:00:00Z".
+func (e *Entry) Name() string {
+	parts := []string{e.Kind}
+	if e.Tool != "" {
+		parts = append(parts, e.Tool)
+	}
+	return strings.Join(append(parts, e.RunTimestamp.UTC().Format(time.RFC3339)), " ")
+}
+
+// Due reports whether the backoff of the entry has passed.
+func (e *Entry) Due(now time.Time) bool {
+	return !now.Before(e.NextAttempt)
+}
+
+// fileName is unique per kind, tool and run timestamp.
+func (e *Entry) fileName() string {
+	name := e.Kind
+	if e.Tool != "" {
+		name += "-" + e.Tool
+	}
+	return name + "-" + e.RunTimestamp.UTC().Format("20060102T150405.000000000Z") + ".json"
+}
+
+// Backoff is the wait before the next attempt after the given number of
+// failed attempts.
+func Backoff(attempts int) time.Duration {
+	d := MinBackoff
+	for i := 1; i < attempts && d < MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > MaxBackoff {
+		d = MaxBackoff
+	}
+	return d
+}
+
+// permanentError marks a failure that retrying cannot fix.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps an error returned by a send function to drop the upload
+// instead of retrying it, e.g. when the API rejected the payload.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// Outbox is a directory of queued uploads.
+type Outbox struct {
+	dir  string
+	keys *storage.Keys
+	now  func() time.Time
+}
+
+// Open returns the outbox in dir. The directory is created by the first Add.
+func Open(dir string) *Outbox {
+	return &Outbox{dir: dir, now: time.Now}
+}
+
+// OpenDir returns the outbox of a storage directory.
+func OpenDir(storageDir string) *Outbox {
+	return Open(filepath.Join(storageDir, DirName))
+}
+
+// SetKeys sets the storage keys. With an encryption key, payloads are
+// written encrypted and encrypted payloads are decrypted when read.
+func (o *Outbox) SetKeys(keys *storage.Keys) {
+	o.keys = keys
+}
+
+// Dir returns the directory backing the outbox.
+func (o *Outbox) Dir() string {
+	return o.dir
+}
+
+// Add queues an upload that failed with cause. An upload already queued for
+// the same kind, tool and run keeps its attempt count and gets the new
+// payload.
+func (o *Outbox) Add(kind, tool string, runTimestamp time.Time, payload interface{}, cause error) (*Entry, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s payload: %w", kind, err)
+	}
+
+	now := o.now().UTC()
+	entry := &Entry{Kind: kind, Tool: tool, RunTimestamp: runTimestamp.UTC(), QueuedAt: now}
+	if existing, err := o.load(filepath.Join(o.dir, entry.fileName())); err == nil {
+		entry = existing
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	entry.Payload = data
+	entry.SealedPayload = nil
+	entry.Attempts++
+	entry.LastAttempt = now
+	if cause != nil {
+		entry.LastError = cause.Error()
+	}
+	entry.NextAttempt = now.Add(Backoff(entry.Attempts))
+	return entry, o.save(entry)
+}
+
+// Entries returns the queued uploads, oldest run first and, within a run,
+// the report before findings and waste.
+func (o *Outbox) Entries() ([]*Entry, error) {
+	files, err := filepath.Glob(filepath.Join(o.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list outbox: %w", err)
+	}
+
+	entries := make([]*Entry, 0, len(files))
+	for _, file := range files {
+		entry, err := o.load(file)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if !a.RunTimestamp.Equal(b.RunTimestamp) {
+			return a.RunTimestamp.Before(b.RunTimestamp)
+		}
+		if kindOrder[a.Kind] != kindOrder[b.Kind] {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		return a.Tool < b.Tool
+	})
+	return entries, nil
+}
+
+// Len returns the number of queued uploads.
+func (o *Outbox) Len() (int, error) {
+	files, err := filepath.Glob(filepath.Join(o.dir, "*.json"))
+	if err != nil {
+		return 0, fmt.Errorf("list outbox: %w", err)
+	}
+	return len(files), nil
+}
+
+// Remove deletes a queued upload.
+func (o *Outbox) Remove(entry *Entry) error {
+	if err := os.Remove(filepath.Join(o.dir, entry.fileName())); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove %s from outbox: %w", entry.Name(), err)
+	}
+	return nil
+}
+
+// DrainResult counts what happened to the queued uploads.
+type DrainResult struct {
+	Sent    int
+	Dropped []error // uploads rejected for good, one error each
+	Pending int     // still queued after draining
+	// LastError is the failure that stopped draining, nil if none did.
+	LastError error
+}
+
+// Drain sends queued uploads in order. Unless all is set, uploads whose
+// backoff has not passed are left for later. A failed upload is rescheduled
+// and draining stops, since the API is most likely still unreachable; an
+// upload that fails with a Permanent error is dropped instead.
+func (o *Outbox) Drain(all bool, send func(*Entry) error) (DrainResult, error) {
+	var result DrainResult
+
+	entries, err := o.Entries()
+	if err != nil {
+		return result, err
+	}
+
+	now := o.now().UTC()
+	for _, entry := range entries {
+		if result.LastError != nil || (!all && !entry.Due(now)) {
+			result.Pending++
+			continue
+		}
+		if entry.Payload == nil && entry.SealedPayload != nil {
+			result.LastError = fmt.Errorf("%s: payload is encrypted and no storage encryption key is configured", entry.Name())
+			result.Pending++
+			continue
+		}
+
+		sendErr := send(entry)
+		switch {
+		case sendErr == nil:
+			if err := o.Remove(entry); err != nil {
+				return result, err
+			}
+			result.Sent++
+		case IsPermanent(sendErr):
+			if err := o.Remove(entry); err != nil {
+				return result, err
+			}
+			result.Dropped = append(result.Dropped, fmt.Errorf("%s: %w", entry.Name(), sendErr))
+		default:
+			entry.Attempts++
+			entry.LastAttempt = now
+			entry.LastError = sendErr.Error()
+			entry.NextAttempt = now.Add(Backoff(entry.Attempts))
+			if err := o.save(entry); err != nil {
+				return result, err
+			}
+			result.LastError = fmt.Errorf("%s: %w", entry.Name(), sendErr)
+			result.Pending++
+		}
+	}
+	return result, nil
+}
+
+func (o *Outbox) load(path string) (*Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("read outbox entry: %w", err)
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("parse outbox entry %s: %w", path, err)
+	}
+	// Without the key the entry is still listed, but cannot be sent.
+	if entry.SealedPayload != nil && o.keys.Encrypts() {
+		payload, err := o.keys.Decrypt(entry.SealedPayload)
+		if err != nil {
+			return nil, fmt.Errorf("outbox entry %s: %w", path, err)
+		}
+		entry.Payload = payload
+		entry.SealedPayload = nil
+	}
+	return &entry, nil
+}
+
+// save writes an entry atomically, encrypting the payload when an
+// encryption key is set.
+func (o *Outbox) save(entry *Entry) error {
+	onDisk := *entry
+	if o.keys.Encrypts() && entry.Payload != nil {
+		sealed, err := o.keys.Encrypt(entry.Payload)
+		if err != nil {
+			return fmt.Errorf("encrypt outbox entry: %w", err)
+		}
+		onDisk.Payload = nil
+		onDisk.SealedPayload = sealed
+	}
+
+	data, err := json.MarshalIndent(&onDisk, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal outbox entry: %w", err)
+	}
+	if err := atomicfile.WritePrivate(filepath.Join(o.dir, entry.fileName()), data); err != nil {
+		return fmt.Errorf("write outbox entry: %w", err)
+	}
+	return nil
+}
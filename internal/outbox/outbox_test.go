@@ -0,0 +1,285 @@
+package outbox
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/storage"
+)
+
+var (
+	testNow = time.Date(2026, 2, 15, 10, 0, 0, 0, time.UTC)
+	run1    = time.Date(2026, 2, 14, 9, 0, 0, 0, time.UTC)
+	run2    = time.Date(2026, 2, 15, 9, 0, 0, 0, time.UTC)
+)
+
+func newTestOutbox(t *testing.T) *Outbox {
+	t.Helper()
+	o := OpenDir(t.TempDir())
+	o.now = func() time.Time { return testNow }
+	return o
+}
+
+func TestAddDeduplicatesByRun(t *testing.T) {
+	o := newTestOutbox(t)
+	cause := errors.New("connection refused")
+
+	if _, err := o.Add(KindReport, "", run1, map[string]int{"issues": 1}, cause); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := o.Add(KindReport, "", run1, map[string]int{"issues": 2}, cause)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.Add(KindFindings, "awsspectre", run1, map[string]int{}, cause); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, _ := o.Len(); n != 2 {
+		t.Errorf("Len() = %d, want 2 (report queued once per run)", n)
+	}
+	if entry.Attempts != 2 || string(entry.Payload) != `{"issues":2}` {
+		t.Errorf("entry = attempts %d payload %s, want 2 attempts and the new payload", entry.Attempts, entry.Payload)
+	}
+	if entry.LastError != "connection refused" || !entry.NextAttempt.Equal(testNow.Add(2*MinBackoff)) {
+		t.Errorf("entry error/next = %q/%v", entry.LastError, entry.NextAttempt)
+	}
+}
+
+func TestEntriesOrder(t *testing.T) {
+	o := newTestOutbox(t)
+	for _, add := range []struct {
+		kind, tool string
+		run        time.Time
+	}{
+		{KindWaste, "awsspectre", run2},
+		{KindFindings, "iamspectre", run1},
+		{KindReport, "", run2},
+		{KindFindings, "awsspectre", run1},
+		{KindReport, "", run1},
+	} {
+		if _, err := o.Add(add.kind, add.tool, add.run, struct{}{}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := o.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	want := []string{
+		"report 2026-02-14T09:00:00Z",
+		"findings awsspectre 2026-02-14T09:00:00Z",
+		"findings iamspectre 2026-02-14T09:00:00Z",
+		"report 2026-02-15T09:00:00Z",
+		"waste awsspectre 2026-02-15T09:00:00Z",
+	}
+	if strings.Join(names, "; ") != strings.Join(want, "; ") {
+		t.Errorf("Entries() = %v, want %v", names, want)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	o := newTestOutbox(t)
+	for _, run := range []time.Time{run1, run2} {
+		if _, err := o.Add(KindReport, "", run, struct{}{}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := o.Add(KindFindings, "awsspectre", run1, struct{}{}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is due before the backoff has passed.
+	result, err := o.Drain(false, func(*Entry) error {
+		t.Fatal("send called before backoff passed")
+		return nil
+	})
+	if err != nil || result.Pending != 3 {
+		t.Fatalf("Drain(not due) = %+v, %v", result, err)
+	}
+
+	// The first failure stops draining and reschedules only that upload.
+	o.now = func() time.Time { return testNow.Add(time.Hour) }
+	var sent []string
+	result, err = o.Drain(false, func(e *Entry) error {
+		sent = append(sent, e.Name())
+		if e.Kind == KindFindings {
+			return errors.New("503 unavailable")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sent != 1 || result.Pending != 2 || result.LastError == nil {
+		t.Errorf("Drain() = %+v, want 1 sent, 2 pending and an error", result)
+	}
+	if len(sent) != 2 {
+		t.Errorf("sent %v, want the run1 report and findings only", sent)
+	}
+
+	entries, _ := o.Entries()
+	if entries[0].Kind != KindFindings || entries[0].Attempts != 2 || entries[0].LastError != "503 unavailable" {
+		t.Errorf("rescheduled entry = %+v", entries[0])
+	}
+	if entries[1].Attempts != 1 {
+		t.Errorf("untried entry attempts = %d, want 1", entries[1].Attempts)
+	}
+
+	// all ignores backoff; permanent failures are dropped.
+	result, err = o.Drain(true, func(e *Entry) error {
+		if e.Kind == KindFindings {
+			return Permanent(errors.New("400 bad payload"))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sent != 1 || len(result.Dropped) != 1 || result.Pending != 0 || result.LastError != nil {
+		t.Errorf("Drain(all) = %+v, want 1 sent and 1 dropped", result)
+	}
+	if !strings.Contains(result.Dropped[0].Error(), "findings awsspectre") {
+		t.Errorf("dropped error = %v, want the upload name", result.Dropped[0])
+	}
+	if n, _ := o.Len(); n != 0 {
+		t.Errorf("Len() = %d after draining, want 0", n)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := map[int]time.Duration{
+		0:  MinBackoff,
+		1:  MinBackoff,
+		2:  2 * MinBackoff,
+		4:  8 * MinBackoff,
+		20: MaxBackoff,
+	}
+	for attempts, want := range tests {
+		if got := Backoff(attempts); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestEmptyOutbox(t *testing.T) {
+	o := Open(filepath.Join(t.TempDir(), "missing"))
+	if n, err := o.Len(); err != nil || n != 0 {
+		t.Errorf("Len() = %d, %v, want 0", n, err)
+	}
+	if entries, err := o.Entries(); err != nil || len(entries) != 0 {
+		t.Errorf("Entries() = %v, %v, want none", entries, err)
+	}
+}
+
+func TestCorruptEntry(t *testing.T) {
+	o := newTestOutbox(t)
+	if err := os.MkdirAll(o.Dir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(o.Dir(), "report-x.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.Entries(); err == nil || !strings.Contains(err.Error(), "parse outbox entry") {
+		t.Errorf("Entries() error = %v, want parse error", err)
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	if Permanent(nil) != nil {
+		t.Error("Permanent(nil) != nil")
+	}
+	base := errors.New("rejected")
+	err := Permanent(base)
+	if !IsPermanent(err) || !errors.Is(err, base) || err.Error() != "rejected" {
+		t.Errorf("Permanent(err) = %v", err)
+	}
+	if IsPermanent(base) {
+		t.Error("IsPermanent(plain error) = true")
+	}
+}
+
+func TestEntriesAreOwnerOnly(t *testing.T) {
+	o := newTestOutbox(t)
+	if _, err := o.Add(KindReport, "", run1, map[string]string{"bucket": "payroll-exports"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(o.Dir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0700 {
+		t.Errorf("outbox directory mode = %o, want 700", perm)
+	}
+	files, _ := filepath.Glob(filepath.Join(o.Dir(), "*.json"))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("%s mode = %o, want 600", filepath.Base(file), perm)
+		}
+	}
+}
+
+func TestEncryptedPayloads(t *testing.T) {
+	dir := t.TempDir()
+	keys := &storage.Keys{Encryption: bytes.Repeat([]byte{7}, 32)}
+
+	o := Open(dir)
+	o.SetKeys(keys)
+	if _, err := o.Add(KindReport, "", run1, map[string]string{"bucket": "payroll-exports"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("expected one entry, got %v", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	if strings.Contains(string(data), "payroll-exports") || !strings.Contains(string(data), "sealed_payload") {
+		t.Fatalf("payload stored readable:\n%s", data)
+	}
+
+	entries, err := o.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(entries[0].Payload) != `{"bucket":"payroll-exports"}` {
+		t.Errorf("Payload = %s, want the decrypted payload", entries[0].Payload)
+	}
+
+	// Without the key the entry is listed but not sent.
+	locked := Open(dir)
+	entries, err = locked.Entries()
+	if err != nil || len(entries) != 1 || entries[0].Payload != nil {
+		t.Fatalf("Entries() without key = %+v, %v", entries, err)
+	}
+	sent := 0
+	result, err := locked.Drain(true, func(*Entry) error { sent++; return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 0 || result.Pending != 1 || result.LastError == nil || !strings.Contains(result.LastError.Error(), "no storage encryption key") {
+		t.Errorf("Drain without key = sent %d, %+v", sent, result)
+	}
+
+	// A wrong key is an error, not an empty payload.
+	wrong := Open(dir)
+	wrong.SetKeys(&storage.Keys{Encryption: bytes.Repeat([]byte{8}, 32)})
+	if _, err := wrong.Entries(); err == nil {
+		t.Error("Entries() with the wrong key should fail")
+	}
+}
@@ -0,0 +1,396 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+// Custom rule scopes.
+const (
+	ScopeIssue  = "issue"  // expression is evaluated once per finding
+	ScopeReport = "report" // expression is evaluated once per report
+)
+
+// DefaultCustomTimeout bounds how long a single custom rule may run across
+// all findings of a report.
+const DefaultCustomTimeout = time.Second
+
+// customCostLimit caps the CEL runtime cost of one evaluation so a runaway
+// comprehension cannot pin the CPU even before the timeout is noticed.
+const customCostLimit = 10_000_000
+
+// CustomRule is an expression-based rule from the `custom` section of the
+// policy file. The expression is written in CEL and describes the condition
+// that violates the policy: when it evaluates to true the rule fires.
+//
+// Issue-scoped rules see `issue` (one NormalizedIssue) and `report`; report-
+// scoped rules see only `report`. Both use the JSON field names, e.g.
+// issue.tool, issue.id, issue.resource, report.summary.total_issues.
+type CustomRule struct {
+	Name     string `yaml:"name"`
+	Expr     string `yaml:"expr"`
+	Scope    string `yaml:"scope,omitempty"`    // issue (default) or report
+	Message  string `yaml:"message,omitempty"`  // text/template over .Rule, .Severity, .Issue, .Report
+	Severity string `yaml:"severity,omitempty"` // critical, high, medium (default), low
+	Timeout  string `yaml:"timeout,omitempty"`  // Go duration, default 1s
+}
+
+// compiledRule is a CustomRule ready for evaluation.
+type compiledRule struct {
+	rule    CustomRule
+	program cel.Program
+	message *template.Template
+	timeout time.Duration
+}
+
+// messageData is the value passed to a custom rule's message template.
+type messageData struct {
+	Rule     string
+	Severity string
+	Issue    map[string]interface{}
+	Report   map[string]interface{}
+}
+
+// scope returns the rule scope, defaulting to issue.
+func (r CustomRule) scope() string {
+	if r.Scope == "" {
+		return ScopeIssue
+	}
+	return r.Scope
+}
+
+// severity returns the rule severity, defaulting to medium.
+func (r CustomRule) severity() string {
+	if r.Severity == "" {
+		return models.SeverityMedium
+	}
+	return r.Severity
+}
+
+// celEnv returns the CEL environment for a scope. The environment only
+// exposes the report data and the standard and string libraries; there is
+// no access to files, network or the clock.
+func celEnv(scope string) (*cel.Env, error) {
+	opts := []cel.EnvOption{
+		cel.Variable("report", cel.MapType(cel.StringType, cel.DynType)),
+		ext.Strings(),
+	}
+	if scope == ScopeIssue {
+		opts = append(opts, cel.Variable("issue", cel.MapType(cel.StringType, cel.DynType)))
+	}
+	return cel.NewEnv(opts...)
+}
+
+// CustomRuleError reports an invalid custom rule and the field at fault.
+type CustomRuleError struct {
+	Rule  string
+	Field string
+	Err   error
+}
+
+func (e *CustomRuleError) Error() string {
+	if e.Rule == "" {
+		return fmt.Sprintf("custom rule: %s: %v", e.Field, e.Err)
+	}
+	return fmt.Sprintf("custom rule %q: %s: %v", e.Rule, e.Field, e.Err)
+}
+
+func (e *CustomRuleError) Unwrap() error { return e.Err }
+
+// compileCustomRule validates and compiles a custom rule.
+func compileCustomRule(r CustomRule) (*compiledRule, error) {
+	fail := func(field, format string, args ...interface{}) error {
+		return &CustomRuleError{Rule: r.Name, Field: field, Err: fmt.Errorf(format, args...)}
+	}
+
+	if r.Name == "" {
+		return nil, fail("name", "is required")
+	}
+	if strings.TrimSpace(r.Expr) == "" {
+		return nil, fail("expr", "is required")
+	}
+
+	scope := r.scope()
+	if scope != ScopeIssue && scope != ScopeReport {
+		return nil, fail("scope", "must be %q or %q, got %q", ScopeIssue, ScopeReport, r.Scope)
+	}
+
+	switch r.severity() {
+	case models.SeverityCritical, models.SeverityHigh, models.SeverityMedium, models.SeverityLow:
+	default:
+		return nil, fail("severity", "unknown severity %q", r.Severity)
+	}
+
+	timeout := DefaultCustomTimeout
+	if r.Timeout != "" {
+		d, err := time.ParseDuration(r.Timeout)
+		if err != nil || d <= 0 {
+			return nil, fail("timeout", "invalid duration %q", r.Timeout)
+		}
+		timeout = d
+	}
+
+	env, err := celEnv(scope)
+	if err != nil {
+		return nil, fail("expr", "%w", err)
+	}
+	ast, iss := env.Compile(r.Expr)
+	if iss.Err() != nil {
+		return nil, fail("expr", "%w", iss.Err())
+	}
+	if out := ast.OutputType(); !out.IsExactType(cel.BoolType) && !out.IsExactType(cel.DynType) {
+		return nil, fail("expr", "must return bool, got %s", out)
+	}
+	prg, err := env.Program(ast,
+		cel.CostLimit(customCostLimit),
+		cel.InterruptCheckFrequency(100),
+	)
+	if err != nil {
+		return nil, fail("expr", "%w", err)
+	}
+
+	text := r.Message
+	if text == "" {
+		if scope == ScopeIssue {
+			text = "{{.Issue.tool}} {{.Issue.category}} {{.Issue.resource}}"
+		} else {
+			text = "custom rule {{.Rule}} matched"
+		}
+	}
+	tmpl, err := template.New(r.Name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fail("message", "%w", err)
+	}
+
+	return &compiledRule{rule: r, program: prg, message: tmpl, timeout: timeout}, nil
+}
+
+// Compile compiles every custom rule and checks max_report_age. LoadFromFile
+// calls it so broken expressions are reported when the policy is loaded;
+// Evaluate and Explain compile lazily for policies built in code.
+func (p *Policy) Compile() error {
+	if p == nil {
+		return nil
+	}
+	if p.Rules.MaxReportAge != "" {
+		if d, err := time.ParseDuration(p.Rules.MaxReportAge); err != nil || d <= 0 {
+			return fmt.Errorf("max_report_age: invalid duration %q", p.Rules.MaxReportAge)
+		}
+	}
+	compiled := make([]*compiledRule, 0, len(p.Custom))
+	for _, r := range p.Custom {
+		c, err := compileCustomRule(r)
+		if err != nil {
+			return err
+		}
+		compiled = append(compiled, c)
+	}
+	p.compiled = compiled
+	return nil
+}
+
+// customRuleName is the rule name reported for a custom rule. The prefix
+// keeps custom rules from being confused with the built-in ones.
+func customRuleName(r CustomRule) string {
+	return "custom:" + r.Name
+}
+
+// customResult is the outcome of evaluating one custom rule.
+type customResult struct {
+	rule     CustomRule
+	matched  bool
+	matches  []models.NormalizedIssue
+	messages []string
+	err      error
+}
+
+// evaluateCustom runs every custom rule against the report. Rules that fail
+// to compile or evaluate (including hitting their time budget) are reported
+// as errors so a broken rule never passes silently.
+func (p *Policy) evaluateCustom(report *models.AggregatedReport) []customResult {
+	if len(p.Custom) == 0 {
+		return nil
+	}
+	if p.compiled == nil {
+		if err := p.Compile(); err != nil {
+			return []customResult{{rule: CustomRule{Name: "custom"}, err: err}}
+		}
+	}
+
+	reportVar, issueVars := celInputs(report)
+
+	results := make([]customResult, 0, len(p.compiled))
+	for _, c := range p.compiled {
+		results = append(results, c.evaluate(report, reportVar, issueVars))
+	}
+	return results
+}
+
+func (c *compiledRule) evaluate(report *models.AggregatedReport, reportVar map[string]interface{}, issueVars []map[string]interface{}) customResult {
+	res := customResult{rule: c.rule}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	if c.rule.scope() == ScopeReport {
+		matched, err := c.eval(ctx, map[string]interface{}{"report": reportVar})
+		if err != nil {
+			res.err = err
+			return res
+		}
+		if matched {
+			res.matched = true
+			res.messages = append(res.messages, c.render(messageData{Report: reportVar}))
+		}
+		return res
+	}
+
+	for i, issueVar := range issueVars {
+		matched, err := c.eval(ctx, map[string]interface{}{"report": reportVar, "issue": issueVar})
+		if err != nil {
+			res.err = fmt.Errorf("finding %s: %w", report.Issues[i].Resource, err)
+			return res
+		}
+		if matched {
+			res.matched = true
+			res.matches = append(res.matches, report.Issues[i])
+			res.messages = append(res.messages, c.render(messageData{Issue: issueVar, Report: reportVar}))
+		}
+	}
+	return res
+}
+
+func (c *compiledRule) eval(ctx context.Context, vars map[string]interface{}) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, fmt.Errorf("time budget %s exceeded", c.timeout)
+	}
+	out, _, err := c.program.ContextEval(ctx, vars)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, fmt.Errorf("time budget %s exceeded", c.timeout)
+		}
+		return false, err
+	}
+	b, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression returned %s, want bool", out.Type().TypeName())
+	}
+	return b, nil
+}
+
+func (c *compiledRule) render(data messageData) string {
+	data.Rule = c.rule.Name
+	data.Severity = c.rule.severity()
+	var buf bytes.Buffer
+	if err := c.message.Execute(&buf, data); err != nil {
+		return fmt.Sprintf("%s (message template error: %v)", c.rule.Name, err)
+	}
+	return buf.String()
+}
+
+// celInputs converts the report and its issues into the plain maps CEL
+// evaluates against, keyed by JSON field name. Every field is always present
+// so expressions never trip over omitted keys. Raw tool data is left out to
+// keep evaluation cheap; rules work on normalized issues and summaries.
+func celInputs(report *models.AggregatedReport) (map[string]interface{}, []map[string]interface{}) {
+	issueVars := make([]map[string]interface{}, len(report.Issues))
+	issueList := make([]interface{}, len(report.Issues))
+	for i, issue := range report.Issues {
+		issueVars[i] = issueVar(issue)
+		issueList[i] = issueVars[i]
+	}
+
+	tools := make(map[string]interface{}, len(report.ToolReports))
+	for name, tr := range report.ToolReports {
+		tools[name] = map[string]interface{}{
+			"tool":         tr.Tool,
+			"version":      tr.Version,
+			"timestamp":    tr.Timestamp,
+			"score":        tr.Score,
+			"status":       tr.Status,
+			"issue_count":  tr.IssueCount,
+			"is_supported": tr.IsSupported,
+		}
+	}
+
+	s := report.Summary
+	reportVar := map[string]interface{}{
+		"timestamp":    report.Timestamp,
+		"issues":       issueList,
+		"tool_reports": tools,
+		"summary": map[string]interface{}{
+			"total_issues":       s.TotalIssues,
+			"issues_by_tool":     countMap(s.IssuesByTool),
+			"issues_by_category": countMap(s.IssuesByCategory),
+			"issues_by_severity": countMap(s.IssuesBySeverity),
+			"health_score":       s.HealthScore,
+			"score_percent":      s.ScorePercent,
+			"total_tools":        s.TotalTools,
+			"supported_tools":    s.SupportedTools,
+			"unsupported_tools":  s.UnsupportedTools,
+		},
+	}
+	return reportVar, issueVars
+}
+
+func issueVar(issue models.NormalizedIssue) map[string]interface{} {
+	// spectre/v1.1 metadata is absent from older reports: attributes and
+	// references read as empty and confidence as 1 (certain), so rules
+	// need no has() guards.
+	var attrs models.ResourceAttributes
+	if issue.Attributes != nil {
+		attrs = *issue.Attributes
+	}
+	tags := attrs.Tags
+	if tags == nil {
+		tags = map[string]string{}
+	}
+	references := issue.References
+	if references == nil {
+		references = []string{}
+	}
+	confidence := 1.0
+	if issue.Confidence != nil {
+		confidence = *issue.Confidence
+	}
+	return map[string]interface{}{
+		"tool":       issue.Tool,
+		"id":         issue.ID,
+		"category":   issue.Category,
+		"severity":   issue.Severity,
+		"resource":   issue.Resource,
+		"evidence":   issue.Evidence,
+		"count":      issue.Count,
+		"first_seen": issue.FirstSeen,
+		"last_seen":  issue.LastSeen,
+		"triage":     issue.Triage,
+		"owner":      issue.Owner,
+		"attributes": map[string]interface{}{
+			"account":   attrs.Account,
+			"region":    attrs.Region,
+			"namespace": attrs.Namespace,
+			"tags":      tags,
+		},
+		"remediation": issue.Remediation,
+		"fingerprint": issue.Fingerprint,
+		"references":  references,
+		"confidence":  confidence,
+	}
+}
+
+// countMap returns m, or an empty map when m is nil, so lookups with
+// `in` and has() behave the same either way.
+func countMap(m map[string]int) map[string]int {
+	if m == nil {
+		return map[string]int{}
+	}
+	return m
+}
@@ -0,0 +1,259 @@
+package policy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ppiankov/spectrehub/internal/aggregator"
+	"github.com/ppiankov/spectrehub/internal/collector"
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+// contractReport aggregates every report under testdata/contracts.
+func contractReport(t *testing.T) *models.AggregatedReport {
+	t.Helper()
+	reports, err := collector.New(collector.Config{MaxConcurrency: 2}).
+		CollectFromPaths([]string{"../../testdata/contracts"})
+	if err != nil {
+		t.Fatalf("CollectFromPaths: %v", err)
+	}
+	report, err := aggregator.New().Aggregate(reports)
+	if err != nil {
+		t.Fatalf("Aggregate: %v", err)
+	}
+	return report
+}
+
+func TestCustomIssueRule(t *testing.T) {
+	p := &Policy{Custom: []CustomRule{{
+		Name:     "prod-vault-paths",
+		Expr:     `issue.tool == "vaultspectre" && issue.resource.contains("prod")`,
+		Message:  "{{.Issue.id}} on production path {{.Issue.resource}}",
+		Severity: "critical",
+	}}}
+
+	result := p.Evaluate(contractReport(t))
+	if result.Pass {
+		t.Fatal("expected custom rule to fail")
+	}
+
+	var found bool
+	for _, v := range result.Violations {
+		if v.Rule != "custom:prod-vault-paths" {
+			continue
+		}
+		if v.Severity != "critical" {
+			t.Errorf("Severity = %q, want critical", v.Severity)
+		}
+		if v.Message == "ACCESS_DENIED on production path secret/data/prod-db" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected templated violation for secret/data/prod-db, got %v", result.Violations)
+	}
+}
+
+func TestCustomIssueRuleNoMatch(t *testing.T) {
+	p := &Policy{Custom: []CustomRule{{
+		Name: "none",
+		Expr: `issue.id == "NO_SUCH_FINDING"`,
+	}}}
+	if result := p.Evaluate(contractReport(t)); !result.Pass {
+		t.Errorf("expected pass, got %v", result.Violations)
+	}
+}
+
+func TestCustomReportRule(t *testing.T) {
+	report := contractReport(t)
+
+	p := &Policy{Custom: []CustomRule{{
+		Name:    "too-many-unused-tables",
+		Scope:   ScopeReport,
+		Expr:    `report.issues.filter(i, i.id == "UNUSED_TABLE").size() > 2`,
+		Message: "{{.Rule}}: score {{.Report.summary.score_percent}}",
+	}}}
+	result := p.Evaluate(report)
+	if result.Pass || len(result.Violations) != 1 {
+		t.Fatalf("expected one violation, got %v", result.Violations)
+	}
+	if !strings.HasPrefix(result.Violations[0].Message, "too-many-unused-tables: score ") {
+		t.Errorf("Message = %q", result.Violations[0].Message)
+	}
+
+	p.Custom[0].Expr = `report.issues.filter(i, i.id == "UNUSED_TABLE").size() > 3`
+	p.compiled = nil
+	if result := p.Evaluate(report); !result.Pass {
+		t.Errorf("expected pass with higher limit, got %v", result.Violations)
+	}
+}
+
+func TestCustomRuleSummaryAccess(t *testing.T) {
+	p := &Policy{Custom: []CustomRule{{
+		Name:  "has-vault",
+		Scope: ScopeReport,
+		Expr:  `"vaultspectre" in report.tool_reports && report.summary.issues_by_tool["vaultspectre"] > 0`,
+	}}}
+	if result := p.Evaluate(contractReport(t)); result.Pass {
+		t.Error("expected report rule over summary maps to fire")
+	}
+}
+
+func TestCustomRuleLegacyIssuesHaveEmptyID(t *testing.T) {
+	report := &models.AggregatedReport{Issues: []models.NormalizedIssue{
+		{Tool: "vaultspectre", Category: "missing", Severity: "critical", Resource: "secret/a"},
+	}}
+	p := &Policy{Custom: []CustomRule{{Name: "ids", Expr: `issue.id == ""`}}}
+	result := p.Evaluate(report)
+	if len(result.Violations) != 1 || strings.HasPrefix(result.Violations[0].Message, "evaluation failed") {
+		t.Errorf("expected a single match, got %v", result.Violations)
+	}
+}
+
+func TestCustomRuleTimeout(t *testing.T) {
+	p := &Policy{Custom: []CustomRule{{
+		Name:    "slow",
+		Expr:    `issue.tool != ""`,
+		Timeout: "1ns",
+	}}}
+	result := p.Evaluate(contractReport(t))
+	if result.Pass || !strings.Contains(result.Violations[0].Message, "time budget") {
+		t.Errorf("expected time budget violation, got %v", result.Violations)
+	}
+}
+
+func TestCustomRuleNonBoolResult(t *testing.T) {
+	p := &Policy{Custom: []CustomRule{{Name: "count", Expr: `issue.count`}}}
+	result := p.Evaluate(contractReport(t))
+	if result.Pass || !strings.Contains(result.Violations[0].Message, "evaluation failed") {
+		t.Errorf("expected evaluation failure, got %v", result.Violations)
+	}
+}
+
+func TestCompileCustomRuleErrors(t *testing.T) {
+	tests := []struct {
+		rule  CustomRule
+		field string
+	}{
+		{CustomRule{Expr: "true"}, "name"},
+		{CustomRule{Name: "a"}, "expr"},
+		{CustomRule{Name: "a", Expr: "issue.tool =="}, "expr"},
+		{CustomRule{Name: "a", Expr: `"x"`}, "expr"},
+		{CustomRule{Name: "a", Expr: "issue.tool != ''", Scope: ScopeReport}, "expr"},
+		{CustomRule{Name: "a", Expr: "true", Scope: "tool"}, "scope"},
+		{CustomRule{Name: "a", Expr: "true", Severity: "urgent"}, "severity"},
+		{CustomRule{Name: "a", Expr: "true", Timeout: "soon"}, "timeout"},
+		{CustomRule{Name: "a", Expr: "true", Message: "{{.Issue"}, "message"},
+	}
+	for _, tt := range tests {
+		_, err := compileCustomRule(tt.rule)
+		var ruleErr *CustomRuleError
+		if !errors.As(err, &ruleErr) {
+			t.Errorf("%+v: expected CustomRuleError, got %v", tt.rule, err)
+			continue
+		}
+		if ruleErr.Field != tt.field {
+			t.Errorf("%+v: Field = %q, want %q", tt.rule, ruleErr.Field, tt.field)
+		}
+	}
+}
+
+func TestLoadFromFileCustomRules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "custom.yaml")
+	content := `version: "1"
+custom:
+  - name: no-public-buckets
+    expr: issue.id == "PUBLIC_ACCESS"
+    severity: high
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if len(p.Custom) != 1 || len(p.compiled) != 1 {
+		t.Fatalf("expected 1 compiled custom rule, got %d/%d", len(p.Custom), len(p.compiled))
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("version: \"1\"\ncustom:\n  - name: x\n    expr: issue.tool ==\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFromFile(bad); err == nil {
+		t.Error("expected error for invalid expression")
+	}
+}
+
+func TestExplainCustomRule(t *testing.T) {
+	p := &Policy{Custom: []CustomRule{{
+		Name: "unused-topics",
+		Expr: `issue.id == "UNUSED_TOPIC"`,
+	}}}
+	results := p.Explain(contractReport(t))
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	r := results[0]
+	if r.Pass || len(r.Matches) != 2 || r.Severity != "medium" {
+		t.Errorf("unexpected result: %+v", r)
+	}
+	if !strings.HasPrefix(r.Message, "2 findings match") {
+		t.Errorf("Message = %q", r.Message)
+	}
+}
+
+func TestLintCustom(t *testing.T) {
+	data := []byte(`version: "1"
+custom:
+  - name: a
+    expr: issue.tool ==
+  - name: a
+    expr: "true"
+    severty: high
+  - expr: "true"
+    scope: tool
+`)
+	issues := Lint(data)
+	wantLines := []int{4, 5, 7, 8, 9}
+	if len(issues) != len(wantLines) {
+		t.Fatalf("expected %d issues, got %d: %v", len(wantLines), len(issues), issues)
+	}
+	for i, want := range wantLines {
+		if issues[i].Line != want {
+			t.Errorf("issue %d Line = %d, want %d (%s)", i, issues[i].Line, want, issues[i].Message)
+		}
+	}
+}
+
+func TestCustomRuleSpectreV11Metadata(t *testing.T) {
+	confidence := 0.4
+	report := &models.AggregatedReport{Issues: []models.NormalizedIssue{
+		{Tool: "awsspectre", ID: "IDLE_EC2", Severity: "medium", Resource: "i-1",
+			Attributes: &models.ResourceAttributes{Region: "us-east-1", Tags: map[string]string{"env": "prod"}},
+			Confidence: &confidence},
+		{Tool: "awsspectre", ID: "IDLE_EC2", Severity: "medium", Resource: "i-2"},
+	}}
+	p := &Policy{Custom: []CustomRule{{
+		Name: "prod-us-east",
+		Expr: `issue.attributes.region == "us-east-1" && issue.attributes.tags["env"] == "prod" && issue.confidence < 0.5`,
+	}, {
+		Name: "unattributed",
+		Expr: `issue.attributes.region == "" && issue.confidence == 1.0 && issue.references.size() == 0`,
+	}}}
+	result := p.Evaluate(report)
+	if len(result.Violations) != 2 {
+		t.Fatalf("expected one violation per rule, got %v", result.Violations)
+	}
+	for _, v := range result.Violations {
+		if strings.HasPrefix(v.Message, "evaluation failed") {
+			t.Errorf("unexpected evaluation error: %v", v)
+		}
+	}
+}
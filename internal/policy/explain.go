@@ -0,0 +1,250 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+// RuleResult is the outcome of a single configured rule together with the
+// findings it applies to. Rules that need SpectreHub API data (SLA and user
+// activity) cannot be judged from a report alone and are marked Skipped.
+type RuleResult struct {
+	Rule     string                   `json:"rule"`
+	Setting  string                   `json:"setting"`
+	Pass     bool                     `json:"pass"`
+	Skipped  bool                     `json:"skipped,omitempty"`
+	Severity string                   `json:"severity,omitempty"` // set for custom rules
+	Message  string                   `json:"message"`
+	Matches  []models.NormalizedIssue `json:"matches,omitempty"`
+
+	// violations are what Evaluate reports for the rule, one per offending
+	// category, tool, report or finding.
+	violations []Violation
+}
+
+// violate records a violation of the rule.
+func (res *RuleResult) violate(msg string) {
+	res.violations = append(res.violations, Violation{Rule: res.Rule, Message: msg, Severity: res.Severity})
+}
+
+// Explain evaluates every configured rule against the report and returns
+// one result per rule: built-in rules in the order they are declared in
+// Rules, then custom rules in file order. Unlike Evaluate it also reports
+// passing rules and lists the findings each rule applies to, which is what
+// `spectrehub policy check/explain` display. Evaluate is built on it, so the
+// two always agree on which rules fail.
+func (p *Policy) Explain(report *models.AggregatedReport) []RuleResult {
+	if p == nil {
+		return nil
+	}
+
+	var results []RuleResult
+	r := p.Rules
+
+	if r.MaxIssues != nil {
+		count := report.Summary.TotalIssues
+		results = append(results, limitResult("max_issues", *r.MaxIssues, count,
+			fmt.Sprintf("total issues %d", count), report.Issues))
+	}
+
+	if r.MaxCritical != nil {
+		count := report.Summary.IssuesBySeverity[models.SeverityCritical]
+		results = append(results, limitResult("max_critical", *r.MaxCritical, count,
+			fmt.Sprintf("critical issues %d", count), issuesWithSeverity(report.Issues, models.SeverityCritical)))
+	}
+
+	if r.MaxHigh != nil {
+		count := report.Summary.IssuesBySeverity[models.SeverityHigh]
+		results = append(results, limitResult("max_high", *r.MaxHigh, count,
+			fmt.Sprintf("high issues %d", count), issuesWithSeverity(report.Issues, models.SeverityHigh)))
+	}
+
+	if r.MinScore != nil {
+		res := RuleResult{
+			Rule:    "min_score",
+			Setting: fmt.Sprintf("%.1f", *r.MinScore),
+			Pass:    report.Summary.ScorePercent >= *r.MinScore,
+		}
+		if res.Pass {
+			res.Message = fmt.Sprintf("score %.1f%% meets minimum %.1f%%", report.Summary.ScorePercent, *r.MinScore)
+		} else {
+			res.Message = fmt.Sprintf("score %.1f%% below minimum %.1f%%", report.Summary.ScorePercent, *r.MinScore)
+			res.violate(res.Message)
+		}
+		results = append(results, res)
+	}
+
+	if len(r.ForbidCategories) > 0 {
+		forbidden := make(map[string]bool, len(r.ForbidCategories))
+		for _, c := range r.ForbidCategories {
+			forbidden[c] = true
+		}
+		var matches []models.NormalizedIssue
+		for _, issue := range report.Issues {
+			if forbidden[issue.Category] {
+				matches = append(matches, issue)
+			}
+		}
+
+		res := RuleResult{
+			Rule:    "forbid_categories",
+			Setting: strings.Join(r.ForbidCategories, ", "),
+			Matches: matches,
+		}
+		var hits []string
+		for _, c := range r.ForbidCategories {
+			if n := report.Summary.IssuesByCategory[c]; n > 0 {
+				hits = append(hits, fmt.Sprintf("%s (%d)", c, n))
+				res.violate(fmt.Sprintf("forbidden category %q has %d issues", c, n))
+			}
+		}
+		res.Pass = len(hits) == 0
+		if res.Pass {
+			res.Message = "no issues in forbidden categories"
+		} else {
+			res.Message = "forbidden categories present: " + strings.Join(hits, ", ")
+		}
+		results = append(results, res)
+	}
+
+	if len(r.RequireTools) > 0 {
+		res := RuleResult{
+			Rule:    "require_tools",
+			Setting: strings.Join(r.RequireTools, ", "),
+		}
+		var missing []string
+		for _, tool := range r.RequireTools {
+			if _, found := report.ToolReports[tool]; !found {
+				missing = append(missing, tool)
+				res.violate(fmt.Sprintf("required tool %q not found in report", tool))
+			}
+		}
+		res.Pass = len(missing) == 0
+		if res.Pass {
+			res.Message = "all required tools reported"
+		} else {
+			res.Message = "missing required tools: " + strings.Join(missing, ", ")
+		}
+		results = append(results, res)
+	}
+
+	if r.MinCoverage != nil {
+		res := RuleResult{
+			Rule:    "min_coverage",
+			Setting: fmt.Sprintf("%.1f", *r.MinCoverage),
+			Pass:    true,
+		}
+		switch cov := report.Coverage; {
+		case cov == nil:
+			res.Message = "expected tools unknown for this run"
+		case cov.Percent() < *r.MinCoverage:
+			res.Pass = false
+			res.Message = fmt.Sprintf("coverage %.1f%% below minimum %.1f%% (missing %s)",
+				cov.Percent(), *r.MinCoverage, strings.Join(cov.Missing, ", "))
+			res.violate(res.Message)
+		default:
+			res.Message = fmt.Sprintf("coverage %.1f%% meets minimum %.1f%%", cov.Percent(), *r.MinCoverage)
+		}
+		results = append(results, res)
+	}
+
+	if r.MaxReportAge != "" {
+		msgs := staleReports(report, r.reportAge())
+		res := RuleResult{
+			Rule:    "max_report_age",
+			Setting: r.MaxReportAge,
+			Pass:    len(msgs) == 0,
+		}
+		if res.Pass {
+			res.Message = "all tool reports are within " + r.MaxReportAge
+		} else {
+			res.Message = strings.Join(msgs, "; ")
+		}
+		for _, msg := range msgs {
+			res.violate(msg)
+		}
+		results = append(results, res)
+	}
+
+	apiRules := []struct {
+		name  string
+		value *int
+	}{
+		{"max_open_critical_days", r.MaxOpenCriticalDays},
+		{"max_open_high_days", r.MaxOpenHighDays},
+		{"max_inactive_users", r.MaxInactiveUsers},
+		{"max_never_seen_users", r.MaxNeverSeenUsers},
+	}
+	for _, ar := range apiRules {
+		if ar.value == nil {
+			continue
+		}
+		results = append(results, RuleResult{
+			Rule:    ar.name,
+			Setting: fmt.Sprintf("%d", *ar.value),
+			Pass:    true,
+			Skipped: true,
+			Message: "requires SpectreHub API data (evaluated during run/collect with a license key)",
+		})
+	}
+
+	for _, cr := range p.evaluateCustom(report) {
+		res := RuleResult{
+			Rule:     customRuleName(cr.rule),
+			Setting:  cr.rule.Expr,
+			Severity: cr.rule.severity(),
+			Pass:     cr.err == nil && !cr.matched,
+			Matches:  cr.matches,
+		}
+		switch {
+		case cr.err != nil:
+			res.Message = fmt.Sprintf("evaluation failed: %v", cr.err)
+			res.violate(res.Message)
+		case !cr.matched && cr.rule.scope() == ScopeIssue:
+			res.Message = "no findings match"
+		case !cr.matched:
+			res.Message = "condition not met"
+		case len(cr.messages) == 1:
+			res.Message = cr.messages[0]
+		default:
+			res.Message = fmt.Sprintf("%d findings match (first: %s)", len(cr.messages), cr.messages[0])
+		}
+		if cr.err == nil {
+			for _, msg := range cr.messages {
+				res.violate(msg)
+			}
+		}
+		results = append(results, res)
+	}
+
+	return results
+}
+
+// limitResult builds the result for a "count must not exceed limit" rule.
+func limitResult(rule string, limit, count int, what string, matches []models.NormalizedIssue) RuleResult {
+	res := RuleResult{
+		Rule:    rule,
+		Setting: fmt.Sprintf("%d", limit),
+		Pass:    count <= limit,
+		Matches: matches,
+	}
+	if res.Pass {
+		res.Message = fmt.Sprintf("%s within limit %d", what, limit)
+	} else {
+		res.Message = fmt.Sprintf("%s exceeds limit %d", what, limit)
+		res.violate(res.Message)
+	}
+	return res
+}
+
+func issuesWithSeverity(issues []models.NormalizedIssue, severity string) []models.NormalizedIssue {
+	var out []models.NormalizedIssue
+	for _, issue := range issues {
+		if issue.Severity == severity {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package policy
+
+import "testing"
+
+func findRule(results []RuleResult, name string) *RuleResult {
+	for i := range results {
+		if results[i].Rule == name {
+			return &results[i]
+		}
+	}
+	return nil
+}
+
+func TestExplainNilPolicy(t *testing.T) {
+	var p *Policy
+	if got := p.Explain(baseReport()); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
+
+func TestExplainMatchesEvaluate(t *testing.T) {
+	p := &Policy{Rules: Rules{
+		MaxIssues:        intPtr(5),
+		MaxCritical:      intPtr(0),
+		MinScore:         floatPtr(80),
+		ForbidCategories: []string{"unused"},
+		RequireTools:     []string{"vaultspectre", "kafkaspectre"},
+	}}
+	report := baseReport()
+
+	results := p.Explain(report)
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+
+	failed := map[string]bool{}
+	for _, r := range results {
+		if !r.Pass {
+			failed[r.Rule] = true
+		}
+	}
+	violated := map[string]bool{}
+	for _, v := range p.Evaluate(report).Violations {
+		violated[v.Rule] = true
+		if !failed[v.Rule] {
+			t.Errorf("Evaluate reports %s but Explain passes it", v.Rule)
+		}
+	}
+	for rule := range failed {
+		if !violated[rule] {
+			t.Errorf("Explain fails %s but Evaluate reports no violation", rule)
+		}
+	}
+	if failed["max_issues"] {
+		t.Error("max_issues should pass")
+	}
+}
+
+func TestExplainMatches(t *testing.T) {
+	p := &Policy{Rules: Rules{
+		MaxIssues:        intPtr(5),
+		MaxCritical:      intPtr(0),
+		ForbidCategories: []string{"unused"},
+	}}
+	results := p.Explain(baseReport())
+
+	if r := findRule(results, "max_issues"); r == nil || len(r.Matches) != 2 {
+		t.Errorf("max_issues should apply to all findings, got %+v", r)
+	}
+
+	crit := findRule(results, "max_critical")
+	if crit == nil || len(crit.Matches) != 1 || crit.Matches[0].Resource != "secret/db" {
+		t.Errorf("max_critical should apply to secret/db, got %+v", crit)
+	}
+	if crit.Pass {
+		t.Error("max_critical should fail")
+	}
+
+	forbid := findRule(results, "forbid_categories")
+	if forbid == nil || len(forbid.Matches) != 1 || forbid.Matches[0].Resource != "s3://bucket" {
+		t.Errorf("forbid_categories should apply to s3://bucket, got %+v", forbid)
+	}
+}
+
+func TestExplainRequireToolsMessage(t *testing.T) {
+	p := &Policy{Rules: Rules{RequireTools: []string{"kafkaspectre"}}}
+	r := p.Explain(baseReport())[0]
+	if r.Pass {
+		t.Error("expected require_tools to fail")
+	}
+	if r.Message != "missing required tools: kafkaspectre" {
+		t.Errorf("Message = %q", r.Message)
+	}
+}
+
+func TestExplainAPIRulesSkipped(t *testing.T) {
+	p := &Policy{Rules: Rules{MaxOpenCriticalDays: intPtr(7), MaxInactiveUsers: intPtr(3)}}
+	results := p.Explain(baseReport())
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if !r.Skipped || !r.Pass {
+			t.Errorf("%s should be skipped and passing, got %+v", r.Rule, r)
+		}
+	}
+}
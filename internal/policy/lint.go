@@ -0,0 +1,398 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// SupportedVersion is the only policy file version understood by this build.
+const SupportedVersion = "1"
+
+// valueKind describes the YAML shape a rule value must have.
+type valueKind int
+
+const (
+	kindInt valueKind = iota
+	kindFloat
+	kindStringList
+	kindDuration
+)
+
+// ruleKinds lists every rule key accepted under `rules:` and its value shape.
+// Keep in sync with the yaml tags on Rules.
+var ruleKinds = map[string]valueKind{
+	"max_issues":             kindInt,
+	"max_critical":           kindInt,
+	"max_high":               kindInt,
+	"min_score":              kindFloat,
+	"forbid_categories":      kindStringList,
+	"require_tools":          kindStringList,
+	"min_coverage":           kindFloat,
+	"max_report_age":         kindDuration,
+	"max_open_critical_days": kindInt,
+	"max_open_high_days":     kindInt,
+	"max_inactive_users":     kindInt,
+	"max_never_seen_users":   kindInt,
+}
+
+// topLevelKeys lists the keys accepted at the root of a policy file.
+var topLevelKeys = []string{"version", "rules", "custom"}
+
+// customKeys lists the keys accepted in each entry of `custom:`.
+var customKeys = []string{"name", "expr", "scope", "message", "severity", "timeout"}
+
+// knownCategories are the normalized categories accepted by forbid_categories.
+var knownCategories = map[string]bool{
+	models.StatusMissing:    true,
+	models.StatusUnused:     true,
+	models.StatusStale:      true,
+	models.StatusDrift:      true,
+	models.StatusError:      true,
+	models.StatusMisconfig:  true,
+	models.StatusAccessDeny: true,
+	models.StatusInvalid:    true,
+}
+
+// LintIssue is a single schema problem found in a policy file.
+type LintIssue struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%d:%d: %s", i.Line, i.Column, i.Message)
+}
+
+// yamlErrLine extracts the line number from a yaml.v3 syntax error.
+var yamlErrLine = regexp.MustCompile(`line (\d+)`)
+
+// Lint performs strict schema validation of a policy file. Unlike
+// LoadFromFile it keeps going after the first problem and reports every
+// issue with its line and column, including unknown keys (with a
+// suggestion for likely typos), wrong value types, and out-of-range values.
+func Lint(data []byte) []LintIssue {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		line := 0
+		if m := yamlErrLine.FindStringSubmatch(err.Error()); m != nil {
+			line, _ = strconv.Atoi(m[1])
+		}
+		return []LintIssue{{Line: line, Message: err.Error()}}
+	}
+
+	if len(root.Content) == 0 {
+		return []LintIssue{{Line: 1, Column: 1, Message: "policy file is empty"}}
+	}
+
+	doc := root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return []LintIssue{issueAt(doc, "policy must be a mapping with 'version' and 'rules'")}
+	}
+
+	var issues []LintIssue
+	seen := make(map[string]bool)
+	hasVersion := false
+
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		k, v := doc.Content[i], doc.Content[i+1]
+		if seen[k.Value] {
+			issues = append(issues, issueAt(k, fmt.Sprintf("duplicate key %q", k.Value)))
+			continue
+		}
+		seen[k.Value] = true
+
+		switch k.Value {
+		case "version":
+			hasVersion = true
+			if v.Kind != yaml.ScalarNode {
+				issues = append(issues, issueAt(v, "version must be a string"))
+			} else if v.Value != SupportedVersion {
+				issues = append(issues, issueAt(v, fmt.Sprintf("unsupported policy version %q (expected %q)", v.Value, SupportedVersion)))
+			}
+		case "rules":
+			issues = append(issues, lintRules(v)...)
+		case "custom":
+			issues = append(issues, lintCustom(v)...)
+		default:
+			issues = append(issues, unknownKeyIssue(k, topLevelKeys))
+		}
+	}
+
+	if !hasVersion {
+		issues = append(issues, LintIssue{Line: doc.Line, Column: doc.Column, Message: "missing required key 'version'"})
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Column < issues[j].Column
+	})
+
+	return issues
+}
+
+// lintRules validates the `rules:` mapping.
+func lintRules(node *yaml.Node) []LintIssue {
+	if node.Kind != yaml.MappingNode {
+		return []LintIssue{issueAt(node, "rules must be a mapping")}
+	}
+
+	known := make([]string, 0, len(ruleKinds))
+	for name := range ruleKinds {
+		known = append(known, name)
+	}
+	sort.Strings(known)
+
+	var issues []LintIssue
+	seen := make(map[string]bool)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		if seen[k.Value] {
+			issues = append(issues, issueAt(k, fmt.Sprintf("duplicate rule %q", k.Value)))
+			continue
+		}
+		seen[k.Value] = true
+
+		kind, ok := ruleKinds[k.Value]
+		if !ok {
+			issues = append(issues, unknownKeyIssue(k, known))
+			continue
+		}
+
+		switch kind {
+		case kindInt:
+			issues = append(issues, lintInt(k.Value, v)...)
+		case kindFloat:
+			issues = append(issues, lintScore(k.Value, v)...)
+		case kindStringList:
+			issues = append(issues, lintStringList(k.Value, v)...)
+		case kindDuration:
+			issues = append(issues, lintDuration(k.Value, v)...)
+		}
+	}
+
+	return issues
+}
+
+func lintInt(name string, v *yaml.Node) []LintIssue {
+	if v.Kind != yaml.ScalarNode || v.Tag != "!!int" {
+		return []LintIssue{issueAt(v, fmt.Sprintf("%s must be an integer, got %s", name, describeNode(v)))}
+	}
+	n, err := strconv.Atoi(v.Value)
+	if err != nil {
+		return []LintIssue{issueAt(v, fmt.Sprintf("%s: %v", name, err))}
+	}
+	if n < 0 {
+		return []LintIssue{issueAt(v, fmt.Sprintf("%s must be non-negative, got %d", name, n))}
+	}
+	return nil
+}
+
+func lintDuration(name string, v *yaml.Node) []LintIssue {
+	if v.Kind != yaml.ScalarNode || v.Tag != "!!str" {
+		return []LintIssue{issueAt(v, fmt.Sprintf("%s must be a duration such as 24h, got %s", name, describeNode(v)))}
+	}
+	d, err := time.ParseDuration(v.Value)
+	if err != nil {
+		return []LintIssue{issueAt(v, fmt.Sprintf("%s: %v", name, err))}
+	}
+	if d <= 0 {
+		return []LintIssue{issueAt(v, fmt.Sprintf("%s must be positive, got %s", name, v.Value))}
+	}
+	return nil
+}
+
+func lintScore(name string, v *yaml.Node) []LintIssue {
+	if v.Kind != yaml.ScalarNode || (v.Tag != "!!int" && v.Tag != "!!float") {
+		return []LintIssue{issueAt(v, fmt.Sprintf("%s must be a number, got %s", name, describeNode(v)))}
+	}
+	f, err := strconv.ParseFloat(v.Value, 64)
+	if err != nil {
+		return []LintIssue{issueAt(v, fmt.Sprintf("%s: %v", name, err))}
+	}
+	if f < 0 || f > 100 {
+		return []LintIssue{issueAt(v, fmt.Sprintf("%s must be between 0 and 100, got %g", name, f))}
+	}
+	return nil
+}
+
+func lintStringList(name string, v *yaml.Node) []LintIssue {
+	if v.Kind != yaml.SequenceNode {
+		return []LintIssue{issueAt(v, fmt.Sprintf("%s must be a list of strings, got %s", name, describeNode(v)))}
+	}
+
+	var issues []LintIssue
+	for _, item := range v.Content {
+		if item.Kind != yaml.ScalarNode || item.Tag != "!!str" {
+			issues = append(issues, issueAt(item, fmt.Sprintf("%s entries must be strings, got %s", name, describeNode(item))))
+			continue
+		}
+		switch name {
+		case "forbid_categories":
+			if !knownCategories[item.Value] {
+				issues = append(issues, issueAt(item, fmt.Sprintf("unknown category %q", item.Value)))
+			}
+		case "require_tools":
+			if !models.IsSupportedTool(models.ToolType(item.Value)) {
+				issues = append(issues, issueAt(item, fmt.Sprintf("unknown tool %q", item.Value)))
+			}
+		}
+	}
+	return issues
+}
+
+// lintCustom validates the `custom:` list, compiling each expression so
+// CEL syntax and type errors are reported against the rule's line.
+func lintCustom(node *yaml.Node) []LintIssue {
+	if node.Kind != yaml.SequenceNode {
+		return []LintIssue{issueAt(node, fmt.Sprintf("custom must be a list of rules, got %s", describeNode(node)))}
+	}
+
+	var issues []LintIssue
+	names := make(map[string]bool)
+
+	for _, item := range node.Content {
+		if item.Kind != yaml.MappingNode {
+			issues = append(issues, issueAt(item, fmt.Sprintf("custom rule must be a mapping, got %s", describeNode(item))))
+			continue
+		}
+
+		fields := make(map[string]*yaml.Node)
+		for i := 0; i+1 < len(item.Content); i += 2 {
+			k, v := item.Content[i], item.Content[i+1]
+			if !slices.Contains(customKeys, k.Value) {
+				issues = append(issues, unknownKeyIssue(k, customKeys))
+				continue
+			}
+			if fields[k.Value] != nil {
+				issues = append(issues, issueAt(k, fmt.Sprintf("duplicate key %q", k.Value)))
+				continue
+			}
+			if v.Kind != yaml.ScalarNode || v.Tag != "!!str" {
+				issues = append(issues, issueAt(v, fmt.Sprintf("%s must be a string, got %s", k.Value, describeNode(v))))
+				continue
+			}
+			fields[k.Value] = v
+		}
+
+		name := fields["name"]
+		switch {
+		case name == nil:
+			issues = append(issues, issueAt(item, "custom rule is missing 'name'"))
+		case names[name.Value]:
+			issues = append(issues, issueAt(name, fmt.Sprintf("duplicate custom rule name %q", name.Value)))
+		default:
+			names[name.Value] = true
+		}
+
+		if fields["expr"] == nil {
+			issues = append(issues, issueAt(item, "custom rule is missing 'expr'"))
+			continue
+		}
+
+		rule := CustomRule{Name: "rule"}
+		if name != nil {
+			rule.Name = name.Value
+		}
+		for key, dst := range map[string]*string{
+			"expr": &rule.Expr, "scope": &rule.Scope, "message": &rule.Message,
+			"severity": &rule.Severity, "timeout": &rule.Timeout,
+		} {
+			if v := fields[key]; v != nil {
+				*dst = v.Value
+			}
+		}
+
+		if _, err := compileCustomRule(rule); err != nil {
+			at := item
+			var ruleErr *CustomRuleError
+			if errors.As(err, &ruleErr) && fields[ruleErr.Field] != nil {
+				at = fields[ruleErr.Field]
+			}
+			issues = append(issues, issueAt(at, err.Error()))
+		}
+	}
+
+	return issues
+}
+
+func issueAt(n *yaml.Node, msg string) LintIssue {
+	return LintIssue{Line: n.Line, Column: n.Column, Message: msg}
+}
+
+// unknownKeyIssue reports an unrecognized key, suggesting the closest known
+// key when the name looks like a typo.
+func unknownKeyIssue(k *yaml.Node, known []string) LintIssue {
+	msg := fmt.Sprintf("unknown key %q", k.Value)
+	if s := closestKey(k.Value, known); s != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", s)
+	}
+	return issueAt(k, msg)
+}
+
+// closestKey returns the known key within edit distance 2 of name, if any.
+func closestKey(name string, known []string) string {
+	best, bestDist := "", 3
+	for _, k := range known {
+		if d := editDistance(name, k); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// editDistance computes the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func describeNode(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "mapping"
+	case yaml.SequenceNode:
+		return "list"
+	case yaml.AliasNode:
+		return "alias"
+	}
+	switch n.Tag {
+	case "!!str":
+		return fmt.Sprintf("string %q", n.Value)
+	case "!!null":
+		return "null"
+	case "!!bool":
+		return "boolean"
+	case "!!float":
+		return "float"
+	case "!!int":
+		return "integer"
+	}
+	return n.Tag
+}
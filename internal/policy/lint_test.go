@@ -0,0 +1,144 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintValid(t *testing.T) {
+	data := []byte(`version: "1"
+rules:
+  max_issues: 10
+  max_critical: 0
+  min_score: 85.5
+  forbid_categories:
+    - missing
+  require_tools:
+    - vaultspectre
+`)
+	if issues := Lint(data); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
+
+func TestLintUnknownRuleSuggestsFix(t *testing.T) {
+	data := []byte(`version: "1"
+rules:
+  max_critcal: 0
+`)
+	issues := Lint(data)
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %v", issues)
+	}
+	if issues[0].Line != 3 {
+		t.Errorf("Line = %d, want 3", issues[0].Line)
+	}
+	if !strings.Contains(issues[0].Message, `did you mean "max_critical"`) {
+		t.Errorf("expected suggestion, got %q", issues[0].Message)
+	}
+}
+
+func TestLintUnknownTopLevelKey(t *testing.T) {
+	data := []byte(`version: "1"
+rule:
+  max_issues: 1
+`)
+	issues := Lint(data)
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, `did you mean "rules"`) {
+		t.Errorf("expected unknown key with suggestion, got %v", issues)
+	}
+}
+
+func TestLintWrongTypes(t *testing.T) {
+	data := []byte(`version: "1"
+rules:
+  max_issues: ten
+  min_score: 150
+  forbid_categories: missing
+  require_tools:
+    - vaultspectre
+    - nosuchspectre
+`)
+	issues := Lint(data)
+	if len(issues) != 4 {
+		t.Fatalf("expected 4 issues, got %d: %v", len(issues), issues)
+	}
+	wantLines := []int{3, 4, 5, 8}
+	for i, want := range wantLines {
+		if issues[i].Line != want {
+			t.Errorf("issue %d Line = %d, want %d (%s)", i, issues[i].Line, want, issues[i].Message)
+		}
+	}
+}
+
+func TestLintUnknownCategory(t *testing.T) {
+	data := []byte(`version: "1"
+rules:
+  forbid_categories: [missing, nope]
+`)
+	issues := Lint(data)
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, `unknown category "nope"`) {
+		t.Errorf("expected unknown category, got %v", issues)
+	}
+}
+
+func TestLintNegativeInt(t *testing.T) {
+	issues := Lint([]byte("version: \"1\"\nrules:\n  max_high: -1\n"))
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "non-negative") {
+		t.Errorf("expected non-negative issue, got %v", issues)
+	}
+}
+
+func TestLintMissingVersion(t *testing.T) {
+	issues := Lint([]byte("rules:\n  max_issues: 1\n"))
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "version") {
+		t.Errorf("expected missing version, got %v", issues)
+	}
+}
+
+func TestLintUnsupportedVersion(t *testing.T) {
+	issues := Lint([]byte("version: \"2\"\nrules: {}\n"))
+	if len(issues) != 1 || issues[0].Line != 1 {
+		t.Errorf("expected unsupported version on line 1, got %v", issues)
+	}
+}
+
+func TestLintDuplicateRule(t *testing.T) {
+	issues := Lint([]byte("version: \"1\"\nrules:\n  max_issues: 1\n  max_issues: 2\n"))
+	if len(issues) != 1 || issues[0].Line != 4 {
+		t.Errorf("expected duplicate on line 4, got %v", issues)
+	}
+}
+
+func TestLintSyntaxError(t *testing.T) {
+	issues := Lint([]byte("version: \"1\"\nrules:\n  max_issues: [1\n"))
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %v", issues)
+	}
+	if issues[0].Line == 0 {
+		t.Errorf("expected line number from syntax error, got %v", issues[0])
+	}
+}
+
+func TestLintEmpty(t *testing.T) {
+	issues := Lint(nil)
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "empty") {
+		t.Errorf("expected empty-file issue, got %v", issues)
+	}
+}
+
+func TestLintIssueString(t *testing.T) {
+	got := LintIssue{Line: 3, Column: 5, Message: "boom"}.String()
+	if got != "3:5: boom" {
+		t.Errorf("String() = %q, want %q", got, "3:5: boom")
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	if d := editDistance("max_critcal", "max_critical"); d != 1 {
+		t.Errorf("editDistance = %d, want 1", d)
+	}
+	if d := editDistance("", "abc"); d != 3 {
+		t.Errorf("editDistance = %d, want 3", d)
+	}
+}
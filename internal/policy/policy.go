@@ -1,9 +1,13 @@
 package policy
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/ppiankov/spectrehub/internal/apiclient"
 	"github.com/ppiankov/spectrehub/internal/models"
@@ -12,8 +16,11 @@ import (
 
 // Policy defines enforcement rules for audit results.
 type Policy struct {
-	Version string `yaml:"version"`
-	Rules   Rules  `yaml:"rules"`
+	Version string       `yaml:"version"`
+	Rules   Rules        `yaml:"rules"`
+	Custom  []CustomRule `yaml:"custom,omitempty"`
+
+	compiled []*compiledRule
 }
 
 // Rules contains all configurable policy rules.
@@ -24,6 +31,8 @@ type Rules struct {
 	MinScore            *float64 `yaml:"min_score,omitempty"`
 	ForbidCategories    []string `yaml:"forbid_categories,omitempty"`
 	RequireTools        []string `yaml:"require_tools,omitempty"`
+	MinCoverage         *float64 `yaml:"min_coverage,omitempty"`
+	MaxReportAge        string   `yaml:"max_report_age,omitempty"` // Go duration, e.g. 24h
 	MaxOpenCriticalDays *int     `yaml:"max_open_critical_days,omitempty"`
 	MaxOpenHighDays     *int     `yaml:"max_open_high_days,omitempty"`
 	MaxInactiveUsers    *int     `yaml:"max_inactive_users,omitempty"`
@@ -32,8 +41,9 @@ type Rules struct {
 
 // Violation is a single policy failure.
 type Violation struct {
-	Rule    string `json:"rule"`
-	Message string `json:"message"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+	Severity string `json:"severity,omitempty"` // set for custom rules
 }
 
 // Result holds the outcome of a policy check.
@@ -42,7 +52,9 @@ type Result struct {
 	Violations []Violation `json:"violations"`
 }
 
-// LoadFromFile reads a policy file.
+// LoadFromFile reads a policy file. Unknown keys are rejected so a typo
+// such as max_critcal fails loudly instead of silently disabling the rule;
+// run Lint for a full report with line numbers.
 func LoadFromFile(path string) (*Policy, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -53,7 +65,12 @@ func LoadFromFile(path string) (*Policy, error) {
 	}
 
 	var p Policy
-	if err := yaml.Unmarshal(data, &p); err != nil {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse policy: %w", err)
+	}
+	if err := p.Compile(); err != nil {
 		return nil, fmt.Errorf("parse policy: %w", err)
 	}
 
@@ -87,82 +104,39 @@ func FindPolicyFile() string {
 	return ""
 }
 
-// Evaluate checks an aggregated report against the policy rules.
-func (p *Policy) Evaluate(report *models.AggregatedReport) *Result {
-	if p == nil {
-		return &Result{Pass: true}
-	}
-
-	var violations []Violation
-
-	// max_issues
-	if p.Rules.MaxIssues != nil {
-		if report.Summary.TotalIssues > *p.Rules.MaxIssues {
-			violations = append(violations, Violation{
-				Rule:    "max_issues",
-				Message: fmt.Sprintf("total issues %d exceeds limit %d", report.Summary.TotalIssues, *p.Rules.MaxIssues),
-			})
-		}
-	}
-
-	// max_critical
-	if p.Rules.MaxCritical != nil {
-		count := report.Summary.IssuesBySeverity[models.SeverityCritical]
-		if count > *p.Rules.MaxCritical {
-			violations = append(violations, Violation{
-				Rule:    "max_critical",
-				Message: fmt.Sprintf("critical issues %d exceeds limit %d", count, *p.Rules.MaxCritical),
-			})
-		}
-	}
-
-	// max_high
-	if p.Rules.MaxHigh != nil {
-		count := report.Summary.IssuesBySeverity[models.SeverityHigh]
-		if count > *p.Rules.MaxHigh {
-			violations = append(violations, Violation{
-				Rule:    "max_high",
-				Message: fmt.Sprintf("high issues %d exceeds limit %d", count, *p.Rules.MaxHigh),
-			})
-		}
-	}
+// reportAge parses MaxReportAge; Compile rejects invalid values.
+func (r Rules) reportAge() time.Duration {
+	d, _ := time.ParseDuration(r.MaxReportAge)
+	return d
+}
 
-	// min_score
-	if p.Rules.MinScore != nil {
-		if report.Summary.ScorePercent < *p.Rules.MinScore {
-			violations = append(violations, Violation{
-				Rule:    "min_score",
-				Message: fmt.Sprintf("score %.1f%% below minimum %.1f%%", report.Summary.ScorePercent, *p.Rules.MinScore),
-			})
+// staleReports describes each tool report older than maxAge when the run
+// was aggregated. A report without a timestamp cannot prove its freshness
+// and is reported too.
+func staleReports(report *models.AggregatedReport, maxAge time.Duration) []string {
+	var msgs []string
+	for _, f := range report.Freshness {
+		switch {
+		case f.Timestamp.IsZero():
+			msgs = append(msgs, fmt.Sprintf("%s report has no timestamp", f.Tool))
+		case f.Age() > maxAge:
+			msgs = append(msgs, fmt.Sprintf("%s report is %s old, exceeds %s",
+				f.Tool, f.Age().Round(time.Minute), maxAge))
 		}
 	}
+	return msgs
+}
 
-	// forbid_categories
-	if len(p.Rules.ForbidCategories) > 0 {
-		forbidden := make(map[string]bool, len(p.Rules.ForbidCategories))
-		for _, c := range p.Rules.ForbidCategories {
-			forbidden[c] = true
-		}
-		for cat, count := range report.Summary.IssuesByCategory {
-			if forbidden[cat] && count > 0 {
-				violations = append(violations, Violation{
-					Rule:    "forbid_categories",
-					Message: fmt.Sprintf("forbidden category %q has %d issues", cat, count),
-				})
-			}
-		}
+// Evaluate checks an aggregated report against the policy rules and
+// returns the violations of the rules Explain reports as failing.
+func (p *Policy) Evaluate(report *models.AggregatedReport) *Result {
+	if p == nil {
+		return &Result{Pass: true}
 	}
 
-	// require_tools
-	if len(p.Rules.RequireTools) > 0 {
-		for _, tool := range p.Rules.RequireTools {
-			if _, found := report.ToolReports[tool]; !found {
-				violations = append(violations, Violation{
-					Rule:    "require_tools",
-					Message: fmt.Sprintf("required tool %q not found in report", tool),
-				})
-			}
-		}
+	var violations []Violation
+	for _, res := range p.Explain(report) {
+		violations = append(violations, res.violations...)
 	}
 
 	return &Result{
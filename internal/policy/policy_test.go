@@ -3,7 +3,9 @@ package policy
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ppiankov/spectrehub/internal/apiclient"
 	"github.com/ppiankov/spectrehub/internal/models"
@@ -138,6 +140,31 @@ func TestRequireToolsFail(t *testing.T) {
 	}
 }
 
+func TestMinCoverage(t *testing.T) {
+	p := &Policy{Rules: Rules{MinCoverage: floatPtr(100)}}
+
+	report := baseReport()
+	if result := p.Evaluate(report); !result.Pass {
+		t.Errorf("unknown coverage should pass, got %v", result.Violations)
+	}
+
+	report.Coverage = &models.Coverage{
+		Expected:  []string{"kafkaspectre", "vaultspectre"},
+		Delivered: []string{"vaultspectre"},
+		Missing:   []string{"kafkaspectre"},
+	}
+	result := p.Evaluate(report)
+	if result.Pass || result.Violations[0].Rule != "min_coverage" ||
+		!strings.Contains(result.Violations[0].Message, "missing kafkaspectre") {
+		t.Errorf("expected min_coverage violation, got %v", result.Violations)
+	}
+
+	explained := findRule(p.Explain(report), "min_coverage")
+	if explained == nil || explained.Pass {
+		t.Errorf("expected failing min_coverage in Explain, got %+v", explained)
+	}
+}
+
 func TestMultipleViolations(t *testing.T) {
 	p := &Policy{
 		Rules: Rules{
@@ -217,6 +244,35 @@ func TestLoadFromFileInvalidYAML(t *testing.T) {
 	}
 }
 
+func TestLoadFromFileUnknownKey(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "typo.yaml")
+	if err := os.WriteFile(path, []byte("version: \"1\"\nrules:\n  max_critcal: 0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadFromFile(path)
+	if err == nil {
+		t.Error("expected error for unknown key max_critcal")
+	}
+}
+
+func TestLoadFromFileEmpty(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "empty.yaml")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if p == nil {
+		t.Error("expected empty policy, got nil")
+	}
+}
+
 func TestLoadFromFileUnreadable(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "noperm.yaml")
@@ -619,3 +675,41 @@ rules:
 		t.Errorf("expected max_never_seen_users=0, got %v", p.Rules.MaxNeverSeenUsers)
 	}
 }
+
+func TestMaxReportAge(t *testing.T) {
+	p := &Policy{Rules: Rules{MaxReportAge: "24h"}}
+	report := baseReport()
+	report.Freshness = []models.ToolFreshness{
+		{Tool: "kubespectre", Timestamp: report.Timestamp.Add(-time.Hour), AgeSeconds: 3600},
+		{Tool: "s3spectre", Timestamp: report.Timestamp.Add(-90 * 24 * time.Hour), AgeSeconds: 90 * 24 * 3600},
+		{Tool: "vaultspectre"},
+	}
+
+	result := p.Evaluate(report)
+	if len(result.Violations) != 2 {
+		t.Fatalf("expected 2 violations, got %v", result.Violations)
+	}
+	if !strings.Contains(result.Violations[0].Message, "s3spectre report is 2160h0m0s old") ||
+		!strings.Contains(result.Violations[1].Message, "vaultspectre report has no timestamp") {
+		t.Errorf("unexpected violations: %v", result.Violations)
+	}
+	if r := findRule(p.Explain(report), "max_report_age"); r == nil || r.Pass {
+		t.Errorf("expected failing max_report_age in Explain, got %+v", r)
+	}
+
+	report.Freshness = report.Freshness[:1]
+	if result := p.Evaluate(report); !result.Pass {
+		t.Errorf("expected fresh reports to pass, got %v", result.Violations)
+	}
+}
+
+func TestMaxReportAgeInvalid(t *testing.T) {
+	p := &Policy{Rules: Rules{MaxReportAge: "3 days"}}
+	if err := p.Compile(); err == nil || !strings.Contains(err.Error(), "max_report_age") {
+		t.Errorf("expected invalid duration error, got %v", err)
+	}
+	issues := Lint([]byte("version: \"1\"\nrules:\n  max_report_age: 3 days\n"))
+	if len(issues) != 1 || issues[0].Line != 3 {
+		t.Errorf("expected one lint issue on line 3, got %v", issues)
+	}
+}
@@ -0,0 +1,416 @@
+// Package redact removes sensitive data from reports before they leave the
+// machine: API uploads, exports and notifications.
+//
+// A redaction profile can drop the raw tool output, replace resource
+// identifiers (bucket names, Vault paths, usernames) with a keyed HMAC so
+// they stay stable across runs, strip evidence text and keep only a
+// whitelist of finding fields. Stored runs are never redacted.
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ppiankov/spectrehub/internal/apiclient"
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/policy"
+)
+
+// KeyEnv is the environment variable holding the HMAC key when the config
+// does not set one.
+const KeyEnv = "SPECTREHUB_REDACTION_KEY"
+
+// Resource identifier modes.
+const (
+	ResourcesKeep = ""
+	ResourcesHash = "hash" // replace with an HMAC token
+	ResourcesMask = "mask" // keep the scheme and first letters, append an HMAC token
+)
+
+// Fields lists the finding fields a whitelist can keep. Tool, category,
+// severity and resource are always kept: scoring, diffs and trends need them.
+var Fields = []string{
+	"id", "evidence", "count", "first_seen", "last_seen", "estimated_monthly_waste",
+	"attributes", "remediation", "fingerprint", "references", "confidence", "triage", "owner",
+}
+
+// Config is the redaction section of .spectrehub.yaml.
+type Config struct {
+	// DropRawData removes the raw tool output from uploaded reports.
+	DropRawData bool `mapstructure:"drop_raw_data"`
+	// Resources is hash or mask; empty keeps identifiers as they are.
+	Resources string `mapstructure:"resources"`
+	// Key is the HMAC key; KeyEnv is used when empty.
+	Key string `mapstructure:"key"`
+	// StripEvidence removes evidence text and tool stderr.
+	StripEvidence bool `mapstructure:"strip_evidence"`
+	// Fields whitelists the finding fields to keep; empty keeps all.
+	Fields []string `mapstructure:"fields"`
+}
+
+// Enabled reports whether the profile changes anything.
+func (c Config) Enabled() bool {
+	return c.DropRawData || c.Resources != ResourcesKeep || c.StripEvidence || len(c.Fields) > 0
+}
+
+// Validate checks the resources mode, the key and the field whitelist.
+func (c Config) Validate() error {
+	switch c.Resources {
+	case ResourcesKeep:
+	case ResourcesHash, ResourcesMask:
+		if c.key() == "" {
+			return fmt.Errorf("redaction: resources %q needs a key (redaction.key or %s)", c.Resources, KeyEnv)
+		}
+	default:
+		return fmt.Errorf("redaction: invalid resources mode %q (must be hash or mask)", c.Resources)
+	}
+
+	for _, f := range c.Fields {
+		if !knownField(f) {
+			return fmt.Errorf("redaction: unknown field %q (must be one of %s)", f, strings.Join(Fields, ", "))
+		}
+	}
+	return nil
+}
+
+func (c Config) key() string {
+	if c.Key != "" {
+		return c.Key
+	}
+	return os.Getenv(KeyEnv)
+}
+
+func knownField(name string) bool {
+	for _, f := range Fields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Redactor applies a redaction profile. A nil Redactor leaves data as is.
+type Redactor struct {
+	cfg  Config
+	key  []byte
+	keep map[string]bool // whitelisted fields, nil keeps all
+}
+
+// New returns the Redactor for cfg, or nil when the profile is disabled.
+func New(cfg Config) (*Redactor, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	r := &Redactor{cfg: cfg, key: []byte(cfg.key())}
+	if len(cfg.Fields) > 0 {
+		r.keep = make(map[string]bool, len(cfg.Fields))
+		for _, f := range cfg.Fields {
+			r.keep[f] = true
+		}
+	}
+	return r, nil
+}
+
+// Resource redacts a resource identifier. Empty identifiers stay empty.
+func (r *Redactor) Resource(id string) string {
+	if r == nil || id == "" {
+		return id
+	}
+	switch r.cfg.Resources {
+	case ResourcesHash:
+		return "hmac-" + r.sum(id)[:16]
+	case ResourcesMask:
+		return mask(id) + "#" + r.sum(id)[:8]
+	}
+	return id
+}
+
+// sum is the hex HMAC-SHA256 of s.
+func (r *Redactor) sum(s string) string {
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(s))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// mask keeps the scheme and separators and the first letter of each path
+// segment: s3://billing-exports/2026 becomes s3://b***/2***.
+func mask(id string) string {
+	scheme, rest := "", id
+	if i := strings.Index(id, "://"); i >= 0 {
+		scheme, rest = id[:i+3], id[i+3:]
+	}
+	segments := strings.Split(rest, "/")
+	for i, seg := range segments {
+		if seg == "" {
+			continue
+		}
+		first, _ := utf8.DecodeRuneInString(seg)
+		segments[i] = string(first) + "***"
+	}
+	return scheme + strings.Join(segments, "/")
+}
+
+// Report returns a redacted copy of report; report itself is not modified.
+func (r *Redactor) Report(report *models.AggregatedReport) *models.AggregatedReport {
+	if r == nil || report == nil {
+		return report
+	}
+
+	out := *report
+	out.Issues = r.issues(report.Issues)
+	out.Suppressed = r.issues(report.Suppressed)
+
+	if report.ToolReports != nil {
+		out.ToolReports = make(map[string]models.ToolReport, len(report.ToolReports))
+		for name, tr := range report.ToolReports {
+			// Raw output cannot be redacted field by field, so redacting
+			// identifiers drops it as well.
+			if r.cfg.DropRawData || r.cfg.Resources != ResourcesKeep {
+				tr.RawData = nil
+			}
+			out.ToolReports[name] = tr
+		}
+	}
+
+	if report.Recommendations != nil {
+		out.Recommendations = make([]models.Recommendation, len(report.Recommendations))
+		for i, rec := range report.Recommendations {
+			if rec.TopResources != nil {
+				top := make([]string, len(rec.TopResources))
+				for j, res := range rec.TopResources {
+					top[j] = r.Resource(res)
+				}
+				rec.TopResources = top
+			}
+			out.Recommendations[i] = rec
+		}
+	}
+
+	if report.Incidents != nil {
+		out.Incidents = make([]models.Incident, len(report.Incidents))
+		for i, inc := range report.Incidents {
+			entity := r.Resource(inc.Entity)
+			inc.ID = inc.Rule + ":" + entity
+			if inc.Entity != "" {
+				inc.Title = strings.ReplaceAll(inc.Title, inc.Entity, entity)
+			}
+			inc.Entity = entity
+			inc.Issues = r.issues(inc.Issues)
+			out.Incidents[i] = inc
+		}
+	}
+
+	if r.cfg.StripEvidence && report.Executions != nil {
+		out.Executions = make([]models.ToolExecution, len(report.Executions))
+		for i, e := range report.Executions {
+			e.Stderr = nil
+			out.Executions[i] = e
+		}
+	}
+
+	return &out
+}
+
+// Violations returns copies of violations whose messages have the
+// identifiers of report's issues redacted. Custom rule messages can render
+// any finding field, such as the resource, so they are redacted like the
+// incident titles in Report.
+func (r *Redactor) Violations(violations []policy.Violation, report *models.AggregatedReport) []policy.Violation {
+	if r == nil || r.cfg.Resources == ResourcesKeep || report == nil || violations == nil {
+		return violations
+	}
+
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, issues := range [][]models.NormalizedIssue{report.Issues, report.Suppressed} {
+		for _, issue := range issues {
+			add(issue.Resource)
+			add(issue.Owner)
+			if a := issue.Attributes; a != nil {
+				add(a.Account)
+				add(a.Namespace)
+				for _, v := range a.Tags {
+					add(v)
+				}
+			}
+		}
+	}
+	// Longest first, so an identifier that is part of another one does not
+	// break the longer one's replacement.
+	sort.Slice(ids, func(i, j int) bool {
+		if len(ids[i]) != len(ids[j]) {
+			return len(ids[i]) > len(ids[j])
+		}
+		return ids[i] < ids[j]
+	})
+	pairs := make([]string, 0, 2*len(ids))
+	for _, id := range ids {
+		pairs = append(pairs, id, r.Resource(id))
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	out := make([]policy.Violation, len(violations))
+	for i, v := range violations {
+		v.Message = replacer.Replace(v.Message)
+		out[i] = v
+	}
+	return out
+}
+
+// Issues returns redacted copies of issues.
+func (r *Redactor) Issues(issues []models.NormalizedIssue) []models.NormalizedIssue {
+	if r == nil {
+		return issues
+	}
+	return r.issues(issues)
+}
+
+func (r *Redactor) issues(issues []models.NormalizedIssue) []models.NormalizedIssue {
+	if issues == nil {
+		return nil
+	}
+	out := make([]models.NormalizedIssue, len(issues))
+	for i, issue := range issues {
+		out[i] = r.issue(issue)
+	}
+	return out
+}
+
+func (r *Redactor) issue(issue models.NormalizedIssue) models.NormalizedIssue {
+	issue.Resource = r.Resource(issue.Resource)
+	issue.Owner = r.Resource(issue.Owner)
+	if r.cfg.Resources != ResourcesKeep {
+		if issue.Fingerprint != "" {
+			issue.Fingerprint = r.sum(issue.Fingerprint)[:16]
+		}
+		if a := issue.Attributes; a != nil {
+			redacted := &models.ResourceAttributes{
+				Account:   r.Resource(a.Account),
+				Region:    a.Region,
+				Namespace: r.Resource(a.Namespace),
+			}
+			if a.Tags != nil {
+				redacted.Tags = make(map[string]string, len(a.Tags))
+				for k, v := range a.Tags {
+					redacted.Tags[k] = r.Resource(v)
+				}
+			}
+			issue.Attributes = redacted
+		}
+	}
+	if r.cfg.StripEvidence {
+		issue.Evidence = ""
+	}
+
+	if r.keep == nil {
+		return issue
+	}
+	kept := models.NormalizedIssue{
+		Tool:     issue.Tool,
+		Category: issue.Category,
+		Severity: issue.Severity,
+		Resource: issue.Resource,
+	}
+	if r.keep["id"] {
+		kept.ID = issue.ID
+	}
+	if r.keep["evidence"] {
+		kept.Evidence = issue.Evidence
+	}
+	if r.keep["count"] {
+		kept.Count = issue.Count
+	}
+	if r.keep["first_seen"] {
+		kept.FirstSeen = issue.FirstSeen
+	}
+	if r.keep["last_seen"] {
+		kept.LastSeen = issue.LastSeen
+	}
+	if r.keep["estimated_monthly_waste"] {
+		kept.EstimatedMonthlyWaste = issue.EstimatedMonthlyWaste
+	}
+	if r.keep["attributes"] {
+		kept.Attributes = issue.Attributes
+	}
+	if r.keep["remediation"] {
+		kept.Remediation = issue.Remediation
+	}
+	if r.keep["fingerprint"] {
+		kept.Fingerprint = issue.Fingerprint
+	}
+	if r.keep["references"] {
+		kept.References = issue.References
+	}
+	if r.keep["confidence"] {
+		kept.Confidence = issue.Confidence
+	}
+	if r.keep["triage"] {
+		kept.Triage = issue.Triage
+	}
+	if r.keep["owner"] {
+		kept.Owner = issue.Owner
+	}
+	return kept
+}
+
+// Findings redacts a finding lifecycle upload. The finding hash is derived
+// from the plain resource, so it is replaced with an HMAC as well.
+func (r *Redactor) Findings(payload apiclient.FindingsPayload) apiclient.FindingsPayload {
+	if r == nil || r.cfg.Resources == ResourcesKeep {
+		return payload
+	}
+	findings := make([]apiclient.FindingEntry, len(payload.Findings))
+	for i, f := range payload.Findings {
+		f.FindingHash = r.sum(f.FindingHash)
+		f.ResourceID = r.Resource(f.ResourceID)
+		f.Identity = r.Resource(f.Identity)
+		findings[i] = f
+	}
+	payload.Findings = findings
+	return payload
+}
+
+// Waste redacts a waste tracking upload.
+func (r *Redactor) Waste(payload apiclient.WastePayload) apiclient.WastePayload {
+	if r == nil || r.cfg.Resources == ResourcesKeep {
+		return payload
+	}
+	entries := make([]apiclient.WasteEntry, len(payload.Entries))
+	for i, e := range payload.Entries {
+		e.ResourceID = r.Resource(e.ResourceID)
+		entries[i] = e
+	}
+	payload.Entries = entries
+	return payload
+}
+
+// UserActivity redacts a user activity upload.
+func (r *Redactor) UserActivity(payload apiclient.UserActivityPayload) apiclient.UserActivityPayload {
+	if r == nil || r.cfg.Resources == ResourcesKeep {
+		return payload
+	}
+	users := make([]apiclient.UserActivityEntry, len(payload.Users))
+	for i, u := range payload.Users {
+		u.Username = r.Resource(u.Username)
+		u.DatabaseName = r.Resource(u.DatabaseName)
+		users[i] = u
+	}
+	payload.Users = users
+	return payload
+}
@@ -0,0 +1,230 @@
+package redact
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ppiankov/spectrehub/internal/apiclient"
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/policy"
+)
+
+func testReport() *models.AggregatedReport {
+	issue := models.NormalizedIssue{
+		Tool:        "s3spectre",
+		ID:          "UNUSED_BUCKET",
+		Category:    models.StatusUnused,
+		Severity:    models.SeverityMedium,
+		Resource:    "s3://billing-exports/",
+		Evidence:    "bucket billing-exports has no reads in 90 days",
+		Count:       1,
+		Fingerprint: "abc123",
+		Owner:       "alice",
+		Attributes:  &models.ResourceAttributes{Account: "123456789012", Region: "eu-west-1", Tags: map[string]string{"team": "billing"}},
+	}
+	return &models.AggregatedReport{
+		Issues: []models.NormalizedIssue{issue},
+		ToolReports: map[string]models.ToolReport{
+			"s3spectre": {Tool: "s3spectre", RawData: map[string]string{"bucket": "billing-exports"}},
+		},
+		Recommendations: []models.Recommendation{{Tool: "s3spectre", TopResources: []string{"s3://billing-exports/"}}},
+		Incidents: []models.Incident{{
+			ID:     "unused-bucket-policy:billing-exports",
+			Rule:   "unused-bucket-policy",
+			Title:  "Unused bucket billing-exports is still referenced by an unattached IAM policy",
+			Entity: "billing-exports",
+			Issues: []models.NormalizedIssue{issue},
+		}},
+		Executions: []models.ToolExecution{{Tool: "s3spectre", Success: true, Stderr: []string{"scanning billing-exports"}}},
+	}
+}
+
+func TestNewDisabled(t *testing.T) {
+	r, err := New(Config{})
+	if err != nil || r != nil {
+		t.Fatalf("New(empty) = %v, %v, want nil", r, err)
+	}
+	report := testReport()
+	if r.Report(report) != report || r.Resource("s3://a") != "s3://a" {
+		t.Error("nil Redactor changed data")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Setenv(KeyEnv, "")
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"hash without key", Config{Resources: ResourcesHash}, "needs a key"},
+		{"bad mode", Config{Resources: "encrypt", Key: "k"}, "invalid resources mode"},
+		{"unknown field", Config{Fields: []string{"resource_name"}}, "unknown field"},
+		{"valid", Config{Resources: ResourcesMask, Key: "k", Fields: []string{"id", "count"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	t.Setenv(KeyEnv, "from-env")
+	if err := (Config{Resources: ResourcesHash}).Validate(); err != nil {
+		t.Errorf("Validate() with %s = %v", KeyEnv, err)
+	}
+}
+
+func TestResourceStableAndKeyed(t *testing.T) {
+	a, _ := New(Config{Resources: ResourcesHash, Key: "one"})
+	b, _ := New(Config{Resources: ResourcesHash, Key: "two"})
+
+	got := a.Resource("s3://billing-exports/")
+	if !strings.HasPrefix(got, "hmac-") || len(got) != len("hmac-")+16 {
+		t.Errorf("hash = %q", got)
+	}
+	if a.Resource("s3://billing-exports/") != got {
+		t.Error("hash is not stable")
+	}
+	if b.Resource("s3://billing-exports/") == got {
+		t.Error("hash does not depend on the key")
+	}
+	if a.Resource("") != "" {
+		t.Error("empty resource was hashed")
+	}
+
+	m, _ := New(Config{Resources: ResourcesMask, Key: "one"})
+	masked := m.Resource("s3://billing-exports/2026/q1.csv")
+	if !strings.HasPrefix(masked, "s3://b***/2***/q***#") {
+		t.Errorf("mask = %q", masked)
+	}
+	if m.Resource("s3://billing-archive/2026/q1.csv") == masked {
+		t.Error("masks of different resources collide")
+	}
+}
+
+func TestReport(t *testing.T) {
+	r, err := New(Config{Resources: ResourcesHash, Key: "k", StripEvidence: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := testReport()
+	out := r.Report(report)
+
+	data, _ := json.Marshal(out)
+	for _, secret := range []string{"billing-exports", "alice", "123456789012", "abc123"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("redacted report still contains %q:\n%s", secret, data)
+		}
+	}
+
+	issue := out.Issues[0]
+	if issue.Resource != r.Resource("s3://billing-exports/") || issue.Evidence != "" {
+		t.Errorf("issue = %+v", issue)
+	}
+	if issue.Attributes.Region != "eu-west-1" || issue.Attributes.Tags["team"] != r.Resource("billing") {
+		t.Errorf("attributes = %+v", issue.Attributes)
+	}
+	if out.ToolReports["s3spectre"].RawData != nil {
+		t.Error("raw data kept while identifiers are redacted")
+	}
+	inc := out.Incidents[0]
+	entity := r.Resource("billing-exports")
+	if inc.Entity != entity || inc.ID != "unused-bucket-policy:"+entity || !strings.Contains(inc.Title, entity) {
+		t.Errorf("incident = %+v", inc)
+	}
+	if out.Executions[0].Stderr != nil {
+		t.Error("stderr kept with strip_evidence")
+	}
+
+	// The input is untouched.
+	if report.Issues[0].Resource != "s3://billing-exports/" || report.ToolReports["s3spectre"].RawData == nil {
+		t.Error("Report() modified its input")
+	}
+}
+
+func TestViolations(t *testing.T) {
+	r, err := New(Config{Resources: ResourcesMask, Key: "k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := testReport()
+	report.Issues = append(report.Issues, models.NormalizedIssue{Resource: "s3://billing-exports/2026/"})
+	violations := []policy.Violation{
+		{Rule: "custom:unused", Message: "s3://billing-exports/2026/ and s3://billing-exports/ owned by alice"},
+		{Rule: "max_high", Message: "high issues 3 exceeds limit 0"},
+	}
+
+	out := r.Violations(violations, report)
+	want := r.Resource("s3://billing-exports/2026/") + " and " + r.Resource("s3://billing-exports/") + " owned by " + r.Resource("alice")
+	if out[0].Message != want {
+		t.Errorf("message = %q, want %q", out[0].Message, want)
+	}
+	if out[1] != violations[1] {
+		t.Errorf("violation without identifiers changed: %+v", out[1])
+	}
+	if violations[0].Message == out[0].Message {
+		t.Error("Violations() modified its input")
+	}
+
+	var keep *Redactor
+	if got := keep.Violations(violations, report); got[0].Message != violations[0].Message {
+		t.Errorf("nil redactor changed %q", got[0].Message)
+	}
+}
+
+func TestReportDropRawDataOnly(t *testing.T) {
+	r, _ := New(Config{DropRawData: true})
+	out := r.Report(testReport())
+	if out.ToolReports["s3spectre"].RawData != nil {
+		t.Error("raw data kept")
+	}
+	if out.Issues[0].Resource != "s3://billing-exports/" || out.Issues[0].Evidence == "" {
+		t.Errorf("issue changed without resources or strip_evidence: %+v", out.Issues[0])
+	}
+}
+
+func TestReportFieldWhitelist(t *testing.T) {
+	r, _ := New(Config{Fields: []string{"id", "count"}})
+	issue := r.Report(testReport()).Issues[0]
+	want := models.NormalizedIssue{
+		Tool: "s3spectre", ID: "UNUSED_BUCKET", Category: models.StatusUnused,
+		Severity: models.SeverityMedium, Resource: "s3://billing-exports/", Count: 1,
+	}
+	got, _ := json.Marshal(issue)
+	wantJSON, _ := json.Marshal(want)
+	if string(got) != string(wantJSON) {
+		t.Errorf("issue = %s, want %s", got, wantJSON)
+	}
+}
+
+func TestUploads(t *testing.T) {
+	r, _ := New(Config{Resources: ResourcesHash, Key: "k"})
+
+	findings := r.Findings(apiclient.FindingsPayload{Tool: "iamspectre", Findings: []apiclient.FindingEntry{
+		{FindingHash: "deadbeef", FindingID: "STALE_ACCESS_KEY", ResourceID: "user/alice", Identity: "user/alice", Severity: "high"},
+	}})
+	f := findings.Findings[0]
+	if f.ResourceID != r.Resource("user/alice") || f.Identity != f.ResourceID || f.FindingHash == "deadbeef" || f.FindingID != "STALE_ACCESS_KEY" {
+		t.Errorf("finding = %+v", f)
+	}
+
+	waste := r.Waste(apiclient.WastePayload{Tool: "awsspectre", Entries: []apiclient.WasteEntry{{ResourceID: "i-0abc", Region: "us-east-1"}}})
+	if e := waste.Entries[0]; e.ResourceID != r.Resource("i-0abc") || e.Region != "us-east-1" {
+		t.Errorf("waste = %+v", e)
+	}
+
+	users := r.UserActivity(apiclient.UserActivityPayload{Users: []apiclient.UserActivityEntry{{Username: "alice", DatabaseName: "billing"}}})
+	if u := users.Users[0]; u.Username != r.Resource("alice") || u.DatabaseName != r.Resource("billing") {
+		t.Errorf("user = %+v", u)
+	}
+}
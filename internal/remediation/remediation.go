@@ -0,0 +1,142 @@
+// Package remediation provides fix guidance for spectre/v1 findings.
+//
+// A built-in knowledge base is embedded in the binary; teams can override
+// or extend it with their own YAML file using the same layout. Entries are
+// keyed by finding ID, or by <tool>/<ID> for IDs several tools emit with
+// different meanings.
+package remediation
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed remediations.yaml
+var builtin []byte
+
+// KnowledgeBase maps finding IDs to remediation guidance. Keys are <ID> or
+// <tool>/<ID>.
+type KnowledgeBase struct {
+	entries map[string]models.Remediation
+}
+
+var (
+	defaultOnce sync.Once
+	defaultKB   *KnowledgeBase
+)
+
+// Default returns the built-in knowledge base. The embedded file is
+// validated by tests, so a parse failure here is a build defect.
+func Default() *KnowledgeBase {
+	defaultOnce.Do(func() {
+		entries, err := parse(builtin)
+		if err != nil {
+			panic(fmt.Sprintf("remediation: invalid built-in knowledge base: %v", err))
+		}
+		defaultKB = &KnowledgeBase{entries: entries}
+	})
+	return defaultKB
+}
+
+// Load returns the built-in knowledge base with the entries from path
+// layered on top. Fields set in the file replace the built-in ones; fields
+// left empty keep the built-in value. An empty path returns Default().
+func Load(path string) (*KnowledgeBase, error) {
+	if path == "" {
+		return Default(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read remediation file: %w", err)
+	}
+	overrides, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse remediation file %s: %w", path, err)
+	}
+
+	return Default().Merge(overrides), nil
+}
+
+// Merge returns a copy of kb with overrides applied field by field.
+func (kb *KnowledgeBase) Merge(overrides map[string]models.Remediation) *KnowledgeBase {
+	entries := make(map[string]models.Remediation, len(kb.entries)+len(overrides))
+	for id, e := range kb.entries {
+		entries[id] = e
+	}
+	for id, o := range overrides {
+		e := entries[id]
+		if o.Fix != "" {
+			e.Fix = o.Fix
+		}
+		if o.Snippet != "" {
+			e.Snippet = o.Snippet
+		}
+		if o.Effort != "" {
+			e.Effort = o.Effort
+		}
+		if o.DocURL != "" {
+			e.DocURL = o.DocURL
+		}
+		entries[id] = e
+	}
+	return &KnowledgeBase{entries: entries}
+}
+
+// Lookup returns the remediation for a finding ID reported by tool: the
+// <tool>/<ID> entry if there is one, else the <ID> entry.
+func (kb *KnowledgeBase) Lookup(tool, id string) (models.Remediation, bool) {
+	if kb == nil || id == "" {
+		return models.Remediation{}, false
+	}
+	if tool != "" {
+		if e, ok := kb.entries[tool+"/"+id]; ok {
+			return e, true
+		}
+	}
+	e, ok := kb.entries[id]
+	return e, ok
+}
+
+// IDs returns the keys with an entry, <ID> or <tool>/<ID>, sorted.
+func (kb *KnowledgeBase) IDs() []string {
+	ids := make([]string, 0, len(kb.entries))
+	for id := range kb.entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// parse decodes a knowledge base file, rejecting unknown fields and
+// invalid effort values.
+func parse(data []byte) (map[string]models.Remediation, error) {
+	entries := make(map[string]models.Remediation)
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&entries); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	for id, e := range entries {
+		if tool, findingID, ok := strings.Cut(id, "/"); ok && (tool == "" || findingID == "" || strings.Contains(findingID, "/")) {
+			return nil, fmt.Errorf("%s: key must be <ID> or <tool>/<ID>", id)
+		}
+		switch e.Effort {
+		case "", "low", "medium", "high":
+		default:
+			return nil, fmt.Errorf("%s: effort must be low, medium or high, got %q", id, e.Effort)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,136 @@
+package remediation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultEntriesComplete(t *testing.T) {
+	kb := Default()
+	if len(kb.IDs()) < 50 {
+		t.Fatalf("expected the built-in knowledge base to cover most finding IDs, got %d", len(kb.IDs()))
+	}
+	for _, id := range kb.IDs() {
+		tool, findingID, ok := strings.Cut(id, "/")
+		if !ok {
+			tool, findingID = "", id
+		}
+		e, _ := kb.Lookup(tool, findingID)
+		if e.Fix == "" {
+			t.Errorf("%s: missing fix", id)
+		}
+		if e.Effort == "" {
+			t.Errorf("%s: missing effort", id)
+		}
+		if !strings.HasPrefix(e.DocURL, "https://") {
+			t.Errorf("%s: doc link %q is not https", id, e.DocURL)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	e, ok := Default().Lookup("s3spectre", "STALE_ACCESS_KEY")
+	if !ok || !strings.Contains(e.Snippet, "aws iam update-access-key") {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if _, ok := Default().Lookup("s3spectre", ""); ok {
+		t.Error("empty ID should not match")
+	}
+	if _, ok := Default().Lookup("s3spectre", "NO_SUCH_FINDING"); ok {
+		t.Error("unknown ID should not match")
+	}
+	var nilKB *KnowledgeBase
+	if _, ok := nilKB.Lookup("s3spectre", "UNUSED_BUCKET"); ok {
+		t.Error("nil knowledge base should not match")
+	}
+}
+
+func TestLookupPrefersToolEntry(t *testing.T) {
+	kb := Default()
+
+	ch, ok := kb.Lookup("clickspectre", "UNUSED_TABLE")
+	if !ok || !strings.Contains(ch.Snippet, "ON CLUSTER") || !strings.Contains(ch.DocURL, "clickhouse.com") {
+		t.Errorf("clickspectre UNUSED_TABLE got %+v", ch)
+	}
+	pg, ok := kb.Lookup("pgspectre", "UNUSED_TABLE")
+	if !ok || strings.Contains(pg.Snippet, "ON CLUSTER") || !strings.Contains(pg.DocURL, "postgresql.org") {
+		t.Errorf("pgspectre UNUSED_TABLE got %+v", pg)
+	}
+	if _, ok := kb.Lookup("kafkaspectre", "UNUSED_TABLE"); ok {
+		t.Error("a tool-qualified entry should not match other tools")
+	}
+
+	// Plain IDs apply to every tool.
+	if e, ok := kb.Lookup("gcsspectre", "UNUSED_BUCKET"); !ok || e.Fix == "" {
+		t.Errorf("ID-only entry should be the fallback, got %+v, %v", e, ok)
+	}
+}
+
+func TestLoadOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "remediations.yaml")
+	content := `UNUSED_BUCKET:
+  fix: Open a ticket with the data platform team before deleting.
+  doc: https://wiki.example.com/buckets
+CUSTOM_FINDING:
+  fix: Do the custom thing.
+  effort: high
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	kb, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	e, _ := kb.Lookup("s3spectre", "UNUSED_BUCKET")
+	if e.Fix != "Open a ticket with the data platform team before deleting." || e.DocURL != "https://wiki.example.com/buckets" {
+		t.Errorf("override not applied: %+v", e)
+	}
+	if e.Snippet == "" || e.Effort != "low" {
+		t.Errorf("unset fields should keep built-in values: %+v", e)
+	}
+	if e, ok := kb.Lookup("s3spectre", "CUSTOM_FINDING"); !ok || e.Effort != "high" {
+		t.Errorf("custom entry not added: %+v", e)
+	}
+
+	// The default knowledge base is not modified by overrides.
+	if e, _ := Default().Lookup("s3spectre", "UNUSED_BUCKET"); strings.Contains(e.Fix, "ticket") {
+		t.Error("Default() was mutated by Load")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected error for missing file")
+	}
+
+	tests := map[string]string{
+		"unknown-field.yaml": "UNUSED_BUCKET:\n  fx: typo\n",
+		"bad-effort.yaml":    "UNUSED_BUCKET:\n  effort: trivial\n",
+		"not-a-map.yaml":     "- UNUSED_BUCKET\n",
+		"empty-tool.yaml":    "/UNUSED_BUCKET:\n  fix: x\n",
+		"nested-key.yaml":    "s3spectre/a/UNUSED_BUCKET:\n  fix: x\n",
+	}
+	for name, content := range tests {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoadEmptyPath(t *testing.T) {
+	kb, err := Load("")
+	if err != nil || kb != Default() {
+		t.Errorf("Load(\"\") = %v, %v; want Default()", kb, err)
+	}
+}
@@ -0,0 +1,206 @@
+package reporter
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+// SARIF 2.1.0 output for GitHub Advanced Security integration.
+// Minimal structures — only what's needed for valid SARIF.
+
+// SARIFLog is the top-level SARIF document.
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun holds the results of one analysis run.
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+	// Properties describe how the results were selected, e.g. filters.
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// SARIFTool describes the producer of a run.
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the tool component that produced the results.
+type SARIFDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Rules   []SARIFRule `json:"rules"`
+}
+
+// SARIFRule is one rule referenced by results.
+type SARIFRule struct {
+	ID               string             `json:"id"`
+	ShortDescription SARIFMessage       `json:"shortDescription"`
+	DefaultConfig    SARIFDefaultConfig `json:"defaultConfiguration"`
+}
+
+// SARIFDefaultConfig holds a rule's default level.
+type SARIFDefaultConfig struct {
+	Level string `json:"level"`
+}
+
+// SARIFMessage is a plain-text message.
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult is one finding.
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+	// PartialFingerprints carries the tool's stable finding fingerprint
+	// so code scanning tracks the result across runs.
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	// Properties hold spectre/v1.1 metadata: remediation, references,
+	// confidence and resource attributes.
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// SARIFLocation points at the affected resource.
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysical `json:"physicalLocation"`
+}
+
+// SARIFPhysical is a physical location.
+type SARIFPhysical struct {
+	ArtifactLocation SARIFArtifact `json:"artifactLocation"`
+}
+
+// SARIFArtifact identifies an artifact by URI.
+type SARIFArtifact struct {
+	URI string `json:"uri"`
+}
+
+// BuildSARIF converts issues into a single-run SARIF log. properties, when
+// non-empty, are attached to the run.
+func BuildSARIF(issues []models.NormalizedIssue, properties map[string]string) *SARIFLog {
+	rulesMap := map[string]SARIFRule{}
+	results := make([]SARIFResult, 0, len(issues))
+
+	for _, issue := range issues {
+		ruleID := issue.Tool + "/" + issue.Category
+		if _, exists := rulesMap[ruleID]; !exists {
+			rulesMap[ruleID] = SARIFRule{
+				ID:               ruleID,
+				ShortDescription: SARIFMessage{Text: issue.Tool + " " + issue.Category},
+				DefaultConfig:    SARIFDefaultConfig{Level: SARIFLevel(issue.Severity)},
+			}
+		}
+
+		result := SARIFResult{
+			RuleID:  ruleID,
+			Level:   SARIFLevel(issue.Severity),
+			Message: SARIFMessage{Text: FormatEvidence(issue)},
+			Locations: []SARIFLocation{{
+				PhysicalLocation: SARIFPhysical{
+					ArtifactLocation: SARIFArtifact{URI: issue.Resource},
+				},
+			}},
+			Properties: sarifProperties(issue),
+		}
+		if issue.Fingerprint != "" {
+			result.PartialFingerprints = map[string]string{"spectreFingerprint/v1": issue.Fingerprint}
+		}
+		results = append(results, result)
+	}
+
+	var rules []SARIFRule
+	for _, r := range rulesMap {
+		rules = append(rules, r)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	run := SARIFRun{
+		Tool: SARIFTool{
+			Driver: SARIFDriver{
+				Name:    "spectrehub",
+				Version: "0.2.0",
+				Rules:   rules,
+			},
+		},
+		Results: results,
+	}
+	if len(properties) > 0 {
+		run.Properties = properties
+	}
+
+	return &SARIFLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []SARIFRun{run},
+	}
+}
+
+// sarifProperties returns an issue's spectre/v1.1 metadata, or nil.
+func sarifProperties(issue models.NormalizedIssue) map[string]interface{} {
+	props := make(map[string]interface{})
+	if issue.Remediation != "" {
+		props["remediation"] = issue.Remediation
+	}
+	if len(issue.References) > 0 {
+		props["references"] = issue.References
+	}
+	if issue.Confidence != nil {
+		props["confidence"] = *issue.Confidence
+	}
+	if a := issue.Attributes; a != nil {
+		if a.Account != "" {
+			props["account"] = a.Account
+		}
+		if a.Region != "" {
+			props["region"] = a.Region
+		}
+		if a.Namespace != "" {
+			props["namespace"] = a.Namespace
+		}
+		if len(a.Tags) > 0 {
+			props["tags"] = a.Tags
+		}
+	}
+	if len(props) == 0 {
+		return nil
+	}
+	return props
+}
+
+// WriteSARIF writes issues as an indented SARIF log.
+func WriteSARIF(w io.Writer, issues []models.NormalizedIssue, properties map[string]string) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(BuildSARIF(issues, properties))
+}
+
+// SARIFLevel maps a severity to a SARIF result level.
+func SARIFLevel(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "error"
+	case "medium":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// FormatEvidence renders an issue as a one-line message.
+func FormatEvidence(issue models.NormalizedIssue) string {
+	parts := []string{issue.Tool + ": " + issue.Category + " — " + issue.Resource}
+	if issue.Evidence != "" {
+		parts = append(parts, issue.Evidence)
+	}
+	return strings.Join(parts, ". ")
+}
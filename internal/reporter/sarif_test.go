@@ -0,0 +1,102 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+func TestWriteSARIFProperties(t *testing.T) {
+	issues := []models.NormalizedIssue{
+		{Tool: "vaultspectre", Category: "missing", Severity: "critical", Resource: "secret/db"},
+		{Tool: "vaultspectre", Category: "missing", Severity: "critical", Resource: "secret/api"},
+		{Tool: "s3spectre", Category: "unused", Severity: "low", Resource: "s3://old"},
+	}
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, issues, map[string]string{"filters": "critical"}); err != nil {
+		t.Fatalf("WriteSARIF: %v", err)
+	}
+
+	var log SARIFLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	run := log.Runs[0]
+	if len(run.Results) != 3 || len(run.Tool.Driver.Rules) != 2 {
+		t.Errorf("expected 3 results and 2 rules, got %d/%d", len(run.Results), len(run.Tool.Driver.Rules))
+	}
+	if run.Tool.Driver.Rules[0].ID != "s3spectre/unused" {
+		t.Errorf("rules not sorted: %+v", run.Tool.Driver.Rules)
+	}
+	if run.Properties["filters"] != "critical" {
+		t.Errorf("properties = %v", run.Properties)
+	}
+
+	buf.Reset()
+	if err := WriteSARIF(&buf, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "properties") {
+		t.Error("empty properties should be omitted")
+	}
+}
+
+func TestBuildSARIFSpectreV11Metadata(t *testing.T) {
+	confidence := 0.5
+	issues := []models.NormalizedIssue{
+		{Tool: "awsspectre", Category: "unused", Severity: "medium", Resource: "i-1",
+			Fingerprint: "fp-1", Remediation: "stop it", References: []string{"https://example.com"},
+			Confidence: &confidence, Attributes: &models.ResourceAttributes{Region: "us-east-1"}},
+		{Tool: "awsspectre", Category: "unused", Severity: "medium", Resource: "i-2"},
+	}
+	results := BuildSARIF(issues, nil).Runs[0].Results
+
+	first := results[0]
+	if first.PartialFingerprints["spectreFingerprint/v1"] != "fp-1" {
+		t.Errorf("PartialFingerprints = %v", first.PartialFingerprints)
+	}
+	if first.Properties["remediation"] != "stop it" || first.Properties["region"] != "us-east-1" || first.Properties["confidence"] != 0.5 {
+		t.Errorf("Properties = %v", first.Properties)
+	}
+	if results[1].PartialFingerprints != nil || results[1].Properties != nil {
+		t.Errorf("expected no metadata for a spectre/v1 issue, got %+v", results[1])
+	}
+}
+
+func TestSarifLevel(t *testing.T) {
+	tests := []struct {
+		severity string
+		want     string
+	}{
+		{"critical", "error"},
+		{"high", "error"},
+		{"medium", "warning"},
+		{"low", "note"},
+		{"", "note"},
+	}
+	for _, tt := range tests {
+		got := SARIFLevel(tt.severity)
+		if got != tt.want {
+			t.Errorf("SARIFLevel(%q) = %q, want %q", tt.severity, got, tt.want)
+		}
+	}
+}
+
+func TestFormatEvidence(t *testing.T) {
+	issue := models.NormalizedIssue{
+		Tool:     "vaultspectre",
+		Category: "missing",
+		Resource: "secret/db",
+		Evidence: "key not found",
+	}
+	got := FormatEvidence(issue)
+	if !strings.Contains(got, "vaultspectre") {
+		t.Errorf("expected tool in evidence, got %s", got)
+	}
+	if !strings.Contains(got, "key not found") {
+		t.Errorf("expected evidence text, got %s", got)
+	}
+}
@@ -35,6 +35,16 @@ func (r *TextReporter) Generate(report *models.AggregatedReport) error {
 	// Per-tool breakdown
 	r.printToolBreakdown(report)
 
+	// Tools that failed to produce a report
+	if failed := report.ToolErrors(); len(failed) > 0 {
+		r.printToolErrors(failed)
+	}
+
+	// Correlated incidents
+	if len(report.Incidents) > 0 {
+		r.printIncidents(report.Incidents)
+	}
+
 	// Recommendations
 	if len(report.Recommendations) > 0 {
 		r.printRecommendations(report.Recommendations)
@@ -65,12 +75,18 @@ func (r *TextReporter) printOverallSummary(report *models.AggregatedReport) {
 		report.Summary.SupportedTools,
 		report.Summary.UnsupportedTools)
 	r.printf("  Total Issues: %d\n", report.Summary.TotalIssues)
+	if n := len(report.Suppressed); n > 0 {
+		r.printf("  Suppressed: %d (triage)\n", n)
+	}
 	r.printf("  Health Score: %s", strings.ToUpper(report.Summary.HealthScore))
 
 	// Add percentage if available
 	if report.Summary.ScorePercent > 0 {
 		r.printf(" (%.1f%%)", report.Summary.ScorePercent)
 	}
+	if report.Summary.Partial {
+		r.printf(" [PARTIAL]")
+	}
 
 	// Add trend indicator if available
 	if report.Trend != nil {
@@ -78,7 +94,17 @@ func (r *TextReporter) printOverallSummary(report *models.AggregatedReport) {
 		r.printf(" %s %.1f%% from previous run", indicator, report.Trend.ChangePercent)
 	}
 
-	r.printf("\n\n")
+	r.printf("\n")
+
+	// Coverage, when the expected tools are known
+	if cov := report.Coverage; cov != nil {
+		r.printf("  Coverage: %d/%d tools (%.0f%%)", len(cov.Delivered), len(cov.Expected), cov.Percent())
+		if len(cov.Missing) > 0 {
+			r.printf(" — missing %s, their issues are not counted", strings.Join(cov.Missing, ", "))
+		}
+		r.printf("\n")
+	}
+	r.printf("\n")
 
 	// Issues by category
 	if len(report.Summary.IssuesByCategory) > 0 {
@@ -101,9 +127,17 @@ func (r *TextReporter) printOverallSummary(report *models.AggregatedReport) {
 
 // printToolBreakdown prints detailed breakdown for each tool
 func (r *TextReporter) printToolBreakdown(report *models.AggregatedReport) {
+	fresh := make(map[string]models.ToolFreshness, len(report.Freshness))
+	for _, f := range report.Freshness {
+		fresh[f.Tool] = f
+	}
+
 	for toolName, toolReport := range report.ToolReports {
 		r.printf("\n%s (v%s)\n", toolName, toolReport.Version)
 		r.printf("--------------------------------------------------\n")
+		if f, ok := fresh[toolName]; ok {
+			r.printReportTime(f)
+		}
 
 		issueCount := report.Summary.IssuesByTool[toolName]
 
@@ -129,6 +163,19 @@ func (r *TextReporter) printToolBreakdown(report *models.AggregatedReport) {
 	}
 }
 
+// printReportTime prints when a tool's input report was generated.
+func (r *TextReporter) printReportTime(f models.ToolFreshness) {
+	if f.Timestamp.IsZero() {
+		r.printf("  Report Time: unknown\n")
+		return
+	}
+	r.printf("  Report Time: %s (%s old)", formatTimestamp(f.Timestamp), formatAge(f.Age()))
+	if f.Stale {
+		r.printf(" [STALE]")
+	}
+	r.printf("\n")
+}
+
 // printVaultDetails prints VaultSpectre-specific details
 func (r *TextReporter) printVaultDetails(toolReport models.ToolReport, issueCount int) {
 	if vaultReport, ok := toolReport.RawData.(*models.VaultReport); ok {
@@ -223,26 +270,88 @@ func (r *TextReporter) printClickHouseDetails(toolReport models.ToolReport, issu
 	}
 }
 
-// printRecommendations prints the recommendations section
-func (r *TextReporter) printRecommendations(recommendations []models.Recommendation) {
+// printToolErrors prints tool runs that failed, with their failure class
+// and the tail of stderr.
+func (r *TextReporter) printToolErrors(failed []models.ToolExecution) {
 	r.printf("\n")
-	r.printf("Recommended Actions:\n")
+	r.printf("Tool Errors:\n")
 	r.printf("--------------------------------------------------\n")
 
-	// Group by severity
-	gen := aggregator.NewRecommendationGenerator()
-	grouped := gen.GroupBySeverity(recommendations)
+	for _, e := range failed {
+		class := e.FailureClass
+		if class == "" {
+			class = "error"
+		}
+		r.printf("  %s [%s]: %s", e.Tool, strings.ToUpper(class), e.Error)
+		if e.Attempts > 1 {
+			r.printf(" (%d attempts)", e.Attempts)
+		}
+		r.printf("\n")
+		for _, line := range e.Stderr {
+			r.printf("     | %s\n", line)
+		}
+	}
+}
+
+// printIncidents prints findings that were correlated across tools
+func (r *TextReporter) printIncidents(incidents []models.Incident) {
+	r.printf("\n")
+	r.printf("Correlated Incidents:\n")
+	r.printf("--------------------------------------------------\n")
 
-	// Print in severity order
-	for _, severity := range []string{models.SeverityCritical, models.SeverityHigh, models.SeverityMedium, models.SeverityLow} {
-		recs := grouped[severity]
-		if len(recs) == 0 {
-			continue
+	for i, inc := range incidents {
+		r.printf("  %d. [%s] %s\n", i+1, strings.ToUpper(inc.Severity), inc.Title)
+		for _, issue := range inc.Issues {
+			r.printf("     - %s: %s (%s)\n", issue.Tool, issue.Resource, issueLabel(issue))
 		}
+	}
+}
+
+// issueLabel returns the finding ID, or the category for legacy reports.
+func issueLabel(issue models.NormalizedIssue) string {
+	if issue.ID != "" {
+		return issue.ID
+	}
+	return issue.Category
+}
+
+// printRecommendations prints the recommendations section in ranked order
+func (r *TextReporter) printRecommendations(recommendations []models.Recommendation) {
+	r.printf("\n")
+	r.printf("Recommended Actions:\n")
+	r.printf("--------------------------------------------------\n")
 
-		for i, rec := range recs {
-			r.printf("  %d. [%s] %s\n", i+1, strings.ToUpper(rec.Severity), rec.Action)
-			r.printf("     Impact: %s\n", rec.Impact)
+	for i, rec := range recommendations {
+		r.printf("  %d. [%s] %s", i+1, strings.ToUpper(rec.Severity), rec.Action)
+		if rec.ID != "" {
+			r.printf(" (%s)", rec.ID)
+		}
+		r.printf("\n")
+		r.printf("     Impact: %s\n", rec.Impact)
+		if rec.MonthlyWaste > 0 {
+			r.printf("     Waste: $%.2f/month\n", rec.MonthlyWaste)
+		}
+		if len(rec.TopResources) > 0 {
+			r.printf("     Affected: %s", strings.Join(rec.TopResources, ", "))
+			if more := rec.Count - len(rec.TopResources); more > 0 {
+				r.printf(" (+%d more)", more)
+			}
+			r.printf("\n")
+		}
+		if fix := rec.Remediation; fix != nil {
+			r.printf("     Fix: %s", fix.Fix)
+			if fix.Effort != "" {
+				r.printf(" [effort: %s]", fix.Effort)
+			}
+			r.printf("\n")
+			if fix.Snippet != "" {
+				for _, line := range strings.Split(strings.TrimRight(fix.Snippet, "\n"), "\n") {
+					r.printf("       %s\n", line)
+				}
+			}
+			if fix.DocURL != "" {
+				r.printf("     Docs: %s\n", fix.DocURL)
+			}
 		}
 	}
 }
@@ -263,6 +372,9 @@ func (r *TextReporter) printTrendInfo(trend *models.Trend) {
 	if trend.ResolvedIssues > 0 {
 		r.printf("  Resolved: %d\n", trend.ResolvedIssues)
 	}
+	if len(trend.MissingTools) > 0 {
+		r.printf("  Not Compared: %s (missing from this run)\n", strings.Join(trend.MissingTools, ", "))
+	}
 
 	r.printf("  Compared With: %s\n", formatTimestamp(trend.ComparedWith))
 }
@@ -286,3 +398,15 @@ func toTitle(s string) string {
 func formatTimestamp(t time.Time) string {
 	return t.Format("2006-01-02 15:04:05")
 }
+
+// formatAge renders an age in the largest whole unit: 45m, 6h, 12d.
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
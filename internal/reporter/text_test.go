@@ -365,3 +365,180 @@ func TestTextReporterClickHouseDetails(t *testing.T) {
 		t.Error("expected anomalies count")
 	}
 }
+
+func TestTextReporterGenerateWithIncidents(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewTextReporter(&buf)
+
+	report := sampleReport()
+	report.Incidents = []models.Incident{{
+		ID:       "missing-secret-mount:api-key",
+		Rule:     "missing-secret-mount",
+		Title:    "Secret api-key is missing in Vault and mounted but unused in Kubernetes",
+		Severity: "high",
+		Issues: []models.NormalizedIssue{
+			{Tool: "vaultspectre", ID: "MISSING_SECRET", Resource: "secret/data/api-key"},
+			{Tool: "kubespectre", Category: "unused", Resource: "default/pod:web/secret:api-key"},
+		},
+	}}
+
+	if err := r.Generate(report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, frag := range []string{
+		"Correlated Incidents:",
+		"1. [HIGH] Secret api-key is missing in Vault",
+		"- vaultspectre: secret/data/api-key (MISSING_SECRET)",
+		"- kubespectre: default/pod:web/secret:api-key (unused)",
+	} {
+		if !strings.Contains(output, frag) {
+			t.Errorf("expected output to contain %q", frag)
+		}
+	}
+}
+
+func TestTextReporterGenerateWithToolErrors(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewTextReporter(&buf)
+
+	report := sampleReport()
+	report.Executions = []models.ToolExecution{
+		{Tool: "vaultspectre", Success: true},
+		{Tool: "s3spectre", Error: "exit status 1", FailureClass: "auth", Attempts: 1,
+			Stderr: []string{"error: AccessDenied: User is not authorized to perform s3:ListAllMyBuckets"}},
+		{Tool: "pgspectre", Error: "timed out after 5m0s", FailureClass: "timeout", Attempts: 3},
+	}
+
+	if err := r.Generate(report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, frag := range []string{
+		"Tool Errors:",
+		"s3spectre [AUTH]: exit status 1\n",
+		"| error: AccessDenied",
+		"pgspectre [TIMEOUT]: timed out after 5m0s (3 attempts)",
+	} {
+		if !strings.Contains(output, frag) {
+			t.Errorf("expected output to contain %q", frag)
+		}
+	}
+	if strings.Contains(output, "vaultspectre [") {
+		t.Error("successful tool listed as an error")
+	}
+}
+
+func TestTextReporterGenerateWithPartialCoverage(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewTextReporter(&buf)
+
+	report := sampleReport()
+	report.Coverage = &models.Coverage{
+		Expected:  []string{"kafkaspectre", "vaultspectre"},
+		Delivered: []string{"vaultspectre"},
+		Missing:   []string{"kafkaspectre"},
+	}
+	report.Summary.Partial = true
+	report.Trend = &models.Trend{Direction: "stable", MissingTools: []string{"kafkaspectre"}}
+
+	if err := r.Generate(report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, frag := range []string{
+		"[PARTIAL]",
+		"Coverage: 1/2 tools (50%) — missing kafkaspectre",
+		"Not Compared: kafkaspectre",
+	} {
+		if !strings.Contains(output, frag) {
+			t.Errorf("expected output to contain %q", frag)
+		}
+	}
+}
+
+func TestTextReporterReportTime(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewTextReporter(&buf)
+
+	report := sampleReport()
+	var tool string
+	for name := range report.ToolReports {
+		tool = name
+		break
+	}
+	report.Freshness = []models.ToolFreshness{{
+		Tool:       tool,
+		Timestamp:  time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		AgeSeconds: (90 * 24 * time.Hour).Seconds(),
+		Stale:      true,
+	}}
+
+	if err := r.Generate(report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "Report Time: 2026-01-01 12:00:00 (90d old) [STALE]"; !strings.Contains(buf.String(), want) {
+		t.Errorf("expected output to contain %q", want)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		30 * time.Minute: "30m",
+		5 * time.Hour:    "5h",
+		47 * time.Hour:   "47h",
+		72 * time.Hour:   "3d",
+	} {
+		if got := formatAge(d); got != want {
+			t.Errorf("formatAge(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestTextReporterGenerateWithRemediation(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewTextReporter(&buf)
+
+	report := sampleReport()
+	report.Recommendations = []models.Recommendation{
+		{
+			Severity:     "medium",
+			Tool:         "awsspectre",
+			ID:           "IDLE_NAT_GATEWAY",
+			Action:       "Clean up 7 unused resources",
+			Impact:       "Resources are wasted but no immediate risk",
+			Count:        7,
+			MonthlyWaste: 224.5,
+			TopResources: []string{"nat-a", "nat-b"},
+			Remediation: &models.Remediation{
+				Fix:     "Delete the NAT gateway.",
+				Snippet: "aws ec2 delete-nat-gateway --nat-gateway-id <nat-id>\n",
+				Effort:  "medium",
+				DocURL:  "https://docs.aws.amazon.com/vpc/latest/userguide/vpc-nat-gateway.html",
+			},
+		},
+		{Severity: "low", Tool: "kafkaspectre", Action: "Clean up 1 unused Kafka topics", Impact: "Minor cleanup"},
+	}
+
+	if err := r.Generate(report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, frag := range []string{
+		"1. [MEDIUM] Clean up 7 unused resources (IDLE_NAT_GATEWAY)",
+		"Waste: $224.50/month",
+		"Affected: nat-a, nat-b (+5 more)",
+		"Fix: Delete the NAT gateway. [effort: medium]",
+		"       aws ec2 delete-nat-gateway --nat-gateway-id <nat-id>\n",
+		"Docs: https://docs.aws.amazon.com/vpc/latest/userguide/vpc-nat-gateway.html",
+		"2. [LOW] Clean up 1 unused Kafka topics\n",
+	} {
+		if !strings.Contains(output, frag) {
+			t.Errorf("expected output to contain %q", frag)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os/exec"
+	"strings"
+)
+
+// FailureClass categorizes why a tool run failed.
+type FailureClass string
+
+const (
+	FailureNotFound FailureClass = "not_found" // binary missing or not executable
+	FailureTimeout  FailureClass = "timeout"   // killed after the per-tool timeout
+	FailureAuth     FailureClass = "auth"      // credentials missing, expired or denied
+	FailureNonJSON  FailureClass = "non_json"  // exited 0 but stdout is not JSON
+	FailureCrash    FailureClass = "crash"     // any other non-zero exit or signal
+)
+
+// Transient reports whether a retry may succeed. Missing binaries, bad
+// credentials and malformed output fail the same way every time.
+func (c FailureClass) Transient() bool {
+	return c == FailureTimeout || c == FailureCrash
+}
+
+// authMarkers are lower-cased stderr fragments that cloud SDKs, database
+// drivers and HTTP clients print on credential errors.
+var authMarkers = []string{
+	"access denied",
+	"accessdenied",
+	"unauthorized",
+	"unauthenticated",
+	"forbidden",
+	"permission denied",
+	"expiredtoken",
+	"invalidclienttokenid",
+	"signaturedoesnotmatch",
+	"no valid credential",
+	"credentials not found",
+	"could not find default credentials",
+	"authentication failed",
+	"invalid credentials",
+	"permission_denied",
+	"status code: 401",
+	"status code: 403",
+}
+
+// classify determines the failure class of an exec error. toolCtx is the
+// per-tool context, whose deadline marks a timeout.
+func classify(toolCtx context.Context, err error, stderr []string) FailureClass {
+	switch {
+	case errors.Is(err, exec.ErrNotFound), errors.Is(err, fs.ErrNotExist), errors.Is(err, fs.ErrPermission):
+		return FailureNotFound
+	case errors.Is(toolCtx.Err(), context.DeadlineExceeded), errors.Is(err, context.DeadlineExceeded):
+		return FailureTimeout
+	}
+	text := strings.ToLower(strings.Join(stderr, "\n") + "\n" + err.Error())
+	for _, m := range authMarkers {
+		if strings.Contains(text, m) {
+			return FailureAuth
+		}
+	}
+	return FailureCrash
+}
+
+// stderrOf returns the stderr captured with a failed command. exec.Cmd.Output
+// fills it into *exec.ExitError when Cmd.Stderr is unset.
+func stderrOf(err error) []byte {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Stderr
+	}
+	return nil
+}
+
+// tailLines returns the last n non-empty lines of b.
+func tailLines(b []byte, n int) []string {
+	var lines []string
+	for _, line := range strings.Split(string(bytes.TrimSpace(b)), "\n") {
+		if line = strings.TrimRight(line, "\r"); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
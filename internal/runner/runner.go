@@ -1,22 +1,38 @@
 package runner
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/ppiankov/spectrehub/internal/discovery"
 	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // DefaultTimeout is the per-tool execution timeout.
 const DefaultTimeout = 5 * time.Minute
 
+// DefaultRetryBackoff is the wait before the first retry; it doubles on
+// each further attempt.
+const DefaultRetryBackoff = 2 * time.Second
+
+// StderrLines is how many trailing stderr lines a failed run keeps.
+const StderrLines = 20
+
 // ExecFunc is the signature for running a command and capturing stdout.
 // It receives the context, binary path, and args. Returns stdout bytes and error.
+// Stderr of a failed command is read from *exec.ExitError, as populated by
+// exec.Cmd.Output.
 type ExecFunc func(ctx context.Context, name string, args ...string) ([]byte, error)
 
 // RunConfig describes a single tool invocation.
@@ -27,6 +43,10 @@ type RunConfig struct {
 	JSONFlag   string
 	ExtraArgs  []string
 	Timeout    time.Duration
+	// Retries is how many times a transient failure (timeout, crash) is
+	// retried, waiting RetryBackoff (doubling) in between.
+	Retries      int
+	RetryBackoff time.Duration
 }
 
 // RunResult is the outcome of a single tool invocation.
@@ -37,6 +57,10 @@ type RunResult struct {
 	Duration   time.Duration   `json:"duration"`
 	Success    bool            `json:"success"`
 	Error      string          `json:"error,omitempty"`
+
+	FailureClass FailureClass `json:"failure_class,omitempty"`
+	Stderr       []string     `json:"stderr,omitempty"` // last StderrLines lines
+	Attempts     int          `json:"attempts"`
 }
 
 // Runner executes spectre tools and captures their JSON output.
@@ -82,8 +106,76 @@ func (r *Runner) Run(ctx context.Context, configs []RunConfig) []RunResult {
 	return results
 }
 
-// runOne executes a single tool.
+// runOne executes a single tool, retrying transient failures, inside a
+// span tagged with the tool, duration and exit status.
 func (r *Runner) runOne(ctx context.Context, cfg RunConfig) RunResult {
+	ctx, span := tracing.Start(ctx, "runner.run_tool",
+		attribute.String("spectrehub.tool", string(cfg.Tool)),
+		attribute.String("spectrehub.binary", cfg.Binary),
+	)
+
+	backoff := cfg.RetryBackoff
+	if backoff == 0 {
+		backoff = DefaultRetryBackoff
+	}
+	start := time.Now()
+	var result RunResult
+	var execErr error
+	for attempt := 1; ; attempt++ {
+		result, execErr = r.execute(ctx, cfg)
+		result.Attempts = attempt
+		if result.Success || attempt > cfg.Retries || !result.FailureClass.Transient() {
+			break
+		}
+		span.AddEvent("retry", trace.WithAttributes(
+			attribute.Int("spectrehub.attempt", attempt),
+			attribute.String("spectrehub.failure_class", string(result.FailureClass)),
+		))
+		if !sleep(ctx, backoff) {
+			break
+		}
+		backoff *= 2
+	}
+	result.Duration = time.Since(start)
+
+	span.SetAttributes(
+		attribute.Float64("spectrehub.duration_seconds", result.Duration.Seconds()),
+		attribute.Bool("spectrehub.success", result.Success),
+		attribute.Int("spectrehub.attempts", result.Attempts),
+	)
+	if result.FailureClass != "" {
+		span.SetAttributes(attribute.String("spectrehub.failure_class", string(result.FailureClass)))
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case execErr == nil:
+		span.SetAttributes(attribute.Int("process.exit.code", 0))
+	case errors.As(execErr, &exitErr):
+		span.SetAttributes(attribute.Int("process.exit.code", exitErr.ExitCode()))
+	}
+	var err error
+	if !result.Success {
+		err = errors.New(result.Error)
+	}
+	tracing.End(span, err)
+	return result
+}
+
+// sleep waits for d, returning false if ctx is cancelled first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// execute runs the tool once and writes its output to the temp directory.
+// The error is the one returned by the exec function, if any.
+func (r *Runner) execute(ctx context.Context, cfg RunConfig) (RunResult, error) {
 	timeout := cfg.Timeout
 	if timeout == 0 {
 		timeout = DefaultTimeout
@@ -102,13 +194,32 @@ func (r *Runner) runOne(ctx context.Context, cfg RunConfig) RunResult {
 	duration := time.Since(start)
 
 	if err != nil {
-		return RunResult{
-			Tool:     cfg.Tool,
-			Binary:   cfg.Binary,
-			Duration: duration,
-			Success:  false,
-			Error:    err.Error(),
+		stderr := tailLines(stderrOf(err), StderrLines)
+		class := classify(toolCtx, err, stderr)
+		msg := err.Error()
+		if class == FailureTimeout {
+			msg = fmt.Sprintf("timed out after %s", timeout)
 		}
+		return RunResult{
+			Tool:         cfg.Tool,
+			Binary:       cfg.Binary,
+			Duration:     duration,
+			Success:      false,
+			Error:        msg,
+			FailureClass: class,
+			Stderr:       stderr,
+		}, err
+	}
+
+	if !json.Valid(bytes.TrimSpace(stdout)) {
+		return RunResult{
+			Tool:         cfg.Tool,
+			Binary:       cfg.Binary,
+			Duration:     duration,
+			Success:      false,
+			Error:        fmt.Sprintf("output is not valid JSON: %q", outputPreview(stdout)),
+			FailureClass: FailureNonJSON,
+		}, nil
 	}
 
 	// Write output to temp file
@@ -120,7 +231,7 @@ func (r *Runner) runOne(ctx context.Context, cfg RunConfig) RunResult {
 			Duration: duration,
 			Success:  false,
 			Error:    fmt.Sprintf("failed to write output: %v", err),
-		}
+		}, nil
 	}
 
 	return RunResult{
@@ -129,7 +240,20 @@ func (r *Runner) runOne(ctx context.Context, cfg RunConfig) RunResult {
 		OutputFile: outputFile,
 		Duration:   duration,
 		Success:    true,
+	}, nil
+}
+
+// outputPreview is the start of a tool's output for error messages.
+func outputPreview(stdout []byte) string {
+	const max = 80
+	s := strings.TrimSpace(string(stdout))
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
 	}
+	if len(s) > max {
+		s = s[:max] + "..."
+	}
+	return s
 }
 
 // OutputFiles returns paths of successful run outputs only.
@@ -143,6 +267,23 @@ func OutputFiles(results []RunResult) []string {
 	return paths
 }
 
+// Executions converts results into the execution records stored with a run.
+func Executions(results []RunResult) []models.ToolExecution {
+	execs := make([]models.ToolExecution, 0, len(results))
+	for _, r := range results {
+		execs = append(execs, models.ToolExecution{
+			Tool:            string(r.Tool),
+			DurationSeconds: r.Duration.Seconds(),
+			Success:         r.Success,
+			Error:           r.Error,
+			FailureClass:    string(r.FailureClass),
+			Attempts:        r.Attempts,
+			Stderr:          r.Stderr,
+		})
+	}
+	return execs
+}
+
 // Cleanup removes the temp directory and all output files.
 func (r *Runner) Cleanup() error {
 	if r.tempDir == "" {
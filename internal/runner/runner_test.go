@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"os"
+	"os/exec"
 	"testing"
 	"time"
 
 	"github.com/ppiankov/spectrehub/internal/discovery"
 	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/tracing/tracingtest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 // mockExec returns a function that produces canned output per binary.
@@ -222,6 +226,188 @@ func TestCleanup_NoTempDir(t *testing.T) {
 	}
 }
 
+func TestExecutions(t *testing.T) {
+	results := []RunResult{
+		{Tool: models.ToolVault, Duration: 1500 * time.Millisecond, Success: true, OutputFile: "/tmp/v.json"},
+		{Tool: models.ToolS3, Duration: 2 * time.Second, Error: "exit status 1",
+			FailureClass: FailureCrash, Attempts: 2, Stderr: []string{"panic"}},
+	}
+
+	execs := Executions(results)
+	if len(execs) != 2 {
+		t.Fatalf("expected 2 executions, got %d", len(execs))
+	}
+	if execs[0].Tool != "vaultspectre" || execs[0].DurationSeconds != 1.5 || !execs[0].Success {
+		t.Errorf("unexpected execution: %+v", execs[0])
+	}
+	if execs[1].Success || execs[1].Error != "exit status 1" || execs[1].FailureClass != "crash" ||
+		execs[1].Attempts != 2 || len(execs[1].Stderr) != 1 {
+		t.Errorf("unexpected execution: %+v", execs[1])
+	}
+}
+
+func TestRun_Spans(t *testing.T) {
+	rec := tracingtest.Record(t)
+
+	// A real exit error carries the exit status into the span.
+	exitErr := exec.Command("sh", "-c", "exit 3").Run()
+	execFn := mockExec(
+		map[string][]byte{"/bin/vaultspectre": []byte(`{}`)},
+		map[string]error{"/bin/s3spectre": exitErr},
+	)
+
+	r := New(execFn)
+	defer func() { _ = r.Cleanup() }()
+
+	r.Run(context.Background(), []RunConfig{
+		{Tool: models.ToolVault, Binary: "/bin/vaultspectre"},
+		{Tool: models.ToolS3, Binary: "/bin/s3spectre"},
+	})
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	want := map[string]struct {
+		exitCode int64
+		success  bool
+	}{"vaultspectre": {0, true}, "s3spectre": {3, false}}
+	for _, s := range spans {
+		attrs := map[attribute.Key]attribute.Value{}
+		for _, kv := range s.Attributes() {
+			attrs[kv.Key] = kv.Value
+		}
+		tool := attrs["spectrehub.tool"].AsString()
+		w, ok := want[tool]
+		if !ok || s.Name() != "runner.run_tool" {
+			t.Fatalf("unexpected span %s for %q", s.Name(), tool)
+		}
+		if attrs["process.exit.code"].AsInt64() != w.exitCode || attrs["spectrehub.success"].AsBool() != w.success {
+			t.Errorf("%s: unexpected attributes %v", tool, s.Attributes())
+		}
+		if _, ok := attrs["spectrehub.duration_seconds"]; !ok {
+			t.Errorf("%s: missing duration", tool)
+		}
+		if !w.success && s.Status().Code != codes.Error {
+			t.Errorf("%s: expected error status", tool)
+		}
+	}
+}
+
+func TestRun_FailureClasses(t *testing.T) {
+	_, authErr := exec.Command("sh", "-c", "echo starting; echo 'AccessDenied: not authorized' >&2; exit 1").Output()
+	_, crashErr := exec.Command("sh", "-c", "echo 'panic: nil map' >&2; exit 2").Output()
+	_, notFoundErr := exec.LookPath("/nonexistent/pgspectre")
+
+	execFn := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		switch name {
+		case "auth":
+			return nil, authErr
+		case "crash":
+			return nil, crashErr
+		case "missing":
+			return nil, notFoundErr
+		case "text":
+			return []byte("Scanning buckets...\nDone."), nil
+		case "slow":
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return []byte(`{}`), nil
+	}
+
+	r := New(execFn)
+	defer func() { _ = r.Cleanup() }()
+
+	tests := []struct {
+		binary string
+		want   FailureClass
+	}{
+		{"auth", FailureAuth},
+		{"crash", FailureCrash},
+		{"missing", FailureNotFound},
+		{"text", FailureNonJSON},
+		{"slow", FailureTimeout},
+		{"ok", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.binary, func(t *testing.T) {
+			res := r.Run(context.Background(), []RunConfig{
+				{Tool: models.ToolS3, Binary: tt.binary, Timeout: 20 * time.Millisecond},
+			})[0]
+			if res.FailureClass != tt.want || res.Success != (tt.want == "") {
+				t.Errorf("got class %q success=%v (%s), want %q", res.FailureClass, res.Success, res.Error, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_CapturesStderrTail(t *testing.T) {
+	script := "for i in $(seq 1 30); do echo line$i >&2; done; exit 1"
+	_, exitErr := exec.Command("sh", "-c", script).Output()
+	r := New(mockExec(nil, map[string]error{"s3spectre": exitErr}))
+	defer func() { _ = r.Cleanup() }()
+
+	res := r.Run(context.Background(), []RunConfig{{Tool: models.ToolS3, Binary: "s3spectre"}})[0]
+	if len(res.Stderr) != StderrLines || res.Stderr[0] != "line11" || res.Stderr[StderrLines-1] != "line30" {
+		t.Errorf("unexpected stderr tail: %v", res.Stderr)
+	}
+}
+
+func TestRun_RetriesTransientFailures(t *testing.T) {
+	var calls int
+	execFn := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		calls++
+		if calls < 3 {
+			return nil, errors.New("connection reset by peer")
+		}
+		return []byte(`{}`), nil
+	}
+	r := New(execFn)
+	defer func() { _ = r.Cleanup() }()
+
+	res := r.Run(context.Background(), []RunConfig{
+		{Tool: models.ToolS3, Binary: "s3spectre", Retries: 3, RetryBackoff: time.Millisecond},
+	})[0]
+	if !res.Success || res.Attempts != 3 || calls != 3 {
+		t.Errorf("expected success on attempt 3, got %+v after %d calls", res, calls)
+	}
+}
+
+func TestRun_DoesNotRetryPermanentFailures(t *testing.T) {
+	var calls int
+	execFn := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		calls++
+		return nil, errors.New("ExpiredToken: the security token included in the request is expired")
+	}
+	r := New(execFn)
+	defer func() { _ = r.Cleanup() }()
+
+	res := r.Run(context.Background(), []RunConfig{
+		{Tool: models.ToolS3, Binary: "s3spectre", Retries: 3, RetryBackoff: time.Millisecond},
+	})[0]
+	if res.FailureClass != FailureAuth || res.Attempts != 1 || calls != 1 {
+		t.Errorf("expected a single auth failure, got %+v after %d calls", res, calls)
+	}
+}
+
+func TestRun_RetryStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	execFn := func(context.Context, string, ...string) ([]byte, error) {
+		cancel()
+		return nil, errors.New("exit status 1")
+	}
+	r := New(execFn)
+	defer func() { _ = r.Cleanup() }()
+
+	res := r.Run(ctx, []RunConfig{
+		{Tool: models.ToolS3, Binary: "s3spectre", Retries: 5, RetryBackoff: time.Hour},
+	})[0]
+	if res.Success || res.Attempts != 1 {
+		t.Errorf("expected to give up after cancellation, got %+v", res)
+	}
+}
+
 func TestOutputFiles_Empty(t *testing.T) {
 	paths := OutputFiles(nil)
 	if len(paths) != 0 {
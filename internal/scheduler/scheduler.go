@@ -0,0 +1,280 @@
+// Package scheduler runs audit jobs on cron-style schedules for
+// spectrehub daemon.
+//
+// Each job fires on its own schedule plus a random jitter, never overlaps
+// itself (an activation while the previous run is still going is skipped),
+// and records its runs in a state file so that a restarted daemon runs a
+// job it missed while down. On shutdown no new runs start; running jobs get
+// a grace period to finish before they are cancelled.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// DefaultShutdownTimeout is how long running jobs may take to finish after
+// a shutdown request.
+const DefaultShutdownTimeout = 5 * time.Minute
+
+// Config is the daemon section of .spectrehub.yaml.
+type Config struct {
+	// Jitter delays each activation by a random duration up to this value.
+	Jitter time.Duration `mapstructure:"jitter"`
+	// ShutdownTimeout bounds how long running jobs may take to finish
+	// after SIGTERM; 0 uses DefaultShutdownTimeout.
+	ShutdownTimeout time.Duration    `mapstructure:"shutdown_timeout"`
+	Schedules       []ScheduleConfig `mapstructure:"schedules"`
+}
+
+// ScheduleConfig is one scheduled audit.
+type ScheduleConfig struct {
+	Name    string        `mapstructure:"name"`
+	Cron    string        `mapstructure:"cron"`    // cron expression or @descriptor
+	Tools   []string      `mapstructure:"tools"`   // empty runs every runnable tool
+	Timeout time.Duration `mapstructure:"timeout"` // per-tool timeout; 0 uses the runner default
+}
+
+// Validate checks names and cron expressions.
+func (c Config) Validate() error {
+	if c.Jitter < 0 || c.ShutdownTimeout < 0 {
+		return fmt.Errorf("daemon: jitter and shutdown_timeout cannot be negative")
+	}
+	names := make(map[string]bool)
+	for i, sc := range c.Schedules {
+		if sc.Name == "" {
+			return fmt.Errorf("daemon.schedules[%d]: name is required", i)
+		}
+		if names[sc.Name] {
+			return fmt.Errorf("daemon.schedules: duplicate name %q", sc.Name)
+		}
+		names[sc.Name] = true
+		if _, err := Parse(sc.Cron); err != nil {
+			return fmt.Errorf("daemon schedule %q: %w", sc.Name, err)
+		}
+		if sc.Timeout < 0 {
+			return fmt.Errorf("daemon schedule %q: timeout cannot be negative", sc.Name)
+		}
+	}
+	return nil
+}
+
+// Job is a named unit of work run on a schedule.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	Run      func(ctx context.Context) error
+}
+
+// Options tune a Scheduler.
+type Options struct {
+	Jitter          time.Duration
+	ShutdownTimeout time.Duration
+	// Logf receives progress messages; nil discards them.
+	Logf func(format string, args ...interface{})
+}
+
+// Scheduler runs jobs until its context is cancelled.
+type Scheduler struct {
+	jobs  []Job
+	state *State
+	opts  Options
+
+	now    func() time.Time
+	jitter func(max time.Duration) time.Duration
+
+	mu      sync.Mutex
+	running map[string]bool
+	wg      sync.WaitGroup
+}
+
+// New creates a scheduler. state provides and records last runs.
+func New(jobs []Job, state *State, opts Options) *Scheduler {
+	if opts.ShutdownTimeout == 0 {
+		opts.ShutdownTimeout = DefaultShutdownTimeout
+	}
+	if opts.Logf == nil {
+		opts.Logf = func(string, ...interface{}) {}
+	}
+	return &Scheduler{
+		jobs:  jobs,
+		state: state,
+		opts:  opts,
+		now:   time.Now,
+		jitter: func(max time.Duration) time.Duration {
+			if max <= 0 {
+				return 0
+			}
+			return time.Duration(rand.Int63n(int64(max)))
+		},
+		running: make(map[string]bool),
+	}
+}
+
+// Run dispatches jobs as they come due. When ctx is cancelled it stops
+// scheduling and waits for running jobs up to the shutdown timeout, then
+// cancels them; it returns an error only in that case.
+func (s *Scheduler) Run(ctx context.Context) error {
+	// Jobs outlive ctx so a shutdown lets them finish.
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	now := s.now()
+	due := make([]time.Time, len(s.jobs))
+	for i, job := range s.jobs {
+		due[i] = s.firstDue(job, now)
+		if due[i].IsZero() {
+			s.opts.Logf("%s: schedule never fires, ignoring", job.Name)
+			continue
+		}
+		s.opts.Logf("%s: next run at %s", job.Name, due[i].Format(time.RFC3339))
+	}
+
+	for {
+		var timer *time.Timer
+		var fire <-chan time.Time // nil, blocking forever, when nothing is due
+		if next := earliest(due); next >= 0 {
+			timer = time.NewTimer(due[next].Sub(s.now()))
+			fire = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return s.shutdown(cancelJobs)
+		case <-fire:
+		}
+
+		now = s.now()
+		for i, job := range s.jobs {
+			if due[i].IsZero() || due[i].After(now) {
+				continue
+			}
+			s.dispatch(jobCtx, job)
+			due[i] = s.nextDue(job, now)
+		}
+	}
+}
+
+// firstDue is the first activation after startup, counted from the last
+// recorded run. A job whose activation passed while the daemon was down
+// runs right away.
+func (s *Scheduler) firstDue(job Job, now time.Time) time.Time {
+	last := s.state.Job(job.Name).LastStart
+	if last.IsZero() {
+		return s.nextDue(job, now)
+	}
+	next := job.Schedule.Next(last)
+	if next.IsZero() || next.After(now) {
+		return s.nextDue(job, last)
+	}
+	return now
+}
+
+// nextDue is the next activation after now, plus jitter.
+func (s *Scheduler) nextDue(job Job, now time.Time) time.Time {
+	next := job.Schedule.Next(now)
+	if next.IsZero() {
+		return next
+	}
+	return next.Add(s.jitter(s.opts.Jitter))
+}
+
+// dispatch starts a job unless its previous run is still going.
+func (s *Scheduler) dispatch(ctx context.Context, job Job) {
+	s.mu.Lock()
+	if s.running[job.Name] {
+		s.mu.Unlock()
+		s.opts.Logf("%s: previous run still in progress, skipping", job.Name)
+		s.record(job.Name, func(js *JobState) { js.Skipped++ })
+		return
+	}
+	s.running[job.Name] = true
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, job.Name)
+			s.mu.Unlock()
+		}()
+
+		start := s.now()
+		s.opts.Logf("%s: starting", job.Name)
+		s.record(job.Name, func(js *JobState) { js.LastStart = start })
+
+		err := job.Run(ctx)
+
+		end := s.now()
+		s.record(job.Name, func(js *JobState) {
+			js.LastEnd = end
+			js.Runs++
+			if err != nil {
+				js.Failures++
+				js.LastError = err.Error()
+				return
+			}
+			js.LastSuccess = end
+			js.LastError = ""
+		})
+		if err != nil {
+			s.opts.Logf("%s: failed after %s: %v", job.Name, end.Sub(start).Round(time.Millisecond), err)
+			return
+		}
+		s.opts.Logf("%s: finished in %s", job.Name, end.Sub(start).Round(time.Millisecond))
+	}()
+}
+
+func (s *Scheduler) record(name string, fn func(*JobState)) {
+	if err := s.state.update(name, fn); err != nil {
+		s.opts.Logf("%s: %v", name, err)
+	}
+}
+
+// shutdown waits for running jobs, cancelling them after the timeout.
+func (s *Scheduler) shutdown(cancelJobs context.CancelFunc) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	s.mu.Lock()
+	n := len(s.running)
+	s.mu.Unlock()
+	if n > 0 {
+		s.opts.Logf("shutting down: waiting up to %s for %d running job(s)", s.opts.ShutdownTimeout, n)
+	}
+
+	timer := time.NewTimer(s.opts.ShutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		cancelJobs()
+		<-done
+		return fmt.Errorf("shutdown timeout %s exceeded: cancelled running jobs", s.opts.ShutdownTimeout)
+	}
+}
+
+// earliest returns the index of the soonest non-zero time, or -1.
+func earliest(times []time.Time) int {
+	idx := -1
+	for i, t := range times {
+		if t.IsZero() {
+			continue
+		}
+		if idx < 0 || t.Before(times[idx]) {
+			idx = i
+		}
+	}
+	return idx
+}
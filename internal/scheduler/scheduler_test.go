@@ -0,0 +1,224 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, expr string) Schedule {
+	t.Helper()
+	s, err := Parse(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// runFor runs the scheduler for d and returns Run's error.
+func runFor(s *Scheduler, d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return s.Run(ctx)
+}
+
+func TestSchedulerRunsJobsAndPersistsState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), StateFileName)
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ok, failing atomic.Int32
+	jobs := []Job{
+		{Name: "s3", Schedule: every(20 * time.Millisecond), Run: func(context.Context) error {
+			ok.Add(1)
+			return nil
+		}},
+		{Name: "iam", Schedule: every(20 * time.Millisecond), Run: func(context.Context) error {
+			failing.Add(1)
+			return errors.New("exit status 1")
+		}},
+	}
+	if err := runFor(New(jobs, state, Options{}), 110*time.Millisecond); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if ok.Load() < 3 || failing.Load() < 3 {
+		t.Fatalf("expected at least 3 runs each, got %d/%d", ok.Load(), failing.Load())
+	}
+
+	reloaded, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s3 := reloaded.Job("s3")
+	if s3.Runs != int(ok.Load()) || s3.LastSuccess.IsZero() || s3.LastError != "" {
+		t.Errorf("unexpected s3 state: %+v", s3)
+	}
+	iam := reloaded.Job("iam")
+	if iam.Failures != iam.Runs || iam.LastError != "exit status 1" || !iam.LastSuccess.IsZero() {
+		t.Errorf("unexpected iam state: %+v", iam)
+	}
+}
+
+func TestSchedulerSkipsWhileRunning(t *testing.T) {
+	state, _ := LoadState("")
+	release := make(chan struct{})
+	var runs atomic.Int32
+	jobs := []Job{{Name: "slow", Schedule: every(10 * time.Millisecond), Run: func(context.Context) error {
+		runs.Add(1)
+		<-release
+		return nil
+	}}}
+
+	s := New(jobs, state, Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+
+	time.Sleep(80 * time.Millisecond)
+	close(release)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if runs.Load() != 1 {
+		t.Errorf("expected a single run while blocked, got %d", runs.Load())
+	}
+	if state.Job("slow").Skipped < 2 {
+		t.Errorf("expected skipped activations, got %+v", state.Job("slow"))
+	}
+}
+
+func TestSchedulerShutdownWaitsForRunningJobs(t *testing.T) {
+	state, _ := LoadState("")
+	started := make(chan struct{})
+	var finished atomic.Bool
+	var once sync.Once
+	jobs := []Job{{Name: "scan", Schedule: every(10 * time.Millisecond), Run: func(ctx context.Context) error {
+		once.Do(func() { close(started) })
+		time.Sleep(50 * time.Millisecond)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		finished.Store(true)
+		return nil
+	}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- New(jobs, state, Options{ShutdownTimeout: time.Second}).Run(ctx) }()
+
+	<-started
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !finished.Load() {
+		t.Error("running job was not allowed to finish")
+	}
+	if state.Job("scan").Runs != 1 {
+		t.Errorf("expected no new run after shutdown, got %+v", state.Job("scan"))
+	}
+}
+
+func TestSchedulerShutdownTimeoutCancelsJobs(t *testing.T) {
+	state, _ := LoadState("")
+	started := make(chan struct{})
+	var cancelled atomic.Bool
+	jobs := []Job{{Name: "hung", Schedule: every(10 * time.Millisecond), Run: func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		cancelled.Store(true)
+		return ctx.Err()
+	}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- New(jobs, state, Options{ShutdownTimeout: 20 * time.Millisecond}).Run(ctx) }()
+
+	<-started
+	cancel()
+	err := <-done
+	if err == nil || !strings.Contains(err.Error(), "shutdown timeout") {
+		t.Fatalf("expected shutdown timeout error, got %v", err)
+	}
+	if !cancelled.Load() {
+		t.Error("job context was not cancelled")
+	}
+}
+
+func TestFirstDue(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 17, 0, 0, time.UTC)
+	state, _ := LoadState("")
+	s := New(nil, state, Options{Jitter: time.Minute})
+	s.now = func() time.Time { return now }
+	s.jitter = func(max time.Duration) time.Duration { return max / 2 }
+
+	hourly := Job{Name: "iam", Schedule: mustParse(t, "@hourly")}
+	if got, want := s.firstDue(hourly, now), time.Date(2026, 3, 1, 10, 0, 30, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("no history: firstDue = %s, want %s", got, want)
+	}
+
+	// Missed the 09:00 run while down: run now.
+	state.Jobs["iam"] = &JobState{LastStart: time.Date(2026, 3, 1, 7, 59, 0, 0, time.UTC)}
+	if got := s.firstDue(hourly, now); !got.Equal(now) {
+		t.Errorf("missed run: firstDue = %s, want now", got)
+	}
+
+	// Intervals count from the last run, not from startup.
+	interval := Job{Name: "s3", Schedule: mustParse(t, "@every 1h")}
+	state.Jobs["s3"] = &JobState{LastStart: now.Add(-10 * time.Minute)}
+	if got, want := s.firstDue(interval, now), now.Add(50*time.Minute+30*time.Second); !got.Equal(want) {
+		t.Errorf("interval: firstDue = %s, want %s", got, want)
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), StateFileName)
+	state, _ := LoadState(path)
+	if err := state.update("x", func(js *JobState) { js.Runs = 1 }); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded, err := LoadState(path); err != nil || reloaded.Job("x").Runs != 1 {
+		t.Fatalf("round trip failed: %v %+v", err, reloaded)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("state file should be owner-only: %v, %v", info, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"valid", Config{Jitter: time.Minute, Schedules: []ScheduleConfig{{Name: "iam", Cron: "@hourly"}, {Name: "s3", Cron: "0 3 * * *"}}}, ""},
+		{"missing name", Config{Schedules: []ScheduleConfig{{Cron: "@hourly"}}}, "name is required"},
+		{"duplicate", Config{Schedules: []ScheduleConfig{{Name: "a", Cron: "@hourly"}, {Name: "a", Cron: "@daily"}}}, "duplicate"},
+		{"bad cron", Config{Schedules: []ScheduleConfig{{Name: "a", Cron: "every hour"}}}, "must have 5 fields"},
+		{"negative jitter", Config{Jitter: -time.Second}, "cannot be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
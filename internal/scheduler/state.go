@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/atomicfile"
+)
+
+// StateFileName is the daemon state file inside the storage directory.
+const StateFileName = "daemon.json"
+
+// JobState is the persisted history of one job.
+type JobState struct {
+	LastStart   time.Time `json:"last_start,omitempty"`
+	LastEnd     time.Time `json:"last_end,omitempty"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+	Runs        int       `json:"runs"`
+	Failures    int       `json:"failures"`
+	Skipped     int       `json:"skipped"` // activations skipped while still running
+}
+
+// State records the last run of every job so a restarted daemon picks up
+// where it left off. It is safe for concurrent use.
+type State struct {
+	mu   sync.Mutex
+	path string
+	Jobs map[string]*JobState `json:"jobs"`
+}
+
+// LoadState reads the state file. A missing file yields an empty state; an
+// empty path keeps the state in memory only.
+func LoadState(path string) (*State, error) {
+	s := &State{path: path, Jobs: make(map[string]*JobState)}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("read daemon state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parse daemon state %s: %w", path, err)
+	}
+	if s.Jobs == nil {
+		s.Jobs = make(map[string]*JobState)
+	}
+	return s, nil
+}
+
+// Job returns a copy of a job's state.
+func (s *State) Job(name string) JobState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if js, ok := s.Jobs[name]; ok {
+		return *js
+	}
+	return JobState{}
+}
+
+// update applies fn to a job's state and saves the file.
+func (s *State) update(name string, fn func(*JobState)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	js, ok := s.Jobs[name]
+	if !ok {
+		js = &JobState{}
+		s.Jobs[name] = js
+	}
+	fn(js)
+	return s.save()
+}
+
+// save writes the state atomically. Callers hold mu.
+func (s *State) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal daemon state: %w", err)
+	}
+	if err := atomicfile.WritePrivate(s.path, data); err != nil {
+		return fmt.Errorf("write daemon state: %w", err)
+	}
+	return nil
+}
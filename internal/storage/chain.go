@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+// headFile records the sequence and hash of the newest run, so removing
+// the latest runs is detected even though no later run links to them.
+const headFile = "chain.json"
+
+// chainHead is the content of headFile.
+type chainHead struct {
+	Sequence  int       `json:"sequence"`
+	Hash      string    `json:"hash"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ChainResult is the outcome of VerifyChain.
+type ChainResult struct {
+	Runs      int      `json:"runs"`               // stored runs checked
+	Unchained int      `json:"unchained"`          // runs stored before chaining existed
+	Length    int      `json:"length"`             // chained runs
+	Root      string   `json:"root,omitempty"`     // hash of the first chained run
+	Head      string   `json:"head,omitempty"`     // hash of the newest run
+	Problems  []string `json:"problems,omitempty"` // gaps, edits and reordering found
+}
+
+// Valid reports whether the chain verified without problems.
+func (r *ChainResult) Valid() bool {
+	return len(r.Problems) == 0
+}
+
+// hashRun is the chain hash of a run: the SHA-256 of its plain JSON.
+func hashRun(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// nextLink returns the chain link of a run saved at timestamp: it points
+// at the newest run stored before it.
+func (s *runStore) nextLink(timestamp time.Time) (*models.ChainLink, error) {
+	timestamps, err := s.ListRuns()
+	if err != nil {
+		return nil, err
+	}
+
+	name := s.formatTimestamp(timestamp)
+	for i := len(timestamps) - 1; i >= 0; i-- {
+		if s.formatTimestamp(timestamps[i]) >= name {
+			continue
+		}
+		previous, data, err := s.loadRun(timestamps[i])
+		if err != nil {
+			return nil, err
+		}
+		link := &models.ChainLink{Sequence: 1, PreviousHash: hashRun(data)}
+		if previous.Chain != nil {
+			link.Sequence = previous.Chain.Sequence + 1
+		}
+		return link, nil
+	}
+	return &models.ChainLink{Sequence: 1}, nil
+}
+
+// updateHead records a saved run in headFile when it is the newest run.
+func (s *runStore) updateHead(timestamp time.Time, sequence int, hash string) error {
+	timestamps, err := s.ListRuns()
+	if err != nil {
+		return err
+	}
+	if n := len(timestamps); n > 0 && s.formatTimestamp(timestamps[n-1]) != s.formatTimestamp(timestamp) {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(chainHead{Sequence: sequence, Hash: hash, Timestamp: timestamp}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal chain head: %w", err)
+	}
+	if err := s.files.write(headFile, data); err != nil {
+		return fmt.Errorf("failed to write chain head: %w", err)
+	}
+	return nil
+}
+
+// readHead returns the recorded chain head, or nil if none was written.
+func (s *runStore) readHead() (*chainHead, error) {
+	data, err := s.files.read(headFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chain head: %w", err)
+	}
+	var head chainHead
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, fmt.Errorf("failed to parse chain head: %w", err)
+	}
+	return &head, nil
+}
+
+// VerifyChain walks the stored runs in timestamp order and checks that each
+// run links to the one before it. Runs stored before chaining existed are
+// accepted at the start of the history only. Problems are collected rather
+// than returned as an error; the error reports runs that cannot be listed.
+func (s *runStore) VerifyChain() (*ChainResult, error) {
+	timestamps, err := s.ListRuns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ChainResult{Runs: len(timestamps)}
+	problem := func(format string, args ...interface{}) {
+		result.Problems = append(result.Problems, fmt.Sprintf(format, args...))
+	}
+
+	var (
+		prevHash string // hash of the previous run, empty if unreadable or none
+		prevSeq  int
+		started  bool
+	)
+	for i, ts := range timestamps {
+		name := s.formatTimestamp(ts)
+		report, data, err := s.loadRun(ts)
+		if err != nil {
+			problem("run %s cannot be verified: %v", name, err)
+			prevHash = ""
+			prevSeq++
+			continue
+		}
+		hash := hashRun(data)
+		if s.formatTimestamp(report.Timestamp) != name {
+			problem("run %s holds the run of %s: runs were reordered or replaced", name, s.formatTimestamp(report.Timestamp))
+		}
+
+		link := report.Chain
+		switch {
+		case link == nil && started:
+			problem("run %s is not chained: it was added outside spectrehub", name)
+		case link == nil:
+			result.Unchained++
+		case !started && link.Sequence != 1:
+			problem("chain starts at run %s with sequence %d: %d earlier run(s) are missing", name, link.Sequence, link.Sequence-1)
+		case started && link.Sequence > prevSeq+1:
+			problem("%d run(s) missing before run %s", link.Sequence-prevSeq-1, name)
+		case started && link.Sequence <= prevSeq:
+			problem("run %s is out of order: sequence %d follows %d", name, link.Sequence, prevSeq)
+		case i == 0 && link.PreviousHash != "":
+			problem("run before %s is missing", name)
+		case i > 0 && prevHash != "" && link.PreviousHash != prevHash:
+			problem("run before %s was modified or replaced", name)
+		}
+
+		if link != nil {
+			if !started {
+				started = true
+				result.Root = hash
+			}
+			result.Length++
+			prevSeq = link.Sequence
+		}
+		prevHash = hash
+		result.Head = hash
+	}
+
+	head, err := s.readHead()
+	switch {
+	case err != nil:
+		problem("%v", err)
+	case head == nil && started:
+		problem("chain head %s is missing", headFile)
+	case head != nil && len(timestamps) == 0:
+		problem("all runs were removed: the chain head records sequence %d", head.Sequence)
+	case head != nil && head.Sequence > prevSeq:
+		problem("%d run(s) missing after run %s", head.Sequence-prevSeq, s.formatTimestamp(timestamps[len(timestamps)-1]))
+	case head != nil && head.Hash != result.Head:
+		problem("newest run %s does not match the chain head: it was modified or replaced", s.formatTimestamp(timestamps[len(timestamps)-1]))
+	}
+
+	return result, nil
+}
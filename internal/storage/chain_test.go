@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func chainedStore(t *testing.T, n int) (*LocalStorage, []string) {
+	t.Helper()
+	s := NewLocal(t.TempDir())
+	var paths []string
+	for i := 0; i < n; i++ {
+		ts := time.Date(2026, 2, 10+i, 10, 0, 0, 0, time.UTC)
+		if err := s.SaveAggregatedReport(sampleReport(ts)); err != nil {
+			t.Fatalf("SaveAggregatedReport: %v", err)
+		}
+		paths = append(paths, filepath.Join(s.BaseDir(), "runs", s.formatTimestamp(ts)+runSuffix))
+	}
+	return s, paths
+}
+
+func TestSaveLinksRuns(t *testing.T) {
+	s, paths := chainedStore(t, 3)
+
+	runs, err := s.GetLastNRuns(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := os.ReadFile(paths[0])
+	second, _ := os.ReadFile(paths[1])
+	if runs[0].Chain == nil || runs[0].Chain.Sequence != 1 || runs[0].Chain.PreviousHash != "" {
+		t.Errorf("first link = %+v", runs[0].Chain)
+	}
+	if runs[1].Chain.Sequence != 2 || runs[1].Chain.PreviousHash != hashRun(first) {
+		t.Errorf("second link = %+v", runs[1].Chain)
+	}
+	if runs[2].Chain.Sequence != 3 || runs[2].Chain.PreviousHash != hashRun(second) {
+		t.Errorf("third link = %+v", runs[2].Chain)
+	}
+
+	result, err := s.VerifyChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid() || result.Length != 3 || result.Root != hashRun(first) {
+		t.Errorf("VerifyChain() = %+v", result)
+	}
+}
+
+func TestSaveDoesNotModifyReport(t *testing.T) {
+	s := NewLocal(t.TempDir())
+	report := sampleReport(time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC))
+	if err := s.SaveAggregatedReport(report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Chain != nil {
+		t.Errorf("report.Chain = %+v, want nil", report.Chain)
+	}
+}
+
+func TestVerifyChainEncrypted(t *testing.T) {
+	s, _ := protectedStore(t, testKeys())
+	for i := 0; i < 2; i++ {
+		if err := s.SaveAggregatedReport(sampleReport(time.Date(2026, 2, 10+i, 10, 0, 0, 0, time.UTC))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	result, err := s.VerifyChain()
+	if err != nil || !result.Valid() || result.Length != 2 {
+		t.Errorf("VerifyChain() = %+v, %v", result, err)
+	}
+}
+
+func TestVerifyChainLegacyRuns(t *testing.T) {
+	s := NewLocal(t.TempDir())
+	runsDir := filepath.Join(s.BaseDir(), "runs")
+	if err := os.MkdirAll(runsDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	legacy := []byte(`{"timestamp": "2026-02-09T10:00:00Z"}`)
+	if err := os.WriteFile(filepath.Join(runsDir, "2026-02-09T10-00-00"+runSuffix), legacy, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveAggregatedReport(sampleReport(time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC))); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := s.VerifyChain()
+	if err != nil || !result.Valid() || result.Unchained != 1 || result.Length != 1 {
+		t.Errorf("VerifyChain() = %+v, %v", result, err)
+	}
+}
+
+func TestVerifyChainDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(t *testing.T, s *LocalStorage, paths []string)
+		want   string
+	}{
+		{"edited", func(t *testing.T, _ *LocalStorage, paths []string) {
+			data, _ := os.ReadFile(paths[1])
+			data = bytes.Replace(data, []byte(`"total_issues": 2`), []byte(`"total_issues": 0`), 1)
+			if err := os.WriteFile(paths[1], data, 0600); err != nil {
+				t.Fatal(err)
+			}
+		}, "was modified or replaced"},
+		{"gap", func(t *testing.T, _ *LocalStorage, paths []string) {
+			if err := os.Remove(paths[1]); err != nil {
+				t.Fatal(err)
+			}
+		}, "1 run(s) missing before"},
+		{"first removed", func(t *testing.T, _ *LocalStorage, paths []string) {
+			if err := os.Remove(paths[0]); err != nil {
+				t.Fatal(err)
+			}
+		}, "1 earlier run(s) are missing"},
+		{"newest removed", func(t *testing.T, _ *LocalStorage, paths []string) {
+			if err := os.Remove(paths[2]); err != nil {
+				t.Fatal(err)
+			}
+		}, "1 run(s) missing after"},
+		{"newest edited", func(t *testing.T, _ *LocalStorage, paths []string) {
+			data, _ := os.ReadFile(paths[2])
+			data = bytes.Replace(data, []byte(`"total_issues": 2`), []byte(`"total_issues": 0`), 1)
+			if err := os.WriteFile(paths[2], data, 0600); err != nil {
+				t.Fatal(err)
+			}
+		}, "does not match the chain head"},
+		{"reordered", func(t *testing.T, _ *LocalStorage, paths []string) {
+			a, _ := os.ReadFile(paths[0])
+			b, _ := os.ReadFile(paths[1])
+			if err := os.WriteFile(paths[0], b, 0600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(paths[1], a, 0600); err != nil {
+				t.Fatal(err)
+			}
+		}, "were reordered or replaced"},
+		{"unchained insert", func(t *testing.T, s *LocalStorage, paths []string) {
+			late := []byte(`{"timestamp": "2026-02-11T12:00:00Z"}`)
+			if err := os.WriteFile(filepath.Join(s.BaseDir(), "runs", "2026-02-11T12-00-00"+runSuffix), late, 0600); err != nil {
+				t.Fatal(err)
+			}
+		}, "is not chained"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, paths := chainedStore(t, 3)
+			tt.tamper(t, s, paths)
+
+			result, err := s.VerifyChain()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Valid() || !strings.Contains(strings.Join(result.Problems, "\n"), tt.want) {
+				t.Errorf("problems = %q, want %q", result.Problems, tt.want)
+			}
+		})
+	}
+}
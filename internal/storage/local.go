@@ -1,192 +1,170 @@
 package storage
 
 import (
-	"encoding/json"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
-	"sort"
-	"strings"
 	"time"
 
-	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/atomicfile"
 )
 
+// Save lock. A lock older than lockTTL was left by a crashed process and
+// is taken over; a save waits up to lockWait for a live one.
+const lockName = "save.lock"
+
+var (
+	lockTTL  = 2 * time.Minute
+	lockWait = 30 * time.Second
+	lockPoll = 100 * time.Millisecond
+)
+
+// lockToken returns a random token identifying one holder of the lock.
+func lockToken() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d-%d", os.Getpid(), time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
 // LocalStorage implements Storage interface using local filesystem
 type LocalStorage struct {
+	runStore
 	baseDir string
 }
 
 // NewLocal creates a new local storage instance
 func NewLocal(baseDir string) *LocalStorage {
 	return &LocalStorage{
-		baseDir: baseDir,
+		runStore: runStore{files: localFiles(baseDir)},
+		baseDir:  baseDir,
 	}
 }
 
-// SaveAggregatedReport stores an aggregated report to disk
-func (s *LocalStorage) SaveAggregatedReport(report *models.AggregatedReport) error {
-	// Create runs directory
-	runsDir := filepath.Join(s.baseDir, "runs")
-	if err := os.MkdirAll(runsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create runs directory: %w", err)
-	}
-
-	// Generate filename with timestamp
-	filename := s.formatTimestamp(report.Timestamp) + "-aggregated.json"
-	path := filepath.Join(runsDir, filename)
+// Location returns the storage directory
+func (s *LocalStorage) Location() string {
+	return s.baseDir
+}
 
-	// Marshal to JSON with indentation
-	data, err := json.MarshalIndent(report, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal report: %w", err)
-	}
+// BaseDir returns the storage directory
+func (s *LocalStorage) BaseDir() string {
+	return s.baseDir
+}
 
-	// Write to file
-	if err := os.WriteFile(path, data, 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
+// GetStoragePath returns the full path to the storage directory
+func (s *LocalStorage) GetStoragePath() string {
+	return s.baseDir
+}
 
-	return nil
+// EnsureDirectoryExists creates the storage directory if it doesn't exist
+func (s *LocalStorage) EnsureDirectoryExists() error {
+	return os.MkdirAll(filepath.Join(s.baseDir, runsDir), 0700)
 }
 
-// LoadAggregatedReport loads a report from a specific timestamp
-func (s *LocalStorage) LoadAggregatedReport(timestamp time.Time) (*models.AggregatedReport, error) {
-	filename := s.formatTimestamp(timestamp) + "-aggregated.json"
-	path := filepath.Join(s.baseDir, "runs", filename)
+// localFiles is a backend rooted at a directory. Runs describe everything
+// wrong with the infrastructure, so directories are 0700 and files 0600.
+type localFiles string
 
-	return s.loadReportFromFile(path)
+func (d localFiles) path(name string) string {
+	return filepath.Join(string(d), filepath.FromSlash(name))
 }
 
-// GetLatestRun retrieves the most recent aggregated report
-func (s *LocalStorage) GetLatestRun() (*models.AggregatedReport, error) {
-	timestamps, err := s.ListRuns()
-	if err != nil {
-		return nil, err
-	}
-
-	if len(timestamps) == 0 {
-		return nil, fmt.Errorf("no runs found")
-	}
+func (d localFiles) read(name string) ([]byte, error) {
+	return os.ReadFile(d.path(name))
+}
 
-	// Get the latest timestamp
-	latest := timestamps[len(timestamps)-1]
-	return s.LoadAggregatedReport(latest)
+func (d localFiles) write(name string, data []byte) error {
+	return atomicfile.WritePrivate(d.path(name), data)
 }
 
-// GetLastNRuns retrieves the last N aggregated reports
-func (s *LocalStorage) GetLastNRuns(n int) ([]*models.AggregatedReport, error) {
-	timestamps, err := s.ListRuns()
-	if err != nil {
-		return nil, err
+func (d localFiles) remove(name string) error {
+	if err := os.Remove(d.path(name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
 	}
+	return nil
+}
 
-	if len(timestamps) == 0 {
-		return nil, fmt.Errorf("no runs found")
+func (d localFiles) list(dir string) ([]string, error) {
+	entries, err := os.ReadDir(d.path(dir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
 	}
-
-	// Get the last N timestamps
-	start := len(timestamps) - n
-	if start < 0 {
-		start = 0
+	if err != nil {
+		return nil, err
 	}
-
-	selectedTimestamps := timestamps[start:]
-	reports := make([]*models.AggregatedReport, 0, len(selectedTimestamps))
-
-	for _, timestamp := range selectedTimestamps {
-		report, err := s.LoadAggregatedReport(timestamp)
-		if err != nil {
-			// Skip reports that fail to load but continue with others
-			continue
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
 		}
-		reports = append(reports, report)
 	}
-
-	return reports, nil
+	return names, nil
 }
 
-// ListRuns returns all available run timestamps sorted chronologically
-func (s *LocalStorage) ListRuns() ([]time.Time, error) {
-	runsDir := filepath.Join(s.baseDir, "runs")
-
-	// Check if directory exists
-	if _, err := os.Stat(runsDir); os.IsNotExist(err) {
-		return []time.Time{}, nil
+// lock creates the lock file exclusively, taking over one older than
+// lockTTL. The file holds an owner token so unlocking never removes a lock
+// another save has taken over.
+func (d localFiles) lock() (func(), error) {
+	if err := os.MkdirAll(string(d), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
 	}
-
-	// Read directory
-	entries, err := os.ReadDir(runsDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read runs directory: %w", err)
-	}
-
-	var timestamps []time.Time
-
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
+	path := d.path(lockName)
+	deadline := time.Now().Add(lockWait)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			token := []byte(lockToken())
+			_, werr := f.Write(token)
+			if cerr := f.Close(); werr == nil {
+				werr = cerr
+			}
+			if werr != nil {
+				_ = os.Remove(path)
+				return nil, fmt.Errorf("failed to lock storage: %w", werr)
+			}
+			return func() {
+				removeLockIf(path, func(_ fs.FileInfo, data []byte) bool { return bytes.Equal(data, token) })
+			}, nil
 		}
-
-		// Only process aggregated report files
-		if !strings.HasSuffix(entry.Name(), "-aggregated.json") {
-			continue
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to lock storage: %w", err)
 		}
-
-		// Parse timestamp from filename
-		// Format: 2006-01-02T15-04-05-aggregated.json
-		timestampStr := strings.TrimSuffix(entry.Name(), "-aggregated.json")
-		timestamp, err := s.parseTimestamp(timestampStr)
-		if err != nil {
-			// Skip files with invalid timestamp format
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > lockTTL {
+			removeLockIf(path, func(info fs.FileInfo, _ []byte) bool { return time.Since(info.ModTime()) > lockTTL })
 			continue
 		}
-
-		timestamps = append(timestamps, timestamp)
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("storage is locked by another save (%s)", path)
+		}
+		time.Sleep(lockPoll)
 	}
-
-	// Sort chronologically
-	sort.Slice(timestamps, func(i, j int) bool {
-		return timestamps[i].Before(timestamps[j])
-	})
-
-	return timestamps, nil
 }
 
-// loadReportFromFile loads a report from a file path
-func (s *LocalStorage) loadReportFromFile(path string) (*models.AggregatedReport, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("report not found: %s", path)
-		}
-		return nil, fmt.Errorf("failed to read file: %w", err)
+// removeLockIf removes the lock file at path if match accepts it. The file
+// is first renamed to a name unique to this caller, so two waiters taking
+// over the same stale lock cannot both remove it, nor can one remove the
+// fresh lock the other has just created. A lock match rejects is put back.
+func removeLockIf(path string, match func(fs.FileInfo, []byte) bool) {
+	claimed := path + "." + lockToken()
+	if err := os.Rename(path, claimed); err != nil {
+		return
 	}
-
-	var report models.AggregatedReport
-	if err := json.Unmarshal(data, &report); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal report: %w", err)
+	info, err := os.Stat(claimed)
+	if err == nil {
+		var data []byte
+		if data, err = os.ReadFile(claimed); err == nil && match(info, data) {
+			_ = os.Remove(claimed)
+			return
+		}
 	}
-
-	return &report, nil
-}
-
-// formatTimestamp converts a time.Time to filename-safe format
-func (s *LocalStorage) formatTimestamp(t time.Time) string {
-	return t.Format("2006-01-02T15-04-05")
-}
-
-// parseTimestamp converts filename format back to time.Time
-func (s *LocalStorage) parseTimestamp(str string) (time.Time, error) {
-	return time.Parse("2006-01-02T15-04-05", str)
-}
-
-// GetStoragePath returns the full path to the storage directory
-func (s *LocalStorage) GetStoragePath() string {
-	return s.baseDir
-}
-
-// EnsureDirectoryExists creates the storage directory if it doesn't exist
-func (s *LocalStorage) EnsureDirectoryExists() error {
-	return os.MkdirAll(filepath.Join(s.baseDir, "runs"), 0755)
+	// Not ours to remove. Link fails if a new lock was created meanwhile,
+	// which then holds the lock instead.
+	_ = os.Link(claimed, path)
+	_ = os.Remove(claimed)
 }
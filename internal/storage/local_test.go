@@ -3,6 +3,7 @@ package storage
 import (
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -269,3 +270,79 @@ func TestParseTimestampInvalid(t *testing.T) {
 		t.Fatal("expected error for invalid timestamp")
 	}
 }
+
+func TestLocalLockTakeover(t *testing.T) {
+	oldTTL, oldPoll := lockTTL, lockPoll
+	lockTTL, lockPoll = time.Millisecond, time.Millisecond
+	t.Cleanup(func() { lockTTL, lockPoll = oldTTL, oldPoll })
+
+	dir := t.TempDir()
+	files := localFiles(dir)
+	unlockSlow, err := files.lock()
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	unlockNew, err := files.lock()
+	if err != nil {
+		t.Fatalf("takeover: %v", err)
+	}
+	held, _ := os.ReadFile(filepath.Join(dir, lockName))
+
+	// The save that overran lockTTL must not release the new owner's lock.
+	unlockSlow()
+	if after, err := os.ReadFile(filepath.Join(dir, lockName)); err != nil || string(after) != string(held) {
+		t.Fatalf("lock after the stale owner unlocked = %q, %v; want %q", after, err, held)
+	}
+
+	unlockNew()
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("files left: %v", entries)
+	}
+}
+
+func TestLocalLockStaleTakeoverRace(t *testing.T) {
+	oldTTL, oldWait, oldPoll := lockTTL, lockWait, lockPoll
+	lockTTL, lockWait, lockPoll = time.Hour, 2*time.Second, time.Millisecond
+	t.Cleanup(func() { lockTTL, lockWait, lockPoll = oldTTL, oldWait, oldPoll })
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, lockName)
+	if err := os.WriteFile(path, []byte("crashed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// Waiters racing to take over the stale lock hold it one at a time.
+	var mu sync.Mutex
+	holders, maxHolders := 0, 0
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := localFiles(dir).lock()
+			if err != nil {
+				t.Errorf("lock: %v", err)
+				return
+			}
+			mu.Lock()
+			holders++
+			maxHolders = max(maxHolders, holders)
+			mu.Unlock()
+			time.Sleep(2 * time.Millisecond)
+			mu.Lock()
+			holders--
+			mu.Unlock()
+			unlock()
+		}()
+	}
+	wg.Wait()
+	if maxHolders != 1 {
+		t.Errorf("%d waiters held the lock at once", maxHolders)
+	}
+}
@@ -0,0 +1,230 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variables holding keys when the config names no key file.
+const (
+	EncryptionKeyEnv = "SPECTREHUB_STORAGE_KEY"
+	SigningKeyEnv    = "SPECTREHUB_SIGNING_KEY"
+)
+
+// encryptedMagic starts every encrypted run file.
+var encryptedMagic = []byte("SPECTREHUB-AES256GCM-1\n")
+
+// Config is the storage section of .spectrehub.yaml.
+type Config struct {
+	// EncryptionKeyFile holds a 32-byte AES-256 key, hex or base64.
+	// Runs are saved encrypted with AES-GCM when a key is set.
+	EncryptionKeyFile string `mapstructure:"encryption_key_file"`
+	// SigningKeyFile holds an ed25519 private key (32-byte seed or 64-byte
+	// key, hex or base64). Every saved run gets a detached signature, and
+	// loaded runs must carry a valid one.
+	SigningKeyFile string `mapstructure:"signing_key_file"`
+	// PublicKey verifies signatures on machines that only read runs.
+	PublicKey string `mapstructure:"public_key"`
+
+	// URL stores runs in an S3-compatible bucket instead of the storage
+	// directory: s3://bucket/prefix.
+	URL string `mapstructure:"url"`
+	// Environment separates the histories of one repo in the bucket: runs
+	// go under <prefix>/<repo>/<environment>/.
+	Environment string `mapstructure:"environment"`
+	// S3 configures the endpoint used with an s3:// URL.
+	S3 S3Config `mapstructure:"s3"`
+}
+
+// Keys are the keys protecting stored runs. Nil fields disable the
+// corresponding protection.
+type Keys struct {
+	Encryption []byte
+	Signing    ed25519.PrivateKey
+	Verify     ed25519.PublicKey
+}
+
+// Load reads the configured keys, falling back to EncryptionKeyEnv and
+// SigningKeyEnv. It returns nil when no key is configured.
+func (c Config) Load() (*Keys, error) {
+	keys := &Keys{}
+
+	if raw, err := keyMaterial(c.EncryptionKeyFile, EncryptionKeyEnv); err != nil {
+		return nil, err
+	} else if raw != "" {
+		key, err := decodeKey(raw)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("storage encryption key must be 32 bytes, hex or base64")
+		}
+		keys.Encryption = key
+	}
+
+	if raw, err := keyMaterial(c.SigningKeyFile, SigningKeyEnv); err != nil {
+		return nil, err
+	} else if raw != "" {
+		key, err := decodeKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("storage signing key: %w", err)
+		}
+		switch len(key) {
+		case ed25519.SeedSize:
+			keys.Signing = ed25519.NewKeyFromSeed(key)
+		case ed25519.PrivateKeySize:
+			keys.Signing = ed25519.PrivateKey(key)
+		default:
+			return nil, fmt.Errorf("storage signing key must be a 32-byte ed25519 seed or 64-byte private key")
+		}
+		keys.Verify = keys.Signing.Public().(ed25519.PublicKey)
+	}
+
+	if c.PublicKey != "" {
+		key, err := decodeKey(c.PublicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("storage public_key must be a 32-byte ed25519 key, hex or base64")
+		}
+		if keys.Verify != nil && !bytes.Equal(keys.Verify, key) {
+			return nil, fmt.Errorf("storage public_key does not match the signing key")
+		}
+		keys.Verify = key
+	}
+
+	if keys.Encryption == nil && keys.Verify == nil {
+		return nil, nil
+	}
+	return keys, nil
+}
+
+// keyMaterial reads a key file, or the environment variable when no file
+// is configured.
+func keyMaterial(path, env string) (string, error) {
+	if path == "" {
+		return strings.TrimSpace(os.Getenv(env)), nil
+	}
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read key file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// decodeKey accepts hex or standard base64.
+func decodeKey(s string) ([]byte, error) {
+	if key, err := hex.DecodeString(s); err == nil {
+		return key, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("key is neither hex nor base64")
+	}
+	return key, nil
+}
+
+// IntegrityError reports a stored run that failed verification: a missing
+// or invalid signature, or an encrypted file that does not decrypt.
+type IntegrityError struct {
+	Path   string
+	Reason string
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("stored run %s failed verification: %s", filepath.Base(e.Path), e.Reason)
+}
+
+// Encrypts reports whether an encryption key is set.
+func (k *Keys) Encrypts() bool {
+	return k != nil && k.Encryption != nil
+}
+
+// Encrypt seals data with the encryption key, for files kept next to the
+// stored runs such as queued uploads.
+func (k *Keys) Encrypt(data []byte) ([]byte, error) {
+	if !k.Encrypts() {
+		return nil, fmt.Errorf("no storage encryption key configured")
+	}
+	return encrypt(k.Encryption, data)
+}
+
+// Decrypt opens data sealed by Encrypt.
+func (k *Keys) Decrypt(data []byte) ([]byte, error) {
+	if !k.Encrypts() {
+		return nil, fmt.Errorf("data is encrypted and no storage encryption key is configured")
+	}
+	if !isEncrypted(data) {
+		return nil, fmt.Errorf("data is not encrypted")
+	}
+	plain, err := decrypt(k.Encryption, data)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+	return plain, nil
+}
+
+// encrypt seals data with AES-256-GCM behind encryptedMagic.
+func encrypt(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	out := append([]byte(nil), encryptedMagic...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, data, encryptedMagic), nil
+}
+
+// decrypt opens data written by encrypt.
+func decrypt(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	body := bytes.TrimPrefix(data, encryptedMagic)
+	if len(body) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, sealed := body[:gcm.NonceSize()], body[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, encryptedMagic)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// isEncrypted reports whether data was written by encrypt.
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedMagic)
+}
+
+// sign returns the detached signature file content for data.
+func sign(key ed25519.PrivateKey, data []byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, data)) + "\n")
+}
+
+// verify checks a detached signature written by sign.
+func verify(key ed25519.PublicKey, data, sig []byte) bool {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(key, data, raw)
+}
@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	testEncryptionKey = bytes.Repeat([]byte{0x11}, 32)
+	testSigningSeed   = bytes.Repeat([]byte{0x22}, ed25519.SeedSize)
+)
+
+func testKeys() *Keys {
+	signing := ed25519.NewKeyFromSeed(testSigningSeed)
+	return &Keys{
+		Encryption: testEncryptionKey,
+		Signing:    signing,
+		Verify:     signing.Public().(ed25519.PublicKey),
+	}
+}
+
+func protectedStore(t *testing.T, keys *Keys) (*LocalStorage, string) {
+	t.Helper()
+	dir := t.TempDir()
+	s := NewLocal(dir)
+	s.SetKeys(keys)
+	return s, filepath.Join(dir, "runs")
+}
+
+func TestEncryptedSignedRoundTrip(t *testing.T) {
+	s, runsDir := protectedStore(t, testKeys())
+	ts := time.Date(2026, 2, 15, 10, 30, 0, 0, time.UTC)
+	if err := s.SaveAggregatedReport(sampleReport(ts)); err != nil {
+		t.Fatalf("SaveAggregatedReport: %v", err)
+	}
+
+	path := filepath.Join(runsDir, "2026-02-15T10-30-00"+encryptedSuffix)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("encrypted run not written: %v", err)
+	}
+	if !isEncrypted(data) || bytes.Contains(data, []byte("vaultspectre")) {
+		t.Error("run is stored in plain text")
+	}
+	if _, err := os.Stat(path + signatureSuffix); err != nil {
+		t.Errorf("signature not written: %v", err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("run mode = %v, want 0600", info.Mode().Perm())
+	}
+	if info, _ := os.Stat(runsDir); info.Mode().Perm() != 0700 {
+		t.Errorf("runs dir mode = %v, want 0700", info.Mode().Perm())
+	}
+
+	runs, err := s.ListRuns()
+	if err != nil || len(runs) != 1 || !runs[0].Equal(ts) {
+		t.Fatalf("ListRuns() = %v, %v", runs, err)
+	}
+	loaded, err := s.GetLatestRun()
+	if err != nil {
+		t.Fatalf("GetLatestRun: %v", err)
+	}
+	if loaded.Summary.TotalIssues != 2 {
+		t.Errorf("loaded %+v", loaded.Summary)
+	}
+}
+
+func TestEncryptedRunNeedsKey(t *testing.T) {
+	s, _ := protectedStore(t, &Keys{Encryption: testEncryptionKey})
+	ts := time.Date(2026, 2, 15, 10, 30, 0, 0, time.UTC)
+	if err := s.SaveAggregatedReport(sampleReport(ts)); err != nil {
+		t.Fatal(err)
+	}
+
+	plain := NewLocal(s.BaseDir())
+	if _, err := plain.LoadAggregatedReport(ts); err == nil || !strings.Contains(err.Error(), EncryptionKeyEnv) {
+		t.Errorf("load without key = %v, want a key hint", err)
+	}
+
+	wrong := NewLocal(s.BaseDir())
+	wrong.SetKeys(&Keys{Encryption: bytes.Repeat([]byte{0x33}, 32)})
+	var integrityErr *IntegrityError
+	if _, err := wrong.LoadAggregatedReport(ts); !errors.As(err, &integrityErr) {
+		t.Errorf("load with wrong key = %v, want IntegrityError", err)
+	}
+}
+
+func TestTamperedRunsRefused(t *testing.T) {
+	ts1 := time.Date(2026, 2, 14, 10, 0, 0, 0, time.UTC)
+	ts2 := time.Date(2026, 2, 15, 10, 0, 0, 0, time.UTC)
+	verifyOnly := &Keys{Verify: testKeys().Verify}
+
+	tests := []struct {
+		name   string
+		tamper func(t *testing.T, runsDir string)
+		reason string
+	}{
+		{"modified", func(t *testing.T, runsDir string) {
+			path := filepath.Join(runsDir, "2026-02-14T10-00-00"+runSuffix)
+			data, _ := os.ReadFile(path)
+			data = bytes.Replace(data, []byte(`"total_issues": 2`), []byte(`"total_issues": 0`), 1)
+			if err := os.WriteFile(path, data, 0600); err != nil {
+				t.Fatal(err)
+			}
+		}, "signature does not match"},
+		{"signature removed", func(t *testing.T, runsDir string) {
+			if err := os.Remove(filepath.Join(runsDir, "2026-02-14T10-00-00"+runSuffix+signatureSuffix)); err != nil {
+				t.Fatal(err)
+			}
+		}, "missing signature"},
+		{"renamed", func(t *testing.T, runsDir string) {
+			// Replace the older run with a copy of the newer one.
+			for _, suffix := range []string{runSuffix, runSuffix + signatureSuffix} {
+				data, _ := os.ReadFile(filepath.Join(runsDir, "2026-02-15T10-00-00"+suffix))
+				if err := os.WriteFile(filepath.Join(runsDir, "2026-02-14T10-00-00"+suffix), data, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}, "timestamp does not match"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, runsDir := protectedStore(t, &Keys{Signing: testKeys().Signing, Verify: testKeys().Verify})
+			for _, ts := range []time.Time{ts1, ts2} {
+				if err := s.SaveAggregatedReport(sampleReport(ts)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			tt.tamper(t, runsDir)
+
+			reader := NewLocal(s.BaseDir())
+			reader.SetKeys(verifyOnly)
+			_, err := reader.GetLastNRuns(2)
+			var integrityErr *IntegrityError
+			if !errors.As(err, &integrityErr) || !strings.Contains(err.Error(), tt.reason) {
+				t.Errorf("GetLastNRuns() = %v, want IntegrityError %q", err, tt.reason)
+			}
+
+			// The untouched newer run still loads.
+			if _, err := reader.GetLatestRun(); err != nil {
+				t.Errorf("GetLatestRun: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfigLoad(t *testing.T) {
+	t.Setenv(EncryptionKeyEnv, "")
+	t.Setenv(SigningKeyEnv, "")
+
+	if keys, err := (Config{}).Load(); err != nil || keys != nil {
+		t.Fatalf("Load(empty) = %v, %v, want nil", keys, err)
+	}
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "storage.key")
+	if err := os.WriteFile(keyFile, []byte(hex.EncodeToString(testEncryptionKey)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(SigningKeyEnv, base64.StdEncoding.EncodeToString(testSigningSeed))
+
+	keys, err := Config{EncryptionKeyFile: keyFile}.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := testKeys()
+	if !bytes.Equal(keys.Encryption, want.Encryption) || !keys.Signing.Equal(want.Signing) || !keys.Verify.Equal(want.Verify) {
+		t.Errorf("keys = %+v", keys)
+	}
+
+	t.Setenv(SigningKeyEnv, "")
+	keys, err = Config{PublicKey: hex.EncodeToString(want.Verify)}.Load()
+	if err != nil || keys.Signing != nil || !keys.Verify.Equal(want.Verify) {
+		t.Errorf("Load(public key) = %+v, %v", keys, err)
+	}
+
+	for name, cfg := range map[string]Config{
+		"short key":    {EncryptionKeyFile: writeKey(t, dir, "abcd")},
+		"not encoded":  {SigningKeyFile: writeKey(t, dir, "not a key!")},
+		"missing file": {EncryptionKeyFile: filepath.Join(dir, "missing")},
+		"bad public":   {PublicKey: "abcd"},
+	} {
+		if _, err := cfg.Load(); err == nil {
+			t.Errorf("%s: Load() succeeded", name)
+		}
+	}
+
+	t.Setenv(SigningKeyEnv, hex.EncodeToString(testSigningSeed))
+	if _, err := (Config{PublicKey: hex.EncodeToString(make([]byte, 32))}).Load(); err == nil {
+		t.Error("mismatched public key accepted")
+	}
+}
+
+func writeKey(t *testing.T, dir, content string) string {
+	t.Helper()
+	f, err := os.CreateTemp(dir, "key-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	return f.Name()
+}
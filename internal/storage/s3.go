@@ -0,0 +1,343 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// S3Config configures the S3-compatible endpoint of runs stored under an
+// s3:// URL. Credentials come from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY
+// and AWS_SESSION_TOKEN.
+type S3Config struct {
+	// Endpoint is the S3 API URL, e.g. http://localhost:9000 for MinIO.
+	// Defaults to AWS_ENDPOINT_URL_S3, then https://s3.<region>.amazonaws.com.
+	Endpoint string `mapstructure:"endpoint"`
+	// Region defaults to AWS_REGION, AWS_DEFAULT_REGION, then us-east-1.
+	Region string `mapstructure:"region"`
+	// PathStyle puts the bucket in the path instead of the host name, as
+	// MinIO and most other S3-compatible stores need.
+	PathStyle bool `mapstructure:"path_style"`
+}
+
+// errPreconditionFailed is a conditional put that lost against another
+// writer.
+var errPreconditionFailed = errors.New("precondition failed")
+
+// S3Storage implements Storage on an S3-compatible bucket. Runs are stored
+// under a key prefix with the same layout as the storage directory, so a
+// history can be copied between the two.
+type S3Storage struct {
+	runStore
+	bucket string
+	prefix string
+}
+
+// NewS3 returns storage for the runs under prefix in bucket.
+func NewS3(bucket, prefix string, cfg S3Config, creds Credentials) (*S3Storage, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("s3 storage needs a bucket")
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3 storage needs AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+
+	region := firstNonEmpty(cfg.Region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"), "us-east-1")
+	endpoint := firstNonEmpty(cfg.Endpoint, os.Getenv("AWS_ENDPOINT_URL_S3"), "https://s3."+region+".amazonaws.com")
+	base, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || base.Host == "" || (base.Scheme != "http" && base.Scheme != "https") {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", endpoint)
+	}
+	if cfg.PathStyle {
+		base.Path += "/" + bucket
+	} else {
+		base.Host = bucket + "." + base.Host
+	}
+
+	prefix = strings.Trim(prefix, "/")
+	client := &s3Client{
+		base:   base,
+		region: region,
+		creds:  creds,
+		http:   &http.Client{Timeout: 30 * time.Second},
+	}
+	return &S3Storage{
+		runStore: runStore{files: &s3Files{client: client, prefix: prefix}},
+		bucket:   bucket,
+		prefix:   prefix,
+	}, nil
+}
+
+// Location returns the s3:// URL of the run history.
+func (s *S3Storage) Location() string {
+	return "s3://" + s.bucket + "/" + s.prefix
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// s3Files is a backend storing each file as an object under prefix.
+type s3Files struct {
+	client *s3Client
+	prefix string
+}
+
+func (f *s3Files) key(name string) string {
+	if f.prefix == "" {
+		return name
+	}
+	return f.prefix + "/" + name
+}
+
+func (f *s3Files) read(name string) ([]byte, error) {
+	data, _, err := f.client.get(f.key(name))
+	return data, err
+}
+
+func (f *s3Files) write(name string, data []byte) error {
+	_, err := f.client.put(f.key(name), data, nil)
+	return err
+}
+
+func (f *s3Files) remove(name string) error {
+	return f.client.delete(f.key(name), nil)
+}
+
+func (f *s3Files) list(dir string) ([]string, error) {
+	prefix := f.key(dir) + "/"
+	keys, err := f.client.list(prefix)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		names = append(names, strings.TrimPrefix(key, prefix))
+	}
+	return names, nil
+}
+
+// lock creates the lock object with a conditional put that fails when it
+// exists. The object holds its expiry and an owner token; an expired lock is
+// taken over with a put conditional on its ETag, so two waiting writers
+// cannot both win. Unlocking only deletes the object this call wrote, so a
+// save that overran lockTTL cannot release the lock of the writer that took
+// it over.
+func (f *s3Files) lock() (func(), error) {
+	key := f.key(lockName)
+	deadline := time.Now().Add(lockWait)
+	for {
+		expiry := time.Now().Add(lockTTL).UTC().Format(time.RFC3339Nano)
+		body := []byte(expiry + "\n" + lockToken())
+		unlock := func(etag string) func() {
+			return func() { f.unlock(key, body, etag) }
+		}
+
+		etag, err := f.client.put(key, body, http.Header{"If-None-Match": {"*"}})
+		if err == nil {
+			return unlock(etag), nil
+		}
+		if !errors.Is(err, errPreconditionFailed) {
+			return nil, fmt.Errorf("failed to lock storage: %w", err)
+		}
+
+		held, etag, err := f.client.get(key)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			continue // released in the meantime
+		case err != nil:
+			return nil, fmt.Errorf("failed to read storage lock: %w", err)
+		}
+		heldExpiry, _, _ := strings.Cut(string(held), "\n")
+		if until, perr := time.Parse(time.RFC3339Nano, heldExpiry); perr != nil || time.Now().After(until) {
+			etag, err := f.client.put(key, body, http.Header{"If-Match": {etag}})
+			if err == nil {
+				return unlock(etag), nil
+			}
+			if !errors.Is(err, errPreconditionFailed) {
+				return nil, fmt.Errorf("failed to lock storage: %w", err)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("storage is locked by another save (%s)", key)
+		}
+		time.Sleep(lockPoll)
+	}
+}
+
+// unlock deletes the lock object if it still holds body. The delete is
+// conditional on the ETag of the put that took the lock; when the store did
+// not return one, the object is read back first.
+func (f *s3Files) unlock(key string, body []byte, etag string) {
+	if etag == "" {
+		held, heldETag, err := f.client.get(key)
+		if err != nil || !bytes.Equal(held, body) {
+			return
+		}
+		etag = heldETag
+	}
+	_ = f.client.delete(key, http.Header{"If-Match": {etag}})
+}
+
+// s3Client speaks the subset of the S3 REST API the run store needs:
+// GetObject, conditional PutObject, DeleteObject and ListObjectsV2.
+type s3Client struct {
+	base   *url.URL // endpoint with the bucket in the host or path
+	region string
+	creds  Credentials
+	http   *http.Client
+}
+
+// get returns the object and its ETag.
+func (c *s3Client) get(key string) ([]byte, string, error) {
+	resp, err := c.do(http.MethodGet, key, nil, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", fmt.Errorf("s3 object %s: %w", key, fs.ErrNotExist)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", s3Error(resp, "get", key)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("s3 get %s: %w", key, err)
+	}
+	return data, resp.Header.Get("ETag"), nil
+}
+
+// put writes the object and returns its ETag. Conditional headers
+// (If-None-Match, If-Match) turn a lost race into errPreconditionFailed.
+func (c *s3Client) put(key string, data []byte, header http.Header) (string, error) {
+	resp, err := c.do(http.MethodPut, key, nil, header, data)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Header.Get("ETag"), nil
+	case http.StatusPreconditionFailed, http.StatusConflict:
+		return "", fmt.Errorf("s3 put %s: %w", key, errPreconditionFailed)
+	}
+	return "", s3Error(resp, "put", key)
+}
+
+// delete removes the object; a missing object is not an error. An If-Match
+// header that no longer matches leaves the object in place.
+func (c *s3Client) delete(key string, header http.Header) error {
+	resp, err := c.do(http.MethodDelete, key, nil, header, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	case http.StatusPreconditionFailed:
+		return fmt.Errorf("s3 delete %s: %w", key, errPreconditionFailed)
+	}
+	return s3Error(resp, "delete", key)
+}
+
+// listResult is the ListObjectsV2 response.
+type listResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// list returns the keys directly under prefix, following continuation
+// tokens.
+func (c *s3Client) list(prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}, "delimiter": {"/"}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := c.do(http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			err := s3Error(resp, "list", prefix)
+			_ = resp.Body.Close()
+			return nil, err
+		}
+		var result listResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("s3 list %s: %w", prefix, err)
+		}
+
+		for _, obj := range result.Contents {
+			keys = append(keys, obj.Key)
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// do sends a signed request for key (empty for the bucket itself).
+func (c *s3Client) do(method, key string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	u := *c.base
+	u.Path = c.base.Path + "/" + key
+	u.RawPath = awsEscapePath(u.Path)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s %s: %w", strings.ToLower(method), key, err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	signV4(req, sha256Hex(body), c.creds, c.region, "s3", time.Now())
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s %s: %w", strings.ToLower(method), key, err)
+	}
+	return resp, nil
+}
+
+// s3ErrorBody is the XML error document S3 returns.
+type s3ErrorBody struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func s3Error(resp *http.Response, op, key string) error {
+	var body s3ErrorBody
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if xml.Unmarshal(data, &body) == nil && body.Code != "" {
+		return fmt.Errorf("s3 %s %s: %s: %s (HTTP %d)", op, key, body.Code, body.Message, resp.StatusCode)
+	}
+	return fmt.Errorf("s3 %s %s: HTTP %d", op, key, resp.StatusCode)
+}
@@ -0,0 +1,404 @@
+package storage
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 is an in-memory S3-compatible server for one path-style bucket.
+// It checks that requests are signed and honors If-None-Match and If-Match
+// on puts and If-Match on deletes like S3 does.
+type fakeS3 struct {
+	bucket  string
+	mu      sync.Mutex
+	objects map[string][]byte
+	// noPutETag leaves the ETag out of put responses, as some
+	// S3-compatible stores do.
+	noPutETag bool
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	f := &fakeS3{bucket: "history", objects: make(map[string][]byte)}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func etag(data []byte) string {
+	sum := md5.Sum(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDTEST/") {
+		http.Error(w, "unsigned", http.StatusForbidden)
+		return
+	}
+	if r.Header.Get("X-Amz-Content-Sha256") != sha256Hex(body) {
+		http.Error(w, "payload hash mismatch", http.StatusBadRequest)
+		return
+	}
+
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+f.bucket+"/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, "<Error><Code>NoSuchBucket</Code><Message>The specified bucket does not exist</Message></Error>")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	existing, exists := f.objects[key]
+
+	switch {
+	case r.Method == http.MethodGet && key == "" && r.URL.Query().Get("list-type") == "2":
+		f.list(w, r)
+	case r.Method == http.MethodGet:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", etag(existing))
+		_, _ = w.Write(existing)
+	case r.Method == http.MethodPut:
+		if r.Header.Get("If-None-Match") == "*" && exists {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if match := r.Header.Get("If-Match"); match != "" && (!exists || match != etag(existing)) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		f.objects[key] = body
+		if !f.noPutETag {
+			w.Header().Set("ETag", etag(body))
+		}
+	case r.Method == http.MethodDelete:
+		if match := r.Header.Get("If-Match"); match != "" && exists && match != etag(existing) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// list serves ListObjectsV2 with a delimiter, two keys per page.
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("prefix")
+	var keys []string
+	for key := range f.objects {
+		if strings.HasPrefix(key, prefix) && !strings.Contains(strings.TrimPrefix(key, prefix), "/") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	start := 0
+	if token := r.URL.Query().Get("continuation-token"); token != "" {
+		_, _ = fmt.Sscanf(token, "page-%d", &start)
+	}
+	end := start + 2
+	type content struct {
+		Key string `xml:"Key"`
+	}
+	result := struct {
+		XMLName               xml.Name  `xml:"ListBucketResult"`
+		Contents              []content `xml:"Contents"`
+		IsTruncated           bool      `xml:"IsTruncated"`
+		NextContinuationToken string    `xml:"NextContinuationToken,omitempty"`
+	}{}
+	if end < len(keys) {
+		result.IsTruncated = true
+		result.NextContinuationToken = fmt.Sprintf("page-%d", end)
+	} else {
+		end = len(keys)
+	}
+	for _, key := range keys[start:end] {
+		result.Contents = append(result.Contents, content{Key: key})
+	}
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+func (f *fakeS3) keys() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var keys []string
+	for key := range f.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var testCreds = Credentials{AccessKeyID: "AKIDTEST", SecretAccessKey: "secret"}
+
+func s3Store(t *testing.T, srv *httptest.Server, prefix string) *S3Storage {
+	t.Helper()
+	s, err := NewS3("history", prefix, S3Config{Endpoint: srv.URL, Region: "eu-west-1", PathStyle: true}, testCreds)
+	if err != nil {
+		t.Fatalf("NewS3: %v", err)
+	}
+	return s
+}
+
+func TestSignV4(t *testing.T) {
+	// get-vanilla from the AWS Signature Version 4 test suite.
+	req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	creds := Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signV4(req, sha256Hex(nil), creds, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAWSEscape(t *testing.T) {
+	if got := awsEscapePath("/history/org/infra/runs/a b+c~"); got != "/history/org/infra/runs/a%20b%2Bc~" {
+		t.Errorf("awsEscapePath = %q", got)
+	}
+}
+
+func TestS3StorageRoundTrip(t *testing.T) {
+	fake, srv := newFakeS3(t)
+	s := s3Store(t, srv, "spectrehub/org/infra/prod")
+
+	for i := 0; i < 3; i++ {
+		if err := s.SaveAggregatedReport(sampleReport(time.Date(2026, 2, 10+i, 10, 0, 0, 0, time.UTC))); err != nil {
+			t.Fatalf("SaveAggregatedReport: %v", err)
+		}
+	}
+
+	for _, key := range fake.keys() {
+		if !strings.HasPrefix(key, "spectrehub/org/infra/prod/") {
+			t.Errorf("key %q outside the prefix", key)
+		}
+		if strings.HasSuffix(key, lockName) {
+			t.Error("lock object left behind")
+		}
+	}
+
+	runs, err := s.ListRuns()
+	if err != nil || len(runs) != 3 {
+		t.Fatalf("ListRuns() = %v, %v", runs, err)
+	}
+	reports, err := s.GetLastNRuns(2)
+	if err != nil || len(reports) != 2 || !reports[1].Timestamp.Equal(runs[2]) {
+		t.Fatalf("GetLastNRuns() = %v, %v", reports, err)
+	}
+	result, err := s.VerifyChain()
+	if err != nil || !result.Valid() || result.Length != 3 {
+		t.Errorf("VerifyChain() = %+v, %v", result, err)
+	}
+	if s.Location() != "s3://history/spectrehub/org/infra/prod" {
+		t.Errorf("Location() = %q", s.Location())
+	}
+
+	// Another environment of the same repo has its own history.
+	staging := s3Store(t, srv, "spectrehub/org/infra/staging")
+	if runs, err := staging.ListRuns(); err != nil || len(runs) != 0 {
+		t.Errorf("staging ListRuns() = %v, %v", runs, err)
+	}
+}
+
+func TestS3StorageProtected(t *testing.T) {
+	fake, srv := newFakeS3(t)
+	s := s3Store(t, srv, "runs")
+	s.SetKeys(testKeys())
+	ts := time.Date(2026, 2, 15, 10, 30, 0, 0, time.UTC)
+	if err := s.SaveAggregatedReport(sampleReport(ts)); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.mu.Lock()
+	data := fake.objects["runs/runs/2026-02-15T10-30-00"+encryptedSuffix]
+	_, signed := fake.objects["runs/runs/2026-02-15T10-30-00"+encryptedSuffix+signatureSuffix]
+	fake.mu.Unlock()
+	if !isEncrypted(data) || !signed {
+		t.Fatalf("run not encrypted and signed: %v", fake.keys())
+	}
+	if report, err := s.LoadAggregatedReport(ts); err != nil || report.Summary.TotalIssues != 2 {
+		t.Errorf("LoadAggregatedReport() = %v, %v", report, err)
+	}
+}
+
+func TestS3ConcurrentSaves(t *testing.T) {
+	_, srv := newFakeS3(t)
+	oldPoll := lockPoll
+	lockPoll = time.Millisecond
+	t.Cleanup(func() { lockPoll = oldPoll })
+
+	// Saves from separate processes hold the lock one at a time.
+	var (
+		mu               sync.Mutex
+		holders, maxHeld int
+	)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := s3Store(t, srv, "ci").files.lock()
+			if err != nil {
+				t.Errorf("lock: %v", err)
+				return
+			}
+			mu.Lock()
+			holders++
+			if holders > maxHeld {
+				maxHeld = holders
+			}
+			mu.Unlock()
+			time.Sleep(2 * time.Millisecond)
+			mu.Lock()
+			holders--
+			mu.Unlock()
+			unlock()
+		}()
+	}
+	wg.Wait()
+	if maxHeld != 1 {
+		t.Errorf("%d saves held the lock at once", maxHeld)
+	}
+
+	// Two CI jobs with their own store see one history.
+	for i := 0; i < 2; i++ {
+		job := s3Store(t, srv, "ci")
+		if err := job.SaveAggregatedReport(sampleReport(time.Date(2026, 2, 10+i, 10, 0, 0, 0, time.UTC))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	result, err := s3Store(t, srv, "ci").VerifyChain()
+	if err != nil || !result.Valid() || result.Length != 2 {
+		t.Errorf("VerifyChain() = %+v, %v", result, err)
+	}
+}
+
+func TestS3Lock(t *testing.T) {
+	fake, srv := newFakeS3(t)
+	oldWait, oldPoll := lockWait, lockPoll
+	lockWait, lockPoll = 50*time.Millisecond, 5*time.Millisecond
+	t.Cleanup(func() { lockWait, lockPoll = oldWait, oldPoll })
+
+	files := s3Store(t, srv, "locked").files
+	unlock, err := files.lock()
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	if _, err := files.lock(); err == nil || !strings.Contains(err.Error(), "locked by another save") {
+		t.Errorf("second lock = %v, want locked", err)
+	}
+	unlock()
+
+	// A lock past its expiry was left by a crashed save and is taken over.
+	fake.mu.Lock()
+	fake.objects["locked/"+lockName] = []byte(time.Now().Add(-time.Minute).UTC().Format(time.RFC3339Nano))
+	fake.mu.Unlock()
+	unlock, err = files.lock()
+	if err != nil {
+		t.Fatalf("lock over an expired lock: %v", err)
+	}
+	unlock()
+	if len(fake.keys()) != 0 {
+		t.Errorf("objects left: %v", fake.keys())
+	}
+}
+
+func TestS3UnlockAfterTakeover(t *testing.T) {
+	for _, noPutETag := range []bool{false, true} {
+		fake, srv := newFakeS3(t)
+		fake.noPutETag = noPutETag
+		oldTTL, oldPoll := lockTTL, lockPoll
+		lockTTL, lockPoll = time.Millisecond, time.Millisecond
+		t.Cleanup(func() { lockTTL, lockPoll = oldTTL, oldPoll })
+
+		files := s3Store(t, srv, "locked").files
+		unlockSlow, err := files.lock()
+		if err != nil {
+			t.Fatalf("lock: %v", err)
+		}
+		// The first save overruns lockTTL and a second writer takes over.
+		time.Sleep(5 * time.Millisecond)
+		unlockNew, err := files.lock()
+		if err != nil {
+			t.Fatalf("takeover: %v", err)
+		}
+		fake.mu.Lock()
+		held := string(fake.objects["locked/"+lockName])
+		fake.mu.Unlock()
+
+		unlockSlow()
+		fake.mu.Lock()
+		after := string(fake.objects["locked/"+lockName])
+		fake.mu.Unlock()
+		if after != held {
+			t.Errorf("noPutETag=%v: the overrun save released the new owner's lock", noPutETag)
+		}
+
+		unlockNew()
+		if len(fake.keys()) != 0 {
+			t.Errorf("noPutETag=%v: objects left: %v", noPutETag, fake.keys())
+		}
+	}
+}
+
+func TestS3Errors(t *testing.T) {
+	_, srv := newFakeS3(t)
+	s, err := NewS3("missing", "", S3Config{Endpoint: srv.URL, PathStyle: true}, testCreds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ListRuns(); err == nil || !strings.Contains(err.Error(), "NoSuchBucket") {
+		t.Errorf("ListRuns() = %v, want NoSuchBucket", err)
+	}
+
+	if _, err := NewS3("history", "", S3Config{}, Credentials{}); err == nil {
+		t.Error("NewS3 without credentials succeeded")
+	}
+	if _, err := NewS3("history", "", S3Config{Endpoint: "ftp://example.com"}, testCreds); err == nil {
+		t.Error("NewS3 with an ftp endpoint succeeded")
+	}
+}
+
+func TestConfigOpen(t *testing.T) {
+	t.Setenv(EncryptionKeyEnv, "")
+	t.Setenv(SigningKeyEnv, "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+
+	dir := t.TempDir()
+	store, err := Config{}.Open(dir, "org/infra")
+	if local, ok := store.(*LocalStorage); err != nil || !ok || local.BaseDir() != dir {
+		t.Fatalf("Open(local) = %T, %v", store, err)
+	}
+
+	store, err = Config{URL: "s3://history/spectrehub", Environment: "prod", S3: S3Config{Endpoint: "http://localhost:9000"}}.Open(dir, "org/infra")
+	if err != nil {
+		t.Fatalf("Open(s3): %v", err)
+	}
+	if s3, ok := store.(*S3Storage); !ok || s3.Location() != "s3://history/spectrehub/org/infra/prod" {
+		t.Errorf("Open(s3) = %#v", store)
+	}
+
+	for _, url := range []string{"s3:///prefix", "gs://bucket/prefix", "://"} {
+		if _, err := (Config{URL: url}).Open(dir, ""); err == nil {
+			t.Errorf("Open(%q) succeeded", url)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package storage
 
 import (
+	"fmt"
+	"net/url"
+	"path"
 	"time"
 
 	"github.com/ppiankov/spectrehub/internal/models"
@@ -22,4 +25,40 @@ type Storage interface {
 
 	// ListRuns returns all available run timestamps
 	ListRuns() ([]time.Time, error)
+
+	// Verifies reports whether loaded runs must carry a valid signature
+	Verifies() bool
+
+	// VerifyChain checks the hash chain across stored runs
+	VerifyChain() (*ChainResult, error)
+
+	// Location describes where runs are stored, for messages
+	Location() string
+}
+
+// Open returns the run storage cfg selects, with its keys loaded: the S3
+// bucket of cfg.URL, or dir. In a bucket, runs of repo and cfg.Environment
+// get their own key prefix.
+func (c Config) Open(dir, repo string) (Storage, error) {
+	keys, err := c.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.URL == "" {
+		store := NewLocal(dir)
+		store.SetKeys(keys)
+		return store, nil
+	}
+
+	u, err := url.Parse(c.URL)
+	if err != nil || u.Scheme != "s3" || u.Host == "" {
+		return nil, fmt.Errorf("invalid storage url %q (must be s3://bucket/prefix)", c.URL)
+	}
+	store, err := NewS3(u.Host, path.Join(u.Path, repo, c.Environment), c.S3, CredentialsFromEnv())
+	if err != nil {
+		return nil, err
+	}
+	store.SetKeys(keys)
+	return store, nil
 }
@@ -0,0 +1,288 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+// Run file suffixes. Encrypted runs use encryptedSuffix; a detached
+// signature is stored next to the run file with signatureSuffix appended.
+const (
+	runSuffix       = "-aggregated.json"
+	encryptedSuffix = runSuffix + ".enc"
+	signatureSuffix = ".sig"
+)
+
+// runsDir holds the run files inside the storage root.
+const runsDir = "runs"
+
+// backend holds the files of a run history, named by slash-separated paths
+// relative to the storage root: runs/<timestamp>-aggregated.json, chain.json.
+type backend interface {
+	// read returns the content of a file, or an error wrapping
+	// fs.ErrNotExist when it does not exist.
+	read(name string) ([]byte, error)
+	// write replaces a file atomically.
+	write(name string, data []byte) error
+	// remove deletes a file; a missing file is not an error.
+	remove(name string) error
+	// list returns the base names of the files in dir.
+	list(dir string) ([]string, error)
+	// lock serializes saves across processes until unlock is called.
+	lock() (unlock func(), err error)
+}
+
+// runStore implements Storage on top of a backend. It encrypts, signs and
+// chains saved runs and verifies loaded ones.
+type runStore struct {
+	files backend
+	keys  *Keys
+}
+
+// SetKeys enables encryption and signing of saved runs and signature
+// verification of loaded runs. Nil keys store plain, unsigned runs.
+func (s *runStore) SetKeys(keys *Keys) {
+	s.keys = keys
+}
+
+// Verifies reports whether loaded runs must carry a valid signature.
+func (s *runStore) Verifies() bool {
+	return s.keys != nil && s.keys.Verify != nil
+}
+
+// SaveAggregatedReport stores an aggregated report
+func (s *runStore) SaveAggregatedReport(report *models.AggregatedReport) error {
+	// Concurrent saves would link to the same predecessor
+	unlock, err := s.files.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Link the run to the one stored before it
+	link, err := s.nextLink(report.Timestamp)
+	if err != nil {
+		return fmt.Errorf("failed to link run to history: %w", err)
+	}
+	stored := *report
+	stored.Chain = link
+
+	// Marshal to JSON with indentation
+	data, err := json.MarshalIndent(&stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+	hash := hashRun(data)
+
+	// Generate filename with timestamp
+	stem := runsDir + "/" + s.formatTimestamp(report.Timestamp)
+	name := stem + runSuffix
+	if s.keys != nil && s.keys.Encryption != nil {
+		if data, err = encrypt(s.keys.Encryption, data); err != nil {
+			return fmt.Errorf("failed to encrypt report: %w", err)
+		}
+		name = stem + encryptedSuffix
+	}
+
+	// Sign the bytes on disk, so signatures verify without the encryption key
+	if s.keys != nil && s.keys.Signing != nil {
+		if err := s.files.write(name+signatureSuffix, sign(s.keys.Signing, data)); err != nil {
+			return fmt.Errorf("failed to write signature: %w", err)
+		}
+	}
+
+	// Write to file
+	if err := s.files.write(name, data); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	// A run saved again in the other format replaces the old file
+	for _, old := range []string{stem + runSuffix, stem + encryptedSuffix} {
+		if old != name {
+			_ = s.files.remove(old)
+			_ = s.files.remove(old + signatureSuffix)
+		}
+	}
+
+	return s.updateHead(report.Timestamp, link.Sequence, hash)
+}
+
+// LoadAggregatedReport loads a report from a specific timestamp
+func (s *runStore) LoadAggregatedReport(timestamp time.Time) (*models.AggregatedReport, error) {
+	report, _, err := s.loadRun(timestamp)
+	return report, err
+}
+
+// GetLatestRun retrieves the most recent aggregated report
+func (s *runStore) GetLatestRun() (*models.AggregatedReport, error) {
+	timestamps, err := s.ListRuns()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(timestamps) == 0 {
+		return nil, fmt.Errorf("no runs found")
+	}
+
+	// Get the latest timestamp
+	latest := timestamps[len(timestamps)-1]
+	return s.LoadAggregatedReport(latest)
+}
+
+// GetLastNRuns retrieves the last N aggregated reports
+func (s *runStore) GetLastNRuns(n int) ([]*models.AggregatedReport, error) {
+	timestamps, err := s.ListRuns()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(timestamps) == 0 {
+		return nil, fmt.Errorf("no runs found")
+	}
+
+	// Get the last N timestamps
+	start := len(timestamps) - n
+	if start < 0 {
+		start = 0
+	}
+
+	selectedTimestamps := timestamps[start:]
+	reports := make([]*models.AggregatedReport, 0, len(selectedTimestamps))
+
+	for _, timestamp := range selectedTimestamps {
+		report, err := s.LoadAggregatedReport(timestamp)
+		if err != nil {
+			// Tampered history is refused rather than skipped
+			var integrityErr *IntegrityError
+			if errors.As(err, &integrityErr) {
+				return nil, err
+			}
+			// Skip reports that fail to load but continue with others
+			continue
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// ListRuns returns all available run timestamps sorted chronologically
+func (s *runStore) ListRuns() ([]time.Time, error) {
+	names, err := s.files.list(runsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runs directory: %w", err)
+	}
+
+	var timestamps []time.Time
+	seen := make(map[time.Time]bool)
+
+	for _, name := range names {
+		// Only process aggregated report files, plain or encrypted
+		var timestampStr string
+		switch {
+		case strings.HasSuffix(name, runSuffix):
+			timestampStr = strings.TrimSuffix(name, runSuffix)
+		case strings.HasSuffix(name, encryptedSuffix):
+			timestampStr = strings.TrimSuffix(name, encryptedSuffix)
+		default:
+			continue
+		}
+
+		// Parse timestamp from filename
+		// Format: 2006-01-02T15-04-05-aggregated.json
+		timestamp, err := s.parseTimestamp(timestampStr)
+		if err != nil {
+			// Skip files with invalid timestamp format
+			continue
+		}
+
+		if !seen[timestamp] {
+			seen[timestamp] = true
+			timestamps = append(timestamps, timestamp)
+		}
+	}
+
+	// Sort chronologically
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i].Before(timestamps[j])
+	})
+
+	return timestamps, nil
+}
+
+// loadRun loads the run at timestamp, preferring the encrypted file. It
+// also returns the plain JSON, which the hash chain is computed over.
+func (s *runStore) loadRun(timestamp time.Time) (*models.AggregatedReport, []byte, error) {
+	stem := runsDir + "/" + s.formatTimestamp(timestamp)
+	name := stem + encryptedSuffix
+	data, err := s.files.read(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		name = stem + runSuffix
+		data, err = s.files.read(name)
+	}
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, fmt.Errorf("report not found: %s", name)
+		}
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return s.parseRun(name, data)
+}
+
+// parseRun verifies, decrypts and parses the content of a run file.
+func (s *runStore) parseRun(name string, data []byte) (*models.AggregatedReport, []byte, error) {
+	if s.Verifies() {
+		sig, err := s.files.read(name + signatureSuffix)
+		if err != nil {
+			return nil, nil, &IntegrityError{Path: name, Reason: "missing signature"}
+		}
+		if !verify(s.keys.Verify, data, sig) {
+			return nil, nil, &IntegrityError{Path: name, Reason: "signature does not match"}
+		}
+	}
+
+	if isEncrypted(data) {
+		if s.keys == nil || s.keys.Encryption == nil {
+			return nil, nil, fmt.Errorf("report %s is encrypted: set %s or storage.encryption_key_file", baseName(name), EncryptionKeyEnv)
+		}
+		plain, err := decrypt(s.keys.Encryption, data)
+		if err != nil {
+			return nil, nil, &IntegrityError{Path: name, Reason: "decryption failed (wrong key or modified file)"}
+		}
+		data = plain
+	}
+
+	var report models.AggregatedReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal report: %w", err)
+	}
+
+	// A signed run renamed to another timestamp is still tampered history
+	if s.Verifies() && !strings.HasPrefix(baseName(name), s.formatTimestamp(report.Timestamp)+"-") {
+		return nil, nil, &IntegrityError{Path: name, Reason: "timestamp does not match the file name"}
+	}
+
+	return &report, data, nil
+}
+
+// formatTimestamp converts a time.Time to filename-safe format
+func (s *runStore) formatTimestamp(t time.Time) string {
+	return t.Format("2006-01-02T15-04-05")
+}
+
+// parseTimestamp converts filename format back to time.Time
+func (s *runStore) parseTimestamp(str string) (time.Time, error) {
+	return time.Parse("2006-01-02T15-04-05", str)
+}
+
+// baseName returns the last element of a slash-separated name.
+func baseName(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
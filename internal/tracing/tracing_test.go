@@ -0,0 +1,173 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	coltrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ppiankov/spectrehub/internal/tracing/tracingtest"
+)
+
+func envFunc(env map[string]string) GetenvFunc {
+	return func(key string) string { return env[key] }
+}
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"unset", nil, false},
+		{"endpoint", map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318"}, true},
+		{"traces endpoint", map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://localhost:4318/v1/traces"}, true},
+		{"sdk disabled", map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://x", "OTEL_SDK_DISABLED": "TRUE"}, false},
+		{"exporter none", map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://x", "OTEL_TRACES_EXPORTER": "none"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Enabled(envFunc(tt.env)); got != tt.want {
+				t.Errorf("Enabled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), envFunc(nil), "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+}
+
+func TestSetupRejectsGRPC(t *testing.T) {
+	env := envFunc(map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4317",
+		"OTEL_EXPORTER_OTLP_PROTOCOL": "grpc",
+	})
+	if _, err := Setup(context.Background(), env, "dev"); err == nil {
+		t.Error("expected error for grpc protocol")
+	}
+}
+
+// collectorStub is an in-process OTLP/HTTP trace receiver.
+type collectorStub struct {
+	mu       sync.Mutex
+	requests []*coltrace.ExportTraceServiceRequest
+}
+
+func (c *collectorStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/traces" {
+		http.NotFound(w, r)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	req := &coltrace.ExportTraceServiceRequest{}
+	if err := proto.Unmarshal(body, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	c.requests = append(c.requests, req)
+	c.mu.Unlock()
+
+	resp, _ := proto.Marshal(&coltrace.ExportTraceServiceResponse{})
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(resp)
+}
+
+func TestSetupExportsToCollector(t *testing.T) {
+	stub := &collectorStub{}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	prev := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	// The exporter reads the standard variables from the process environment.
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", srv.URL)
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "deployment.environment=test")
+
+	ctx := context.Background()
+	shutdown, err := Setup(ctx, func(k string) string {
+		if k == "OTEL_EXPORTER_OTLP_ENDPOINT" {
+			return srv.URL
+		}
+		return ""
+	}, "1.2.3")
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+
+	ctx, parent := Start(ctx, "spectrehub.run")
+	_, child := Start(ctx, "runner.run_tool", attribute.String("spectrehub.tool", "vaultspectre"))
+	End(child, errors.New("exit status 1"))
+	End(parent, nil)
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	if len(stub.requests) == 0 {
+		t.Fatal("collector received no spans")
+	}
+
+	resourceAttrs := map[string]string{}
+	spans := map[string]string{} // name -> status code
+	var parentID, childParentID []byte
+	for _, req := range stub.requests {
+		for _, rs := range req.ResourceSpans {
+			for _, kv := range rs.Resource.Attributes {
+				resourceAttrs[kv.Key] = kv.Value.GetStringValue()
+			}
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					spans[s.Name] = s.Status.GetCode().String()
+					if s.Name == "spectrehub.run" {
+						parentID = s.SpanId
+					} else {
+						childParentID = s.ParentSpanId
+					}
+				}
+			}
+		}
+	}
+
+	if resourceAttrs["service.name"] != "spectrehub" || resourceAttrs["service.version"] != "1.2.3" || resourceAttrs["deployment.environment"] != "test" {
+		t.Errorf("unexpected resource attributes: %v", resourceAttrs)
+	}
+	if len(spans) != 2 || spans["runner.run_tool"] != "STATUS_CODE_ERROR" {
+		t.Errorf("unexpected spans: %v", spans)
+	}
+	if string(parentID) != string(childParentID) {
+		t.Error("child span is not parented to the run span")
+	}
+}
+
+func TestEndRecordsError(t *testing.T) {
+	rec := tracingtest.Record(t)
+	_, span := Start(context.Background(), "storage.save")
+	End(span, errors.New("disk full"))
+
+	s := tracingtest.Find(rec, "storage.save")
+	if s == nil {
+		t.Fatal("span not recorded")
+	}
+	if s.Status().Description != "disk full" || len(s.Events()) != 1 {
+		t.Errorf("expected error status and event, got %+v %v", s.Status(), s.Events())
+	}
+}
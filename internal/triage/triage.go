@@ -0,0 +1,285 @@
+// Package triage records decisions people make about findings —
+// acknowledgements, suppressions and owners — so later runs can honor them.
+//
+// The store is a single JSON file next to the stored runs. Findings are
+// matched by tool, finding ID, category and resource, which stay the same
+// from one run to the next.
+package triage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/atomicfile"
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+// FileName is the name of the triage store inside the storage directory.
+const FileName = "triage.json"
+
+// Actions recorded in a finding's history.
+const (
+	ActionAcknowledge = "acknowledge"
+	ActionSuppress    = "suppress"
+	ActionAssign      = "assign"
+	ActionReopen      = "reopen"
+)
+
+// Event is one triage decision.
+type Event struct {
+	Time    time.Time  `json:"time"`
+	Action  string     `json:"action"`
+	Note    string     `json:"note,omitempty"`    // acknowledgement note or suppression reason
+	Owner   string     `json:"owner,omitempty"`   // for assign
+	Expires *time.Time `json:"expires,omitempty"` // for suppress; nil means never
+	By      string     `json:"by,omitempty"`
+}
+
+// Record is the current triage state of a finding and how it got there.
+type Record struct {
+	Key      string     `json:"key"`
+	Tool     string     `json:"tool"`
+	ID       string     `json:"id,omitempty"`
+	Category string     `json:"category,omitempty"`
+	Resource string     `json:"resource"`
+	Status   string     `json:"status"` // acknowledged, suppressed or open
+	Note     string     `json:"note,omitempty"`
+	Owner    string     `json:"owner,omitempty"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	History  []Event    `json:"history"`
+}
+
+// StatusAt returns the effective status at now. A suppression that has
+// expired no longer applies and the finding is open again.
+func (r *Record) StatusAt(now time.Time) string {
+	if r == nil {
+		return models.TriageOpen
+	}
+	if r.Status == models.TriageSuppressed && r.Expires != nil && !now.Before(*r.Expires) {
+		return models.TriageOpen
+	}
+	return r.Status
+}
+
+// fileFormat is the on-disk layout of the store.
+type fileFormat struct {
+	Version int       `json:"version"`
+	Records []*Record `json:"records"`
+}
+
+// Store holds triage records, keyed by finding.
+type Store struct {
+	path    string
+	user    string
+	records map[string]*Record
+}
+
+// Key identifies a finding across runs.
+func Key(issue models.NormalizedIssue) string {
+	return issue.Tool + "|" + issue.ID + "|" + issue.Category + "|" + issue.Resource
+}
+
+// Open loads the store at path. A missing file yields an empty store that
+// is created on the first Save.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, user: currentUser(), records: make(map[string]*Record)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("read triage store: %w", err)
+	}
+
+	var f fileFormat
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse triage store %s: %w", path, err)
+	}
+	for _, r := range f.Records {
+		if r != nil && r.Key != "" {
+			s.records[r.Key] = r
+		}
+	}
+	return s, nil
+}
+
+// OpenDir loads the store from a storage directory.
+func OpenDir(dir string) (*Store, error) {
+	return Open(filepath.Join(dir, FileName))
+}
+
+// Path returns the file backing the store.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Len returns the number of findings with a triage record.
+func (s *Store) Len() int {
+	return len(s.records)
+}
+
+// Save writes the store atomically.
+func (s *Store) Save() error {
+	f := fileFormat{Version: 1, Records: make([]*Record, 0, len(s.records))}
+	for _, r := range s.records {
+		f.Records = append(f.Records, r)
+	}
+	sort.Slice(f.Records, func(i, j int) bool { return f.Records[i].Key < f.Records[j].Key })
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal triage store: %w", err)
+	}
+
+	if err := atomicfile.WritePrivate(s.path, data); err != nil {
+		return fmt.Errorf("write triage store: %w", err)
+	}
+	return nil
+}
+
+// Lookup returns the record for a finding, or nil if it was never triaged.
+func (s *Store) Lookup(issue models.NormalizedIssue) *Record {
+	if s == nil {
+		return nil
+	}
+	return s.records[Key(issue)]
+}
+
+// Status returns the effective triage status of a finding at now.
+func (s *Store) Status(issue models.NormalizedIssue, now time.Time) string {
+	return s.Lookup(issue).StatusAt(now)
+}
+
+// Acknowledge marks a finding as seen and accepted for now, with a note.
+func (s *Store) Acknowledge(issue models.NormalizedIssue, note string, now time.Time) *Record {
+	r := s.record(issue)
+	r.Status = models.TriageAcknowledged
+	r.Note = note
+	r.Expires = nil
+	r.History = append(r.History, Event{Time: now, Action: ActionAcknowledge, Note: note, By: s.user})
+	return r
+}
+
+// Suppress hides a finding from later runs until expires. A zero expires
+// suppresses it indefinitely.
+func (s *Store) Suppress(issue models.NormalizedIssue, reason string, expires, now time.Time) *Record {
+	r := s.record(issue)
+	r.Status = models.TriageSuppressed
+	r.Note = reason
+	r.Expires = nil
+	if !expires.IsZero() {
+		exp := expires
+		r.Expires = &exp
+	}
+	r.History = append(r.History, Event{Time: now, Action: ActionSuppress, Note: reason, Expires: r.Expires, By: s.user})
+	return r
+}
+
+// Assign sets the owner of a finding. An empty owner clears it.
+func (s *Store) Assign(issue models.NormalizedIssue, owner string, now time.Time) *Record {
+	r := s.record(issue)
+	r.Owner = owner
+	r.History = append(r.History, Event{Time: now, Action: ActionAssign, Owner: owner, By: s.user})
+	return r
+}
+
+// Reopen clears an acknowledgement or suppression. The owner is kept.
+func (s *Store) Reopen(issue models.NormalizedIssue, now time.Time) *Record {
+	r := s.record(issue)
+	r.Status = models.TriageOpen
+	r.Note = ""
+	r.Expires = nil
+	r.History = append(r.History, Event{Time: now, Action: ActionReopen, By: s.user})
+	return r
+}
+
+// record returns the record for a finding, creating it if needed.
+func (s *Store) record(issue models.NormalizedIssue) *Record {
+	key := Key(issue)
+	r, ok := s.records[key]
+	if !ok {
+		r = &Record{
+			Key:      key,
+			Tool:     issue.Tool,
+			ID:       issue.ID,
+			Category: issue.Category,
+			Resource: issue.Resource,
+			Status:   models.TriageOpen,
+		}
+		s.records[key] = r
+	}
+	return r
+}
+
+// Apply annotates the report's issues with their triage status and owner,
+// and moves actively suppressed issues from Issues to Suppressed so they no
+// longer count toward the summary, policy or thresholds. It returns the
+// number of suppressed issues; the caller should recompute the summary.
+func (s *Store) Apply(report *models.AggregatedReport, now time.Time) int {
+	if s == nil || len(s.records) == 0 {
+		return 0
+	}
+
+	kept := report.Issues[:0]
+	var suppressed int
+	for _, issue := range report.Issues {
+		r := s.Lookup(issue)
+		if r == nil {
+			kept = append(kept, issue)
+			continue
+		}
+		issue.Triage = r.StatusAt(now)
+		issue.Owner = r.Owner
+		if issue.Triage == models.TriageSuppressed {
+			report.Suppressed = append(report.Suppressed, issue)
+			suppressed++
+			continue
+		}
+		kept = append(kept, issue)
+	}
+	report.Issues = kept
+	return suppressed
+}
+
+// ParseExpiry parses a suppression expiry relative to now: empty for
+// never, a number of days such as "30d", a Go duration such as "12h", or a
+// date in YYYY-MM-DD form.
+func ParseExpiry(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	var days int
+	if n, err := fmt.Sscanf(value, "%dd", &days); err == nil && n == 1 && fmt.Sprintf("%dd", days) == value {
+		if days <= 0 {
+			return time.Time{}, fmt.Errorf("expiry must be in the future: %q", value)
+		}
+		return now.AddDate(0, 0, days), nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("expiry must be in the future: %q", value)
+		}
+		return now.Add(d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		if !t.After(now) {
+			return time.Time{}, fmt.Errorf("expiry must be in the future: %q", value)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid expiry %q (use 30d, 12h or YYYY-MM-DD)", value)
+}
+
+// currentUser returns the login name recorded with triage events.
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
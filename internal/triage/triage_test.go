@@ -0,0 +1,216 @@
+package triage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+var now = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func bucket() models.NormalizedIssue {
+	return models.NormalizedIssue{Tool: "s3spectre", ID: "UNUSED_BUCKET", Category: "unused", Severity: "medium", Resource: "s3://old"}
+}
+
+func secret() models.NormalizedIssue {
+	return models.NormalizedIssue{Tool: "vaultspectre", Category: "missing", Severity: "critical", Resource: "secret/db"}
+}
+
+func TestOpenMissingFile(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "none", FileName))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if s.Len() != 0 || s.Lookup(bucket()) != nil {
+		t.Error("expected empty store")
+	}
+	if got := s.Status(bucket(), now); got != models.TriageOpen {
+		t.Errorf("Status = %q, want open", got)
+	}
+}
+
+func TestOpenInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestSaveAndReopen(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+	s, err := OpenDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.user = "alice"
+
+	s.Acknowledge(bucket(), "owned by data team", now)
+	s.Assign(bucket(), "bob", now.Add(time.Minute))
+	s.Suppress(secret(), "false positive", now.AddDate(0, 0, 7), now)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := OpenDir(dir)
+	if err != nil {
+		t.Fatalf("OpenDir: %v", err)
+	}
+	r := loaded.Lookup(bucket())
+	if r == nil {
+		t.Fatal("expected bucket record after reload")
+	}
+	if r.Status != models.TriageAcknowledged || r.Note != "owned by data team" || r.Owner != "bob" {
+		t.Errorf("unexpected record: %+v", r)
+	}
+	if len(r.History) != 2 || r.History[0].By != "alice" || r.History[1].Owner != "bob" {
+		t.Errorf("unexpected history: %+v", r.History)
+	}
+	if got := loaded.Status(secret(), now); got != models.TriageSuppressed {
+		t.Errorf("secret Status = %q, want suppressed", got)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestSaveIsOwnerOnly(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+	s, err := OpenDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Acknowledge(bucket(), "owned by data team", now)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	for path, want := range map[string]os.FileMode{dir: 0700, s.Path(): 0600} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != want {
+			t.Errorf("%s mode = %o, want %o", filepath.Base(path), perm, want)
+		}
+	}
+}
+
+func TestSuppressionExpires(t *testing.T) {
+	s, _ := Open(filepath.Join(t.TempDir(), FileName))
+	s.Suppress(bucket(), "migration pending", now.Add(24*time.Hour), now)
+
+	if got := s.Status(bucket(), now.Add(time.Hour)); got != models.TriageSuppressed {
+		t.Errorf("before expiry Status = %q", got)
+	}
+	if got := s.Status(bucket(), now.Add(24*time.Hour)); got != models.TriageOpen {
+		t.Errorf("after expiry Status = %q, want open", got)
+	}
+
+	s.Suppress(secret(), "accepted risk", time.Time{}, now)
+	if got := s.Status(secret(), now.AddDate(5, 0, 0)); got != models.TriageSuppressed {
+		t.Errorf("indefinite suppression Status = %q", got)
+	}
+}
+
+func TestReopenKeepsOwner(t *testing.T) {
+	s, _ := Open(filepath.Join(t.TempDir(), FileName))
+	s.Assign(bucket(), "bob", now)
+	s.Suppress(bucket(), "later", time.Time{}, now)
+	r := s.Reopen(bucket(), now)
+
+	if r.Status != models.TriageOpen || r.Note != "" || r.Expires != nil || r.Owner != "bob" {
+		t.Errorf("unexpected record after reopen: %+v", r)
+	}
+	if last := r.History[len(r.History)-1]; last.Action != ActionReopen {
+		t.Errorf("last action = %q, want reopen", last.Action)
+	}
+}
+
+func TestApply(t *testing.T) {
+	s, _ := Open(filepath.Join(t.TempDir(), FileName))
+	s.Suppress(secret(), "false positive", time.Time{}, now)
+	s.Acknowledge(bucket(), "", now)
+	s.Assign(bucket(), "bob", now)
+	expired := models.NormalizedIssue{Tool: "kafkaspectre", Category: "unused", Resource: "topic-a"}
+	s.Suppress(expired, "temporary", now.Add(-time.Hour), now.Add(-2*time.Hour))
+
+	untouched := models.NormalizedIssue{Tool: "pgspectre", Category: "unused", Resource: "public.t"}
+	report := &models.AggregatedReport{Issues: []models.NormalizedIssue{secret(), bucket(), expired, untouched}}
+
+	if n := s.Apply(report, now); n != 1 {
+		t.Fatalf("Apply suppressed %d, want 1", n)
+	}
+	if len(report.Issues) != 3 || len(report.Suppressed) != 1 {
+		t.Fatalf("expected 3 kept and 1 suppressed, got %d/%d", len(report.Issues), len(report.Suppressed))
+	}
+	if report.Suppressed[0].Resource != "secret/db" || report.Suppressed[0].Triage != models.TriageSuppressed {
+		t.Errorf("unexpected suppressed issue: %+v", report.Suppressed[0])
+	}
+	got := map[string]models.NormalizedIssue{}
+	for _, issue := range report.Issues {
+		got[issue.Resource] = issue
+	}
+	if b := got["s3://old"]; b.Triage != models.TriageAcknowledged || b.Owner != "bob" {
+		t.Errorf("unexpected acknowledged issue: %+v", b)
+	}
+	if e := got["topic-a"]; e.Triage != models.TriageOpen {
+		t.Errorf("expired suppression should be open, got %q", e.Triage)
+	}
+	if u := got["public.t"]; u.Triage != "" {
+		t.Errorf("untriaged issue should have no status, got %q", u.Triage)
+	}
+}
+
+func TestApplyNilStore(t *testing.T) {
+	var s *Store
+	report := &models.AggregatedReport{Issues: []models.NormalizedIssue{bucket()}}
+	if n := s.Apply(report, now); n != 0 || len(report.Issues) != 1 {
+		t.Errorf("nil store should not change the report")
+	}
+}
+
+func TestKeyDistinguishesFindingIDs(t *testing.T) {
+	a := bucket()
+	b := bucket()
+	b.ID = "STALE_PREFIX"
+	if Key(a) == Key(b) {
+		t.Error("different finding IDs on the same resource must not share a key")
+	}
+}
+
+func TestParseExpiry(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr string
+	}{
+		{"", time.Time{}, ""},
+		{"30d", now.AddDate(0, 0, 30), ""},
+		{"12h", now.Add(12 * time.Hour), ""},
+		{"2026-04-01", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), ""},
+		{"0d", time.Time{}, "future"},
+		{"-1h", time.Time{}, "future"},
+		{"2026-01-01", time.Time{}, "future"},
+		{"3dd", time.Time{}, "invalid expiry"},
+		{"soon", time.Time{}, "invalid expiry"},
+	}
+	for _, tt := range tests {
+		got, err := ParseExpiry(tt.in, now)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseExpiry(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseExpiry(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
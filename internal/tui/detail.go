@@ -2,16 +2,18 @@ package tui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/ppiankov/spectrehub/internal/models"
 )
 
 // detailHeight is the fixed number of lines for the detail panel.
-const detailHeight = 5
+const detailHeight = 10
 
-// renderDetail produces the detail view for a selected issue.
-func renderDetail(issue *models.NormalizedIssue, width int) string {
+// renderDetail produces the detail view for a selected issue, the
+// correlated incident it belongs to, if any, and its rendered triage state.
+func renderDetail(issue *models.NormalizedIssue, incident *models.Incident, triageInfo string, width int) string {
 	if issue == nil {
 		return styleDetailPanel.Width(width).Render("No issue selected")
 	}
@@ -25,8 +27,14 @@ func renderDetail(issue *models.NormalizedIssue, width int) string {
 	if issue.Evidence != "" {
 		b.WriteString(fmt.Sprintf("Evidence: %s\n", issue.Evidence))
 	}
+	if where := attributesLine(issue.Attributes); where != "" {
+		b.WriteString(fmt.Sprintf("Where: %s\n", where))
+	}
+	if issue.Remediation != "" {
+		b.WriteString(fmt.Sprintf("Fix: %s\n", issue.Remediation))
+	}
 
-	parts := make([]string, 0, 3)
+	parts := make([]string, 0, 4)
 	if issue.Count > 0 {
 		parts = append(parts, fmt.Sprintf("Count: %d", issue.Count))
 	}
@@ -36,9 +44,42 @@ func renderDetail(issue *models.NormalizedIssue, width int) string {
 	if !issue.LastSeen.IsZero() {
 		parts = append(parts, fmt.Sprintf("Last: %s", issue.LastSeen.Format("2006-01-02")))
 	}
+	if issue.Confidence != nil {
+		parts = append(parts, fmt.Sprintf("Confidence: %.0f%%", *issue.Confidence*100))
+	}
 	if len(parts) > 0 {
 		b.WriteString(strings.Join(parts, "  "))
 	}
 
+	if incident != nil {
+		b.WriteString(fmt.Sprintf("\nIncident: %s [%s]", incident.Title, strings.Join(incident.Tools, ", ")))
+	}
+
+	if triageInfo != "" {
+		b.WriteString("\n" + triageInfo)
+	}
+
 	return styleDetailPanel.Width(width).Render(b.String())
 }
+
+// attributesLine renders resource attributes as "account=…, region=…".
+func attributesLine(a *models.ResourceAttributes) string {
+	if a == nil {
+		return ""
+	}
+	var parts []string
+	for _, kv := range [][2]string{{"account", a.Account}, {"region", a.Region}, {"namespace", a.Namespace}} {
+		if kv[1] != "" {
+			parts = append(parts, kv[0]+"="+kv[1])
+		}
+	}
+	keys := make([]string, 0, len(a.Tags))
+	for k := range a.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, "tag:"+k+"="+a.Tags[k])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,374 @@
+package tui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/reporter"
+)
+
+// defaultExportFile is offered when the export prompt opens.
+const defaultExportFile = "spectrehub-findings.csv"
+
+// metadataSuffix names the sidecar file describing a CSV export, which has
+// no room for anything but rows.
+const metadataSuffix = ".meta.json"
+
+// exportHeader describes how an exported view was selected, so the file
+// explains itself when handed to someone else.
+type exportHeader struct {
+	ExportedAt   time.Time
+	RunTimestamp time.Time
+	Filters      filterState
+	Sort         string
+	Shown        int
+	Total        int
+}
+
+// filterPairs returns the active filters as name/value pairs in a stable
+// order.
+func (f filterState) filterPairs() [][2]string {
+	var pairs [][2]string
+	add := func(name, value string) {
+		if value != "" {
+			pairs = append(pairs, [2]string{name, value})
+		}
+	}
+	add("severity", f.Severity)
+	add("tool", f.Tool)
+	add("category", f.Category)
+	add("owner", f.Owner)
+	add("search", f.SearchText)
+	if f.Incidents != nil {
+		add("incidents", "true")
+	}
+	return pairs
+}
+
+// filterLine renders the active filters as "name=value, ..." or "none".
+func (h exportHeader) filterLine() string {
+	pairs := h.Filters.filterPairs()
+	if len(pairs) == 0 {
+		return "none"
+	}
+	parts := make([]string, len(pairs))
+	for i, p := range pairs {
+		parts[i] = p[0] + "=" + p[1]
+	}
+	return strings.Join(parts, ", ")
+}
+
+// exportFormat picks the export format from the file extension.
+func exportFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return "csv", nil
+	case ".json":
+		return "json", nil
+	case ".md", ".markdown":
+		return "markdown", nil
+	case ".sarif":
+		return "sarif", nil
+	default:
+		return "", fmt.Errorf("unknown export format %q (use .csv, .json, .md or .sarif)", filepath.Ext(path))
+	}
+}
+
+// writeExport writes issues, already filtered and sorted, in format. Each
+// issue's Triage field carries the status to export.
+func writeExport(w io.Writer, format string, h exportHeader, issues []models.NormalizedIssue) error {
+	switch format {
+	case "csv":
+		return writeExportCSV(w, issues)
+	case "json":
+		return writeExportJSON(w, h, issues)
+	case "markdown":
+		return writeExportMarkdown(w, h, issues)
+	case "sarif":
+		return reporter.WriteSARIF(w, issues, map[string]string{
+			"exported_at":   h.ExportedAt.Format(time.RFC3339),
+			"run_timestamp": h.RunTimestamp.Format(time.RFC3339),
+			"filters":       h.filterLine(),
+			"sort":          h.Sort,
+			"issues":        fmt.Sprintf("%d of %d", h.Shown, h.Total),
+		})
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// writeExportCSV writes the header row and one row per issue. The view
+// description goes to the metadata sidecar, see writeExportMetadata.
+func writeExportCSV(w io.Writer, issues []models.NormalizedIssue) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"severity", "tool", "id", "category", "resource", "count", "status", "owner", "evidence", "fingerprint", "remediation"}); err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		row := []string{
+			issue.Severity, issue.Tool, issue.ID, issue.Category, issue.Resource,
+			strconv.Itoa(issue.Count), issue.Triage, issue.Owner, issue.Evidence,
+			issue.Fingerprint, issue.Remediation,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportMetadata describes the exported view. It heads the JSON export and
+// is the sidecar of a CSV export.
+type exportMetadata struct {
+	ExportedAt   string            `json:"exported_at"`
+	RunTimestamp string            `json:"run_timestamp"`
+	Filters      map[string]string `json:"filters"`
+	Sort         string            `json:"sort"`
+	IssueCount   int               `json:"issue_count"`
+	TotalIssues  int               `json:"total_issues"`
+}
+
+// exportDocument is the JSON export layout.
+type exportDocument struct {
+	exportMetadata
+	Issues []models.NormalizedIssue `json:"issues"`
+}
+
+func (h exportHeader) metadata() exportMetadata {
+	filters := make(map[string]string)
+	for _, p := range h.Filters.filterPairs() {
+		filters[p[0]] = p[1]
+	}
+	return exportMetadata{
+		ExportedAt:   h.ExportedAt.Format(time.RFC3339),
+		RunTimestamp: h.RunTimestamp.Format(time.RFC3339),
+		Filters:      filters,
+		Sort:         h.Sort,
+		IssueCount:   h.Shown,
+		TotalIssues:  h.Total,
+	}
+}
+
+func writeExportJSON(w io.Writer, h exportHeader, issues []models.NormalizedIssue) error {
+	if issues == nil {
+		issues = []models.NormalizedIssue{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(exportDocument{exportMetadata: h.metadata(), Issues: issues})
+}
+
+// writeExportMetadata writes the view description of a CSV export.
+func writeExportMetadata(w io.Writer, h exportHeader) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(h.metadata())
+}
+
+func writeExportMarkdown(w io.Writer, h exportHeader, issues []models.NormalizedIssue) error {
+	var b strings.Builder
+	b.WriteString("# SpectreHub findings\n\n")
+	b.WriteString(fmt.Sprintf("- Exported: %s\n", h.ExportedAt.Format(time.RFC3339)))
+	b.WriteString(fmt.Sprintf("- Run: %s\n", h.RunTimestamp.Format(time.RFC3339)))
+	b.WriteString(fmt.Sprintf("- Filters: %s\n", h.filterLine()))
+	b.WriteString(fmt.Sprintf("- Sort: %s\n", h.Sort))
+	b.WriteString(fmt.Sprintf("- Issues: %d of %d\n\n", h.Shown, h.Total))
+
+	b.WriteString("| Severity | Tool | Category | Rule | Resource | Count | Status | Owner |\n")
+	b.WriteString("|---|---|---|---|---|---|---|---|\n")
+	for _, issue := range issues {
+		b.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %d | %s | %s |\n",
+			markdownCell(issue.Severity), markdownCell(issue.Tool), markdownCell(issue.Category),
+			markdownCell(issue.ID), markdownCell(issue.Resource), issue.Count,
+			markdownCell(issue.Triage), markdownCell(issue.Owner)))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell escapes a value for a Markdown table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.NewReplacer("\r\n", " ", "\n", " ").Replace(s)
+}
+
+// startExport prompts for the file to export the current view to.
+func (m Model) startExport() (tea.Model, tea.Cmd) {
+	m.promptInput.SetValue(defaultExportFile)
+	m.promptInput.CursorEnd()
+	m.promptInput.Focus()
+	m.mode = modeExport
+	return m, textinput.Blink
+}
+
+func (m Model) handleExportKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeNormal
+		m.promptInput.Blur()
+		m.statusMsg = "Export cancelled"
+		return m, nil
+	case "enter":
+		path := strings.TrimSpace(m.promptInput.Value())
+		if path == "" {
+			m.statusMsg = "A file name is required"
+			return m, nil
+		}
+		files, err := exportFiles(path)
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Export failed: %v", err)
+			return m, nil
+		}
+		var existing []string
+		for _, f := range files {
+			if _, err := os.Stat(f); err == nil {
+				existing = append(existing, f)
+			}
+		}
+		if len(existing) > 0 {
+			m.overwrite = existing
+			m.mode = modeExportConfirm
+			m.promptInput.Blur()
+			return m, nil
+		}
+		return m.finishExport(path, false)
+	}
+
+	var cmd tea.Cmd
+	m.promptInput, cmd = m.promptInput.Update(msg)
+	return m, cmd
+}
+
+// handleExportConfirmKey answers the question whether to overwrite the
+// existing export files: n goes back to the file name prompt.
+func (m Model) handleExportConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		m.overwrite = nil
+		return m.finishExport(strings.TrimSpace(m.promptInput.Value()), true)
+	case "n", "N":
+		m.overwrite = nil
+		m.mode = modeExport
+		m.promptInput.Focus()
+		return m, textinput.Blink
+	case "esc":
+		m.overwrite = nil
+		m.mode = modeNormal
+		m.statusMsg = "Export cancelled"
+	}
+	return m, nil
+}
+
+// finishExport writes the export and leaves the prompt, or returns to it
+// when the export fails.
+func (m Model) finishExport(path string, force bool) (tea.Model, tea.Cmd) {
+	written, err := m.exportView(path, force)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Export failed: %v", err)
+		m.mode = modeExport
+		m.promptInput.Focus()
+		return m, nil
+	}
+	m.statusMsg = fmt.Sprintf("Exported %d issues to %s", len(m.filteredIssues), strings.Join(written, " and "))
+	m.mode = modeNormal
+	m.promptInput.Blur()
+	return m, nil
+}
+
+// exportFiles returns the files an export to path writes: a CSV export
+// also writes its metadata sidecar.
+func exportFiles(path string) ([]string, error) {
+	format, err := exportFormat(path)
+	if err != nil {
+		return nil, err
+	}
+	if format == "csv" {
+		return []string{path, path + metadataSuffix}, nil
+	}
+	return []string{path}, nil
+}
+
+// exportView writes the filtered and sorted issues to path, in the format
+// given by its extension, and returns the files written: a CSV export also
+// writes its metadata sidecar. Existing files are only replaced with force.
+func (m *Model) exportView(path string, force bool) ([]string, error) {
+	format, err := exportFormat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]models.NormalizedIssue, len(m.filteredIssues))
+	for i, issue := range m.filteredIssues {
+		issue.Triage = m.issueStatus(issue)
+		issues[i] = issue
+	}
+	h := exportHeader{
+		ExportedAt:   m.now().UTC(),
+		RunTimestamp: m.report.Timestamp,
+		Filters:      m.filters,
+		Sort:         sortFieldName(m.sortBy),
+		Shown:        len(issues),
+		Total:        len(m.allIssues),
+	}
+
+	files, err := exportFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	if !force {
+		for _, f := range files {
+			if _, err := os.Stat(f); err == nil {
+				return nil, fmt.Errorf("%s already exists", f)
+			}
+		}
+	}
+
+	if err := writeExportFile(path, force, func(w io.Writer) error {
+		return writeExport(w, format, h, issues)
+	}); err != nil {
+		return nil, err
+	}
+	if format == "csv" {
+		if err := writeExportFile(files[1], force, func(w io.Writer) error {
+			return writeExportMetadata(w, h)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// writeExportFile creates path, failing if it exists unless force is set,
+// and fills it with write.
+func writeExportFile(path string, force bool, write func(io.Writer) error) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !force {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -1,17 +1,99 @@
 package tui
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 
 	"github.com/ppiankov/spectrehub/internal/models"
 )
 
+// ownerUnassigned is the owner filter value matching issues without an owner.
+const ownerUnassigned = "(unassigned)"
+
 // filterState holds current active filters.
 type filterState struct {
 	Tool       string
 	Severity   string
+	Category   string
+	Owner      string // ownerUnassigned matches issues without an owner
 	SearchText string
+	// Incidents, when non-nil, restricts the view to issues whose
+	// issueKey is in the set (issues linked into a correlated incident).
+	Incidents map[string]bool
+}
+
+// describe summarizes the active filters, most selective first, e.g.
+// "critical kubespectre misconfig". It is empty when nothing is filtered.
+func (f filterState) describe() string {
+	var parts []string
+	for _, v := range []string{f.Severity, f.Tool, f.Category} {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	if f.Owner != "" {
+		parts = append(parts, "owner:"+f.Owner)
+	}
+	if f.SearchText != "" {
+		parts = append(parts, fmt.Sprintf("%q", f.SearchText))
+	}
+	if f.Incidents != nil {
+		parts = append(parts, "incidents")
+	}
+	return strings.Join(parts, " ")
+}
+
+// filterField enumerates the fields that can be picked from a list.
+type filterField int
+
+const (
+	filterByTool filterField = iota
+	filterBySeverity
+	filterByCategory
+	filterByOwner
+)
+
+// name returns the field name shown in the picker.
+func (f filterField) name() string {
+	switch f {
+	case filterBySeverity:
+		return "severity"
+	case filterByCategory:
+		return "category"
+	case filterByOwner:
+		return "owner"
+	default:
+		return "tool"
+	}
+}
+
+// get returns the filter value of field.
+func (f filterState) get(field filterField) string {
+	switch field {
+	case filterBySeverity:
+		return f.Severity
+	case filterByCategory:
+		return f.Category
+	case filterByOwner:
+		return f.Owner
+	default:
+		return f.Tool
+	}
+}
+
+// set sets the filter value of field; empty lifts the filter.
+func (f *filterState) set(field filterField, value string) {
+	switch field {
+	case filterBySeverity:
+		f.Severity = value
+	case filterByCategory:
+		f.Category = value
+	case filterByOwner:
+		f.Owner = value
+	default:
+		f.Tool = value
+	}
 }
 
 // sortField enumerates columns that can be sorted.
@@ -44,14 +126,30 @@ func applyFilters(issues []models.NormalizedIssue, f filterState) []models.Norma
 		if f.Severity != "" && issue.Severity != f.Severity {
 			continue
 		}
+		if f.Category != "" && issue.Category != f.Category {
+			continue
+		}
+		if f.Owner != "" && !matchesOwner(issue, f.Owner) {
+			continue
+		}
 		if searchLower != "" && !matchesSearch(issue, searchLower) {
 			continue
 		}
+		if f.Incidents != nil && !f.Incidents[issueKey(issue)] {
+			continue
+		}
 		result = append(result, issue)
 	}
 	return result
 }
 
+func matchesOwner(issue models.NormalizedIssue, owner string) bool {
+	if owner == ownerUnassigned {
+		return issue.Owner == ""
+	}
+	return issue.Owner == owner
+}
+
 func matchesSearch(issue models.NormalizedIssue, searchLower string) bool {
 	return strings.Contains(strings.ToLower(issue.Tool), searchLower) ||
 		strings.Contains(strings.ToLower(issue.Category), searchLower) ||
@@ -82,16 +180,54 @@ func sortIssues(issues []models.NormalizedIssue, field sortField) {
 
 // uniqueTools returns deduplicated, sorted tool names from issues.
 func uniqueTools(issues []models.NormalizedIssue) []string {
+	return uniqueValues(issues, func(issue models.NormalizedIssue) string { return issue.Tool })
+}
+
+// uniqueValues returns the deduplicated, sorted non-empty values of a field.
+func uniqueValues(issues []models.NormalizedIssue, field func(models.NormalizedIssue) string) []string {
 	seen := make(map[string]bool)
-	var tools []string
+	var values []string
 	for _, issue := range issues {
-		if !seen[issue.Tool] {
-			seen[issue.Tool] = true
-			tools = append(tools, issue.Tool)
+		v := field(issue)
+		if v != "" && !seen[v] {
+			seen[v] = true
+			values = append(values, v)
 		}
 	}
-	sort.Strings(tools)
-	return tools
+	sort.Strings(values)
+	return values
+}
+
+// filterChoices returns the values offered by the picker for field.
+// Severities keep their natural order; owners include ownerUnassigned when
+// some issues have no owner.
+func filterChoices(issues []models.NormalizedIssue, field filterField) []string {
+	switch field {
+	case filterBySeverity:
+		present := make(map[string]bool)
+		for _, issue := range issues {
+			present[issue.Severity] = true
+		}
+		var choices []string
+		for _, sev := range []string{"critical", "high", "medium", "low"} {
+			if present[sev] {
+				choices = append(choices, sev)
+			}
+		}
+		return choices
+	case filterByCategory:
+		return uniqueValues(issues, func(issue models.NormalizedIssue) string { return issue.Category })
+	case filterByOwner:
+		choices := uniqueValues(issues, func(issue models.NormalizedIssue) string { return issue.Owner })
+		for _, issue := range issues {
+			if issue.Owner == "" {
+				return append(choices, ownerUnassigned)
+			}
+		}
+		return choices
+	default:
+		return uniqueTools(issues)
+	}
 }
 
 // sortFieldName returns a human-readable name for the sort field.
@@ -0,0 +1,200 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+// groupColumns replace tableColumns while findings are grouped.
+var groupColumns = []table.Column{
+	{Title: "Findings", Width: 54},
+	{Title: "Severity", Width: 10},
+	{Title: "Count", Width: 6},
+	{Title: "Status", Width: 10},
+}
+
+// noRuleID labels findings that carry no rule ID.
+const noRuleID = "(no rule ID)"
+
+// groupLevels are the tree levels above the resources: tool, category and
+// rule ID.
+var groupLevels = []func(models.NormalizedIssue) string{
+	func(issue models.NormalizedIssue) string { return issue.Tool },
+	func(issue models.NormalizedIssue) string { return issue.Category },
+	func(issue models.NormalizedIssue) string {
+		if issue.ID == "" {
+			return noRuleID
+		}
+		return issue.ID
+	},
+}
+
+// treeRow is one visible row of the grouped view: a group node or, at the
+// deepest level, a single issue.
+type treeRow struct {
+	depth    int
+	path     string // slash-joined group labels; identifies expanded nodes
+	label    string
+	issues   int    // number of issues below the node
+	count    int    // sum of issue counts below the node
+	severity string // most severe issue below the node
+	issue    int    // index into the filtered issues, -1 for group nodes
+}
+
+// buildTree groups issues (already filtered and sorted) by tool, category
+// and rule ID. Children of a node are listed only when its path is in
+// expanded; issues keep their sort order below their rule.
+func buildTree(issues []models.NormalizedIssue, expanded map[string]bool) []treeRow {
+	idx := make([]int, len(issues))
+	for i := range idx {
+		idx[i] = i
+	}
+	var rows []treeRow
+	addTreeLevel(&rows, issues, idx, 0, "", expanded)
+	return rows
+}
+
+func addTreeLevel(rows *[]treeRow, issues []models.NormalizedIssue, idx []int, depth int, prefix string, expanded map[string]bool) {
+	if depth == len(groupLevels) {
+		for _, i := range idx {
+			*rows = append(*rows, treeRow{
+				depth:    depth,
+				label:    issues[i].Resource,
+				issues:   1,
+				count:    issues[i].Count,
+				severity: issues[i].Severity,
+				issue:    i,
+			})
+		}
+		return
+	}
+
+	groups := make(map[string][]int)
+	for _, i := range idx {
+		k := groupLevels[depth](issues[i])
+		groups[k] = append(groups[k], i)
+	}
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		members := groups[k]
+		row := treeRow{depth: depth, path: prefix + k, label: k, issues: len(members), issue: -1}
+		for _, i := range members {
+			row.count += issues[i].Count
+			if row.severity == "" || severityPriority[issues[i].Severity] < severityPriority[row.severity] {
+				row.severity = issues[i].Severity
+			}
+		}
+		*rows = append(*rows, row)
+		if expanded[row.path] {
+			addTreeLevel(rows, issues, members, depth+1, row.path+"/", expanded)
+		}
+	}
+}
+
+// buildTreeTableRows renders tree rows for the grouped table.
+func buildTreeTableRows(rows []treeRow, issues []models.NormalizedIssue, expanded map[string]bool, status func(models.NormalizedIssue) string) []table.Row {
+	out := make([]table.Row, 0, len(rows))
+	for _, r := range rows {
+		indent := strings.Repeat("  ", r.depth)
+		var label, st string
+		if r.issue < 0 {
+			marker := "▸ "
+			if expanded[r.path] {
+				marker = "▾ "
+			}
+			label = fmt.Sprintf("%s%s%s (%d)", indent, marker, r.label, r.issues)
+		} else {
+			label = indent + r.label
+			issue := issues[r.issue]
+			st = issue.Triage
+			if status != nil {
+				st = status(issue)
+			}
+			st = statusLabel(st)
+		}
+		out = append(out, table.Row{
+			truncate(label, groupColumns[0].Width),
+			severityLabel(r.severity),
+			fmt.Sprintf("%d", r.count),
+			st,
+		})
+	}
+	return out
+}
+
+// toggleGrouping switches between the flat table and the group tree.
+func (m *Model) toggleGrouping() {
+	m.grouped = !m.grouped
+	if m.grouped {
+		m.statusMsg = "Grouped by tool → category → rule"
+	} else {
+		m.statusMsg = "Ungrouped"
+	}
+	m.rebuildTable()
+	m.table.SetCursor(0)
+}
+
+// selectedTreeRow returns the tree row under the cursor in grouped mode.
+func (m *Model) selectedTreeRow() *treeRow {
+	if !m.grouped {
+		return nil
+	}
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.treeRows) {
+		return nil
+	}
+	return &m.treeRows[cursor]
+}
+
+// setExpanded expands or collapses the group under the cursor. Collapsing
+// an issue or an already collapsed group collapses its parent instead and
+// moves the cursor there.
+func (m *Model) setExpanded(expand bool) {
+	row := m.selectedTreeRow()
+	if row == nil {
+		return
+	}
+	if expand {
+		if row.issue < 0 {
+			m.expanded[row.path] = true
+			m.rebuildTable()
+		}
+		return
+	}
+
+	if row.issue < 0 && m.expanded[row.path] {
+		delete(m.expanded, row.path)
+		m.rebuildTable()
+		return
+	}
+	// Walk up to the parent node.
+	cursor := m.table.Cursor()
+	for i := cursor - 1; i >= 0; i-- {
+		if m.treeRows[i].depth < row.depth {
+			delete(m.expanded, m.treeRows[i].path)
+			m.rebuildTable()
+			m.table.SetCursor(i)
+			return
+		}
+	}
+}
+
+// toggleExpanded flips the group under the cursor. It reports false when
+// the cursor is on an issue rather than a group.
+func (m *Model) toggleExpanded() bool {
+	row := m.selectedTreeRow()
+	if row == nil || row.issue >= 0 {
+		return false
+	}
+	m.setExpanded(!m.expanded[row.path])
+	return true
+}
@@ -0,0 +1,355 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ppiankov/spectrehub/internal/aggregator"
+	"github.com/ppiankov/spectrehub/internal/models"
+)
+
+// historyState holds the run browser, timeline and diff views.
+type historyState struct {
+	runs     []*models.AggregatedReport // oldest first
+	current  int                        // index of the run shown in the issue table
+	cursor   int                        // run browser cursor
+	marks    []int                      // runs marked for diff, at most two
+	timeline []aggregator.TimelineEntry
+	issue    models.NormalizedIssue // issue the timeline is about
+	diff     *aggregator.DiffResult
+	diffPair [2]int // baseline and current run indexes of diff
+	diffTop  int    // first visible diff line
+}
+
+// withHistory attaches stored runs (oldest first) so the run browser,
+// issue timelines and run diffs are available. The last run is shown.
+func (m Model) withHistory(runs []*models.AggregatedReport) Model {
+	if len(runs) == 0 {
+		return m
+	}
+	m.history.runs = runs
+	m.history.current = len(runs) - 1
+	m.history.cursor = m.history.current
+	m.setReport(runs[m.history.current])
+	return m
+}
+
+// openRuns switches to the run browser.
+func (m Model) openRuns() (tea.Model, tea.Cmd) {
+	if len(m.history.runs) == 0 {
+		m.statusMsg = "No run history"
+		return m, nil
+	}
+	m.history.cursor = m.history.current
+	m.mode = modeRuns
+	return m, nil
+}
+
+func (m Model) handleRunsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	h := &m.history
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		if h.cursor > 0 {
+			h.cursor--
+		}
+	case "down", "j":
+		if h.cursor < len(h.runs)-1 {
+			h.cursor++
+		}
+	case " ":
+		m.toggleMark(h.cursor)
+	case "enter":
+		h.current = h.cursor
+		m.setReport(h.runs[h.current])
+		m.mode = modeNormal
+		m.statusMsg = "Run " + h.runs[h.current].Timestamp.Format("2006-01-02 15:04")
+	case "d":
+		m.openDiff()
+	case "esc", "h":
+		m.mode = modeNormal
+	}
+	return m, nil
+}
+
+// toggleMark marks or unmarks a run for diffing. Marking a third run
+// drops the oldest mark.
+func (m *Model) toggleMark(i int) {
+	h := &m.history
+	for j, mark := range h.marks {
+		if mark == i {
+			h.marks = append(h.marks[:j], h.marks[j+1:]...)
+			return
+		}
+	}
+	h.marks = append(h.marks, i)
+	if len(h.marks) > 2 {
+		h.marks = h.marks[1:]
+	}
+}
+
+// openDiff diffs the two marked runs, or the run under the cursor against
+// the one before it when fewer than two are marked.
+func (m *Model) openDiff() {
+	h := &m.history
+	var a, b int
+	switch {
+	case len(h.marks) == 2:
+		a, b = h.marks[0], h.marks[1]
+		if a > b {
+			a, b = b, a
+		}
+	case h.cursor > 0:
+		a, b = h.cursor-1, h.cursor
+	default:
+		m.statusMsg = "Mark two runs with space, or pick a run with an earlier one"
+		return
+	}
+	h.diff = aggregator.ComputeDiff(h.runs[a], h.runs[b])
+	h.diffPair = [2]int{a, b}
+	h.diffTop = 0
+	m.mode = modeDiff
+}
+
+func (m Model) handleDiffKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		if m.history.diffTop > 0 {
+			m.history.diffTop--
+		}
+	case "down", "j":
+		if m.history.diffTop < m.diffLines()-1 {
+			m.history.diffTop++
+		}
+	case "esc":
+		m.mode = modeRuns
+	}
+	return m, nil
+}
+
+// openTimeline shows where the selected issue appeared, disappeared or
+// changed severity across the stored runs.
+func (m Model) openTimeline() (tea.Model, tea.Cmd) {
+	issue := m.selectedIssue()
+	if issue == nil {
+		m.statusMsg = "No issue selected"
+		return m, nil
+	}
+	runs := m.history.runs
+	if len(runs) == 0 {
+		runs = []*models.AggregatedReport{m.report}
+	}
+	m.history.issue = *issue
+	m.history.timeline = aggregator.IssueTimeline(runs, *issue)
+	m.mode = modeTimeline
+	return m, nil
+}
+
+func (m Model) handleTimelineKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc", "enter":
+		m.mode = modeNormal
+	}
+	return m, nil
+}
+
+// historyBodyHeight is the number of lines available below the header.
+func (m *Model) historyBodyHeight() int {
+	h := m.height - headerHeight - 6
+	if h < 5 {
+		h = 5
+	}
+	return h
+}
+
+// renderRuns renders the run browser.
+func (m *Model) renderRuns() string {
+	h := m.history
+	var b strings.Builder
+
+	scores := make([]int, len(h.runs))
+	issues := make([]int, len(h.runs))
+	for i, run := range h.runs {
+		scores[i] = int(math.Round(run.Summary.ScorePercent))
+		issues[i] = run.Summary.TotalIssues
+	}
+	b.WriteString(fmt.Sprintf("Runs: %d   Score: %s   Issues: %s\n\n",
+		len(h.runs), renderSparkline(scores), renderSparkline(issues)))
+	b.WriteString(styleFooter.Render(fmt.Sprintf("  %-3s%-18s %-10s %7s %7s %6s", "", "Timestamp", "Health", "Score", "Issues", "Δ")))
+	b.WriteString("\n")
+
+	// Page the list so the cursor stays visible.
+	visible := m.historyBodyHeight() - 3
+	start := 0
+	if h.cursor >= visible {
+		start = h.cursor - visible + 1
+	}
+	end := start + visible
+	if end > len(h.runs) {
+		end = len(h.runs)
+	}
+
+	for i := start; i < end; i++ {
+		run := h.runs[i]
+		cursor := "  "
+		if i == h.cursor {
+			cursor = "> "
+		}
+		mark := " "
+		for _, mk := range h.marks {
+			if mk == i {
+				mark = "*"
+			}
+		}
+		delta := ""
+		if i > 0 {
+			delta = fmt.Sprintf("%+d", run.Summary.TotalIssues-h.runs[i-1].Summary.TotalIssues)
+		}
+		current := ""
+		if i == h.current {
+			current = "  (shown)"
+		}
+		line := fmt.Sprintf("%s%s %-18s %-10s %6.1f%% %7d %6s%s",
+			cursor, mark, run.Timestamp.Format("2006-01-02 15:04"),
+			run.Summary.HealthScore, run.Summary.ScorePercent, run.Summary.TotalIssues, delta, current)
+		if i == h.cursor {
+			line = styleSearchPrompt.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// renderTimeline renders the timeline of the selected issue.
+func (m *Model) renderTimeline() string {
+	h := m.history
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("Timeline: %s %s %s\n", h.issue.Tool, h.issue.Category, h.issue.Resource))
+
+	var strip strings.Builder
+	for _, e := range h.timeline {
+		if e.Present {
+			strip.WriteString(severityStyle(e.Severity).Render("■"))
+		} else {
+			strip.WriteString("·")
+		}
+	}
+	b.WriteString(fmt.Sprintf("Seen in runs: %s  (oldest → newest)\n\n", strip.String()))
+
+	var events int
+	for _, e := range h.timeline {
+		if e.Event == "" {
+			continue
+		}
+		events++
+		line := fmt.Sprintf("  %s  %s", e.Timestamp.Format("2006-01-02 15:04"), e.Event)
+		if e.Detail != "" {
+			line += " (" + e.Detail + ")"
+		}
+		b.WriteString(line + "\n")
+	}
+	if events == 0 {
+		b.WriteString("  Not present in any stored run\n")
+	}
+	return b.String()
+}
+
+// diffLines returns the number of rows of the side-by-side diff.
+func (m *Model) diffLines() int {
+	if m.history.diff == nil {
+		return 0
+	}
+	n := len(m.history.diff.ResolvedIssues)
+	if len(m.history.diff.NewIssues) > n {
+		n = len(m.history.diff.NewIssues)
+	}
+	return n
+}
+
+// renderDiff renders resolved and new issues of two runs side by side.
+func (m *Model) renderDiff() string {
+	h := m.history
+	d := h.diff
+	var b strings.Builder
+
+	a, c := h.runs[h.diffPair[0]], h.runs[h.diffPair[1]]
+	b.WriteString(fmt.Sprintf("Diff  %s (%d issues) → %s (%d issues)   +%d new  -%d resolved  Δ %+d\n\n",
+		a.Timestamp.Format("2006-01-02 15:04"), d.Summary.BaselineTotal,
+		c.Timestamp.Format("2006-01-02 15:04"), d.Summary.CurrentTotal,
+		d.Summary.NewCount, d.Summary.ResolvedCount, d.Summary.Delta))
+
+	colWidth := (m.width - 3) / 2
+	if colWidth < 20 {
+		colWidth = 20
+	}
+	resolved := diffColumn(d.ResolvedIssues, colWidth)
+	added := diffColumn(d.NewIssues, colWidth)
+
+	visible := m.historyBodyHeight() - 3
+	left := []string{lipgloss.NewStyle().Bold(true).Render(padRight("Resolved", colWidth))}
+	right := []string{lipgloss.NewStyle().Bold(true).Render("New")}
+	for i := h.diffTop; i < h.diffTop+visible && i < m.diffLines(); i++ {
+		l, r := "", ""
+		if i < len(resolved) {
+			l = resolved[i]
+		}
+		if i < len(added) {
+			r = added[i]
+		}
+		left = append(left, padRight(l, colWidth))
+		right = append(right, r)
+	}
+	if m.diffLines() == 0 {
+		left = append(left, padRight("(none)", colWidth))
+		right = append(right, "(none)")
+	}
+
+	sep := make([]string, len(left))
+	for i := range sep {
+		sep[i] = " │ "
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+		strings.Join(left, "\n"), strings.Join(sep, "\n"), strings.Join(right, "\n")))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// diffColumn formats issues for one side of the diff, most severe first.
+func diffColumn(issues []models.NormalizedIssue, width int) []string {
+	sorted := make([]models.NormalizedIssue, len(issues))
+	copy(sorted, issues)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if pa, pb := severityPriority[a.Severity], severityPriority[b.Severity]; pa != pb {
+			return pa < pb
+		}
+		if a.Tool != b.Tool {
+			return a.Tool < b.Tool
+		}
+		return a.Resource < b.Resource
+	})
+
+	lines := make([]string, len(sorted))
+	for i, issue := range sorted {
+		lines[i] = truncate(fmt.Sprintf("%-8s %s %s", severityLabel(issue.Severity), issue.Tool, issue.Resource), width)
+	}
+	return lines
+}
+
+// padRight pads s with spaces to width display cells.
+func padRight(s string, width int) string {
+	if w := lipgloss.Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
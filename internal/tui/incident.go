@@ -0,0 +1,32 @@
+package tui
+
+import "github.com/ppiankov/spectrehub/internal/models"
+
+// issueKey identifies an issue for incident lookups.
+func issueKey(issue models.NormalizedIssue) string {
+	return issue.Tool + "|" + issue.ID + "|" + issue.Category + "|" + issue.Resource
+}
+
+// indexIncidents maps each linked issue to the incident it belongs to.
+// An issue linked by several rules keeps the first (most severe) incident.
+func indexIncidents(incidents []models.Incident) map[string]*models.Incident {
+	index := make(map[string]*models.Incident)
+	for i := range incidents {
+		for _, issue := range incidents[i].Issues {
+			k := issueKey(issue)
+			if _, ok := index[k]; !ok {
+				index[k] = &incidents[i]
+			}
+		}
+	}
+	return index
+}
+
+// incidentKeySet returns the keys of all issues linked into an incident.
+func incidentKeySet(index map[string]*models.Incident) map[string]bool {
+	set := make(map[string]bool, len(index))
+	for k := range index {
+		set[k] = true
+	}
+	return set
+}
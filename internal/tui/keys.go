@@ -3,12 +3,28 @@ package tui
 import "github.com/charmbracelet/bubbles/key"
 
 type keyMap struct {
-	Quit        key.Binding
-	Search      key.Binding
-	FilterTool  key.Binding
-	Sort        key.Binding
-	Copy        key.Binding
-	ClearFilter key.Binding
+	Quit           key.Binding
+	Search         key.Binding
+	FilterTool     key.Binding
+	FilterSeverity key.Binding
+	FilterCategory key.Binding
+	FilterOwner    key.Binding
+	Group          key.Binding
+	Expand         key.Binding
+	Collapse       key.Binding
+	Presets        key.Binding
+	SavePreset     key.Binding
+	Sort           key.Binding
+	Export         key.Binding
+	Copy           key.Binding
+	Incidents      key.Binding
+	Acknowledge    key.Binding
+	Suppress       key.Binding
+	Assign         key.Binding
+	Reopen         key.Binding
+	History        key.Binding
+	Timeline       key.Binding
+	ClearFilter    key.Binding
 }
 
 var keys = keyMap{
@@ -24,14 +40,78 @@ var keys = keyMap{
 		key.WithKeys("t"),
 		key.WithHelp("t", "filter tool"),
 	),
+	FilterSeverity: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "filter severity"),
+	),
+	FilterCategory: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "filter category"),
+	),
+	FilterOwner: key.NewBinding(
+		key.WithKeys("O"),
+		key.WithHelp("O", "filter owner"),
+	),
+	Group: key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "group by tool/category/rule"),
+	),
+	Expand: key.NewBinding(
+		key.WithKeys("right"),
+		key.WithHelp("→", "expand group"),
+	),
+	Collapse: key.NewBinding(
+		key.WithKeys("left"),
+		key.WithHelp("←", "collapse group"),
+	),
+	Presets: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "apply preset"),
+	),
+	SavePreset: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "save preset"),
+	),
 	Sort: key.NewBinding(
 		key.WithKeys("s"),
 		key.WithHelp("s", "cycle sort"),
 	),
+	Export: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "export view"),
+	),
 	Copy: key.NewBinding(
 		key.WithKeys("c"),
 		key.WithHelp("c", "copy"),
 	),
+	Incidents: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "incidents"),
+	),
+	Acknowledge: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "acknowledge"),
+	),
+	Suppress: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "suppress"),
+	),
+	Assign: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "assign owner"),
+	),
+	Reopen: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "reopen"),
+	),
+	History: key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h", "run history"),
+	),
+	Timeline: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "issue timeline"),
+	),
 	ClearFilter: key.NewBinding(
 		key.WithKeys("esc"),
 		key.WithHelp("esc", "clear"),
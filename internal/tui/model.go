@@ -4,13 +4,16 @@ import (
 	"encoding/base64"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ppiankov/spectrehub/internal/config"
 	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/triage"
 )
 
 // mode represents the current UI interaction mode.
@@ -19,7 +22,15 @@ type mode int
 const (
 	modeNormal mode = iota
 	modeSearch
-	modeFilterTool
+	modeFilter
+	modeTriage
+	modeRuns
+	modeTimeline
+	modeDiff
+	modePresets
+	modePresetName
+	modeExport
+	modeExportConfirm
 )
 
 const defaultTableHeight = 15
@@ -30,49 +41,76 @@ type Model struct {
 	report    *models.AggregatedReport
 	trend     *models.TrendSummary
 	allIssues []models.NormalizedIssue
+	incidents map[string]*models.Incident
+	triage    *triage.Store
+	now       func() time.Time
+
+	// Saved filter presets and how to persist them
+	presets     []config.FilterPreset
+	savePresets func([]config.FilterPreset) error
 
 	// UI state
 	table          table.Model
 	searchInput    textinput.Model
+	promptInput    textinput.Model
+	prompt         triagePrompt
+	history        historyState
 	filteredIssues []models.NormalizedIssue
 	filters        filterState
 	sortBy         sortField
 	mode           mode
-	toolChoices    []string
-	toolCursor     int
+	filterField    filterField
+	pickCursor     int
+	grouped        bool
+	expanded       map[string]bool // expanded group paths
+	treeRows       []treeRow
+	presetCursor   int
 	width          int
 	height         int
 	statusMsg      string
+	overwrite      []string // existing files an export asks to replace
 	// clipboard is captured here for testing instead of writing to stdout
 	clipboard string
 }
 
 // New creates a new TUI model from report data.
 func New(report *models.AggregatedReport, trend *models.TrendSummary) Model {
-	issues := make([]models.NormalizedIssue, len(report.Issues))
-	copy(issues, report.Issues)
-
-	sortIssues(issues, sortBySeverity)
-	rows := buildRows(issues)
-	t := newTable(rows, defaultTableHeight)
-
 	ti := textinput.New()
 	ti.Placeholder = "search..."
 	ti.CharLimit = 64
 
-	return Model{
-		report:         report,
-		trend:          trend,
-		allIssues:      issues,
-		filteredIssues: issues,
-		table:          t,
-		searchInput:    ti,
-		sortBy:         sortBySeverity,
-		mode:           modeNormal,
-		toolChoices:    uniqueTools(issues),
-		width:          80,
-		height:         24,
+	pi := textinput.New()
+	pi.CharLimit = 200
+
+	m := Model{
+		trend:       trend,
+		table:       newTable(nil, defaultTableHeight),
+		searchInput: ti,
+		promptInput: pi,
+		now:         time.Now,
+		sortBy:      sortBySeverity,
+		mode:        modeNormal,
+		expanded:    make(map[string]bool),
+		width:       80,
+		height:      24,
 	}
+	m.setReport(report)
+	return m
+}
+
+// setReport shows report in the issue table, resetting filters.
+func (m *Model) setReport(report *models.AggregatedReport) {
+	// Issues suppressed by triage are listed too so they can be reopened.
+	issues := make([]models.NormalizedIssue, 0, len(report.Issues)+len(report.Suppressed))
+	issues = append(issues, report.Issues...)
+	issues = append(issues, report.Suppressed...)
+
+	m.report = report
+	m.allIssues = issues
+	m.incidents = indexIncidents(report.Incidents)
+	m.filters = filterState{}
+	m.rebuildTable()
+	m.table.SetCursor(0)
 }
 
 // Init implements tea.Model.
@@ -103,6 +141,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case modeSearch:
 		m.searchInput, cmd = m.searchInput.Update(msg)
 		return m, cmd
+	case modeTriage, modePresetName, modeExport:
+		m.promptInput, cmd = m.promptInput.Update(msg)
+		return m, cmd
 	default:
 		m.table, cmd = m.table.Update(msg)
 		return m, cmd
@@ -113,8 +154,24 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch m.mode {
 	case modeSearch:
 		return m.handleSearchKey(msg)
-	case modeFilterTool:
-		return m.handleFilterToolKey(msg)
+	case modeFilter:
+		return m.handleFilterKey(msg)
+	case modeTriage:
+		return m.handleTriageKey(msg)
+	case modeRuns:
+		return m.handleRunsKey(msg)
+	case modeTimeline:
+		return m.handleTimelineKey(msg)
+	case modeDiff:
+		return m.handleDiffKey(msg)
+	case modePresets:
+		return m.handlePresetsKey(msg)
+	case modePresetName:
+		return m.handlePresetNameKey(msg)
+	case modeExport:
+		return m.handleExportKey(msg)
+	case modeExportConfirm:
+		return m.handleExportConfirmKey(msg)
 	default:
 		return m.handleNormalKey(msg)
 	}
@@ -129,17 +186,54 @@ func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.searchInput.Focus()
 		return m, textinput.Blink
 	case key.Matches(msg, keys.FilterTool):
-		m.mode = modeFilterTool
-		m.toolCursor = 0
+		return m.openFilter(filterByTool)
+	case key.Matches(msg, keys.FilterSeverity):
+		return m.openFilter(filterBySeverity)
+	case key.Matches(msg, keys.FilterCategory):
+		return m.openFilter(filterByCategory)
+	case key.Matches(msg, keys.FilterOwner):
+		return m.openFilter(filterByOwner)
+	case key.Matches(msg, keys.Group):
+		m.toggleGrouping()
+		return m, nil
+	case key.Matches(msg, keys.Expand) && m.grouped:
+		m.setExpanded(true)
 		return m, nil
+	case key.Matches(msg, keys.Collapse) && m.grouped:
+		m.setExpanded(false)
+		return m, nil
+	case key.Matches(msg, keys.Presets):
+		return m.openPresets()
+	case key.Matches(msg, keys.SavePreset):
+		return m.startSavePreset()
 	case key.Matches(msg, keys.Sort):
 		m.sortBy = (m.sortBy + 1) % sortField(sortFieldCount)
 		m.rebuildTable()
 		m.statusMsg = fmt.Sprintf("Sort: %s", sortFieldName(m.sortBy))
 		return m, nil
+	case key.Matches(msg, keys.Export):
+		return m.startExport()
 	case key.Matches(msg, keys.Copy):
 		m.copySelectedIssue()
 		return m, nil
+	case key.Matches(msg, keys.Incidents):
+		m.toggleIncidentFilter()
+		return m, nil
+	case key.Matches(msg, keys.Acknowledge):
+		return m.startTriage(triage.ActionAcknowledge)
+	case key.Matches(msg, keys.Suppress):
+		return m.startTriage(triage.ActionSuppress)
+	case key.Matches(msg, keys.Assign):
+		return m.startTriage(triage.ActionAssign)
+	case key.Matches(msg, keys.Reopen):
+		return m.startTriage(triage.ActionReopen)
+	case key.Matches(msg, keys.History):
+		return m.openRuns()
+	case key.Matches(msg, keys.Timeline):
+		if m.toggleExpanded() {
+			return m, nil
+		}
+		return m.openTimeline()
 	case key.Matches(msg, keys.ClearFilter):
 		m.filters = filterState{}
 		m.statusMsg = ""
@@ -172,26 +266,47 @@ func (m Model) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m Model) handleFilterToolKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+// openFilter shows the value picker for a filter field.
+func (m Model) openFilter(field filterField) (tea.Model, tea.Cmd) {
+	m.mode = modeFilter
+	m.filterField = field
+	m.pickCursor = 0
+	current := m.filters.get(field)
+	for i, c := range m.filterChoices() {
+		if c == current {
+			m.pickCursor = i + 1
+		}
+	}
+	return m, nil
+}
+
+// filterChoices returns the values offered for the field being picked.
+func (m *Model) filterChoices() []string {
+	return filterChoices(m.allIssues, m.filterField)
+}
+
+func (m Model) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	choices := m.filterChoices()
 	switch msg.String() {
 	case "up", "k":
-		if m.toolCursor > 0 {
-			m.toolCursor--
+		if m.pickCursor > 0 {
+			m.pickCursor--
 		}
 	case "down", "j":
-		if m.toolCursor < len(m.toolChoices) {
-			m.toolCursor++
+		if m.pickCursor < len(choices) {
+			m.pickCursor++
 		}
 	case "enter":
-		if m.toolCursor == 0 {
-			m.filters.Tool = ""
-		} else if m.toolCursor <= len(m.toolChoices) {
-			m.filters.Tool = m.toolChoices[m.toolCursor-1]
+		value := ""
+		if m.pickCursor > 0 && m.pickCursor <= len(choices) {
+			value = choices[m.pickCursor-1]
 		}
+		m.filters.set(m.filterField, value)
 		m.mode = modeNormal
 		m.rebuildTable()
-		if m.filters.Tool != "" {
-			m.statusMsg = fmt.Sprintf("Filter: %s", m.filters.Tool)
+		m.table.SetCursor(0)
+		if d := m.filters.describe(); d != "" {
+			m.statusMsg = fmt.Sprintf("Filter: %s", d)
 		} else {
 			m.statusMsg = ""
 		}
@@ -202,13 +317,79 @@ func (m Model) handleFilterToolKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) rebuildTable() {
+	m.syncOwners()
 	filtered := applyFilters(m.allIssues, m.filters)
 	sortIssues(filtered, m.sortBy)
 	m.filteredIssues = filtered
-	m.table.SetRows(buildRows(filtered))
+
+	var columns []table.Column
+	var rows []table.Row
+	if m.grouped {
+		m.treeRows = buildTree(filtered, m.expanded)
+		columns = groupColumns
+		rows = buildTreeTableRows(m.treeRows, filtered, m.expanded, m.issueStatus)
+	} else {
+		m.treeRows = nil
+		columns = tableColumns
+		rows = buildRows(filtered, m.issueStatus)
+	}
+	cursor := m.table.Cursor()
+	if len(m.table.Columns()) != len(columns) {
+		// Clear rows first so the table never renders rows against the
+		// columns of the other layout.
+		m.table.SetRows(nil)
+		m.table.SetColumns(columns)
+	}
+	m.table.SetRows(rows)
+	m.table.SetCursor(cursor)
+}
+
+// syncOwners copies owners from the triage store onto the issues so the
+// owner filter sees assignments made in this session.
+func (m *Model) syncOwners() {
+	if m.triage == nil {
+		return
+	}
+	for i := range m.allIssues {
+		if r := m.triage.Lookup(m.allIssues[i]); r != nil {
+			m.allIssues[i].Owner = r.Owner
+		}
+	}
+}
+
+// toggleIncidentFilter restricts the table to issues linked into a
+// correlated incident, or lifts that restriction.
+func (m *Model) toggleIncidentFilter() {
+	if m.filters.Incidents != nil {
+		m.filters.Incidents = nil
+		m.statusMsg = ""
+	} else if len(m.incidents) == 0 {
+		m.statusMsg = "No correlated incidents"
+		return
+	} else {
+		m.filters.Incidents = incidentKeySet(m.incidents)
+		m.statusMsg = fmt.Sprintf("Filter: %d incidents", len(m.report.Incidents))
+	}
+	m.rebuildTable()
+}
+
+// selectedIncident returns the incident the selected issue belongs to.
+func (m *Model) selectedIncident() *models.Incident {
+	issue := m.selectedIssue()
+	if issue == nil {
+		return nil
+	}
+	return m.incidents[issueKey(*issue)]
 }
 
 func (m *Model) selectedIssue() *models.NormalizedIssue {
+	if m.grouped {
+		row := m.selectedTreeRow()
+		if row == nil || row.issue < 0 {
+			return nil
+		}
+		return &m.filteredIssues[row.issue]
+	}
 	cursor := m.table.Cursor()
 	if cursor < 0 || cursor >= len(m.filteredIssues) {
 		return nil
@@ -245,6 +426,22 @@ func (m Model) View() string {
 	b.WriteString(renderHeader(m.report.Summary, m.report.Trend, sparkline, m.width))
 	b.WriteString("\n")
 
+	// History views replace the table and detail panel
+	switch m.mode {
+	case modeRuns:
+		b.WriteString(m.renderRuns())
+		b.WriteString(m.renderFooter())
+		return b.String()
+	case modeTimeline:
+		b.WriteString(m.renderTimeline())
+		b.WriteString(m.renderFooter())
+		return b.String()
+	case modeDiff:
+		b.WriteString(m.renderDiff())
+		b.WriteString(m.renderFooter())
+		return b.String()
+	}
+
 	// Search bar overlay
 	if m.mode == modeSearch {
 		b.WriteString(styleSearchPrompt.Render("/ "))
@@ -252,9 +449,38 @@ func (m Model) View() string {
 		b.WriteString("\n")
 	}
 
-	// Tool filter overlay
-	if m.mode == modeFilterTool {
-		b.WriteString(m.renderToolFilter())
+	// Triage prompt overlay
+	if m.mode == modeTriage {
+		b.WriteString(styleSearchPrompt.Render(m.prompt.label()))
+		b.WriteString(m.promptInput.View())
+		b.WriteString("\n")
+	}
+
+	// Preset name prompt overlay
+	if m.mode == modePresetName {
+		b.WriteString(styleSearchPrompt.Render("Preset name: "))
+		b.WriteString(m.promptInput.View())
+		b.WriteString("\n")
+	}
+
+	// Export file prompt overlay
+	if m.mode == modeExport {
+		b.WriteString(styleSearchPrompt.Render("Export to (.csv, .json, .md, .sarif): "))
+		b.WriteString(m.promptInput.View())
+		b.WriteString("\n")
+	}
+	if m.mode == modeExportConfirm {
+		b.WriteString(styleSearchPrompt.Render(fmt.Sprintf("%s already exists. Overwrite? (y/n) ", strings.Join(m.overwrite, " and "))))
+		b.WriteString("\n")
+	}
+
+	// Filter and preset picker overlays
+	if m.mode == modeFilter {
+		b.WriteString(m.renderFilterPicker())
+		b.WriteString("\n")
+	}
+	if m.mode == modePresets {
+		b.WriteString(m.renderPresets())
 		b.WriteString("\n")
 	}
 
@@ -263,7 +489,7 @@ func (m Model) View() string {
 	b.WriteString("\n")
 
 	// Detail panel
-	b.WriteString(renderDetail(m.selectedIssue(), m.width))
+	b.WriteString(renderDetail(m.selectedIssue(), m.selectedIncident(), renderTriage(m.selectedRecord(), m.now()), m.width))
 	b.WriteString("\n")
 
 	// Footer
@@ -272,14 +498,14 @@ func (m Model) View() string {
 	return b.String()
 }
 
-func (m *Model) renderToolFilter() string {
+func (m *Model) renderFilterPicker() string {
 	var b strings.Builder
-	b.WriteString("Filter by tool:\n")
+	b.WriteString(fmt.Sprintf("Filter by %s:\n", m.filterField.name()))
 
-	options := append([]string{"All"}, m.toolChoices...)
+	options := append([]string{"All"}, m.filterChoices()...)
 	for i, opt := range options {
 		cursor := "  "
-		if i == m.toolCursor {
+		if i == m.pickCursor {
 			cursor = "> "
 		}
 		b.WriteString(fmt.Sprintf("%s%s\n", cursor, opt))
@@ -288,8 +514,28 @@ func (m *Model) renderToolFilter() string {
 }
 
 func (m *Model) renderFooter() string {
-	left := "q:quit  /:search  t:tool  s:sort  c:copy  esc:clear"
+	var left string
+	switch m.mode {
+	case modeRuns:
+		left = "↑↓:move  enter:open  space:mark  d:diff  esc:back"
+	case modeTimeline:
+		left = "esc:back  q:quit"
+	case modeDiff:
+		left = "↑↓:scroll  esc:back  q:quit"
+	case modePresets:
+		left = "↑↓:move  enter:apply  d:delete  esc:back"
+	case modeExportConfirm:
+		left = "y:overwrite  n:other name  esc:cancel"
+	default:
+		left = "q:quit  /:search  t/v/C/O:filter  g:group  p/P:presets  s:sort  e:export  c:copy  i:incidents  enter:timeline  h:history  a:ack  x:suppress  o:owner  u:reopen  esc:clear"
+		if m.grouped {
+			left = "q:quit  /:search  t/v/C/O:filter  g:ungroup  ←→/enter:fold  p/P:presets  s:sort  e:export  c:copy  a:ack  x:suppress  o:owner  u:reopen  esc:clear"
+		}
+	}
 	right := fmt.Sprintf("%d/%d issues", len(m.filteredIssues), len(m.allIssues))
+	if n := len(m.report.Incidents); n > 0 {
+		right += fmt.Sprintf("  %d incidents", n)
+	}
 
 	if m.statusMsg != "" {
 		right = m.statusMsg + "  " + right
@@ -303,9 +549,27 @@ func (m *Model) renderFooter() string {
 	return styleFooter.Render(left + strings.Repeat(" ", gap) + right)
 }
 
-// Run starts the Bubble Tea program. Called from the summarize command.
-func Run(report *models.AggregatedReport, trend *models.TrendSummary) error {
-	m := New(report, trend)
+// Options are the optional stores the TUI reads and writes.
+type Options struct {
+	// Triage receives triage decisions; nil makes triage read-only.
+	Triage *triage.Store
+	// Presets are the saved filter presets.
+	Presets []config.FilterPreset
+	// SavePresets persists the presets after a change; nil keeps changes
+	// for the session.
+	SavePresets func([]config.FilterPreset) error
+}
+
+// Run starts the Bubble Tea program. Called from the summarize command
+// with the stored runs, oldest first; the latest run is shown.
+func Run(runs []*models.AggregatedReport, trend *models.TrendSummary, opts Options) error {
+	if len(runs) == 0 {
+		return fmt.Errorf("no runs to show")
+	}
+	m := New(runs[len(runs)-1], trend).withHistory(runs).withPresets(opts.Presets, opts.SavePresets)
+	if opts.Triage != nil {
+		m = m.withTriage(opts.Triage)
+	}
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	_, err := p.Run()
 	return err
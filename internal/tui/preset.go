@@ -0,0 +1,171 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ppiankov/spectrehub/internal/config"
+)
+
+// withPresets attaches saved filter presets. save persists the full list
+// after a preset is added or deleted; nil keeps changes for the session.
+func (m Model) withPresets(presets []config.FilterPreset, save func([]config.FilterPreset) error) Model {
+	m.presets = append([]config.FilterPreset(nil), presets...)
+	m.savePresets = save
+	return m
+}
+
+// openPresets shows the preset picker.
+func (m Model) openPresets() (tea.Model, tea.Cmd) {
+	if len(m.presets) == 0 {
+		m.statusMsg = "No saved presets (P saves the current filters)"
+		return m, nil
+	}
+	m.presetCursor = 0
+	m.mode = modePresets
+	return m, nil
+}
+
+func (m Model) handlePresetsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.presetCursor > 0 {
+			m.presetCursor--
+		}
+	case "down", "j":
+		if m.presetCursor < len(m.presets)-1 {
+			m.presetCursor++
+		}
+	case "enter":
+		m.applyPreset(m.presets[m.presetCursor])
+		m.mode = modeNormal
+	case "d", "delete":
+		name := m.presets[m.presetCursor].Name
+		m.presets = append(m.presets[:m.presetCursor:m.presetCursor], m.presets[m.presetCursor+1:]...)
+		m.persistPresets("Deleted preset " + name)
+		if m.presetCursor >= len(m.presets) {
+			m.presetCursor = len(m.presets) - 1
+		}
+		if len(m.presets) == 0 {
+			m.mode = modeNormal
+		}
+	case "esc":
+		m.mode = modeNormal
+	}
+	return m, nil
+}
+
+// applyPreset replaces the active filters and grouping with a preset.
+func (m *Model) applyPreset(p config.FilterPreset) {
+	m.filters = filterState{
+		Tool:       p.Tool,
+		Severity:   p.Severity,
+		Category:   p.Category,
+		Owner:      p.Owner,
+		SearchText: p.Search,
+	}
+	m.searchInput.SetValue(p.Search)
+	m.grouped = p.Group
+	m.rebuildTable()
+	m.table.SetCursor(0)
+	m.statusMsg = "Preset " + p.Name
+	if d := m.filters.describe(); d != "" {
+		m.statusMsg += ": " + d
+	}
+}
+
+// startSavePreset prompts for the name to save the current filters under.
+func (m Model) startSavePreset() (tea.Model, tea.Cmd) {
+	m.promptInput.SetValue("")
+	m.promptInput.Focus()
+	m.mode = modePresetName
+	return m, textinput.Blink
+}
+
+func (m Model) handlePresetNameKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeNormal
+		m.promptInput.Blur()
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(m.promptInput.Value())
+		if name == "" {
+			m.statusMsg = "A preset name is required"
+			return m, nil
+		}
+		m.savePreset(name)
+		m.mode = modeNormal
+		m.promptInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.promptInput, cmd = m.promptInput.Update(msg)
+	return m, cmd
+}
+
+// savePreset stores the current filters and grouping under name,
+// replacing a preset of the same name.
+func (m *Model) savePreset(name string) {
+	p := config.FilterPreset{
+		Name:     name,
+		Tool:     m.filters.Tool,
+		Severity: m.filters.Severity,
+		Category: m.filters.Category,
+		Owner:    m.filters.Owner,
+		Search:   m.filters.SearchText,
+		Group:    m.grouped,
+	}
+	replaced := false
+	for i := range m.presets {
+		if m.presets[i].Name == name {
+			m.presets[i] = p
+			replaced = true
+		}
+	}
+	if !replaced {
+		m.presets = append(m.presets, p)
+	}
+	m.persistPresets("Saved preset " + name)
+}
+
+// persistPresets writes the presets through the save callback.
+func (m *Model) persistPresets(done string) {
+	if m.savePresets == nil {
+		m.statusMsg = done + " (this session only)"
+		return
+	}
+	if err := m.savePresets(append([]config.FilterPreset(nil), m.presets...)); err != nil {
+		m.statusMsg = fmt.Sprintf("Presets not saved: %v", err)
+		return
+	}
+	m.statusMsg = done
+}
+
+// presetSummary describes what a preset selects.
+func presetSummary(p config.FilterPreset) string {
+	d := filterState{Tool: p.Tool, Severity: p.Severity, Category: p.Category, Owner: p.Owner, SearchText: p.Search}.describe()
+	if d == "" {
+		d = "all issues"
+	}
+	if p.Group {
+		d += " [grouped]"
+	}
+	return d
+}
+
+func (m *Model) renderPresets() string {
+	var b strings.Builder
+	b.WriteString("Apply preset:\n")
+	for i, p := range m.presets {
+		cursor := "  "
+		if i == m.presetCursor {
+			cursor = "> "
+		}
+		b.WriteString(fmt.Sprintf("%s%-20s %s\n", cursor, p.Name, presetSummary(p)))
+	}
+	return b.String()
+}
@@ -12,20 +12,27 @@ var tableColumns = []table.Column{
 	{Title: "Severity", Width: 10},
 	{Title: "Tool", Width: 14},
 	{Title: "Category", Width: 12},
-	{Title: "Resource", Width: 36},
+	{Title: "Resource", Width: 28},
 	{Title: "Count", Width: 6},
+	{Title: "Status", Width: 10},
 }
 
-// buildRows converts normalized issues to table rows.
-func buildRows(issues []models.NormalizedIssue) []table.Row {
+// buildRows converts normalized issues to table rows. status returns the
+// triage status of an issue; nil uses the status recorded on the issue.
+func buildRows(issues []models.NormalizedIssue, status func(models.NormalizedIssue) string) []table.Row {
 	rows := make([]table.Row, 0, len(issues))
 	for _, issue := range issues {
+		st := issue.Triage
+		if status != nil {
+			st = status(issue)
+		}
 		rows = append(rows, table.Row{
 			severityLabel(issue.Severity),
 			issue.Tool,
 			issue.Category,
 			truncate(issue.Resource, tableColumns[3].Width),
 			fmt.Sprintf("%d", issue.Count),
+			statusLabel(st),
 		})
 	}
 	return rows
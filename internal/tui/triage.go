@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/triage"
+)
+
+// triagePrompt is the question asked at each step of a triage action.
+type triagePrompt struct {
+	action string // triage.Action*
+	step   int    // suppress asks for a reason, then an expiry
+	reason string // answer to the first suppress step
+	issue  models.NormalizedIssue
+}
+
+// label returns the prompt text for the current step.
+func (p triagePrompt) label() string {
+	switch p.action {
+	case triage.ActionAcknowledge:
+		return "Acknowledge note: "
+	case triage.ActionSuppress:
+		if p.step == 0 {
+			return "Suppress reason: "
+		}
+		return "Expires (30d, 12h, YYYY-MM-DD, empty=never): "
+	case triage.ActionAssign:
+		return "Owner: "
+	default:
+		return "> "
+	}
+}
+
+// withTriage attaches a triage store so findings can be acknowledged,
+// suppressed and assigned from the table.
+func (m Model) withTriage(store *triage.Store) Model {
+	m.triage = store
+	m.rebuildTable()
+	return m
+}
+
+// issueStatus returns the triage status shown for an issue.
+func (m *Model) issueStatus(issue models.NormalizedIssue) string {
+	if m.triage != nil {
+		return m.triage.Status(issue, m.now())
+	}
+	if issue.Triage != "" {
+		return issue.Triage
+	}
+	return models.TriageOpen
+}
+
+// selectedRecord returns the triage record of the selected issue.
+func (m *Model) selectedRecord() *triage.Record {
+	issue := m.selectedIssue()
+	if issue == nil {
+		return nil
+	}
+	return m.triage.Lookup(*issue)
+}
+
+// startTriage opens the prompt for a triage action on the selected issue.
+// Reopen needs no input and is applied immediately.
+func (m Model) startTriage(action string) (tea.Model, tea.Cmd) {
+	if m.triage == nil {
+		m.statusMsg = "Triage unavailable: no storage directory"
+		return m, nil
+	}
+	issue := m.selectedIssue()
+	if issue == nil {
+		m.statusMsg = "No issue selected"
+		return m, nil
+	}
+
+	if action == triage.ActionReopen {
+		m.triage.Reopen(*issue, m.now())
+		m.saveTriage("Reopened")
+		return m, nil
+	}
+
+	m.prompt = triagePrompt{action: action, issue: *issue}
+	m.promptInput.SetValue("")
+	if action == triage.ActionAssign {
+		if r := m.triage.Lookup(*issue); r != nil {
+			m.promptInput.SetValue(r.Owner)
+		}
+	}
+	m.promptInput.Focus()
+	m.mode = modeTriage
+	return m, textinput.Blink
+}
+
+func (m Model) handleTriageKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeNormal
+		m.promptInput.Blur()
+		m.statusMsg = "Triage cancelled"
+		return m, nil
+	case "enter":
+		return m.submitTriage()
+	}
+
+	var cmd tea.Cmd
+	m.promptInput, cmd = m.promptInput.Update(msg)
+	return m, cmd
+}
+
+// submitTriage records the answer to the current prompt step.
+func (m Model) submitTriage() (tea.Model, tea.Cmd) {
+	value := strings.TrimSpace(m.promptInput.Value())
+	p := m.prompt
+	now := m.now()
+
+	switch p.action {
+	case triage.ActionAcknowledge:
+		m.triage.Acknowledge(p.issue, value, now)
+		m.saveTriage("Acknowledged")
+	case triage.ActionAssign:
+		m.triage.Assign(p.issue, value, now)
+		if value == "" {
+			m.saveTriage("Owner cleared")
+		} else {
+			m.saveTriage("Assigned to " + value)
+		}
+	case triage.ActionSuppress:
+		if p.step == 0 {
+			if value == "" {
+				m.statusMsg = "A reason is required to suppress"
+				return m, nil
+			}
+			m.prompt.reason = value
+			m.prompt.step = 1
+			m.promptInput.SetValue("")
+			return m, nil
+		}
+		expires, err := triage.ParseExpiry(value, now)
+		if err != nil {
+			m.statusMsg = err.Error()
+			return m, nil
+		}
+		m.triage.Suppress(p.issue, p.reason, expires, now)
+		if expires.IsZero() {
+			m.saveTriage("Suppressed")
+		} else {
+			m.saveTriage("Suppressed until " + expires.Format("2006-01-02"))
+		}
+	}
+
+	m.mode = modeNormal
+	m.promptInput.Blur()
+	return m, nil
+}
+
+// saveTriage persists the store and refreshes the status column.
+func (m *Model) saveTriage(done string) {
+	if err := m.triage.Save(); err != nil {
+		m.statusMsg = fmt.Sprintf("Triage not saved: %v", err)
+	} else {
+		m.statusMsg = done
+	}
+	m.rebuildTable()
+}
+
+// renderTriage renders the triage state and recent history of a record.
+func renderTriage(record *triage.Record, now time.Time) string {
+	if record == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Status: %s", record.StatusAt(now)))
+	if record.Owner != "" {
+		b.WriteString(fmt.Sprintf("  Owner: %s", record.Owner))
+	}
+	if record.Expires != nil {
+		b.WriteString(fmt.Sprintf("  Until: %s", record.Expires.Format("2006-01-02")))
+	}
+
+	const maxHistory = 3
+	history := record.History
+	if len(history) > maxHistory {
+		history = history[len(history)-maxHistory:]
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		e := history[i]
+		line := fmt.Sprintf("\n  %s %s", e.Time.Format("2006-01-02"), e.Action)
+		if e.By != "" {
+			line += " by " + e.By
+		}
+		switch {
+		case e.Owner != "":
+			line += ": " + e.Owner
+		case e.Note != "":
+			line += ": " + e.Note
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// statusLabel returns the short status shown in the table.
+func statusLabel(status string) string {
+	switch status {
+	case models.TriageAcknowledged:
+		return "ack"
+	case models.TriageSuppressed:
+		return "suppressed"
+	case "", models.TriageOpen:
+		return "open"
+	default:
+		return status
+	}
+}
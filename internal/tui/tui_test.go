@@ -1,12 +1,17 @@
 package tui
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ppiankov/spectrehub/internal/config"
 	"github.com/ppiankov/spectrehub/internal/models"
+	"github.com/ppiankov/spectrehub/internal/triage"
 )
 
 func testIssues() []models.NormalizedIssue {
@@ -176,7 +181,7 @@ func TestUniqueToolsEmpty(t *testing.T) {
 
 func TestBuildRows(t *testing.T) {
 	issues := testIssues()
-	rows := buildRows(issues)
+	rows := buildRows(issues, nil)
 	if len(rows) != len(issues) {
 		t.Errorf("expected %d rows, got %d", len(issues), len(rows))
 	}
@@ -189,7 +194,7 @@ func TestBuildRows(t *testing.T) {
 }
 
 func TestBuildRowsEmpty(t *testing.T) {
-	rows := buildRows(nil)
+	rows := buildRows(nil, nil)
 	if len(rows) != 0 {
 		t.Errorf("expected 0 rows, got %d", len(rows))
 	}
@@ -294,7 +299,7 @@ func TestRenderHeaderSeverityBreakdown(t *testing.T) {
 // --- Detail rendering tests ---
 
 func TestRenderDetailNil(t *testing.T) {
-	output := renderDetail(nil, 80)
+	output := renderDetail(nil, nil, "", 80)
 	if !strings.Contains(output, "No issue selected") {
 		t.Error("expected 'No issue selected' for nil issue")
 	}
@@ -307,7 +312,7 @@ func TestRenderDetailShowsFields(t *testing.T) {
 		FirstSeen: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
 		LastSeen:  time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC),
 	}
-	output := renderDetail(issue, 80)
+	output := renderDetail(issue, nil, "", 80)
 	if !strings.Contains(output, "key not found") {
 		t.Error("expected evidence in detail")
 	}
@@ -325,12 +330,32 @@ func TestRenderDetailShowsFields(t *testing.T) {
 	}
 }
 
+func TestRenderDetailShowsSpectreV11Metadata(t *testing.T) {
+	confidence := 0.75
+	issue := &models.NormalizedIssue{
+		Tool: "awsspectre", Category: "unused", Severity: "medium", Resource: "i-1",
+		Attributes:  &models.ResourceAttributes{Account: "123", Region: "us-east-1", Tags: map[string]string{"team": "core", "env": "prod"}},
+		Remediation: "stop the instance",
+		Confidence:  &confidence,
+	}
+	output := renderDetail(issue, nil, "", 120)
+	for _, want := range []string{
+		"Where: account=123, region=us-east-1, tag:env=prod, tag:team=core",
+		"Fix: stop the instance",
+		"Confidence: 75%",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in detail, got:\n%s", want, output)
+		}
+	}
+}
+
 func TestRenderDetailNoEvidence(t *testing.T) {
 	issue := &models.NormalizedIssue{
 		Tool: "s3spectre", Category: "unused", Severity: "low",
 		Resource: "s3://bucket", Count: 2,
 	}
-	output := renderDetail(issue, 80)
+	output := renderDetail(issue, nil, "", 80)
 	if !strings.Contains(output, "s3://bucket") {
 		t.Error("expected resource in detail")
 	}
@@ -469,8 +494,8 @@ func TestModelEnterFilterTool(t *testing.T) {
 	m := New(testReport(), nil)
 	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
 	model := updated.(Model)
-	if model.mode != modeFilterTool {
-		t.Errorf("expected modeFilterTool, got %d", model.mode)
+	if model.mode != modeFilter {
+		t.Errorf("expected modeFilter, got %d", model.mode)
 	}
 }
 
@@ -522,7 +547,7 @@ func TestModelSearchEscape(t *testing.T) {
 
 func TestModelFilterToolEscape(t *testing.T) {
 	m := New(testReport(), nil)
-	m.mode = modeFilterTool
+	m.mode = modeFilter
 
 	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEscape})
 	model := updated.(Model)
@@ -533,50 +558,50 @@ func TestModelFilterToolEscape(t *testing.T) {
 
 func TestModelFilterToolNavigate(t *testing.T) {
 	m := New(testReport(), nil)
-	m.mode = modeFilterTool
-	m.toolCursor = 0
+	m.mode = modeFilter
+	m.pickCursor = 0
 
 	// Move down
 	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
 	model := updated.(Model)
-	if model.toolCursor != 1 {
-		t.Errorf("expected cursor 1 after down, got %d", model.toolCursor)
+	if model.pickCursor != 1 {
+		t.Errorf("expected cursor 1 after down, got %d", model.pickCursor)
 	}
 
 	// Move up
 	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyUp})
 	model = updated.(Model)
-	if model.toolCursor != 0 {
-		t.Errorf("expected cursor 0 after up, got %d", model.toolCursor)
+	if model.pickCursor != 0 {
+		t.Errorf("expected cursor 0 after up, got %d", model.pickCursor)
 	}
 
 	// Can't go above 0
 	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyUp})
 	model = updated.(Model)
-	if model.toolCursor != 0 {
-		t.Errorf("expected cursor stays at 0, got %d", model.toolCursor)
+	if model.pickCursor != 0 {
+		t.Errorf("expected cursor stays at 0, got %d", model.pickCursor)
 	}
 }
 
 func TestModelFilterToolSelect(t *testing.T) {
 	m := New(testReport(), nil)
-	m.mode = modeFilterTool
-	m.toolCursor = 1 // first actual tool (index 0 = "All")
+	m.mode = modeFilter
+	m.pickCursor = 1 // first actual tool (index 0 = "All")
 
 	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
 	model := updated.(Model)
 	if model.mode != modeNormal {
 		t.Errorf("expected modeNormal after enter, got %d", model.mode)
 	}
-	if model.filters.Tool != m.toolChoices[0] {
-		t.Errorf("expected tool filter %q, got %q", m.toolChoices[0], model.filters.Tool)
+	if model.filters.Tool != m.filterChoices()[0] {
+		t.Errorf("expected tool filter %q, got %q", m.filterChoices()[0], model.filters.Tool)
 	}
 }
 
 func TestModelFilterToolSelectAll(t *testing.T) {
 	m := New(testReport(), nil)
-	m.mode = modeFilterTool
-	m.toolCursor = 0 // "All"
+	m.mode = modeFilter
+	m.pickCursor = 0 // "All"
 
 	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
 	model := updated.(Model)
@@ -616,7 +641,7 @@ func TestModelViewSearchMode(t *testing.T) {
 
 func TestModelViewFilterMode(t *testing.T) {
 	m := New(testReport(), nil)
-	m.mode = modeFilterTool
+	m.mode = modeFilter
 	output := m.View()
 	if !strings.Contains(output, "Filter by tool:") {
 		t.Error("expected tool filter list in view")
@@ -712,3 +737,855 @@ func TestModelDoesNotMutateOriginal(t *testing.T) {
 		t.Errorf("original report mutated: expected %d, got %d", originalLen, len(report.Issues))
 	}
 }
+
+// --- Incident tests ---
+
+func incidentReport() *models.AggregatedReport {
+	report := testReport()
+	issues := report.Issues
+	report.Incidents = []models.Incident{{
+		ID:     "missing-secret-mount:db",
+		Rule:   "missing-secret-mount",
+		Title:  "Secret db is missing in Vault and mounted but unused in Kubernetes",
+		Tools:  []string{"kubespectre", "vaultspectre"},
+		Issues: []models.NormalizedIssue{issues[0], issues[1]},
+	}}
+	return report
+}
+
+func TestModelToggleIncidentFilter(t *testing.T) {
+	m := New(incidentReport(), nil)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	model := updated.(Model)
+	if len(model.filteredIssues) != 2 {
+		t.Fatalf("expected 2 incident issues, got %d", len(model.filteredIssues))
+	}
+	if !strings.Contains(model.statusMsg, "1 incidents") {
+		t.Errorf("unexpected status %q", model.statusMsg)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	model = updated.(Model)
+	if len(model.filteredIssues) != 4 {
+		t.Errorf("expected all issues after second toggle, got %d", len(model.filteredIssues))
+	}
+}
+
+func TestModelToggleIncidentFilterNoIncidents(t *testing.T) {
+	m := New(testReport(), nil)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	model := updated.(Model)
+	if model.filters.Incidents != nil || len(model.filteredIssues) != 4 {
+		t.Error("expected no filter when there are no incidents")
+	}
+	if model.statusMsg != "No correlated incidents" {
+		t.Errorf("unexpected status %q", model.statusMsg)
+	}
+}
+
+func TestModelSelectedIncident(t *testing.T) {
+	m := New(incidentReport(), nil)
+	// Initial sort puts the critical vaultspectre issue first.
+	inc := m.selectedIncident()
+	if inc == nil || inc.Rule != "missing-secret-mount" {
+		t.Fatalf("expected selected issue to belong to incident, got %+v", inc)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Incident: Secret db") {
+		t.Error("expected incident in detail panel")
+	}
+	if !strings.Contains(view, "1 incidents") {
+		t.Error("expected incident count in footer")
+	}
+}
+
+func TestRenderDetailWithIncident(t *testing.T) {
+	issue := &models.NormalizedIssue{Tool: "s3spectre", Category: "unused", Severity: "low", Resource: "s3://b"}
+	inc := &models.Incident{Title: "Unused bucket b", Tools: []string{"iamspectre", "s3spectre"}}
+	output := renderDetail(issue, inc, "", 120)
+	if !strings.Contains(output, "Incident: Unused bucket b [iamspectre, s3spectre]") {
+		t.Errorf("expected incident line, got %q", output)
+	}
+}
+
+// --- Triage tests ---
+
+func triageModel(t *testing.T) (Model, *triage.Store) {
+	t.Helper()
+	store, err := triage.OpenDir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New(testReport(), nil).withTriage(store)
+	m.now = func() time.Time { return time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC) }
+	return m, store
+}
+
+func typeText(m Model, text string) Model {
+	for _, r := range text {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	return m
+}
+
+func press(m Model, msg tea.KeyMsg) Model {
+	updated, _ := m.Update(msg)
+	return updated.(Model)
+}
+
+func TestTriageAcknowledge(t *testing.T) {
+	m, store := triageModel(t)
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if m.mode != modeTriage {
+		t.Fatalf("expected modeTriage, got %d", m.mode)
+	}
+	m = typeText(m, "known, ticket OPS-1")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.mode != modeNormal || m.statusMsg != "Acknowledged" {
+		t.Fatalf("unexpected state: mode=%d status=%q", m.mode, m.statusMsg)
+	}
+	r := store.Lookup(m.filteredIssues[0])
+	if r == nil || r.Status != models.TriageAcknowledged || r.Note != "known, ticket OPS-1" {
+		t.Fatalf("unexpected record: %+v", r)
+	}
+	if got := m.table.Rows()[0][5]; got != "ack" {
+		t.Errorf("status column = %q, want ack", got)
+	}
+
+	// Decisions are persisted immediately.
+	reloaded, err := triage.Open(store.Path())
+	if err != nil || reloaded.Lookup(m.filteredIssues[0]) == nil {
+		t.Errorf("expected record on disk, err=%v", err)
+	}
+}
+
+func TestTriageSuppressTwoSteps(t *testing.T) {
+	m, store := triageModel(t)
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeTriage || !strings.Contains(m.statusMsg, "reason is required") {
+		t.Fatalf("expected reason to be required, status=%q", m.statusMsg)
+	}
+
+	m = typeText(m, "accepted risk")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.prompt.step != 1 || !strings.Contains(m.View(), "Expires") {
+		t.Fatalf("expected expiry prompt, step=%d", m.prompt.step)
+	}
+
+	m = typeText(m, "soon")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeTriage || !strings.Contains(m.statusMsg, "invalid expiry") {
+		t.Fatalf("expected invalid expiry to keep the prompt open, status=%q", m.statusMsg)
+	}
+
+	m.promptInput.SetValue("30d")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeNormal || m.statusMsg != "Suppressed until 2026-03-31" {
+		t.Fatalf("unexpected state: mode=%d status=%q", m.mode, m.statusMsg)
+	}
+	r := store.Lookup(m.filteredIssues[0])
+	if r == nil || r.Status != models.TriageSuppressed || r.Note != "accepted risk" || r.Expires == nil {
+		t.Fatalf("unexpected record: %+v", r)
+	}
+}
+
+func TestTriageAssignAndReopen(t *testing.T) {
+	m, store := triageModel(t)
+	issue := m.filteredIssues[0]
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	m = typeText(m, "bob")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.statusMsg != "Assigned to bob" || store.Lookup(issue).Owner != "bob" {
+		t.Fatalf("assign failed: status=%q", m.statusMsg)
+	}
+
+	// The owner prompt is prefilled with the current owner.
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	if m.promptInput.Value() != "bob" {
+		t.Errorf("expected prefilled owner, got %q", m.promptInput.Value())
+	}
+	m = press(m, tea.KeyMsg{Type: tea.KeyEscape})
+	if m.mode != modeNormal || m.statusMsg != "Triage cancelled" {
+		t.Errorf("expected cancel, status=%q", m.statusMsg)
+	}
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	if m.statusMsg != "Reopened" {
+		t.Errorf("expected reopen, status=%q", m.statusMsg)
+	}
+	r := store.Lookup(issue)
+	if r.Status != models.TriageOpen || len(r.History) != 2 {
+		t.Errorf("unexpected record after reopen: %+v", r)
+	}
+}
+
+func TestTriageDetailShowsHistory(t *testing.T) {
+	m, _ := triageModel(t)
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = typeText(m, "looking into it")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m.width = 140
+
+	view := m.View()
+	for _, frag := range []string{"Status: acknowledged", "2026-03-01 acknowledge", "looking into it"} {
+		if !strings.Contains(view, frag) {
+			t.Errorf("expected detail panel to contain %q", frag)
+		}
+	}
+}
+
+func TestTriageWithoutStore(t *testing.T) {
+	m := New(testReport(), nil)
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if m.mode != modeNormal || !strings.Contains(m.statusMsg, "unavailable") {
+		t.Errorf("expected triage to be unavailable, mode=%d status=%q", m.mode, m.statusMsg)
+	}
+}
+
+func TestModelListsSuppressedIssues(t *testing.T) {
+	report := testReport()
+	report.Suppressed = []models.NormalizedIssue{
+		{Tool: "pgspectre", Category: "unused", Severity: "low", Resource: "public.old", Triage: models.TriageSuppressed},
+	}
+	m := New(report, nil)
+	if len(m.allIssues) != 5 {
+		t.Fatalf("expected suppressed issue to be listed, got %d issues", len(m.allIssues))
+	}
+	var found bool
+	for _, row := range m.table.Rows() {
+		if row[3] == "public.old" && row[5] == "suppressed" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected suppressed status in table")
+	}
+}
+
+// --- History tests ---
+
+func historyRuns() []*models.AggregatedReport {
+	run := func(day int, score float64, issues ...models.NormalizedIssue) *models.AggregatedReport {
+		return &models.AggregatedReport{
+			Timestamp: time.Date(2026, 3, day, 10, 0, 0, 0, time.UTC),
+			Issues:    issues,
+			Summary:   models.CrossToolSummary{TotalIssues: len(issues), ScorePercent: score, HealthScore: "good"},
+		}
+	}
+	bucket := models.NormalizedIssue{Tool: "s3spectre", Category: "unused", Severity: "low", Resource: "s3://old"}
+	secret := models.NormalizedIssue{Tool: "vaultspectre", Category: "missing", Severity: "critical", Resource: "secret/db"}
+	topic := models.NormalizedIssue{Tool: "kafkaspectre", Category: "unused", Severity: "medium", Resource: "topic-a"}
+	escalated := bucket
+	escalated.Severity = "high"
+
+	return []*models.AggregatedReport{
+		run(1, 60, bucket, secret),
+		run(2, 70, bucket, topic),
+		run(3, 80, escalated, topic),
+	}
+}
+
+func TestHistoryShowsLatestRun(t *testing.T) {
+	runs := historyRuns()
+	m := New(runs[2], nil).withHistory(runs)
+	if m.report != runs[2] || len(m.allIssues) != 2 {
+		t.Fatalf("expected latest run to be shown, got %d issues", len(m.allIssues))
+	}
+}
+
+func TestHistoryRunBrowser(t *testing.T) {
+	runs := historyRuns()
+	m := New(runs[2], nil).withHistory(runs)
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+	if m.mode != modeRuns || m.history.cursor != 2 {
+		t.Fatalf("expected run browser at latest run, mode=%d cursor=%d", m.mode, m.history.cursor)
+	}
+	view := m.View()
+	for _, frag := range []string{"Runs: 3", "2026-03-01 10:00", "2026-03-03 10:00", "(shown)", "[60→80]"} {
+		if !strings.Contains(view, frag) {
+			t.Errorf("expected run browser to contain %q", frag)
+		}
+	}
+
+	// Open the first run.
+	m = press(m, tea.KeyMsg{Type: tea.KeyUp})
+	m = press(m, tea.KeyMsg{Type: tea.KeyUp})
+	m = press(m, tea.KeyMsg{Type: tea.KeyUp})
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeNormal || m.report != runs[0] || len(m.filteredIssues) != 2 {
+		t.Fatalf("expected first run in table, mode=%d issues=%d", m.mode, len(m.filteredIssues))
+	}
+	if m.statusMsg != "Run 2026-03-01 10:00" {
+		t.Errorf("statusMsg = %q", m.statusMsg)
+	}
+}
+
+func TestHistoryWithoutRuns(t *testing.T) {
+	m := New(testReport(), nil)
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+	if m.mode != modeNormal || m.statusMsg != "No run history" {
+		t.Errorf("expected no history, mode=%d status=%q", m.mode, m.statusMsg)
+	}
+}
+
+func TestHistoryDiffMarkedRuns(t *testing.T) {
+	runs := historyRuns()
+	m := New(runs[2], nil).withHistory(runs)
+	m.width = 120
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}}) // mark run 3
+	m = press(m, tea.KeyMsg{Type: tea.KeyUp})
+	m = press(m, tea.KeyMsg{Type: tea.KeyUp})
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}}) // mark run 1
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+
+	if m.mode != modeDiff || m.history.diffPair != [2]int{0, 2} {
+		t.Fatalf("expected diff of runs 0 and 2, mode=%d pair=%v", m.mode, m.history.diffPair)
+	}
+	d := m.history.diff
+	if d.Summary.NewCount != 1 || d.Summary.ResolvedCount != 1 {
+		t.Errorf("unexpected diff summary: %+v", d.Summary)
+	}
+
+	view := m.View()
+	for _, frag := range []string{"Resolved", "New", "+1 new  -1 resolved", "CRITICAL vaultspectre secret/db", "MEDIUM   kafkaspectre topic-a"} {
+		if !strings.Contains(view, frag) {
+			t.Errorf("expected diff view to contain %q", frag)
+		}
+	}
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyEscape})
+	if m.mode != modeRuns {
+		t.Errorf("expected esc to return to run browser, got %d", m.mode)
+	}
+}
+
+func TestHistoryDiffAgainstPreviousRun(t *testing.T) {
+	runs := historyRuns()
+	m := New(runs[2], nil).withHistory(runs)
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if m.mode != modeDiff || m.history.diffPair != [2]int{1, 2} {
+		t.Fatalf("expected diff against previous run, pair=%v", m.history.diffPair)
+	}
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyEscape})
+	m = press(m, tea.KeyMsg{Type: tea.KeyHome})
+	for i := 0; i < 3; i++ {
+		m = press(m, tea.KeyMsg{Type: tea.KeyUp})
+	}
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if m.mode != modeRuns || !strings.Contains(m.statusMsg, "Mark two runs") {
+		t.Errorf("expected hint for the oldest run, status=%q", m.statusMsg)
+	}
+}
+
+func TestToggleMarkKeepsTwo(t *testing.T) {
+	m := New(testReport(), nil)
+	m.toggleMark(0)
+	m.toggleMark(1)
+	m.toggleMark(2)
+	if len(m.history.marks) != 2 || m.history.marks[0] != 1 || m.history.marks[1] != 2 {
+		t.Errorf("marks = %v, want [1 2]", m.history.marks)
+	}
+	m.toggleMark(1)
+	if len(m.history.marks) != 1 || m.history.marks[0] != 2 {
+		t.Errorf("marks after unmark = %v, want [2]", m.history.marks)
+	}
+}
+
+func TestHistoryTimeline(t *testing.T) {
+	runs := historyRuns()
+	m := New(runs[2], nil).withHistory(runs)
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeTimeline {
+		t.Fatalf("expected timeline mode, got %d", m.mode)
+	}
+	view := m.View()
+	for _, frag := range []string{"Timeline: s3spectre unused s3://old", "2026-03-01 10:00  appeared (low)", "2026-03-03 10:00  severity changed (low → high)"} {
+		if !strings.Contains(view, frag) {
+			t.Errorf("expected timeline to contain %q", frag)
+		}
+	}
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyEscape})
+	if m.mode != modeNormal {
+		t.Errorf("expected esc to return to the table, got %d", m.mode)
+	}
+}
+
+func TestHistoryTimelineSingleReport(t *testing.T) {
+	m := New(testReport(), nil)
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeTimeline || len(m.history.timeline) != 1 || m.history.timeline[0].Event != "appeared" {
+		t.Errorf("expected single-run timeline, got %+v", m.history.timeline)
+	}
+}
+
+// --- Filter field, grouping and preset tests ---
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestApplyFiltersCategoryAndOwner(t *testing.T) {
+	issues := testIssues()
+	issues[0].Owner = "alice"
+
+	if got := applyFilters(issues, filterState{Category: "unused"}); len(got) != 2 {
+		t.Errorf("category filter: expected 2 issues, got %d", len(got))
+	}
+	got := applyFilters(issues, filterState{Owner: "alice"})
+	if len(got) != 1 || got[0].Resource != "secret/db" {
+		t.Errorf("owner filter: unexpected result %+v", got)
+	}
+	if got := applyFilters(issues, filterState{Owner: ownerUnassigned}); len(got) != 3 {
+		t.Errorf("unassigned filter: expected 3 issues, got %d", len(got))
+	}
+}
+
+func TestFilterDescribe(t *testing.T) {
+	f := filterState{Tool: "kubespectre", Severity: "critical", Category: "misconfig", Owner: "bob", SearchText: "prod"}
+	if got, want := f.describe(), `critical kubespectre misconfig owner:bob "prod"`; got != want {
+		t.Errorf("describe = %q, want %q", got, want)
+	}
+	if got := (filterState{}).describe(); got != "" {
+		t.Errorf("empty describe = %q", got)
+	}
+}
+
+func TestFilterChoices(t *testing.T) {
+	issues := testIssues()
+	issues[1].Owner = "bob"
+
+	if got := strings.Join(filterChoices(issues, filterBySeverity), ","); got != "critical,medium,low" {
+		t.Errorf("severity choices = %s", got)
+	}
+	if got := strings.Join(filterChoices(issues, filterByCategory), ","); got != "missing,stale,unused" {
+		t.Errorf("category choices = %s", got)
+	}
+	if got := strings.Join(filterChoices(issues, filterByOwner), ","); got != "bob,"+ownerUnassigned {
+		t.Errorf("owner choices = %s", got)
+	}
+}
+
+func TestSeverityFilterKey(t *testing.T) {
+	m := New(testReport(), nil)
+	m = press(m, runes("v"))
+	if m.mode != modeFilter || m.filterField != filterBySeverity {
+		t.Fatalf("expected severity picker, mode=%d field=%d", m.mode, m.filterField)
+	}
+	if !strings.Contains(m.View(), "Filter by severity:") {
+		t.Error("expected severity picker in view")
+	}
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown}) // critical
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.filters.Severity != "critical" || len(m.filteredIssues) != 1 {
+		t.Errorf("expected critical filter, got %q with %d issues", m.filters.Severity, len(m.filteredIssues))
+	}
+
+	// Filters combine and the status lists them all.
+	m = press(m, runes("t"))
+	if m.pickCursor != 0 {
+		t.Errorf("expected picker on All, got %d", m.pickCursor)
+	}
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown})
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown})
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown}) // vaultspectre
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.statusMsg != "Filter: critical vaultspectre" {
+		t.Errorf("statusMsg = %q", m.statusMsg)
+	}
+
+	// Reopening the picker starts on the active value.
+	m = press(m, runes("v"))
+	if m.pickCursor != 1 {
+		t.Errorf("expected cursor on active severity, got %d", m.pickCursor)
+	}
+}
+
+func TestOwnerFilterSeesSessionAssignments(t *testing.T) {
+	m, _ := triageModel(t)
+	m = press(m, runes("o"))
+	m = typeText(m, "carol")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	m = press(m, runes("O"))
+	if got := m.filterChoices(); len(got) != 2 || got[0] != "carol" {
+		t.Fatalf("owner choices = %v", got)
+	}
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown})
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.filteredIssues) != 1 || m.filteredIssues[0].Owner != "carol" {
+		t.Errorf("expected carol's issue, got %+v", m.filteredIssues)
+	}
+}
+
+func TestBuildTree(t *testing.T) {
+	issues := testIssues()
+	sortIssues(issues, sortBySeverity)
+
+	rows := buildTree(issues, map[string]bool{})
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 collapsed tool rows, got %d", len(rows))
+	}
+	vault := rows[2]
+	if vault.label != "vaultspectre" || vault.issues != 2 || vault.count != 2 || vault.severity != "critical" {
+		t.Errorf("unexpected vault node: %+v", vault)
+	}
+
+	expanded := map[string]bool{
+		"vaultspectre":                     true,
+		"vaultspectre/missing":             true,
+		"vaultspectre/missing/" + noRuleID: true,
+	}
+	rows = buildTree(issues, expanded)
+	var labels []string
+	for _, r := range rows {
+		labels = append(labels, strings.Repeat(".", r.depth)+r.label)
+	}
+	want := "kafkaspectre s3spectre vaultspectre .missing .." + noRuleID + " ...secret/db .stale"
+	if got := strings.Join(labels, " "); got != want {
+		t.Errorf("tree = %q, want %q", got, want)
+	}
+	if leaf := rows[5]; leaf.issue < 0 || issues[leaf.issue].Resource != "secret/db" {
+		t.Errorf("expected leaf to point at secret/db, got %+v", leaf)
+	}
+}
+
+func TestGroupedNavigation(t *testing.T) {
+	m := New(testReport(), nil)
+	m = press(m, runes("g"))
+	if !m.grouped || len(m.table.Rows()) != 3 {
+		t.Fatalf("expected 3 tool rows when grouped, got %d", len(m.table.Rows()))
+	}
+	if m.selectedIssue() != nil {
+		t.Error("a group row should not select an issue")
+	}
+	if !strings.Contains(m.View(), "▸ kafkaspectre (1)") {
+		t.Error("expected collapsed group with count in view")
+	}
+
+	// Expand kafkaspectre down to its resource.
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown})
+	m = press(m, tea.KeyMsg{Type: tea.KeyRight})
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown})
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown})
+	issue := m.selectedIssue()
+	if issue == nil || issue.Resource != "topic-old" {
+		t.Fatalf("expected topic-old selected, got %+v", issue)
+	}
+
+	// Enter on an issue opens its timeline.
+	if got := press(m, tea.KeyMsg{Type: tea.KeyEnter}); got.mode != modeTimeline {
+		t.Errorf("expected timeline for an issue row, got mode %d", got.mode)
+	}
+
+	// Left on an issue collapses its parent and moves there.
+	m = press(m, tea.KeyMsg{Type: tea.KeyLeft})
+	if c := m.table.Cursor(); c != 2 || m.treeRows[c].label != noRuleID || m.expanded["kafkaspectre/unused/"+noRuleID] {
+		t.Errorf("expected cursor on collapsed rule node, cursor=%d", c)
+	}
+
+	// Filters apply to the tree.
+	m.filters.Severity = "critical"
+	m.rebuildTable()
+	if len(m.table.Rows()) != 1 || m.treeRows[0].label != "vaultspectre" {
+		t.Errorf("expected only vaultspectre after filtering, got %d rows", len(m.table.Rows()))
+	}
+
+	m = press(m, runes("g"))
+	if m.grouped || len(m.table.Rows()) != 1 || len(m.table.Columns()) != len(tableColumns) {
+		t.Errorf("expected flat table after ungrouping")
+	}
+}
+
+func TestPresetSaveAndApply(t *testing.T) {
+	var saved []config.FilterPreset
+	m := New(testReport(), nil).withPresets(nil, func(p []config.FilterPreset) error {
+		saved = p
+		return nil
+	})
+
+	if got := press(m, runes("p")); got.mode != modeNormal || !strings.Contains(got.statusMsg, "No saved presets") {
+		t.Errorf("expected hint without presets, status=%q", got.statusMsg)
+	}
+
+	m.filters = filterState{Tool: "vaultspectre", Severity: "critical"}
+	m.grouped = true
+	m = press(m, runes("P"))
+	if m.mode != modePresetName || !strings.Contains(m.View(), "Preset name:") {
+		t.Fatalf("expected preset name prompt, mode=%d", m.mode)
+	}
+	m = typeText(m, "vault-crit")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	want := config.FilterPreset{Name: "vault-crit", Tool: "vaultspectre", Severity: "critical", Group: true}
+	if len(saved) != 1 || saved[0] != want {
+		t.Fatalf("saved = %+v, want %+v", saved, want)
+	}
+	if m.statusMsg != "Saved preset vault-crit" {
+		t.Errorf("statusMsg = %q", m.statusMsg)
+	}
+
+	// Clear, then re-apply from the picker.
+	m = press(m, tea.KeyMsg{Type: tea.KeyEscape})
+	m = press(m, runes("g"))
+	m = press(m, runes("p"))
+	if m.mode != modePresets || !strings.Contains(m.View(), "critical vaultspectre [grouped]") {
+		t.Fatalf("expected preset picker, mode=%d", m.mode)
+	}
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.filters.Tool != "vaultspectre" || m.filters.Severity != "critical" || !m.grouped {
+		t.Errorf("preset not applied: %+v grouped=%v", m.filters, m.grouped)
+	}
+	if m.statusMsg != "Preset vault-crit: critical vaultspectre" {
+		t.Errorf("statusMsg = %q", m.statusMsg)
+	}
+}
+
+func TestPresetDelete(t *testing.T) {
+	var calls int
+	presets := []config.FilterPreset{{Name: "a", Tool: "s3spectre"}, {Name: "b", Severity: "low"}}
+	m := New(testReport(), nil).withPresets(presets, func(p []config.FilterPreset) error {
+		calls++
+		if len(p) != 1 || p[0].Name != "b" {
+			t.Errorf("unexpected presets after delete: %+v", p)
+		}
+		return nil
+	})
+
+	m = press(m, runes("p"))
+	m = press(m, runes("d"))
+	if calls != 1 || len(m.presets) != 1 || m.mode != modePresets {
+		t.Errorf("expected one preset left, calls=%d presets=%d mode=%d", calls, len(m.presets), m.mode)
+	}
+	if presets[0].Name != "a" {
+		t.Error("deleting must not modify the caller's slice")
+	}
+}
+
+func TestPresetWithoutSaver(t *testing.T) {
+	m := New(testReport(), nil)
+	m = press(m, runes("P"))
+	m = typeText(m, "tmp")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.presets) != 1 || !strings.Contains(m.statusMsg, "this session only") {
+		t.Errorf("expected session-only preset, status=%q", m.statusMsg)
+	}
+}
+
+// --- Export tests ---
+
+func TestExportFormat(t *testing.T) {
+	for path, want := range map[string]string{
+		"a.csv": "csv", "b.JSON": "json", "c.md": "markdown", "d.markdown": "markdown", "e.sarif": "sarif",
+	} {
+		if got, err := exportFormat(path); err != nil || got != want {
+			t.Errorf("exportFormat(%q) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+	if _, err := exportFormat("findings.txt"); err == nil {
+		t.Error("expected error for unknown extension")
+	}
+}
+
+func exportModel(t *testing.T) (Model, string) {
+	t.Helper()
+	m := New(testReport(), nil)
+	m.now = func() time.Time { return time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC) }
+	m.filters = filterState{Tool: "vaultspectre"}
+	m.sortBy = sortByResource
+	m.rebuildTable()
+	return m, t.TempDir()
+}
+
+func exportTo(t *testing.T, m Model, path string) (Model, string) {
+	t.Helper()
+	m = press(m, runes("e"))
+	if m.mode != modeExport || m.promptInput.Value() != defaultExportFile {
+		t.Fatalf("expected export prompt with default name, mode=%d value=%q", m.mode, m.promptInput.Value())
+	}
+	m.promptInput.SetValue(path)
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read export: %v (status %q)", err, m.statusMsg)
+	}
+	return m, string(data)
+}
+
+func TestExportCSV(t *testing.T) {
+	m, dir := exportModel(t)
+	path := filepath.Join(dir, "out.csv")
+	m, data := exportTo(t, m, path)
+
+	if m.mode != modeNormal || m.statusMsg != "Exported 2 issues to "+path+" and "+path+".meta.json" {
+		t.Errorf("mode=%d status=%q", m.mode, m.statusMsg)
+	}
+	want := "severity,tool,id,category,resource,count,status,owner,evidence,fingerprint,remediation\n" +
+		"medium,vaultspectre,,stale,secret/api-key,1,open,,,,\n" +
+		"critical,vaultspectre,,missing,secret/db,1,open,,not found,,\n"
+	if data != want {
+		t.Errorf("CSV =\n%s\nwant plain rows:\n%s", data, want)
+	}
+
+	raw, err := os.ReadFile(path + ".meta.json")
+	if err != nil {
+		t.Fatalf("read metadata sidecar: %v", err)
+	}
+	var meta exportMetadata
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		t.Fatalf("unmarshal metadata: %v", err)
+	}
+	if meta.Filters["tool"] != "vaultspectre" || meta.Sort != "resource" || meta.IssueCount != 2 ||
+		meta.TotalIssues != 4 || meta.RunTimestamp != "2026-02-15T10:00:00Z" {
+		t.Errorf("unexpected metadata: %+v", meta)
+	}
+}
+
+func TestExportRefusesOverwrite(t *testing.T) {
+	m, dir := exportModel(t)
+	path := filepath.Join(dir, "out.json")
+	if err := os.WriteFile(path, []byte("keep me"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m = press(m, runes("e"))
+	m.promptInput.SetValue(path)
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeExportConfirm || !strings.Contains(m.View(), path+" already exists. Overwrite? (y/n)") {
+		t.Errorf("expected overwrite confirmation, mode=%d", m.mode)
+	}
+
+	// n returns to the file name prompt without touching the file.
+	m = press(m, runes("n"))
+	if m.mode != modeExport || m.promptInput.Value() != path {
+		t.Errorf("expected file name prompt, mode=%d value=%q", m.mode, m.promptInput.Value())
+	}
+	if data, _ := os.ReadFile(path); string(data) != "keep me" {
+		t.Errorf("existing file was modified: %q", data)
+	}
+
+	// An existing sidecar asks before a CSV export too.
+	csvPath := filepath.Join(dir, "out.csv")
+	if err := os.WriteFile(csvPath+".meta.json", []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.promptInput.SetValue(csvPath)
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeExportConfirm || len(m.overwrite) != 1 || m.overwrite[0] != csvPath+".meta.json" {
+		t.Errorf("expected confirmation for the sidecar, mode=%d overwrite=%v", m.mode, m.overwrite)
+	}
+	m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if _, err := os.Stat(csvPath); !os.IsNotExist(err) || m.mode != modeNormal || m.statusMsg != "Export cancelled" {
+		t.Errorf("expected CSV export to be cancelled, mode=%d status=%q", m.mode, m.statusMsg)
+	}
+
+	// y overwrites.
+	m = press(m, runes("e"))
+	m.promptInput.SetValue(path)
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = press(m, runes("y"))
+	if m.mode != modeNormal || m.statusMsg != "Exported 2 issues to "+path {
+		t.Errorf("mode=%d status=%q", m.mode, m.statusMsg)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), `"issues"`) {
+		t.Errorf("y did not overwrite the file: %q", data)
+	}
+}
+
+func TestExportFileNameEndingInForce(t *testing.T) {
+	m, dir := exportModel(t)
+	path := filepath.Join(dir, "out --force.json")
+	m, data := exportTo(t, m, path)
+	if m.mode != modeNormal || !strings.Contains(data, `"issues"`) {
+		t.Errorf("mode=%d status=%q", m.mode, m.statusMsg)
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	m, dir := exportModel(t)
+	_, data := exportTo(t, m, filepath.Join(dir, "out.json"))
+
+	var doc exportDocument
+	if err := json.Unmarshal([]byte(data), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if doc.Filters["tool"] != "vaultspectre" || doc.Sort != "resource" || doc.IssueCount != 2 || doc.TotalIssues != 4 {
+		t.Errorf("unexpected header: %+v", doc)
+	}
+	if len(doc.Issues) != 2 || doc.Issues[0].Resource != "secret/api-key" || doc.Issues[0].Triage != "open" {
+		t.Errorf("unexpected issues: %+v", doc.Issues)
+	}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	m, dir := exportModel(t)
+	m.allIssues[2].Resource = "secret/a|b"
+	m.rebuildTable()
+	_, data := exportTo(t, m, filepath.Join(dir, "out.md"))
+
+	for _, frag := range []string{
+		"# SpectreHub findings",
+		"- Filters: tool=vaultspectre",
+		"- Issues: 2 of 4",
+		`| medium | vaultspectre | stale |  | secret/a\|b | 1 | open |  |`,
+	} {
+		if !strings.Contains(data, frag) {
+			t.Errorf("expected Markdown to contain %q, got:\n%s", frag, data)
+		}
+	}
+}
+
+func TestExportSARIF(t *testing.T) {
+	m, dir := exportModel(t)
+	_, data := exportTo(t, m, filepath.Join(dir, "out.sarif"))
+
+	var log struct {
+		Runs []struct {
+			Results    []json.RawMessage `json:"results"`
+			Properties map[string]string `json:"properties"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(data), &log); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	run := log.Runs[0]
+	if len(run.Results) != 2 || run.Properties["filters"] != "tool=vaultspectre" || run.Properties["issues"] != "2 of 4" {
+		t.Errorf("unexpected SARIF run: %d results, properties %v", len(run.Results), run.Properties)
+	}
+}
+
+func TestExportErrors(t *testing.T) {
+	m, dir := exportModel(t)
+	m = press(m, runes("e"))
+	m.promptInput.SetValue(filepath.Join(dir, "out.txt"))
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeExport || !strings.Contains(m.statusMsg, "unknown export format") {
+		t.Errorf("expected prompt to stay open with error, mode=%d status=%q", m.mode, m.statusMsg)
+	}
+
+	m.promptInput.SetValue(filepath.Join(dir, "missing", "out.csv"))
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.HasPrefix(m.statusMsg, "Export failed:") {
+		t.Errorf("expected write failure, status=%q", m.statusMsg)
+	}
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyEscape})
+	if m.mode != modeNormal || m.statusMsg != "Export cancelled" {
+		t.Errorf("expected cancel, mode=%d status=%q", m.mode, m.statusMsg)
+	}
+}
@@ -682,7 +682,7 @@ func TestValidateSpectreV1Report(t *testing.T) {
 				Findings:  []models.SpectreV1Finding{},
 				Summary:   models.SpectreV1Summary{Total: 0},
 			},
-			wantErrContain: "Missing required field: 'tool'",
+			wantErrContain: "/tool: must not be empty",
 		},
 		{
 			name: "missing version",
@@ -694,7 +694,7 @@ func TestValidateSpectreV1Report(t *testing.T) {
 				Findings:  []models.SpectreV1Finding{},
 				Summary:   models.SpectreV1Summary{Total: 0},
 			},
-			wantErrContain: "Missing required field: 'version'",
+			wantErrContain: "/version: \"\" does not match pattern",
 		},
 		{
 			name: "missing timestamp",
@@ -706,7 +706,7 @@ func TestValidateSpectreV1Report(t *testing.T) {
 				Findings: []models.SpectreV1Finding{},
 				Summary:  models.SpectreV1Summary{Total: 0},
 			},
-			wantErrContain: "Missing or invalid field: 'timestamp'",
+			wantErrContain: "/timestamp: must not be the zero time",
 		},
 		{
 			name: "missing target type",
@@ -718,7 +718,7 @@ func TestValidateSpectreV1Report(t *testing.T) {
 				Findings:  []models.SpectreV1Finding{},
 				Summary:   models.SpectreV1Summary{Total: 0},
 			},
-			wantErrContain: "Missing required field: 'target.type'",
+			wantErrContain: "/target/type: \"\" is not one of",
 		},
 		{
 			name: "wrong target type for tool",
@@ -731,7 +731,7 @@ func TestValidateSpectreV1Report(t *testing.T) {
 				Findings:  []models.SpectreV1Finding{},
 				Summary:   models.SpectreV1Summary{Total: 0},
 			},
-			wantErrContain: "target.type",
+			wantErrContain: "/target/type: \"vault\" does not match tool \"s3spectre\"",
 		},
 		{
 			name: "nil findings",
@@ -759,7 +759,7 @@ func TestValidateSpectreV1Report(t *testing.T) {
 				},
 				Summary: models.SpectreV1Summary{Total: 1, Medium: 1},
 			},
-			wantErrContain: "missing required field 'id'",
+			wantErrContain: "/findings/0/id: must not be empty",
 		},
 		{
 			name: "finding invalid severity",
@@ -774,7 +774,7 @@ func TestValidateSpectreV1Report(t *testing.T) {
 				},
 				Summary: models.SpectreV1Summary{Total: 1},
 			},
-			wantErrContain: "invalid severity",
+			wantErrContain: "/findings/0/severity: \"critical\" is not one of",
 		},
 		{
 			name: "finding missing location",
@@ -789,7 +789,7 @@ func TestValidateSpectreV1Report(t *testing.T) {
 				},
 				Summary: models.SpectreV1Summary{Total: 1, Medium: 1},
 			},
-			wantErrContain: "missing required field 'location'",
+			wantErrContain: "/findings/0/location: must not be empty",
 		},
 		{
 			name: "finding missing message",
@@ -804,7 +804,7 @@ func TestValidateSpectreV1Report(t *testing.T) {
 				},
 				Summary: models.SpectreV1Summary{Total: 1, Medium: 1},
 			},
-			wantErrContain: "missing required field 'message'",
+			wantErrContain: "/findings/0/message: must not be empty",
 		},
 		{
 			name: "summary total mismatch",
@@ -819,10 +819,22 @@ func TestValidateSpectreV1Report(t *testing.T) {
 				},
 				Summary: models.SpectreV1Summary{Total: 5, Medium: 1},
 			},
-			wantErrContain: "summary.total=5 does not match findings count=1",
+			wantErrContain: "/summary/total: 5 does not match findings count 1",
 		},
 		{
-			name: "unknown tool target type accepted",
+			name: "unknown tool with known target type accepted",
+			report: models.SpectreV1Report{
+				Schema:    "spectre/v1",
+				Tool:      "customtool",
+				Version:   "0.1.0",
+				Timestamp: now,
+				Target:    models.SpectreV1Target{Type: "s3"},
+				Findings:  []models.SpectreV1Finding{},
+				Summary:   models.SpectreV1Summary{Total: 0},
+			},
+		},
+		{
+			name: "unknown target type",
 			report: models.SpectreV1Report{
 				Schema:    "spectre/v1",
 				Tool:      "customtool",
@@ -832,6 +844,57 @@ func TestValidateSpectreV1Report(t *testing.T) {
 				Findings:  []models.SpectreV1Finding{},
 				Summary:   models.SpectreV1Summary{Total: 0},
 			},
+			wantErrContain: "/target/type: \"custom\" is not one of",
+		},
+		{
+			name: "null findings",
+			raw: []byte(`{"schema":"spectre/v1","tool":"s3spectre","version":"0.2.1","timestamp":"2026-02-15T00:00:00Z",
+				"target":{"type":"s3"},"findings":null,"summary":{"total":0}}`),
+			wantErrContain: "/findings: expected array, got null",
+		},
+		{
+			name: "missing summary",
+			raw: []byte(`{"schema":"spectre/v1","tool":"s3spectre","version":"0.2.1","timestamp":"2026-02-15T00:00:00Z",
+				"target":{"type":"s3"},"findings":[]}`),
+			wantErrContain: "/summary: required property is missing",
+		},
+		{
+			name: "unknown top-level property",
+			raw: []byte(`{"schema":"spectre/v1","tool":"s3spectre","version":"0.2.1","timestamp":"2026-02-15T00:00:00Z",
+				"target":{"type":"s3"},"findings":[],"summary":{"total":0},"extra":true}`),
+			wantErrContain: "/extra: property is not allowed",
+		},
+		{
+			name: "unknown finding property",
+			raw: []byte(`{"schema":"spectre/v1","tool":"s3spectre","version":"0.2.1","timestamp":"2026-02-15T00:00:00Z",
+				"target":{"type":"s3"},"findings":[{"id":"A","severity":"low","location":"b","message":"m","owner":"x"}],
+				"summary":{"total":1,"low":1}}`),
+			wantErrContain: "/findings/0/owner: property is not allowed",
+		},
+		{
+			name: "negative waste",
+			raw: []byte(`{"schema":"spectre/v1","tool":"s3spectre","version":"0.2.1","timestamp":"2026-02-15T00:00:00Z",
+				"target":{"type":"s3"},"findings":[{"id":"A","severity":"low","location":"b","message":"m","estimated_monthly_waste":-1}],
+				"summary":{"total":1,"low":1}}`),
+			wantErrContain: "/findings/0/estimated_monthly_waste: -1 is less than minimum 0",
+		},
+		{
+			name: "fractional summary count",
+			raw: []byte(`{"schema":"spectre/v1","tool":"s3spectre","version":"0.2.1","timestamp":"2026-02-15T00:00:00Z",
+				"target":{"type":"s3"},"findings":[],"summary":{"total":0,"high":0.5}}`),
+			wantErrContain: "/summary/high: expected integer, got number",
+		},
+		{
+			name: "invalid timestamp",
+			raw: []byte(`{"schema":"spectre/v1","tool":"s3spectre","version":"0.2.1","timestamp":"yesterday",
+				"target":{"type":"s3"},"findings":[],"summary":{"total":0}}`),
+			wantErrContain: "/timestamp: \"yesterday\" is not an RFC 3339 date-time",
+		},
+		{
+			name: "wrong schema",
+			raw: []byte(`{"schema":"spectre/v2","tool":"s3spectre","version":"0.2.1","timestamp":"2026-02-15T00:00:00Z",
+				"target":{"type":"s3"},"findings":[],"summary":{"total":0}}`),
+			wantErrContain: "/schema: must be \"spectre/v1\"",
 		},
 	}
 
@@ -864,6 +927,81 @@ func TestValidateSpectreV1Report(t *testing.T) {
 	}
 }
 
+func TestValidateSpectreV11Report(t *testing.T) {
+	validator := New()
+	envelope := func(schema, finding string) []byte {
+		return []byte(`{"schema":"` + schema + `","tool":"awsspectre","version":"0.3.0","timestamp":"2026-03-02T10:00:00Z",
+			"target":{"type":"aws-account"},"findings":[` + finding + `],"summary":{"total":1,"medium":1}}`)
+	}
+	const base = `"id":"IDLE_EC2","severity":"medium","location":"i-1","message":"idle"`
+
+	tests := []struct {
+		name           string
+		data           []byte
+		wantErrContain string
+	}{
+		{
+			name: "full metadata",
+			data: envelope("spectre/v1.1", `{`+base+`,"attributes":{"account":"1","region":"us-east-1","namespace":"ns","tags":{"env":"prod"}},
+				"remediation":"stop it","fingerprint":"fp","references":["https://example.com/x"],
+				"first_detected":"2026-02-01T00:00:00Z","confidence":0.8}`),
+		},
+		{
+			name: "no metadata",
+			data: envelope("spectre/v1.1", `{`+base+`}`),
+		},
+		{
+			name:           "v1.1 field in v1 report",
+			data:           envelope("spectre/v1", `{`+base+`,"fingerprint":"fp"}`),
+			wantErrContain: "/findings/0/fingerprint: property is not allowed",
+		},
+		{
+			name:           "confidence above 1",
+			data:           envelope("spectre/v1.1", `{`+base+`,"confidence":1.5}`),
+			wantErrContain: "/findings/0/confidence: 1.5 is greater than maximum 1",
+		},
+		{
+			name:           "relative reference",
+			data:           envelope("spectre/v1.1", `{`+base+`,"references":["docs/idle.md"]}`),
+			wantErrContain: "/findings/0/references/0: \"docs/idle.md\" is not an absolute URI",
+		},
+		{
+			name:           "non-string tag",
+			data:           envelope("spectre/v1.1", `{`+base+`,"attributes":{"tags":{"cost":5}}}`),
+			wantErrContain: "/findings/0/attributes/tags/cost: expected string, got integer",
+		},
+		{
+			name:           "unknown attribute",
+			data:           envelope("spectre/v1.1", `{`+base+`,"attributes":{"zone":"a"}}`),
+			wantErrContain: "/findings/0/attributes/zone: property is not allowed",
+		},
+		{
+			name:           "bad first_detected",
+			data:           envelope("spectre/v1.1", `{`+base+`,"first_detected":"last week"}`),
+			wantErrContain: "/findings/0/first_detected: \"last week\" is not an RFC 3339 date-time",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateReport(models.ToolAWS, tt.data)
+			if tt.wantErrContain == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var vErr *ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if !containsError(vErr.Errors, tt.wantErrContain) {
+				t.Fatalf("expected error to contain %q, got %v", tt.wantErrContain, vErr.Errors)
+			}
+		})
+	}
+}
+
 func TestValidateReportAllToolTypes(t *testing.T) {
 	validator := New()
 	now := time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)
@@ -0,0 +1,71 @@
+package spectrev1
+
+import "time"
+
+// Builder assembles a report so the summary always matches the findings.
+// It is not safe for concurrent use.
+type Builder struct {
+	report Report
+	now    func() time.Time
+}
+
+// NewBuilder starts a report for tool at version scanning target.
+func NewBuilder(tool, version string, target Target) *Builder {
+	return &Builder{
+		report: Report{Tool: tool, Version: version, Target: target},
+		now:    time.Now,
+	}
+}
+
+// Timestamp sets when the audit ran; Build uses the current time if it is
+// not set.
+func (b *Builder) Timestamp(t time.Time) *Builder {
+	b.report.Timestamp = t
+	return b
+}
+
+// Add appends findings.
+func (b *Builder) Add(findings ...Finding) *Builder {
+	b.report.Findings = append(b.report.Findings, findings...)
+	return b
+}
+
+// Build fills in the schema version, timestamp and summary and validates
+// the result. The schema is spectre/v1 unless a finding uses a spectre/v1.1
+// field, so reports stay readable by older spectrehub releases when they
+// can. An invalid report is returned along with a *ValidationError.
+func (b *Builder) Build() (*Report, error) {
+	r := b.report
+	r.Findings = append([]Finding{}, b.report.Findings...)
+	if r.Timestamp.IsZero() {
+		r.Timestamp = b.now().UTC()
+	}
+
+	r.Schema = SchemaV1
+	for _, f := range r.Findings {
+		if f.usesV11() {
+			r.Schema = SchemaV11
+		}
+	}
+	r.Summary = summarize(r.Findings)
+
+	return &r, r.Validate()
+}
+
+// summarize counts findings in total and by severity.
+func summarize(findings []Finding) Summary {
+	s := Summary{Total: len(findings)}
+	for _, f := range findings {
+		switch f.Severity {
+		case SeverityHigh:
+			s.High++
+		case SeverityMedium:
+			s.Medium++
+		case SeverityLow:
+			s.Low++
+		case SeverityInfo:
+			s.Info++
+		}
+	}
+	return s
+}
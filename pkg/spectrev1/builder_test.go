@@ -0,0 +1,77 @@
+package spectrev1
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBuilderFillsSummaryAndSchema(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	report, err := NewBuilder("s3spectre", "0.4.0", Target{Type: "s3"}).
+		Timestamp(ts).
+		Add(
+			Finding{ID: "PUBLIC_BUCKET", Severity: SeverityHigh, Location: "s3://a", Message: "public"},
+			Finding{ID: "UNUSED_BUCKET", Severity: SeverityMedium, Location: "s3://b", Message: "unused"},
+			Finding{ID: "UNUSED_BUCKET", Severity: SeverityMedium, Location: "s3://c", Message: "unused"},
+		).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if report.Schema != SchemaV1 || !report.Timestamp.Equal(ts) {
+		t.Errorf("schema/timestamp = %s/%s", report.Schema, report.Timestamp)
+	}
+	if want := (Summary{Total: 3, High: 1, Medium: 2}); report.Summary != want {
+		t.Errorf("Summary = %+v, want %+v", report.Summary, want)
+	}
+}
+
+func TestBuilderPicksV11ForMetadata(t *testing.T) {
+	b := NewBuilder("awsspectre", "0.4.0", Target{Type: "aws-account"})
+	b.now = func() time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, time.FixedZone("x", 3600)) }
+	report, err := b.Add(Finding{ID: "IDLE_EC2", Severity: SeverityLow, Location: "i-1", Message: "idle", Fingerprint: "fp"}).Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if report.Schema != SchemaV11 {
+		t.Errorf("Schema = %q, want %q", report.Schema, SchemaV11)
+	}
+	if report.Timestamp.Location() != time.UTC {
+		t.Errorf("default timestamp should be UTC, got %s", report.Timestamp)
+	}
+}
+
+func TestBuilderEmptyAndInvalid(t *testing.T) {
+	report, err := NewBuilder("vaultspectre", "1.0.0", Target{Type: "vault"}).Build()
+	if err != nil {
+		t.Fatalf("empty report: %v", err)
+	}
+	if report.Findings == nil || report.Summary.Total != 0 {
+		t.Errorf("empty report should have an empty findings array: %+v", report)
+	}
+
+	report, err = NewBuilder("s3spectre", "1.0.0", Target{Type: "vault"}).
+		Add(Finding{ID: "X", Severity: "critical", Location: "s3://a", Message: "m"}).
+		Build()
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || report == nil {
+		t.Fatalf("expected the report and a ValidationError, got %v, %v", report, err)
+	}
+	if len(vErr.Errors) != 1 || vErr.Errors[0] != `/findings/0/severity: "critical" is not one of ["high", "medium", "low", "info"]` {
+		t.Errorf("Errors = %v", vErr.Errors)
+	}
+}
+
+func TestBuilderDoesNotAliasFindings(t *testing.T) {
+	b := NewBuilder("s3spectre", "1.0.0", Target{Type: "s3"}).
+		Add(Finding{ID: "A", Severity: SeverityLow, Location: "s3://a", Message: "m"})
+	first, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Add(Finding{ID: "B", Severity: SeverityLow, Location: "s3://b", Message: "m"})
+	if len(first.Findings) != 1 || first.Summary.Total != 1 {
+		t.Errorf("built report changed after Add: %+v", first)
+	}
+}
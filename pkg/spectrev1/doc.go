@@ -0,0 +1,30 @@
+// Package spectrev1 is the Go SDK for the spectre/v1 envelope, the JSON
+// format every Spectre scanner emits with --format spectrehub and
+// spectrehub collects.
+//
+// It contains the envelope types, a Builder that fills in the summary from
+// the findings, the target-type registry, and Validate, which checks a
+// report against the same embedded JSON Schemas spectrehub uses. Scanner
+// repositories can run the checks spectrehub's own contract tests use with
+// the spectrev1test package.
+//
+// A scanner typically builds and writes its report like this:
+//
+//	b := spectrev1.NewBuilder("s3spectre", version, spectrev1.Target{Type: "s3"})
+//	b.Add(spectrev1.Finding{
+//		ID:       "UNUSED_BUCKET",
+//		Severity: spectrev1.SeverityMedium,
+//		Location: "s3://old-logs",
+//		Message:  "no reads in 90 days",
+//	})
+//	report, err := b.Build()
+//	if err != nil {
+//		return err
+//	}
+//	return json.NewEncoder(os.Stdout).Encode(report)
+//
+// The package follows the envelope's compatibility rules: fields are only
+// added, never renamed or removed, and additions go into a new minor
+// schema version (spectre/v1.1) so older spectrehub releases can reject
+// what they do not understand.
+package spectrev1
@@ -0,0 +1,379 @@
+package spectrev1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ppiankov/spectrehub/schemas"
+)
+
+// schema is a node of a JSON Schema (draft 2020-12). Only the keywords the
+// spectre schemas use are supported; loading a schema that uses anything
+// else fails, so a schema change cannot be silently ignored.
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Const                json.RawMessage    `json:"const"`
+	Enum                 []interface{}      `json:"enum"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"` // false, or a schema for the values
+	Items                *schema            `json:"items"`
+	MinLength            *int               `json:"minLength"`
+	Pattern              string             `json:"pattern"`
+	Format               string             `json:"format"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	Defs                 map[string]*schema `json:"$defs"`
+
+	pattern    *regexp.Regexp
+	closed     bool    // additionalProperties: false
+	additional *schema // additionalProperties as a schema
+}
+
+// annotations are keywords that do not affect validation.
+var annotations = map[string]bool{
+	"$schema": true, "$id": true, "title": true, "description": true, "examples": true,
+}
+
+// spectreSchemas are the embedded schema documents by envelope version.
+var spectreSchemas = map[string][]byte{
+	SchemaV1:  schemas.SpectreV1,
+	SchemaV11: schemas.SpectreV11,
+}
+
+var (
+	compiledMu sync.Mutex
+	compiled   = make(map[string]*schema)
+)
+
+// spectreSchema returns the compiled embedded schema of an envelope
+// version.
+func spectreSchema(version string) (*schema, error) {
+	compiledMu.Lock()
+	defer compiledMu.Unlock()
+	if s, ok := compiled[version]; ok {
+		return s, nil
+	}
+	data, ok := spectreSchemas[version]
+	if !ok {
+		return nil, fmt.Errorf("no schema for %q", version)
+	}
+	s, err := compileSchema(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", version, err)
+	}
+	compiled[version] = s
+	return s, nil
+}
+
+// compileSchema parses a schema document and resolves its references.
+func compileSchema(data []byte) (*schema, error) {
+	root, err := parseSchema(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	if err := root.compile(root, ""); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return root, nil
+}
+
+// parseSchema decodes a schema document and rejects unsupported keywords.
+func parseSchema(data []byte) (*schema, error) {
+	s := &schema{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	if err := checkKeywords(data, ""); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// checkKeywords walks the raw schema so that a keyword the validator does
+// not implement is an error rather than silently ignored.
+func checkKeywords(data []byte, ptr string) error {
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keywords); err != nil {
+		return fmt.Errorf("%s: %w", displayPointer(ptr), err)
+	}
+	for kw, raw := range keywords {
+		switch kw {
+		case "properties", "$defs":
+			var children map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &children); err != nil {
+				return fmt.Errorf("%s/%s: %w", ptr, kw, err)
+			}
+			for name, child := range children {
+				if err := checkKeywords(child, ptr+"/"+kw+"/"+escapePointer(name)); err != nil {
+					return err
+				}
+			}
+		case "items":
+			if err := checkKeywords(raw, ptr+"/items"); err != nil {
+				return err
+			}
+		case "additionalProperties":
+			if !isBoolJSON(raw) {
+				if err := checkKeywords(raw, ptr+"/additionalProperties"); err != nil {
+					return err
+				}
+			}
+		case "$ref", "type", "const", "enum", "required",
+			"minLength", "pattern", "format", "minimum", "maximum":
+		default:
+			if !annotations[kw] {
+				return fmt.Errorf("%s: unsupported keyword %q", displayPointer(ptr), kw)
+			}
+		}
+	}
+	return nil
+}
+
+// compile resolves $ref against root and compiles patterns.
+func (s *schema) compile(root *schema, ptr string) error {
+	if s.Ref != "" {
+		name, ok := strings.CutPrefix(s.Ref, "#/$defs/")
+		if !ok || root.Defs[name] == nil {
+			return fmt.Errorf("%s: unresolvable $ref %q", displayPointer(ptr), s.Ref)
+		}
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("%s/pattern: %w", ptr, err)
+		}
+		s.pattern = re
+	}
+	if s.Format != "" && s.Format != "date-time" && s.Format != "uri" {
+		return fmt.Errorf("%s: unsupported format %q", displayPointer(ptr), s.Format)
+	}
+	if raw := s.AdditionalProperties; raw != nil {
+		switch {
+		case string(bytes.TrimSpace(raw)) == "false":
+			s.closed = true
+		case !isBoolJSON(raw):
+			s.additional = &schema{}
+			if err := json.Unmarshal(raw, s.additional); err != nil {
+				return fmt.Errorf("%s/additionalProperties: %w", ptr, err)
+			}
+			if err := s.additional.compile(root, ptr+"/additionalProperties"); err != nil {
+				return err
+			}
+		}
+	}
+	for name, child := range s.Properties {
+		if err := child.compile(root, ptr+"/properties/"+escapePointer(name)); err != nil {
+			return err
+		}
+	}
+	for name, child := range s.Defs {
+		if err := child.compile(root, ptr+"/$defs/"+escapePointer(name)); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.compile(root, ptr+"/items")
+	}
+	return nil
+}
+
+// validateJSON checks a JSON document against the schema and returns one
+// message per violation, prefixed with the JSON pointer of the offending
+// value.
+func (s *schema) validateJSON(data []byte) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	var errs []string
+	s.validate(s, doc, "", &errs)
+	return errs, nil
+}
+
+func (s *schema) validate(root *schema, v interface{}, ptr string, errs *[]string) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, displayPointer(ptr)+": "+fmt.Sprintf(format, args...))
+	}
+
+	if s.Ref != "" {
+		root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")].validate(root, v, ptr, errs)
+	}
+	if s.Type != "" && !hasType(v, s.Type) {
+		fail("expected %s, got %s", s.Type, typeOf(v))
+		return
+	}
+	if s.Const != nil {
+		var want interface{}
+		_ = json.Unmarshal(s.Const, &want)
+		if !jsonEqual(v, want) {
+			fail("must be %s", s.Const)
+		}
+	}
+	if s.Enum != nil {
+		found := false
+		for _, want := range s.Enum {
+			if jsonEqual(v, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("%s is not one of %s", formatValue(v), formatEnum(s.Enum))
+		}
+	}
+
+	switch val := v.(type) {
+	case string:
+		if s.MinLength != nil && len([]rune(val)) < *s.MinLength {
+			if *s.MinLength == 1 {
+				fail("must not be empty")
+			} else {
+				fail("must be at least %d characters", *s.MinLength)
+			}
+		}
+		if s.pattern != nil && !s.pattern.MatchString(val) {
+			fail("%q does not match pattern %q", val, s.Pattern)
+		}
+		switch s.Format {
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339, val); err != nil {
+				fail("%q is not an RFC 3339 date-time", val)
+			}
+		case "uri":
+			if u, err := url.Parse(val); err != nil || !u.IsAbs() {
+				fail("%q is not an absolute URI", val)
+			}
+		}
+	case json.Number:
+		f, err := val.Float64()
+		if err != nil {
+			break
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			fail("%s is less than minimum %v", val, *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			fail("%s is greater than maximum %v", val, *s.Maximum)
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				*errs = append(*errs, displayPointer(ptr+"/"+escapePointer(name))+": required property is missing")
+			}
+		}
+		names := make([]string, 0, len(val))
+		for name := range val {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			child := ptr + "/" + escapePointer(name)
+			switch prop, ok := s.Properties[name]; {
+			case ok:
+				prop.validate(root, val[name], child, errs)
+			case s.additional != nil:
+				s.additional.validate(root, val[name], child, errs)
+			case s.closed:
+				*errs = append(*errs, displayPointer(child)+": property is not allowed")
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range val {
+				s.Items.validate(root, item, fmt.Sprintf("%s/%d", ptr, i), errs)
+			}
+		}
+	}
+}
+
+func isBoolJSON(raw json.RawMessage) bool {
+	v := string(bytes.TrimSpace(raw))
+	return v == "true" || v == "false"
+}
+
+func hasType(v interface{}, want string) bool {
+	got := typeOf(v)
+	if want == "number" {
+		return got == "integer" || got == "number"
+	}
+	return got == want
+}
+
+// typeOf returns the JSON Schema type name of a decoded value.
+func typeOf(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return "integer"
+		}
+		if f, err := val.Float64(); err == nil && f == float64(int64(f)) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// jsonEqual compares a decoded document value (numbers as json.Number)
+// with a schema value (numbers as float64).
+func jsonEqual(v, want interface{}) bool {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		w, isNum := want.(float64)
+		return err == nil && isNum && f == w
+	}
+	a, _ := json.Marshal(v)
+	b, _ := json.Marshal(want)
+	return bytes.Equal(a, b)
+}
+
+func formatValue(v interface{}) string {
+	if n, ok := v.(json.Number); ok {
+		return n.String()
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func formatEnum(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = formatValue(v)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// escapePointer escapes a JSON pointer reference token (RFC 6901).
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// displayPointer renders the document root, whose pointer is empty, as "/".
+func displayPointer(ptr string) string {
+	if ptr == "" {
+		return "/"
+	}
+	return ptr
+}
@@ -0,0 +1,198 @@
+package spectrev1
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestSpectreV1SchemaMatchesModels fails when the latest embedded schema
+// and the Go types disagree on property names or required fields.
+func TestSpectreV1SchemaMatchesModels(t *testing.T) {
+	root, err := spectreSchema(SchemaV11)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		node   *schema
+		goType reflect.Type
+	}{
+		{"report", root, reflect.TypeOf(Report{})},
+		{"target", root.Defs["target"], reflect.TypeOf(Target{})},
+		{"finding", root.Defs["finding"], reflect.TypeOf(Finding{})},
+		{"summary", root.Defs["summary"], reflect.TypeOf(Summary{})},
+		{"attributes", root.Defs["attributes"], reflect.TypeOf(ResourceAttributes{})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.node == nil {
+				t.Fatalf("schema has no %s definition", tt.name)
+			}
+			if !tt.node.closed {
+				t.Errorf("schema %s must set additionalProperties: false", tt.name)
+			}
+
+			fields := jsonFields(tt.goType)
+			var goNames []string
+			for name := range fields {
+				goNames = append(goNames, name)
+			}
+			sort.Strings(goNames)
+			if props := propertyNames(tt.node); !reflect.DeepEqual(props, goNames) {
+				t.Errorf("schema properties %v != %s JSON fields %v", props, tt.goType.Name(), goNames)
+			}
+
+			for _, name := range tt.node.Required {
+				if omitempty, ok := fields[name]; !ok || omitempty {
+					t.Errorf("required property %q must be a %s field without omitempty", name, tt.goType.Name())
+				}
+			}
+		})
+	}
+}
+
+// TestSpectreV11ExtendsV1 fails when spectre/v1.1 stops accepting every
+// spectre/v1 report: it may only add optional properties.
+func TestSpectreV11ExtendsV1(t *testing.T) {
+	v1, err := spectreSchema(SchemaV1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v11, err := spectreSchema(SchemaV11)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pairs := map[string][2]*schema{"report": {v1, v11}}
+	for name, def := range v1.Defs {
+		pairs[name] = [2]*schema{def, v11.Defs[name]}
+	}
+	for name, p := range pairs {
+		old, next := p[0], p[1]
+		if next == nil {
+			t.Errorf("spectre/v1.1 drops definition %q", name)
+			continue
+		}
+		if !reflect.DeepEqual(old.Required, next.Required) {
+			t.Errorf("%s: required %v changed to %v", name, old.Required, next.Required)
+		}
+		for prop, oldProp := range old.Properties {
+			newProp, ok := next.Properties[prop]
+			if !ok {
+				t.Errorf("%s: spectre/v1.1 drops property %q", name, prop)
+				continue
+			}
+			if name == "report" && prop == "schema" {
+				continue // the version itself
+			}
+			if !reflect.DeepEqual(oldProp.Enum, newProp.Enum) || oldProp.Type != newProp.Type || oldProp.Ref != newProp.Ref {
+				t.Errorf("%s: property %q changed between versions", name, prop)
+			}
+		}
+	}
+}
+
+// TestSpectreV1SchemaTargetTypes fails when a target type or severity is
+// added to the registry or a schema version but not all of them.
+func TestSpectreV1SchemaTargetTypes(t *testing.T) {
+	wantTypes := make(map[string]bool)
+	for _, typ := range TargetTypes() {
+		wantTypes[typ] = true
+	}
+	for _, typ := range SharedTargetTypes() {
+		wantTypes[typ] = true
+	}
+
+	for version := range spectreSchemas {
+		root, err := spectreSchema(version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		types := enumSet(root.Defs["target"].Properties["type"])
+		if !reflect.DeepEqual(types, wantTypes) {
+			t.Errorf("%s target.type enum %v != registered target types %v", version, sortedKeys(types), sortedKeys(wantTypes))
+		}
+		severities := enumSet(root.Defs["finding"].Properties["severity"])
+		if !reflect.DeepEqual(severities, Severities()) {
+			t.Errorf("%s severity enum %v != Severities %v", version, sortedKeys(severities), sortedKeys(Severities()))
+		}
+	}
+}
+
+func TestCompileSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{"unsupported keyword", `{"type":"object","properties":{"a":{"oneOf":[]}}}`, `/properties/a: unsupported keyword "oneOf"`},
+		{"bad ref", `{"$ref":"#/$defs/missing"}`, "unresolvable $ref"},
+		{"bad pattern", `{"pattern":"("}`, "/pattern"},
+		{"unsupported format", `{"format":"email"}`, `unsupported format "email"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileSchema([]byte(tt.schema))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateJSONPointerEscaping(t *testing.T) {
+	s, err := compileSchema([]byte(`{"type":"object","additionalProperties":false}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs, err := s.validateJSON([]byte(`{"a/b~c":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || errs[0] != "/a~1b~0c: property is not allowed" {
+		t.Errorf("errors = %v", errs)
+	}
+}
+
+// jsonFields maps a struct's JSON names to whether they are omitempty.
+func jsonFields(typ reflect.Type) map[string]bool {
+	fields := make(map[string]bool)
+	for i := 0; i < typ.NumField(); i++ {
+		tag := typ.Field(i).Tag.Get("json")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fields[name] = strings.Contains(opts, "omitempty")
+	}
+	return fields
+}
+
+func enumSet(s *schema) map[string]bool {
+	set := make(map[string]bool)
+	for _, v := range s.Enum {
+		set[v.(string)] = true
+	}
+	return set
+}
+
+func propertyNames(s *schema) []string {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
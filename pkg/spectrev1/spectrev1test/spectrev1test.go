@@ -0,0 +1,137 @@
+// Package spectrev1test lets scanner repositories run spectrehub's
+// spectre/v1 contract checks in their own CI.
+//
+// Point Run at golden reports checked into the scanner repository:
+//
+//	func TestContract(t *testing.T) {
+//		spectrev1test.Run(t,
+//			spectrev1test.Contract{File: "testdata/s3spectre-spectrev1.json", Tool: "s3spectre", Findings: 3},
+//		)
+//	}
+//
+// and keep the golden files in step with the scanner's real output with
+// Golden:
+//
+//	spectrev1test.Golden(t, out, "testdata/s3spectre-spectrev1.json")
+//
+// spectrehub runs the same checks over its own contract files, so a report
+// that passes here is one spectrehub accepts.
+package spectrev1test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/spectrehub/pkg/spectrev1"
+)
+
+// UpdateEnv is the environment variable that makes Golden rewrite golden
+// files instead of comparing against them.
+const UpdateEnv = "SPECTREV1_UPDATE_GOLDEN"
+
+// Contract describes an expected golden report.
+type Contract struct {
+	File     string // path to the report
+	Tool     string // expected tool name
+	Findings int    // expected number of findings
+}
+
+// Run checks each contract in its own subtest.
+func Run(t *testing.T, contracts ...Contract) {
+	t.Helper()
+	for _, c := range contracts {
+		t.Run(filepath.Base(c.File), func(t *testing.T) {
+			data, err := os.ReadFile(c.File)
+			if err != nil {
+				t.Fatalf("read contract file: %v", err)
+			}
+			report := Check(t, data)
+			if report == nil {
+				return
+			}
+			if report.Tool != c.Tool {
+				t.Errorf("tool = %q, want %q", report.Tool, c.Tool)
+			}
+			if len(report.Findings) != c.Findings {
+				t.Errorf("findings = %d, want %d", len(report.Findings), c.Findings)
+			}
+		})
+	}
+}
+
+// Check validates one report with spectrev1.Validate, the same check
+// spectrehub applies when it reads the report, and returns it parsed, or
+// nil after reporting a failure that prevents parsing. Validate covers:
+//   - the embedded schema of the report's version
+//   - a registered tool using its registered target.type
+//   - the summary counting every severity correctly, not only the total
+func Check(t testing.TB, data []byte) *spectrev1.Report {
+	t.Helper()
+	if err := spectrev1.Validate(data); err != nil {
+		var vErr *spectrev1.ValidationError
+		if errors.As(err, &vErr) {
+			for _, e := range vErr.Errors {
+				t.Errorf("%s: %s", vErr.Schema, e)
+			}
+			return nil
+		}
+		t.Errorf("validate: %v", err)
+		return nil
+	}
+
+	var report spectrev1.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Errorf("parse report: %v", err)
+		return nil
+	}
+	return &report
+}
+
+// Golden compares a report the scanner just produced with the golden file
+// at path. The timestamp and tool version are ignored so golden files
+// survive new runs and releases. With UpdateEnv set the golden file is
+// rewritten instead.
+func Golden(t testing.TB, got []byte, path string) {
+	t.Helper()
+	gotReport := Check(t, got)
+	if gotReport == nil {
+		return
+	}
+
+	if os.Getenv(UpdateEnv) != "" {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, got, "", "  "); err != nil {
+			t.Fatalf("format golden file: %v", err)
+		}
+		buf.WriteByte('\n')
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatalf("write golden file: %v", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (set %s=1 to create it): %v", UpdateEnv, err)
+	}
+	var wantReport spectrev1.Report
+	if err := json.Unmarshal(data, &wantReport); err != nil {
+		t.Fatalf("parse golden file: %v", err)
+	}
+
+	for _, r := range []*spectrev1.Report{gotReport, &wantReport} {
+		r.Timestamp = time.Time{}
+		r.Version = ""
+	}
+	if !reflect.DeepEqual(gotReport, &wantReport) {
+		gotJSON, _ := json.MarshalIndent(gotReport, "", "  ")
+		wantJSON, _ := json.MarshalIndent(&wantReport, "", "  ")
+		t.Errorf("report differs from %s (set %s=1 to update):\ngot:\n%s\nwant:\n%s", path, UpdateEnv, gotJSON, wantJSON)
+	}
+}
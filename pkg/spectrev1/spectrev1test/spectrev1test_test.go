@@ -0,0 +1,92 @@
+package spectrev1test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// recorder is a testing.TB that records failures instead of failing.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// record runs fn with a recorder and returns the failures it reported.
+func record(t *testing.T, fn func(tb testing.TB)) []string {
+	r := &recorder{TB: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(r)
+	}()
+	<-done
+	return r.errors
+}
+
+const report = `{"schema":"spectre/v1","tool":"s3spectre","version":"0.2.1","timestamp":"2026-02-15T00:00:00Z",
+	"target":{"type":"s3"},"findings":[{"id":"A","severity":"low","location":"s3://a","message":"m"}],
+	"summary":{"total":1,"low":1}}`
+
+func TestCheck(t *testing.T) {
+	if errs := record(t, func(tb testing.TB) { Check(tb, []byte(report)) }); len(errs) != 0 {
+		t.Errorf("valid report failed: %v", errs)
+	}
+
+	badCounts := strings.Replace(report, `"low":1`, `"high":1`, 1)
+	errs := record(t, func(tb testing.TB) { Check(tb, []byte(badCounts)) })
+	if len(errs) != 2 || !strings.HasPrefix(errs[0], "spectre/v1: /summary/high:") || !strings.HasPrefix(errs[1], "spectre/v1: /summary/low:") {
+		t.Errorf("expected summary failures, got %v", errs)
+	}
+
+	invalid := strings.Replace(report, `"severity":"low"`, `"severity":"critical"`, 1)
+	errs = record(t, func(tb testing.TB) { Check(tb, []byte(invalid)) })
+	if len(errs) != 1 || !strings.HasPrefix(errs[0], "spectre/v1: /findings/0/severity:") {
+		t.Errorf("expected a schema failure, got %v", errs)
+	}
+}
+
+func TestRunContractFiles(t *testing.T) {
+	Run(t,
+		Contract{File: "../../../testdata/contracts/s3spectre-spectrev1.json", Tool: "s3spectre", Findings: 3},
+		Contract{File: "../../../testdata/contracts/awsspectre-spectrev1.1.json", Tool: "awsspectre", Findings: 2},
+	)
+}
+
+func TestGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+
+	t.Setenv(UpdateEnv, "1")
+	if errs := record(t, func(tb testing.TB) { Golden(tb, []byte(report), path) }); len(errs) != 0 {
+		t.Fatalf("update failed: %v", errs)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("golden file not written: %v", err)
+	}
+
+	t.Setenv(UpdateEnv, "")
+	newRun := strings.Replace(strings.Replace(report, "2026-02-15", "2026-03-01", 1), "0.2.1", "0.3.0", 1)
+	if errs := record(t, func(tb testing.TB) { Golden(tb, []byte(newRun), path) }); len(errs) != 0 {
+		t.Errorf("timestamp and version should be ignored, got %v", errs)
+	}
+
+	changed := strings.Replace(report, `"message":"m"`, `"message":"changed"`, 1)
+	errs := record(t, func(tb testing.TB) { Golden(tb, []byte(changed), path) })
+	if len(errs) != 1 || !strings.Contains(errs[0], "report differs from") {
+		t.Errorf("expected a diff failure, got %v", errs)
+	}
+}
@@ -0,0 +1,51 @@
+package spectrev1
+
+// targetTypes maps tool names to their expected target.type values. Tools
+// not listed here (e.g., iamspectre which emits aws-account or gcp-project
+// depending on the cloud) are accepted with any target type.
+var targetTypes = map[string]string{
+	"s3spectre":    "s3",
+	"pgspectre":    "postgres",
+	"kafkaspectre": "kafka",
+	"clickspectre": "clickhouse",
+	"vaultspectre": "vault",
+	"mongospectre": "mongodb",
+	"awsspectre":   "aws-account",
+	"gcsspectre":   "gcs",
+	"gcpspectre":   "gcp-projects",
+	"kubespectre":  "kubernetes",
+	"redisspectre": "redis",
+	"ecrspectre":   "ecr",
+	"rdsspectre":   "rds",
+	"azurespectre": "azure-subscription",
+}
+
+// sharedTargetTypes are target.type values not tied to a single tool:
+// iamspectre emits aws-account or gcp-project depending on the cloud.
+// Together with the values of targetTypes they are exactly the schema's
+// target.type enum.
+var sharedTargetTypes = []string{"aws-account", "gcp-project"}
+
+// TargetTypes returns a copy of the registry mapping tool names to the
+// target.type they must report.
+func TargetTypes() map[string]string {
+	out := make(map[string]string, len(targetTypes))
+	for tool, typ := range targetTypes {
+		out[tool] = typ
+	}
+	return out
+}
+
+// SharedTargetTypes returns the target.type values not tied to a single
+// tool, such as the aws-account or gcp-project iamspectre emits depending
+// on the cloud.
+func SharedTargetTypes() []string {
+	return append([]string(nil), sharedTargetTypes...)
+}
+
+// TargetType returns the target.type a tool must report, if the tool is
+// registered.
+func TargetType(tool string) (string, bool) {
+	typ, ok := targetTypes[tool]
+	return typ, ok
+}
@@ -0,0 +1,113 @@
+package spectrev1
+
+import "time"
+
+// Versions of the envelope. spectre/v1.1 only adds optional finding fields,
+// so both decode into the same types.
+const (
+	SchemaV1  = "spectre/v1"
+	SchemaV11 = "spectre/v1.1"
+)
+
+// IsSchema reports whether schema names a version of the spectre/v1
+// envelope.
+func IsSchema(schema string) bool {
+	return schema == SchemaV1 || schema == SchemaV11
+}
+
+// Finding severities.
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+	SeverityInfo   = "info"
+)
+
+// ToolSeverityCritical is the only spectre/v1.1 tool_severity. Findings
+// that carry it keep severity high for readers that ignore the field.
+const ToolSeverityCritical = "critical"
+
+// severities are the allowed finding severities.
+var severities = map[string]bool{
+	SeverityHigh:   true,
+	SeverityMedium: true,
+	SeverityLow:    true,
+	SeverityInfo:   true,
+}
+
+// Severities returns the allowed finding severities as a set the caller
+// may modify.
+func Severities() map[string]bool {
+	out := make(map[string]bool, len(severities))
+	for s := range severities {
+		out[s] = true
+	}
+	return out
+}
+
+// IsSeverity reports whether s is an allowed finding severity.
+func IsSeverity(s string) bool {
+	return severities[s]
+}
+
+// Report is a spectre/v1 envelope.
+type Report struct {
+	Schema    string    `json:"schema"` // spectre/v1 or spectre/v1.1
+	Tool      string    `json:"tool"`
+	Version   string    `json:"version"`
+	Timestamp time.Time `json:"timestamp"`
+	Target    Target    `json:"target"`
+	Findings  []Finding `json:"findings"`
+	Summary   Summary   `json:"summary"`
+}
+
+// Target describes what was scanned.
+type Target struct {
+	Type    string `json:"type"` // one of the schema's target types, see TargetTypes
+	URIHash string `json:"uri_hash,omitempty"`
+}
+
+// Finding is a single issue in the envelope. The fields after
+// EstimatedMonthlyWaste are spectre/v1.1 only.
+type Finding struct {
+	ID                    string   `json:"id"`
+	Severity              string   `json:"severity"` // high, medium, low, info
+	Location              string   `json:"location"`
+	Message               string   `json:"message"`
+	EstimatedMonthlyWaste *float64 `json:"estimated_monthly_waste,omitempty"`
+
+	Attributes    *ResourceAttributes `json:"attributes,omitempty"`
+	Remediation   string              `json:"remediation,omitempty"`
+	Fingerprint   string              `json:"fingerprint,omitempty"` // stable across runs, unlike ID unique per resource
+	References    []string            `json:"references,omitempty"`
+	FirstDetected *time.Time          `json:"first_detected,omitempty"`
+	Confidence    *float64            `json:"confidence,omitempty"` // 0 to 1
+	LastDetected  *time.Time          `json:"last_detected,omitempty"`
+	Occurrences   *int                `json:"occurrences,omitempty"`   // defaults to 1
+	ToolSeverity  string              `json:"tool_severity,omitempty"` // ToolSeverityCritical or empty
+}
+
+// usesV11 reports whether the finding sets any spectre/v1.1 field.
+func (f Finding) usesV11() bool {
+	return f.Attributes != nil || f.Remediation != "" || f.Fingerprint != "" ||
+		len(f.References) > 0 || f.FirstDetected != nil || f.Confidence != nil ||
+		f.LastDetected != nil || f.Occurrences != nil || f.ToolSeverity != ""
+}
+
+// ResourceAttributes are structured attributes of the resource behind a
+// finding.
+type ResourceAttributes struct {
+	Account   string            `json:"account,omitempty"`
+	Region    string            `json:"region,omitempty"`
+	Namespace string            `json:"namespace,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+}
+
+// Summary counts findings by severity.
+type Summary struct {
+	Total  int `json:"total"`
+	High   int `json:"high"`
+	Medium int `json:"medium"`
+	Low    int `json:"low"`
+	Info   int `json:"info"`
+}
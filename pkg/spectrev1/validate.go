@@ -0,0 +1,107 @@
+package spectrev1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ValidationError lists every way a report violates the envelope. Each
+// entry starts with the JSON pointer of the offending value, such as
+// /findings/0/severity.
+type ValidationError struct {
+	Schema string // version the report was checked against
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("Invalid %s report:\n  - %s", e.Schema, strings.Join(e.Errors, "\n  - "))
+}
+
+// Validate checks a JSON report against the embedded schema of its version
+// (spectre/v1 or spectre/v1.1), then checks the rules a schema cannot
+// express: target.type matching the tool and the summary counts, total and
+// per severity, matching the findings. A document with any other schema value is checked against
+// spectre/v1. It returns a *ValidationError when the report is invalid.
+func Validate(data []byte) error {
+	var envelope struct {
+		Schema interface{} `json:"schema"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return &ValidationError{
+			Schema: SchemaV1,
+			Errors: []string{fmt.Sprintf("Failed to parse JSON: %v", err)},
+		}
+	}
+	version := SchemaV1
+	if envelope.Schema == SchemaV11 {
+		version = SchemaV11
+	}
+
+	s, err := spectreSchema(version)
+	if err != nil {
+		return err
+	}
+	errs, err := s.validateJSON(data)
+	if err != nil {
+		return &ValidationError{
+			Schema: version,
+			Errors: []string{fmt.Sprintf("Failed to parse JSON: %v", err)},
+		}
+	}
+
+	// Cross-field rules assume a structurally valid document.
+	if len(errs) == 0 {
+		var report Report
+		if err := json.Unmarshal(data, &report); err != nil {
+			errs = append(errs, fmt.Sprintf("Failed to parse JSON: %v", err))
+		} else {
+			errs = semanticErrors(&report)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Schema: version, Errors: errs}
+	}
+	return nil
+}
+
+// Validate checks the report as it will be serialized.
+func (r *Report) Validate() error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("marshal report: %w", err)
+	}
+	return Validate(data)
+}
+
+// semanticErrors checks the cross-field rules of a report.
+func semanticErrors(report *Report) []string {
+	var errs []string
+	if report.Timestamp.IsZero() {
+		errs = append(errs, "/timestamp: must not be the zero time")
+	}
+	if expected, ok := TargetType(report.Tool); ok && report.Target.Type != expected {
+		errs = append(errs, fmt.Sprintf("/target/type: %q does not match tool %q (expected %q)", report.Target.Type, report.Tool, expected))
+	}
+	want := summarize(report.Findings)
+	for _, c := range []struct {
+		field     string
+		got, want int
+	}{
+		{"total", report.Summary.Total, want.Total},
+		{"high", report.Summary.High, want.High},
+		{"medium", report.Summary.Medium, want.Medium},
+		{"low", report.Summary.Low, want.Low},
+		{"info", report.Summary.Info, want.Info},
+	} {
+		if c.got != c.want {
+			what := "findings count"
+			if c.field != "total" {
+				what = c.field + " findings count"
+			}
+			errs = append(errs, fmt.Sprintf("/summary/%s: %d does not match %s %d", c.field, c.got, what, c.want))
+		}
+	}
+	return errs
+}
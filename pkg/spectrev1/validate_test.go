@@ -0,0 +1,80 @@
+package spectrev1
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	const valid = `{"schema":"spectre/v1","tool":"s3spectre","version":"0.2.1","timestamp":"2026-02-15T00:00:00Z",
+		"target":{"type":"s3"},"findings":[{"id":"A","severity":"low","location":"s3://a","message":"m"}],
+		"summary":{"total":1,"low":1}}`
+
+	tests := []struct {
+		name    string
+		data    string
+		schema  string
+		wantErr string
+	}{
+		{"valid", valid, "", ""},
+		{"invalid json", "{", SchemaV1, "Failed to parse JSON"},
+		{"target mismatch", strings.Replace(valid, `"type":"s3"`, `"type":"vault"`, 1), SchemaV1,
+			`/target/type: "vault" does not match tool "s3spectre" (expected "s3")`},
+		{"total mismatch", strings.Replace(valid, `"total":1`, `"total":2`, 1), SchemaV1,
+			"/summary/total: 2 does not match findings count 1"},
+		{"severity count mismatch", strings.Replace(valid, `"low":1`, `"high":1`, 1), SchemaV1,
+			"/summary/high: 1 does not match high findings count 0"},
+		{"v1.1 fields need v1.1", strings.Replace(valid, `"message":"m"`, `"message":"m","confidence":0.5`, 1), SchemaV1,
+			"/findings/0/confidence: property is not allowed"},
+		{"v1.1 report", strings.Replace(strings.Replace(valid, `"message":"m"`, `"message":"m","confidence":0.5`, 1),
+			`"spectre/v1"`, `"spectre/v1.1"`, 1), "", ""},
+		{"tool_severity outside enum", strings.Replace(strings.Replace(valid, `"message":"m"`, `"message":"m","tool_severity":"blocker"`, 1),
+			`"spectre/v1"`, `"spectre/v1.1"`, 1), SchemaV11, "/findings/0/tool_severity"},
+		{"unknown version", strings.Replace(valid, `"spectre/v1"`, `"spectre/v2"`, 1), SchemaV1,
+			`/schema: must be "spectre/v1"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var vErr *ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if vErr.Schema != tt.schema || !strings.Contains(strings.Join(vErr.Errors, "\n"), tt.wantErr) {
+				t.Errorf("got %s %v, want %s %q", vErr.Schema, vErr.Errors, tt.schema, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistryCopies(t *testing.T) {
+	TargetTypes()["s3spectre"] = "vault"
+	SharedTargetTypes()[0] = "vault"
+	Severities()["critical"] = true
+
+	if typ, _ := TargetType("s3spectre"); typ != "s3" {
+		t.Errorf("TargetType(s3spectre) = %q after changing a copy", typ)
+	}
+	if SharedTargetTypes()[0] != "aws-account" {
+		t.Error("SharedTargetTypes changed through a copy")
+	}
+	if IsSeverity("critical") {
+		t.Error("IsSeverity(critical) after changing a copy")
+	}
+}
+
+func TestTargetType(t *testing.T) {
+	if typ, ok := TargetType("rdsspectre"); !ok || typ != "rds" {
+		t.Errorf("TargetType(rdsspectre) = %q, %v", typ, ok)
+	}
+	if _, ok := TargetType("iamspectre"); ok {
+		t.Error("iamspectre emits shared target types and must not be registered")
+	}
+}
@@ -0,0 +1,15 @@
+// Package schemas embeds the JSON Schemas of the formats spectrehub reads,
+// so validation and the published schema files cannot drift apart.
+package schemas
+
+import _ "embed"
+
+// SpectreV1 is the JSON Schema of the spectre/v1 envelope.
+//
+//go:embed spectre-v1.schema.json
+var SpectreV1 []byte
+
+// SpectreV11 is the JSON Schema of the spectre/v1.1 envelope.
+//
+//go:embed spectre-v1.1.schema.json
+var SpectreV11 []byte